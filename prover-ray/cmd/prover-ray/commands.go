@@ -0,0 +1,169 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wire"
+)
+
+// parse parses args into fs, mapping every failure but -h to errUsage.
+func parse(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	switch {
+	case err == nil && fs.NArg() > 0:
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %v\n", fs.Args())
+		fs.Usage()
+		return errUsage
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return err
+	default:
+		return errUsage
+	}
+}
+
+func runProve(args []string) error {
+	var (
+		fs         = flag.NewFlagSet("prove", flag.ContinueOnError)
+		sf         systemFlags
+		inputsFile = fs.String("inputs", "", "JSON inputs file of the program, optionally gzipped")
+		outFile    = fs.String("out", "", "file to write the proof to")
+	)
+	sf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *outFile == "" {
+		return fmt.Errorf("missing -out")
+	}
+
+	s, err := sf.open()
+	if err != nil {
+		return err
+	}
+	proof, err := s.prove(*inputsFile)
+	if err != nil {
+		return err
+	}
+	data := wire.MarshalProof(s.sys, proof)
+	if err := os.WriteFile(*outFile, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote a %d-byte proof to %s\n", len(data), *outFile)
+	return nil
+}
+
+func runVerify(args []string) error {
+	var (
+		fs        = flag.NewFlagSet("verify", flag.ContinueOnError)
+		sf        systemFlags
+		proofFile = fs.String("proof", "", "proof file written by prove")
+	)
+	sf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+	if *proofFile == "" {
+		return fmt.Errorf("missing -proof")
+	}
+	data, err := os.ReadFile(*proofFile)
+	if err != nil {
+		return err
+	}
+
+	s, err := sf.open()
+	if err != nil {
+		return err
+	}
+	proof, err := wire.UnmarshalProof(s.sys, data)
+	if errors.Is(err, wire.ErrDigestMismatch) {
+		return fmt.Errorf("%w: prove and verify must use the same constraints file and pipeline", err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.verify(proof); err != nil {
+		return fmt.Errorf("invalid proof: %w", err)
+	}
+	fmt.Println("proof is valid")
+	return nil
+}
+
+func runStats(args []string) error {
+	var (
+		fs         = flag.NewFlagSet("stats", flag.ContinueOnError)
+		sf         systemFlags
+		inputsFile = fs.String("inputs", "", "JSON inputs file of the program, optionally gzipped")
+	)
+	sf.register(fs)
+	if err := parse(fs, args); err != nil {
+		return err
+	}
+
+	s, err := sf.open()
+	if err != nil {
+		return err
+	}
+	proof, err := s.prove(*inputsFile)
+	if err != nil {
+		return err
+	}
+	size := len(wire.MarshalProof(s.sys, proof))
+	verr := s.verify(proof)
+
+	printStats(s, proof, size)
+	if verr != nil {
+		return fmt.Errorf("invalid proof: %w", verr)
+	}
+	return nil
+}
+
+// printStats prints the steps of s with their durations, then the modules of
+// the system by decreasing area, using the sizes of the dynamic modules in
+// proof.
+func printStats(s *session, proof wiop.Proof, proofSize int) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	var total time.Duration
+	fmt.Fprintln(w, "step\tduration\t")
+	for _, t := range s.timings {
+		fmt.Fprintf(w, "%s\t%s\t\n", t.step, t.d.Round(time.Microsecond))
+		total += t.d
+	}
+	fmt.Fprintf(w, "total\t%s\t\n", total.Round(time.Microsecond))
+	fmt.Fprintf(w, "proof size\t%d B\t\n\n", proofSize)
+
+	type row struct {
+		path          string
+		rows, columns int
+		dynamic       bool
+	}
+	rows := make([]row, len(s.sys.Modules))
+	var area int
+	for i, m := range s.sys.Modules {
+		rows[i] = row{path: m.Context.Path(), rows: m.Size(), columns: len(m.Columns), dynamic: m.IsDynamic()}
+		if m.IsDynamic() {
+			rows[i].rows = proof.DynamicSizes[i]
+		}
+		area += rows[i].rows * rows[i].columns
+	}
+	slices.SortStableFunc(rows, func(a, b row) int { return cmp.Compare(b.rows*b.columns, a.rows*a.columns) })
+
+	fmt.Fprintln(w, "module\trows\tcolumns\tarea\tsizing\t")
+	for _, r := range rows {
+		sizing := "static"
+		if r.dynamic {
+			sizing = "dynamic"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t\n", r.path, r.rows, r.columns, r.rows*r.columns, sizing)
+	}
+	fmt.Fprintf(w, "total\t\t\t%d\t\t\n", area)
+	w.Flush()
+}
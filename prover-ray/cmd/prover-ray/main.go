@@ -0,0 +1,72 @@
+// Command prover-ray proves and verifies ZkC programs with the wiop stack:
+// it defines the system of a ZkC binary constraints file, compiles it with a
+// named pipeline, and proves an inputs file against it. See the README next
+// to this file for usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command is a subcommand of the tool. run receives the arguments following
+// the subcommand name.
+type command struct {
+	name, usage string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"prove", "prove a ZkC program on an inputs file and write the proof", runProve},
+	{"verify", "verify a proof written by prove", runVerify},
+	{"stats", "prove and verify, then print module sizes and timings", runStats},
+}
+
+// errUsage reports a command line that cannot be run. The flag set has
+// already printed the details.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		err := catch(func() error { return c.run(os.Args[2:]) })
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+			return
+		case errors.Is(err, errUsage):
+			os.Exit(2)
+		default:
+			fmt.Fprintf(os.Stderr, "prover-ray %s: %v\n", c.name, err)
+			os.Exit(1)
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: prover-ray <command> [flags]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun prover-ray <command> -h for the flags of a command.\n")
+}
+
+// catch runs f and turns a panic into an error. The zkcdriver and the wiop
+// runtime report invalid inputs and proofs by panicking.
+func catch(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return f()
+}
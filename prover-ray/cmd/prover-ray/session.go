@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers"
+	"github.com/consensys/linea-monorepo/prover-ray/zkcdriver"
+)
+
+// systemName is the label of the root context of every system defined by the
+// tool. It is part of the system digest, so it must not depend on the file
+// names: a proof stays verifiable after its constraints file is renamed.
+const systemName = "zkc"
+
+// session is a ZkC program defined and compiled as a wiop system, along with
+// the duration of every step run on it.
+type session struct {
+	sys     *wiop.System
+	driver  *zkcdriver.ZkCDriver
+	timings []timing
+}
+
+type timing struct {
+	step string
+	d    time.Duration
+}
+
+// systemFlags are the flags selecting the system to prove or verify against.
+type systemFlags struct {
+	bin, pipeline string
+}
+
+func (f *systemFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.bin, "bin", "", "ZkC `.bin` constraints file, optionally gzipped")
+	fs.StringVar(&f.pipeline, "pipeline", "full",
+		"compiler pipeline: full, none, or a comma-separated list of passes among "+compilers.PassNames())
+}
+
+// open defines the system of the constraints file and compiles it.
+func (f *systemFlags) open() (*session, error) {
+	if f.bin == "" {
+		return nil, fmt.Errorf("missing -bin")
+	}
+	passes, err := compilers.ParsePipeline(f.pipeline)
+	if err != nil {
+		return nil, err
+	}
+	bin, err := zkcdriver.ReadMaybeCompressedFile(f.bin)
+	if err != nil {
+		return nil, err
+	}
+	defer bin.Close()
+
+	s := &session{sys: wiop.NewSystemf(systemName)}
+	s.sys.NewRound()
+	s.time("define", func() { s.driver = zkcdriver.NewZkCDriver(s.sys, zkcdriver.Settings{}, bin) })
+	for _, p := range passes {
+		s.time("compile/"+p.Name, func() { p.Compile(s.sys) })
+	}
+	return s, nil
+}
+
+// prove traces the program on the inputs file and proves the trace.
+func (s *session) prove(inputsFile string) (wiop.Proof, error) {
+	if inputsFile == "" {
+		return wiop.Proof{}, fmt.Errorf("missing -inputs")
+	}
+	var inputs zkcdriver.PreReadInputs
+	s.time("read inputs", func() { inputs = zkcdriver.ReadZkcInputs(inputsFile) })
+	if inputs.Err != nil {
+		return wiop.Proof{}, fmt.Errorf("reading %s: %w", inputsFile, inputs.Err)
+	}
+	var proof wiop.Proof
+	s.time("prove", func() {
+		proof = s.sys.Prove(func(rt *wiop.Runtime) { s.driver.AssignWithPreRead(rt, inputs) })
+	})
+	return proof, nil
+}
+
+// verify checks proof against the session's system.
+func (s *session) verify(proof wiop.Proof) error {
+	var err error
+	s.time("verify", func() { err = s.sys.Verify(proof) })
+	return err
+}
+
+func (s *session) time(step string, f func()) {
+	start := time.Now()
+	f()
+	s.timings = append(s.timings, timing{step, time.Since(start)})
+}
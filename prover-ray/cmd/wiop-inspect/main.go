@@ -0,0 +1,88 @@
+// Command wiop-inspect defines the wiop system of a ZkC binary constraints
+// file, runs it through a compiler pipeline and exports the result. See the
+// README next to this file for usage.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/inspect"
+	"github.com/consensys/linea-monorepo/prover-ray/zkcdriver"
+)
+
+func main() {
+	var (
+		binFile  = flag.String("bin", "", "path to the ZkC `.bin` constraints file, optionally gzipped")
+		pipeline = flag.String("pipeline", "full",
+			"compiler pipeline: full, none, or a comma-separated list of passes among "+compilers.PassNames())
+		format  = flag.String("format", "json", "output format: json, dot, mermaid or diff")
+		outFile = flag.String("o", "", "output file, defaults to stdout")
+	)
+	flag.Parse()
+
+	if err := run(*binFile, *pipeline, *format, *outFile); err != nil {
+		fmt.Fprintf(os.Stderr, "wiop-inspect: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(binFile, pipeline, format, outFile string) (err error) {
+	if binFile == "" {
+		return fmt.Errorf("missing -bin")
+	}
+	if !slices.Contains([]string{"json", "dot", "mermaid", "diff"}, format) {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	passes, err := compilers.ParsePipeline(pipeline)
+	if err != nil {
+		return err
+	}
+
+	bin, err := zkcdriver.ReadMaybeCompressedFile(binFile)
+	if err != nil {
+		return err
+	}
+	defer bin.Close()
+
+	sys := wiop.NewSystemf("%s", strings.TrimSuffix(filepath.Base(binFile), ".gz"))
+	sys.NewRound()
+	zkcdriver.NewZkCDriver(sys, zkcdriver.Settings{}, bin)
+
+	rec := inspect.NewRecorder(sys)
+	for _, p := range passes {
+		rec.Run(p.Name, p.Compile)
+	}
+
+	var w io.Writer = os.Stdout
+	if outFile != "" {
+		f, createErr := os.Create(outFile)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	switch format {
+	case "dot":
+		return inspect.WriteDOT(w, rec.Snapshot())
+	case "mermaid":
+		return inspect.WriteMermaid(w, rec.Snapshot())
+	case "diff":
+		return inspect.WriteDiff(w, rec.Passes())
+	default:
+		return inspect.WriteJSON(w, rec.Report())
+	}
+}
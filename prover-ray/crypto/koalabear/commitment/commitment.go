@@ -1,6 +1,8 @@
 package commitment
 
 import (
+	"fmt"
+
 	"github.com/consensys/gnark-crypto/field/koalabear"
 	ext "github.com/consensys/gnark-crypto/field/koalabear/extensions"
 	"github.com/consensys/gnark-crypto/field/koalabear/fft"
@@ -66,6 +68,9 @@ type WMerkleTree struct {
 	numLeaves int
 	baseWidth int
 	extWidth  int
+	// salt holds the random salt columns when the tree was committed
+	// WithSalt. Leaf i absorbs salt[k][i] and salt[k][i+numLeaves].
+	salt []poly.Polynomial
 }
 
 // PointSampling contains the pair evaluation {f(w^i),f(-w^i)} for batches of
@@ -74,7 +79,9 @@ type WMerkleTree struct {
 type WMerkleProof struct {
 	RawLeafBase []PairBase
 	RawLeafExt  []PairExt
-	Proof       merkle.Proof
+	// Salt is the salt of the leaf, empty when the tree is not salted.
+	Salt  []PairBase
+	Proof merkle.Proof
 }
 
 func (wt WMerkleTree) Root() hash.Digest {
@@ -95,6 +102,46 @@ func (wt WMerkleTree) ExtWidth() int {
 	return wt.extWidth
 }
 
+// SaltWidth returns the number of salt pairs absorbed by each leaf, zero when
+// the tree was committed without WithSalt.
+func (wt WMerkleTree) SaltWidth() int {
+	return len(wt.salt)
+}
+
+// Salt returns the salt pairs of leaf i. They must be sent along with any
+// opening of the leaf, see HashSaltedLeaf.
+func (wt WMerkleTree) Salt(i int) []PairBase {
+	return SaltPairs(wt.salt, i, wt.numLeaves)
+}
+
+// NewSalt draws size salt columns from crypto/rand for a tree of n/2 paired
+// leaves: leaf i absorbs salt[k][i] and salt[k][i+n/2], see WithSalt.
+func NewSalt(size, n int) ([]poly.Polynomial, error) {
+	salt := make([]poly.Polynomial, size)
+	for k := range salt {
+		salt[k] = make(poly.Polynomial, n)
+		for i := range salt[k] {
+			if _, err := salt[k][i].SetRandom(); err != nil {
+				return nil, fmt.Errorf("commitment: sample salt: %w", err)
+			}
+		}
+	}
+	return salt, nil
+}
+
+// SaltPairs returns the salt pairs absorbed by leaf i of a tree of numLeaves
+// paired leaves, or nil if salt is empty.
+func SaltPairs(salt []poly.Polynomial, i, numLeaves int) []PairBase {
+	if len(salt) == 0 {
+		return nil
+	}
+	res := make([]PairBase, len(salt))
+	for k, col := range salt {
+		res[k] = PairBase{col[i], col[i+numLeaves]}
+	}
+	return res
+}
+
 // OpenProof returns the Merkle proof for leaf i. Raw leaf values are
 // reconstructed by the prover from the committed polynomials when needed.
 func (wt WMerkleTree) OpenProof(i int) (merkle.Proof, error) {
@@ -128,6 +175,8 @@ func NewRSCommitWithDomainCache(
 // CommitConfig configures RSCommit.Commit.
 type CommitConfig struct {
 	DomainCache *poly.DomainCache
+	// SaltSize is the number of random base pairs absorbed by each leaf.
+	SaltSize int
 }
 
 // CommitOption configures RSCommit.Commit.
@@ -141,6 +190,32 @@ func WithDomainCache(cache *poly.DomainCache) CommitOption {
 	}
 }
 
+// WithSalt makes every leaf absorb size random base-field pairs after its base
+// pairs. Without salt, a leaf holding low-entropy values can be recovered from
+// its digest by enumeration; a salted leaf reveals nothing until it is opened
+// together with its salt. The salt is drawn from crypto/rand at each Commit.
+func WithSalt(size int) CommitOption {
+	return func(c *CommitConfig) error {
+		if size < 0 {
+			return fmt.Errorf("commitment: negative salt size %d", size)
+		}
+		c.SaltSize = size
+		return nil
+	}
+}
+
+// HashSaltedLeaf recomputes the digest of a leaf committed WithSalt from its
+// opened values and salt. With an empty salt it is lh.HashLeaf(base, ext).
+func HashSaltedLeaf(lh LeafHasher, base []PairBase, salt []PairBase, ext []PairExt) hash.Digest {
+	if len(salt) == 0 {
+		return lh.HashLeaf(base, ext)
+	}
+	salted := make([]PairBase, 0, len(base)+len(salt))
+	salted = append(salted, base...)
+	salted = append(salted, salt...)
+	return lh.HashLeaf(salted, ext)
+}
+
 func (Poseidon2LeafHasher) HashLeaf(base []PairBase, ext []PairExt) hash.Digest {
 	h := hash.NewPoseidon2SpongeHasher()
 	h.WriteElements(hash.NewElement(leafDomainTag), hash.NewElement(uint64(len(base))), hash.NewElement(uint64(len(ext))))
@@ -196,7 +271,8 @@ func (Poseidon2NodeHasher) HashNodes(dst, left, right []hash.Digest) {
 
 // Commit commits to base and extension polynomials in one Merkle tree. Inputs
 // are assumed to be in Lagrange form and may have different sizes. Each leaf
-// hash absorbs all base pairs followed by all extension pairs.
+// hash absorbs all base pairs followed by all extension pairs. With WithSalt,
+// the salt pairs are absorbed right after the base pairs.
 func (rs *RSCommit) Commit(
 	basePolys []poly.Polynomial,
 	extPolys []poly.ExtPolynomial,
@@ -250,9 +326,17 @@ func (rs *RSCommit) Commit(
 		baseWidth: len(encodedBase),
 		extWidth:  len(encodedExt),
 	}
+	leafBase := encodedBase
+	if config.SaltSize > 0 {
+		wTree.salt, err = NewSalt(config.SaltSize, int(N))
+		if err != nil {
+			return WMerkleTree{}, err
+		}
+		leafBase = append(leafBase[:len(leafBase):len(leafBase)], wTree.salt...)
+	}
 	leaves := make([]hash.Digest, halfN)
 	src := LeafSource{
-		Base:       encodedBase,
+		Base:       leafBase,
 		Ext:        encodedExt,
 		PairOffset: halfN,
 	}
@@ -248,6 +248,54 @@ func TestRSCommitEmptyRails(t *testing.T) {
 	}
 }
 
+func TestRSCommitWithSalt(t *testing.T) {
+	basePolys := []poly.Polynomial{
+		{baseElement(1), baseElement(2), baseElement(3), baseElement(4)},
+	}
+	extPolys := []poly.ExtPolynomial{
+		{
+			extElement(1, 2, 3, 4),
+			extElement(5, 6, 7, 8),
+			extElement(9, 10, 11, 12),
+			extElement(13, 14, 15, 16),
+		},
+	}
+
+	committer := NewRSCommit(4, 2, DefaultLeafHasher, DefaultNodeHasher)
+	tree, err := committer.Commit(basePolys, extPolys, WithSalt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tree.SaltWidth(); got != 2 {
+		t.Fatalf("SaltWidth = %d, want 2", got)
+	}
+	if got := tree.BaseWidth(); got != len(basePolys) {
+		t.Fatalf("base rail width = %d, want %d", got, len(basePolys))
+	}
+
+	const leafIdx = 3
+	proof, err := tree.OpenProof(leafIdx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseLeaf, extLeaf := rawLeafFromPolys(committer, basePolys, extPolys, leafIdx)
+	leaf := HashSaltedLeaf(DefaultLeafHasher, baseLeaf, tree.Salt(leafIdx), extLeaf)
+	if !merkle.Verify(tree.Root(), proof, leaf, DefaultNodeHasher) {
+		t.Fatal("salted Merkle proof did not verify")
+	}
+	if merkle.Verify(tree.Root(), proof, DefaultLeafHasher.HashLeaf(baseLeaf, extLeaf), DefaultNodeHasher) {
+		t.Fatal("salted leaf verified without its salt")
+	}
+
+	other, err := committer.Commit(basePolys, extPolys, WithSalt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Root() == tree.Root() {
+		t.Fatal("two salted commitments to the same polynomials share their root")
+	}
+}
+
 type scalarOnlyLeafHasher struct {
 	inner LeafHasher
 }
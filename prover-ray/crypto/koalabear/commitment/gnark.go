@@ -0,0 +1,40 @@
+package commitment
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/poseidon2"
+)
+
+// GnarkLeafHasher is implemented by the leaf hashers that can be replayed in
+// a gnark circuit whose native field is KoalaBear.
+type GnarkLeafHasher interface {
+	// GnarkHashPair hashes a leaf made of a single pair {p, q}, as HashLeaf
+	// does. p and q hold one coordinate for a base pair and hash.ExtDegree
+	// coordinates for an extension pair.
+	GnarkHashPair(api frontend.API, p, q []frontend.Variable) poseidon2.GnarkOctuplet
+}
+
+// GnarkNodeHasher is implemented by the node hashers that can be replayed in a
+// gnark circuit whose native field is KoalaBear.
+type GnarkNodeHasher interface {
+	GnarkHashNode(api frontend.API, left, right poseidon2.GnarkOctuplet) poseidon2.GnarkOctuplet
+}
+
+// GnarkHashPair is the in-circuit counterpart of HashLeaf for a single pair.
+func (Poseidon2LeafHasher) GnarkHashPair(api frontend.API, p, q []frontend.Variable) poseidon2.GnarkOctuplet {
+	nbBase, nbExt := 1, 0
+	if len(p) > 1 {
+		nbBase, nbExt = 0, 1
+	}
+
+	h := poseidon2.NewGnarkSpongeHasher(api)
+	h.Write(leafDomainTag, nbBase, nbExt)
+	h.Write(p...)
+	h.Write(q...)
+	return h.Sum()
+}
+
+// GnarkHashNode is the in-circuit counterpart of HashNode.
+func (Poseidon2NodeHasher) GnarkHashNode(api frontend.API, left, right poseidon2.GnarkOctuplet) poseidon2.GnarkOctuplet {
+	return poseidon2.GnarkNodeCompress(api, nodeDomainTag, left, right)
+}
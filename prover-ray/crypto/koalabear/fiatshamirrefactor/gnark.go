@@ -0,0 +1,176 @@
+package fiatshamirrefactor
+
+import (
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/poseidon2"
+)
+
+// GnarkFieldHasher is the in-circuit counterpart of [hash.FieldHasher], e.g.
+// [poseidon2.GnarkSpongeHasher].
+type GnarkFieldHasher interface {
+	Reset()
+	Write(data ...frontend.Variable)
+	Sum() poseidon2.GnarkOctuplet
+}
+
+// GnarkTranscript mirrors [Transcript] in a gnark circuit whose native field is
+// KoalaBear. The challenges are computed in the same order and from the same
+// hashes as the native transcript, so both derive the same values from the
+// same bindings. The errors are raised while the circuit is defined.
+type GnarkTranscript struct {
+	api frontend.API
+	h   GnarkFieldHasher
+
+	challenges         []gnarkChallenge // the order matters
+	nameToChallengePos map[string]int
+}
+
+type gnarkChallenge struct {
+	bindings   []frontend.Variable
+	name       string
+	value      poseidon2.GnarkOctuplet
+	isComputed bool
+}
+
+// NewGnarkTranscript creates a new in-circuit Fiat-Shamir transcript using the
+// given hash function. As for [NewTranscript], challengesID are the names of
+// the challenges in their computation order and it panics on duplicates.
+func NewGnarkTranscript(api frontend.API, h GnarkFieldHasher, challengesID ...string) *GnarkTranscript {
+	t := &GnarkTranscript{
+		api:                api,
+		h:                  h,
+		challenges:         make([]gnarkChallenge, 0, len(challengesID)),
+		nameToChallengePos: make(map[string]int, len(challengesID)),
+	}
+	for _, id := range challengesID {
+		if _, ok := t.nameToChallengePos[id]; ok {
+			panic("duplicate challenge name: " + id)
+		}
+		t.nameToChallengePos[id] = len(t.challenges)
+		t.challenges = append(t.challenges, gnarkChallenge{name: id})
+	}
+	return t
+}
+
+// Bind binds values to the given challenge, see [Transcript.Bind].
+func (t *GnarkTranscript) Bind(challengeID string, bValue []frontend.Variable) error {
+
+	pos, ok := t.nameToChallengePos[challengeID]
+	if !ok {
+		return errChallengeNotFound
+	}
+
+	if t.challenges[pos].isComputed {
+		return errChallengeAlreadyComputed
+	}
+
+	t.challenges[pos].bindings = append(t.challenges[pos].bindings, bValue...)
+	return nil
+}
+
+// NewChallenge appends a new challenge to the transcript, see
+// [Transcript.NewChallenge].
+func (t *GnarkTranscript) NewChallenge(challengeID string) error {
+	if _, ok := t.nameToChallengePos[challengeID]; ok {
+		return errChallengeAlreadyExists
+	}
+	t.nameToChallengePos[challengeID] = len(t.challenges)
+	t.challenges = append(t.challenges, gnarkChallenge{name: challengeID})
+	return nil
+}
+
+// ComputeChallenge computes and returns the challenge corresponding to the
+// given name, see [Transcript.ComputeChallenge].
+func (t *GnarkTranscript) ComputeChallenge(challengeID string) (poseidon2.GnarkOctuplet, error) {
+	return t.computeChallenge(challengeID, nil)
+}
+
+// ComputeChallengeWithProofOfWork computes the challenge corresponding to the
+// given name from the salt of the proof of work recorded by the prover, and
+// asserts that its first nbBits are zero. It is the counterpart of
+// [Transcript.ComputeChallenge] called with [WithGrinding] once the proof of
+// work is set.
+func (t *GnarkTranscript) ComputeChallengeWithProofOfWork(
+	challengeID string,
+	nbBits int,
+	salt frontend.Variable,
+) (poseidon2.GnarkOctuplet, error) {
+
+	if err := validateGrindingBits(nbBits); err != nil {
+		return poseidon2.GnarkOctuplet{}, err
+	}
+	if nbBits == 0 {
+		return t.ComputeChallenge(challengeID)
+	}
+
+	pos, ok := t.nameToChallengePos[challengeID]
+	if !ok {
+		return poseidon2.GnarkOctuplet{}, errChallengeNotFound
+	}
+	if t.challenges[pos].isComputed {
+		// the proof of work is already checked
+		return t.challenges[pos].value, nil
+	}
+
+	value, err := t.computeChallenge(challengeID, []frontend.Variable{proofOfWorkDomainTag, nbBits, salt})
+	if err != nil {
+		return poseidon2.GnarkOctuplet{}, err
+	}
+
+	t.assertZeroGrindingBits(value, nbBits)
+	return value, nil
+}
+
+func (t *GnarkTranscript) computeChallenge(challengeID string, pow []frontend.Variable) (poseidon2.GnarkOctuplet, error) {
+
+	pos, ok := t.nameToChallengePos[challengeID]
+	if !ok {
+		return poseidon2.GnarkOctuplet{}, errChallengeNotFound
+	}
+
+	// if the challenge was already computed we return it
+	challenge := t.challenges[pos]
+	if challenge.isComputed {
+		return challenge.value, nil
+	}
+
+	if pos != 0 && !t.challenges[pos-1].isComputed {
+		return poseidon2.GnarkOctuplet{}, errPreviousChallengeNotComputed
+	}
+
+	t.h.Reset()
+
+	for _, e := range hash.StringToElements(challengeIDDomainTag, challengeID) {
+		t.h.Write(e.Uint64())
+	}
+
+	// write the previous challenge if it's not the first challenge
+	if pos != 0 {
+		t.h.Write(t.challenges[pos-1].value[:]...)
+	}
+
+	// write the binded values in the order they were added
+	t.h.Write(challenge.bindings...)
+	t.h.Write(pow...)
+
+	challenge.value = t.h.Sum()
+	challenge.isComputed = true
+	t.challenges[pos] = challenge
+	t.h.Reset()
+
+	return challenge.value, nil
+}
+
+// assertZeroGrindingBits is the in-circuit counterpart of hasZeroGrindingBits.
+func (t *GnarkTranscript) assertZeroGrindingBits(challenge poseidon2.GnarkOctuplet, nbBits int) {
+	remaining := nbBits
+	for i := 0; i < hash.ExtDegree && remaining > 0; i++ {
+		nbBitsToCheck := min(remaining, koalabearBits)
+		bits := t.api.ToBinary(challenge[i], koalabearBits)
+		for _, b := range bits[:nbBitsToCheck] {
+			t.api.AssertIsEqual(b, 0)
+		}
+		remaining -= nbBitsToCheck
+	}
+}
@@ -0,0 +1,180 @@
+package fiatshamirrefactor
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/koalabear"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/poseidon2"
+)
+
+const gnarkTestGrinding = 8
+
+var gnarkTestChallenges = []string{"zeta", "alpha_DEEP", "beta"}
+
+// gnarkTranscriptCircuit replays a transcript of 3 challenges, each bound to
+// the corresponding bindings, the second one with a proof of work.
+type gnarkTranscriptCircuit struct {
+	Bindings   [3][]frontend.Variable
+	Salt       frontend.Variable
+	Challenges [3]poseidon2.GnarkOctuplet
+}
+
+func (c *gnarkTranscriptCircuit) Define(api frontend.API) error {
+
+	ts := NewGnarkTranscript(api, poseidon2.NewGnarkSpongeHasher(api), gnarkTestChallenges...)
+
+	for i, name := range gnarkTestChallenges {
+		if err := ts.Bind(name, c.Bindings[i]); err != nil {
+			return err
+		}
+
+		var (
+			challenge poseidon2.GnarkOctuplet
+			err       error
+		)
+		if i == 1 {
+			challenge, err = ts.ComputeChallengeWithProofOfWork(name, gnarkTestGrinding, c.Salt)
+		} else {
+			challenge, err = ts.ComputeChallenge(name)
+		}
+		if err != nil {
+			return err
+		}
+
+		for j := range challenge {
+			api.AssertIsEqual(challenge[j], c.Challenges[i][j])
+		}
+	}
+	return nil
+}
+
+// nativeTranscriptAssignment runs the native transcript and returns the
+// matching assignment of gnarkTranscriptCircuit.
+func nativeTranscriptAssignment(t *testing.T) *gnarkTranscriptCircuit {
+	t.Helper()
+
+	h := hash.NewPoseidon2SpongeHasher()
+	ts := NewTranscript(&h, gnarkTestChallenges...)
+
+	var res gnarkTranscriptCircuit
+	for i, name := range gnarkTestChallenges {
+		bindings := make([]koalabear.Element, 5*i+3)
+		res.Bindings[i] = make([]frontend.Variable, len(bindings))
+		for j := range bindings {
+			bindings[j] = testElement(uint64(100*i + j))
+			res.Bindings[i][j] = bindings[j].String()
+		}
+		if err := ts.Bind(name, bindings); err != nil {
+			t.Fatalf("bind %s: %v", name, err)
+		}
+
+		var opts []ComputeChallengeOption
+		if i == 1 {
+			opts = append(opts, WithGrinding(gnarkTestGrinding))
+		}
+		challenge, err := ts.ComputeChallenge(name, opts...)
+		if err != nil {
+			t.Fatalf("compute %s: %v", name, err)
+		}
+		for j := range challenge {
+			res.Challenges[i][j] = challenge[j].String()
+		}
+	}
+
+	pow, ok := ts.ProofOfWork(gnarkTestChallenges[1])
+	if !ok {
+		t.Fatal("missing proof of work")
+	}
+	res.Salt = pow.Salt.String()
+	return &res
+}
+
+func TestGnarkTranscript(t *testing.T) {
+
+	assignment := nativeTranscriptAssignment(t)
+
+	var circuit gnarkTranscriptCircuit
+	for i := range circuit.Bindings {
+		circuit.Bindings[i] = make([]frontend.Variable, len(assignment.Bindings[i]))
+	}
+
+	ccs, err := frontend.CompileU32(koalabear.Modulus(), scs.NewBuilder, &circuit)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+
+	isSolved := func(a *gnarkTranscriptCircuit) error {
+		w, err := frontend.NewWitness(a, koalabear.Modulus())
+		if err != nil {
+			t.Fatalf("witness: %v", err)
+		}
+		return ccs.IsSolved(w)
+	}
+
+	if err := isSolved(assignment); err != nil {
+		t.Fatalf("the native transcript is not replayed by the circuit: %v", err)
+	}
+
+	tamperedBinding := *assignment
+	tamperedBinding.Bindings[0] = append([]frontend.Variable{"1"}, assignment.Bindings[0][1:]...)
+	if err := isSolved(&tamperedBinding); err == nil {
+		t.Fatal("the circuit accepted a tampered binding")
+	}
+
+	// The challenges are rebound to their new values, so that only the proof
+	// of work is wrong.
+	tamperedSalt := nativeTranscriptAssignmentWithSalt(t, assignment, 1)
+	if err := isSolved(tamperedSalt); err == nil {
+		t.Fatal("the circuit accepted an invalid proof of work")
+	}
+}
+
+// nativeTranscriptAssignmentWithSalt returns a copy of assignment where the
+// salt is shifted by delta and the challenges are the ones the native
+// transcript derives from it, without checking the proof of work.
+func nativeTranscriptAssignmentWithSalt(t *testing.T, assignment *gnarkTranscriptCircuit, delta uint64) *gnarkTranscriptCircuit {
+	t.Helper()
+
+	var salt koalabear.Element
+	if _, err := salt.SetString(assignment.Salt.(string)); err != nil {
+		t.Fatalf("salt: %v", err)
+	}
+	salt.Add(&salt, new(koalabear.Element).SetUint64(delta))
+
+	h := hash.NewPoseidon2SpongeHasher()
+	ts := NewTranscript(&h, gnarkTestChallenges...)
+
+	res := *assignment
+	res.Salt = salt.String()
+	for i, name := range gnarkTestChallenges {
+		bindings := make([]koalabear.Element, len(assignment.Bindings[i]))
+		for j := range bindings {
+			bindings[j] = testElement(uint64(100*i + j))
+		}
+		if err := ts.Bind(name, bindings); err != nil {
+			t.Fatalf("bind %s: %v", name, err)
+		}
+
+		var pow *ProofOfWork
+		if i == 1 {
+			pow = &ProofOfWork{NbBits: gnarkTestGrinding, Salt: salt}
+		}
+		challenge, err := ts.computeChallengeDigest(name, i, ts.challenges[i], pow)
+		if err != nil {
+			t.Fatalf("compute %s: %v", name, err)
+		}
+		ts.challenges[i].value = challenge
+		ts.challenges[i].isComputed = true
+		for j := range challenge {
+			res.Challenges[i][j] = challenge[j].String()
+		}
+	}
+
+	if hasZeroGrindingBits(ts.challenges[1].value, gnarkTestGrinding) {
+		t.Skip("the shifted salt is a valid proof of work")
+	}
+	return &res
+}
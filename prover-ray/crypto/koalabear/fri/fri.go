@@ -18,6 +18,12 @@ import (
 	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
 )
 
+// Domain tags of the final polynomial bound to the first query challenge.
+const (
+	finalPolyBaseTag uint64 = 0x42415345 // "BASE"
+	finalPolyExtTag  uint64 = 0x45585450 // "EXTP"
+)
+
 // foldParallelThreshold is the smallest half-layer size at which fan-out
 // across goroutines beats the precomputed-xInv seeding overhead per chunk.
 const foldParallelThreshold = 1 << 12
@@ -36,6 +42,7 @@ type Params struct {
 	domains      []*fft.Domain // domains[j] has cardinality N/2^j, generator ωⱼ
 	domainsLight []domainLight // domainLight stores only the cardinality and the domain generator
 	grinding     int           // grinding bits for PoW, on the alpha
+	zk           bool          // mask the level-0 polynomial, see WithZeroKnowledge
 }
 
 type Config struct {
@@ -44,6 +51,9 @@ type Config struct {
 	// prover, but security goes from log_blowup * num_queries to
 	// log_blowup * num_queries + query_proof_of_work_bits.
 	Grinding int
+	// ZeroKnowledge makes the prover mask the level-0 polynomial with a
+	// random polynomial of the same degree, see WithZeroKnowledge.
+	ZeroKnowledge bool
 }
 
 type Option func(c *Config) error
@@ -65,6 +75,24 @@ func WithGrinding(nbBits int) Option {
 	}
 }
 
+// WithZeroKnowledge makes the proofs hiding. The prover samples a random
+// polynomial R of degree < D, commits to it and folds F + β·R instead of the
+// level-0 polynomial F, where β is drawn after both roots are bound. The FRI
+// layers, the final polynomial and the query openings past the first round
+// are then independent of F; the first round still opens F at the queried
+// positions, so F must itself be hiding at a few points (e.g. a randomized
+// DEEP quotient over salted commitments) and its tree must be salted, see
+// Params.BuildSaltedLevelTree.
+//
+// Prover and verifier must agree on the option: a masked proof is rejected by
+// non-ZK parameters and vice versa. The gnark verifier does not support it.
+func WithZeroKnowledge() Option {
+	return func(c *Config) error {
+		c.ZeroKnowledge = true
+		return nil
+	}
+}
+
 // NewParams constructs and validates a Params, precomputing r+1 domains and inv(2).
 func NewParams(
 	N, D, numQueries int,
@@ -107,6 +135,7 @@ func NewParams(
 		numRounds:  numRounds,
 		invTwo:     invTwo,
 		grinding:   config.Grinding,
+		zk:         config.ZeroKnowledge,
 	}
 
 	if !config.WoFullDomainAllocation {
@@ -142,7 +171,9 @@ type QueryLayer struct {
 	LeafQBase koalabear.Element
 	LeafPExt  ext.E6 // populated when Field == field.KindExt
 	LeafQExt  ext.E6
-	Path      merkle.Proof // authenticates the pair; depth = log₂(Nⱼ/2)
+	// Salt is the salt of the opened leaf, empty when the tree is not salted.
+	Salt []commitment.PairBase
+	Path merkle.Proof // authenticates the pair; depth = log₂(Nⱼ/2)
 }
 
 // Query holds the opening data for one full query path across all r levels.
@@ -196,11 +227,14 @@ func (e LevelEvals) Len() int {
 // Level holds one polynomial introduced at the folding round where the running
 // polynomial's degree matches Level.D. Tree is the pre-built paired-leaf Merkle
 // tree for Evals; build it with Params.BuildLevelTree or Params.BuildLevelTreeExt
-// so the leaf/node hashers match.
+// so the leaf/node hashers match. Salt holds the salt columns of a tree built
+// with Params.BuildSaltedLevelTree or Params.BuildSaltedLevelTreeExt and is
+// nil otherwise.
 type Level struct {
 	D     int
 	Evals LevelEvals
 	Tree  *merkle.Tree
+	Salt  []poly.Polynomial
 }
 
 // Proof is the complete multi-degree FRI proof. Level polynomial Merkle roots
@@ -217,6 +251,17 @@ type Proof struct {
 	FinalPolyExt  []ext.E6                                  // populated when FinalField == field.KindExt
 	FRIQueries    []Query                                   // len = NumQueries
 	PoW           map[string]fiatshamirrefactor.ProofOfWork // proof of work in case grinding has nbBits > 0
+
+	// Zero-knowledge mask, populated only when the parameters were built
+	// WithZeroKnowledge. MaskRoot commits to the mask evaluations on the full
+	// domain and MaskQueries[k] opens them at outer query k.
+	MaskRoot    hash.Digest
+	MaskQueries []QueryLayer
+}
+
+// ZeroKnowledge reports whether the parameters were built WithZeroKnowledge.
+func (p Params) ZeroKnowledge() bool {
+	return p.zk
 }
 
 // FullDomainGenerator returns the generator of the full evaluation domain (layer 0, size N).
@@ -249,13 +294,37 @@ func (p Params) EncodeExt(poly []ext.E6) ([]ext.E6, error) {
 // level polynomial: tree of len(layer)/2 leaves where
 // leaf k = LeafHasher(encode(layer[k]) || encode(layer[k + len(layer)/2])).
 func (p Params) BuildLevelTree(layer []koalabear.Element) (*merkle.Tree, error) {
-	return buildTreeBase(layer, p.LeafHasher, p.NodeHasher)
+	return buildTreeBase(layer, nil, p.LeafHasher, p.NodeHasher)
 }
 
 // BuildLevelTreeExt builds the paired-leaf Merkle tree expected by FRI for an
 // extension-field level polynomial.
 func (p Params) BuildLevelTreeExt(layer []ext.E6) (*merkle.Tree, error) {
-	return buildTreeExt(layer, p.LeafHasher, p.NodeHasher)
+	return buildTreeExt(layer, nil, p.LeafHasher, p.NodeHasher)
+}
+
+// BuildSaltedLevelTree is BuildLevelTree with saltSize random base-field
+// pairs absorbed by every leaf, as with commitment.WithSalt. The returned salt
+// goes in Level.Salt; the queries then open the salt of the queried leaves so
+// that the unopened leaves reveal nothing about the layer.
+func (p Params) BuildSaltedLevelTree(layer []koalabear.Element, saltSize int) (*merkle.Tree, []poly.Polynomial, error) {
+	salt, err := commitment.NewSalt(saltSize, len(layer))
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := buildTreeBase(layer, salt, p.LeafHasher, p.NodeHasher)
+	return tree, salt, err
+}
+
+// BuildSaltedLevelTreeExt is the extension-field counterpart of
+// BuildSaltedLevelTree.
+func (p Params) BuildSaltedLevelTreeExt(layer []ext.E6, saltSize int) (*merkle.Tree, []poly.Polynomial, error) {
+	salt, err := commitment.NewSalt(saltSize, len(layer))
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := buildTreeExt(layer, salt, p.LeafHasher, p.NodeHasher)
+	return tree, salt, err
 }
 
 // ────────────────────────────────────────────────────────────────────────────────
@@ -309,6 +378,9 @@ func buildProvePlan(p Params, levels []Level) (provePlan, error) {
 	if levels[0].Tree == nil {
 		return plan, fmt.Errorf("fri: Prove: levels[0].Tree is nil")
 	}
+	if err := checkLevelSalt(levels[0]); err != nil {
+		return plan, fmt.Errorf("fri: Prove: levels[0]: %w", err)
+	}
 
 	plan.numLevels = len(levels)
 
@@ -342,12 +414,36 @@ func buildProvePlan(p Params, levels []Level) (provePlan, error) {
 		if levels[l].Tree == nil {
 			return plan, fmt.Errorf("fri: Prove: levels[%d].Tree is nil", l)
 		}
+		if err := checkLevelSalt(levels[l]); err != nil {
+			return plan, fmt.Errorf("fri: Prove: levels[%d]: %w", l, err)
+		}
 	}
 
 	return plan, nil
 }
 
-func registerChallenges(p Params, numExtraLevels int, ts *fiatshamirrefactor.Transcript) error {
+// checkLevelSalt checks that the salt columns of a level cover its
+// evaluations.
+func checkLevelSalt(level Level) error {
+	for k, col := range level.Salt {
+		if len(col) != level.Evals.Len() {
+			return fmt.Errorf("salt column %d has length %d, evaluations have length %d", k, len(col), level.Evals.Len())
+		}
+	}
+	return nil
+}
+
+// challengeRegistry is implemented by the native and the gnark transcripts.
+type challengeRegistry interface {
+	NewChallenge(challengeID string) error
+}
+
+func registerChallenges(p Params, numExtraLevels int, ts challengeRegistry) error {
+	if p.zk {
+		if err := ts.NewChallenge(maskName()); err != nil {
+			return err
+		}
+	}
 	if numExtraLevels > 0 {
 		if err := ts.NewChallenge(gammaName()); err != nil {
 			return err
@@ -367,6 +463,28 @@ func registerChallenges(p Params, numExtraLevels int, ts *fiatshamirrefactor.Tra
 }
 
 func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.Transcript) (Proof, []int, error) {
+	var prf Proof
+
+	// ── Zero-knowledge mask (bound before any other challenge) ──────────────
+	var (
+		mask     []koalabear.Element
+		maskTree *merkle.Tree
+		beta     koalabear.Element
+	)
+	if p.zk {
+		var err error
+		mask, maskTree, err = sampleMaskBase(p)
+		if err != nil {
+			return Proof{}, nil, err
+		}
+		prf.MaskRoot = maskTree.Root()
+		challenge, err := computeMaskChallenge(ts, levels[0].Tree.Root(), prf.MaskRoot)
+		if err != nil {
+			return Proof{}, nil, fmt.Errorf("fri: Prove: compute mask challenge: %w", err)
+		}
+		beta.Set(&challenge[0])
+	}
+
 	// ── Gamma computation (all level roots, including level 0, bound upfront) ─
 	gammas := make([]koalabear.Element, plan.numLevels)
 	if plan.numLevels > 1 {
@@ -398,7 +516,6 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 	friTrees := make([]*merkle.Tree, p.numRounds)
 	alphas := make([]koalabear.Element, p.numRounds)
 
-	var prf Proof
 	if p.numRounds > 1 {
 		prf.FRIRoots = make([]hash.Digest, p.numRounds-1)
 	}
@@ -425,7 +542,7 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 			tree = levels[0].Tree // caller-supplied, root must match running pre-fold
 		} else {
 			var err error
-			tree, err = buildTreeBase(running, p.LeafHasher, p.NodeHasher)
+			tree, err = buildTreeBase(running, nil, p.LeafHasher, p.NodeHasher)
 			if err != nil {
 				return Proof{}, nil, fmt.Errorf("fri: Prove: build tree layer %d: %w", j, err)
 			}
@@ -448,8 +565,15 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 			prf.FRIRoots[j-1] = root
 		}
 
+		// The mask is folded along with level 0 but not committed with it:
+		// layers[0] must match the caller's tree.
+		toFold := running
+		if j == 0 && mask != nil {
+			toFold = addScaledBase(running, mask, beta)
+		}
+
 		// foldLayer returns a new slice, so running for round j+1 is independent.
-		running = foldLayerBase(running, alphas[j], p.domains[j], p.invTwo)
+		running = foldLayerBase(toFold, alphas[j], p.domains[j], p.invTwo)
 	}
 	layers[p.numRounds] = running
 	prf.FinalField = field.KindBase
@@ -472,6 +596,10 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 		}
 	}
 
+	if mask != nil {
+		prf.MaskQueries = make([]QueryLayer, p.NumQueries)
+	}
+
 	queryPositions := make([]int, p.NumQueries)
 	for k := 0; k < p.NumQueries; k++ {
 		challenge, err := ts.ComputeChallenge(queryName(k))
@@ -491,8 +619,17 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 		if err != nil {
 			return Proof{}, nil, fmt.Errorf("fri: Prove: open FRI query %d: %w", k, err)
 		}
+		q.Layers[0].Salt = commitment.SaltPairs(levels[0].Salt, s, p.N/2)
 		prf.FRIQueries[k] = q
 
+		if mask != nil {
+			mq, err := openQueryBase(s, [][]koalabear.Element{mask}, []*merkle.Tree{maskTree}, 1)
+			if err != nil {
+				return Proof{}, nil, fmt.Errorf("fri: Prove: open mask query %d: %w", k, err)
+			}
+			prf.MaskQueries[k] = mq.Layers[0]
+		}
+
 		for l := 1; l < plan.numLevels; l++ {
 			jl := log2(p.D / levels[l].D)
 			Nl := p.N >> jl
@@ -506,6 +643,7 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 				Field:     field.KindBase,
 				LeafPBase: levels[l].Evals.Base[base],
 				LeafQBase: levels[l].Evals.Base[base+Nl/2],
+				Salt:      commitment.SaltPairs(levels[l].Salt, base, Nl/2),
 				Path:      path,
 			}
 		}
@@ -515,6 +653,27 @@ func proveBase(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.
 }
 
 func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.Transcript) (Proof, []int, error) {
+	var prf Proof
+
+	var (
+		mask     []ext.E6
+		maskTree *merkle.Tree
+		beta     ext.E6
+	)
+	if p.zk {
+		var err error
+		mask, maskTree, err = sampleMaskExt(p)
+		if err != nil {
+			return Proof{}, nil, err
+		}
+		prf.MaskRoot = maskTree.Root()
+		challenge, err := computeMaskChallenge(ts, levels[0].Tree.Root(), prf.MaskRoot)
+		if err != nil {
+			return Proof{}, nil, fmt.Errorf("fri: Prove: compute mask challenge: %w", err)
+		}
+		beta = hash.OutputToExt(challenge)
+	}
+
 	// ── Gamma computation (all level roots, including level 0, bound upfront) ─
 	gammas := make([]ext.E6, plan.numLevels)
 	if plan.numLevels > 1 {
@@ -542,7 +701,6 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 	friTrees := make([]*merkle.Tree, p.numRounds)
 	alphas := make([]ext.E6, p.numRounds)
 
-	var prf Proof
 	if p.numRounds > 1 {
 		prf.FRIRoots = make([]hash.Digest, p.numRounds-1)
 	}
@@ -566,7 +724,7 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 			tree = levels[0].Tree
 		} else {
 			var err error
-			tree, err = buildTreeExt(running, p.LeafHasher, p.NodeHasher)
+			tree, err = buildTreeExt(running, nil, p.LeafHasher, p.NodeHasher)
 			if err != nil {
 				return Proof{}, nil, fmt.Errorf("fri: Prove: build tree layer %d: %w", j, err)
 			}
@@ -588,7 +746,12 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 			prf.FRIRoots[j-1] = root
 		}
 
-		running = foldLayerExt(running, alphas[j], p.domains[j], p.invTwo)
+		toFold := running
+		if j == 0 && mask != nil {
+			toFold = addScaledExt(running, mask, beta)
+		}
+
+		running = foldLayerExt(toFold, alphas[j], p.domains[j], p.invTwo)
 	}
 	layers[p.numRounds] = running
 	prf.FinalField = field.KindExt
@@ -609,6 +772,10 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 		}
 	}
 
+	if mask != nil {
+		prf.MaskQueries = make([]QueryLayer, p.NumQueries)
+	}
+
 	queryPositions := make([]int, p.NumQueries)
 	for k := 0; k < p.NumQueries; k++ {
 		challenge, err := ts.ComputeChallenge(queryName(k))
@@ -628,8 +795,17 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 		if err != nil {
 			return Proof{}, nil, fmt.Errorf("fri: Prove: open FRI query %d: %w", k, err)
 		}
+		q.Layers[0].Salt = commitment.SaltPairs(levels[0].Salt, s, p.N/2)
 		prf.FRIQueries[k] = q
 
+		if mask != nil {
+			mq, err := openQueryExt(s, [][]ext.E6{mask}, []*merkle.Tree{maskTree}, 1)
+			if err != nil {
+				return Proof{}, nil, fmt.Errorf("fri: Prove: open mask query %d: %w", k, err)
+			}
+			prf.MaskQueries[k] = mq.Layers[0]
+		}
+
 		for l := 1; l < plan.numLevels; l++ {
 			jl := log2(p.D / levels[l].D)
 			Nl := p.N >> jl
@@ -643,6 +819,7 @@ func proveExt(p Params, levels []Level, plan provePlan, ts *fiatshamirrefactor.T
 				Field:    field.KindExt,
 				LeafPExt: levels[l].Evals.Ext[base],
 				LeafQExt: levels[l].Evals.Ext[base+Nl/2],
+				Salt:     commitment.SaltPairs(levels[l].Salt, base, Nl/2),
 				Path:     path,
 			}
 		}
@@ -673,8 +850,11 @@ func Verify(p Params, levelRoots []hash.Digest, levelDs []int, prf Proof, ts *fi
 	if len(levelRoots) != len(levelDs) {
 		return fmt.Errorf("fri: Verify: levelRoots has %d entries, levelDs has %d", len(levelRoots), len(levelDs))
 	}
-	if levelDs[0] != p.D {
-		return fmt.Errorf("fri: Verify: levelDs[0]=%d must equal p.D=%d", levelDs[0], p.D)
+
+	// levelAtRound: folding round j → level index l (1-based).
+	levelAtRound, err := levelIntroRounds(p, levelDs)
+	if err != nil {
+		return fmt.Errorf("fri: Verify: %w", err)
 	}
 
 	numLevels := len(levelDs)
@@ -707,27 +887,12 @@ func Verify(p Params, levelRoots []hash.Digest, levelDs []int, prf Proof, ts *fi
 	if prf.FinalField == field.KindExt && len(prf.FinalPolyExt) == 0 {
 		return fmt.Errorf("fri: Verify: ext final field with empty FinalPolyExt")
 	}
-
-	// levelAtRound: folding round j → level index l (1-based).
-	levelAtRound := make(map[int]int, numExtraLevels)
-	for l := 1; l < numLevels; l++ {
-		if levelDs[l] <= 0 || levelDs[l]&(levelDs[l]-1) != 0 {
-			return fmt.Errorf("fri: Verify: levelDs[%d]=%d is not a positive power of two", l, levelDs[l])
-		}
-		ratio := p.D / levelDs[l]
-		if ratio <= 0 || ratio*levelDs[l] != p.D || ratio&(ratio-1) != 0 {
-			return fmt.Errorf("fri: Verify: levelDs[%d]=%d does not divide p.D=%d by a power-of-two ratio", l, levelDs[l], p.D)
-		}
-		jl := log2(ratio)
-		if jl < 1 || jl >= p.numRounds {
-			return fmt.Errorf(
-				"fri: Verify: levelDs[%d]=%d gives intro round %d, must be in 1..%d",
-				l, levelDs[l], jl, p.numRounds-1)
-		}
-		if _, dup := levelAtRound[jl]; dup {
-			return fmt.Errorf("fri: Verify: two levels share intro round %d", jl)
-		}
-		levelAtRound[jl] = l
+	wantMaskQueries := 0
+	if p.zk {
+		wantMaskQueries = p.NumQueries
+	}
+	if len(prf.MaskQueries) != wantMaskQueries {
+		return fmt.Errorf("fri: Verify: proof has %d mask queries, want %d", len(prf.MaskQueries), wantMaskQueries)
 	}
 
 	if err := registerChallenges(p, numExtraLevels, ts); err != nil {
@@ -753,6 +918,36 @@ func Verify(p Params, levelRoots []hash.Digest, levelDs []int, prf Proof, ts *fi
 	return verifyBase(p, levelRoots, levelRootsExtra, levelAtRound, roots, prf, ts)
 }
 
+// levelIntroRounds checks the level sizes passed to Verify and maps each
+// folding round j introducing a level to the index l (1-based) of this level.
+func levelIntroRounds(p Params, levelDs []int) (map[int]int, error) {
+	if levelDs[0] != p.D {
+		return nil, fmt.Errorf("levelDs[0]=%d must equal p.D=%d", levelDs[0], p.D)
+	}
+
+	levelAtRound := make(map[int]int, len(levelDs)-1)
+	for l := 1; l < len(levelDs); l++ {
+		if levelDs[l] <= 0 || levelDs[l]&(levelDs[l]-1) != 0 {
+			return nil, fmt.Errorf("levelDs[%d]=%d is not a positive power of two", l, levelDs[l])
+		}
+		ratio := p.D / levelDs[l]
+		if ratio <= 0 || ratio*levelDs[l] != p.D || ratio&(ratio-1) != 0 {
+			return nil, fmt.Errorf("levelDs[%d]=%d does not divide p.D=%d by a power-of-two ratio", l, levelDs[l], p.D)
+		}
+		jl := log2(ratio)
+		if jl < 1 || jl >= p.numRounds {
+			return nil, fmt.Errorf(
+				"levelDs[%d]=%d gives intro round %d, must be in 1..%d",
+				l, levelDs[l], jl, p.numRounds-1)
+		}
+		if _, dup := levelAtRound[jl]; dup {
+			return nil, fmt.Errorf("two levels share intro round %d", jl)
+		}
+		levelAtRound[jl] = l
+	}
+	return levelAtRound, nil
+}
+
 func verifyBase(
 	p Params,
 	levelRoots, levelRootsExtra []hash.Digest,
@@ -765,6 +960,15 @@ func verifyBase(
 	numExtraLevels := numLevels - 1
 
 	// ── Replay commit phase ───────────────────────────────────────────────────
+	var beta koalabear.Element
+	if p.zk {
+		challenge, err := computeMaskChallenge(ts, levelRoots[0], prf.MaskRoot)
+		if err != nil {
+			return fmt.Errorf("fri: Verify: compute mask challenge: %w", err)
+		}
+		beta.Set(&challenge[0])
+	}
+
 	gammas := make([]koalabear.Element, numLevels)
 	if numExtraLevels > 0 {
 		for l := 0; l < numLevels; l++ {
@@ -825,8 +1029,17 @@ func verifyBase(
 			}
 		}
 
+		var mask *maskOpeningBase
+		if p.zk {
+			mq := prf.MaskQueries[k]
+			if err := verifyMaskOpening(p, prf.MaskRoot, mq, field.KindBase); err != nil {
+				return fmt.Errorf("fri: Verify: query %d failed: %w", k, err)
+			}
+			mask = &maskOpeningBase{p: mq.LeafPBase, q: mq.LeafQBase, beta: beta}
+		}
+
 		if err := checkQuery(s, prf.FRIQueries[k], levelQueriesForQuery, levelRootsExtra,
-			levelAtRound, gammas, roots, prf.FinalPolyBase, alphas, p); err != nil {
+			levelAtRound, gammas, roots, prf.FinalPolyBase, alphas, mask, p); err != nil {
 			return fmt.Errorf("fri: Verify: query %d failed: %w", k, err)
 		}
 	}
@@ -845,6 +1058,15 @@ func verifyExt(
 	numLevels := len(levelRoots)
 	numExtraLevels := numLevels - 1
 
+	var beta ext.E6
+	if p.zk {
+		challenge, err := computeMaskChallenge(ts, levelRoots[0], prf.MaskRoot)
+		if err != nil {
+			return fmt.Errorf("fri: Verify: compute mask challenge: %w", err)
+		}
+		beta = hash.OutputToExt(challenge)
+	}
+
 	gammas := make([]ext.E6, numLevels)
 	if numExtraLevels > 0 {
 		for l := 0; l < numLevels; l++ {
@@ -903,8 +1125,17 @@ func verifyExt(
 			}
 		}
 
+		var mask *maskOpeningExt
+		if p.zk {
+			mq := prf.MaskQueries[k]
+			if err := verifyMaskOpening(p, prf.MaskRoot, mq, field.KindExt); err != nil {
+				return fmt.Errorf("fri: Verify: query %d failed: %w", k, err)
+			}
+			mask = &maskOpeningExt{p: mq.LeafPExt, q: mq.LeafQExt, beta: beta}
+		}
+
 		if err := checkQueryExt(s, prf.FRIQueries[k], levelQueriesForQuery, levelRootsExtra,
-			levelAtRound, gammas, roots, prf.FinalPolyExt, alphas, p); err != nil {
+			levelAtRound, gammas, roots, prf.FinalPolyExt, alphas, mask, p); err != nil {
 			return fmt.Errorf("fri: Verify: query %d failed: %w", k, err)
 		}
 	}
@@ -914,6 +1145,7 @@ func verifyExt(
 
 // ── helpers ──────────────────────────────────────────────────────────────────
 
+func maskName() string       { return "fri_mask" }
 func gammaName() string      { return "fri_gamma" }
 func foldName(j int) string  { return fmt.Sprintf("fri_fold_%d", j) }
 func queryName(k int) string { return fmt.Sprintf("fri_query_%d", k) }
@@ -975,9 +1207,11 @@ func log2(n int) int {
 }
 
 // buildTreeBase builds a Merkle tree of Nⱼ/2 leaves where
-// leaf k = LeafHasher(layer[k] || layer[k + Nⱼ/2]).
+// leaf k = LeafHasher(layer[k] || layer[k + Nⱼ/2]), followed by the salt pairs
+// of leaf k when salt is not empty (see commitment.HashSaltedLeaf).
 func buildTreeBase(
 	layer []koalabear.Element,
+	salt []poly.Polynomial,
 	lh commitment.LeafHasher,
 	nh commitment.NodeHasher,
 ) (*merkle.Tree, error) {
@@ -988,14 +1222,14 @@ func buildTreeBase(
 	}
 	leaves := make([]hash.Digest, half)
 	commitment.HashLeavesParallel(lh, leaves, commitment.LeafSource{
-		Base:       []poly.Polynomial{layer},
+		Base:       append([]poly.Polynomial{layer}, salt...),
 		PairOffset: half,
 	})
 	return tree, tree.Build(leaves)
 }
 
 // buildTreeExt is the extension-field counterpart of buildTreeBase.
-func buildTreeExt(layer []ext.E6, lh commitment.LeafHasher, nh commitment.NodeHasher) (*merkle.Tree, error) {
+func buildTreeExt(layer []ext.E6, salt []poly.Polynomial, lh commitment.LeafHasher, nh commitment.NodeHasher) (*merkle.Tree, error) {
 	half := len(layer) / 2
 	tree, err := merkle.New(half, nh)
 	if err != nil {
@@ -1003,6 +1237,7 @@ func buildTreeExt(layer []ext.E6, lh commitment.LeafHasher, nh commitment.NodeHa
 	}
 	leaves := make([]hash.Digest, half)
 	commitment.HashLeavesParallel(lh, leaves, commitment.LeafSource{
+		Base:       salt,
 		Ext:        []poly.ExtPolynomial{layer},
 		PairOffset: half,
 	})
@@ -1071,14 +1306,14 @@ func foldLayerExt(layer []ext.E6, alpha ext.E6, domain *fft.Domain, invTwo koala
 
 func transcriptBasePoly(poly []koalabear.Element) []koalabear.Element {
 	res := make([]koalabear.Element, 0, 2+len(poly))
-	res = append(res, hash.NewElement(0x42415345), hash.NewElement(uint64(len(poly)))) // "BASE"
+	res = append(res, hash.NewElement(finalPolyBaseTag), hash.NewElement(uint64(len(poly))))
 	res = append(res, poly...)
 	return res
 }
 
 func transcriptExtPoly(poly []ext.E6) []koalabear.Element {
 	res := make([]koalabear.Element, 0, 2+hash.ExtDegree*len(poly))
-	res = append(res, hash.NewElement(0x45585450), hash.NewElement(uint64(len(poly)))) // "EXTP"
+	res = append(res, hash.NewElement(finalPolyExtTag), hash.NewElement(uint64(len(poly))))
 	for _, v := range poly {
 		res = hash.AppendExtElements(res, v)
 	}
@@ -1147,6 +1382,8 @@ func openQueryExt(s int, layers [][]ext.E6, trees []*merkle.Tree, numRounds int)
 // levelQueriesForQuery[l-1] holds the opening for levels[l] (l 0-based index offset by 1).
 // levelRoots[l-1] is the Merkle root of levels[l].Evals (l 0-based offset by 1).
 // gammas[l] is the batching challenge for levels[l] (1-based; gammas[0] unused).
+// mask is the already authenticated mask opening in zero-knowledge mode, nil
+// otherwise.
 func checkQuery(s int, fq Query,
 	levelQueriesForQuery []QueryLayer,
 	levelRoots []hash.Digest,
@@ -1155,6 +1392,7 @@ func checkQuery(s int, fq Query,
 	roots []hash.Digest,
 	finalPoly []koalabear.Element,
 	alphas []koalabear.Element,
+	mask *maskOpeningBase,
 	p Params) error {
 
 	// Verify Merkle proofs for all level polynomial openings.
@@ -1163,7 +1401,7 @@ func checkQuery(s int, fq Query,
 			return fmt.Errorf("level %d: expected base query layer, got %s", lIdx+1, ld.Field)
 		}
 		pair := []commitment.PairBase{{ld.LeafPBase, ld.LeafQBase}}
-		leaf := p.LeafHasher.HashLeaf(pair, nil)
+		leaf := commitment.HashSaltedLeaf(p.LeafHasher, pair, ld.Salt, nil)
 		if !merkle.Verify(levelRoots[lIdx], ld.Path, leaf, p.NodeHasher) {
 			return fmt.Errorf("level %d: Merkle proof invalid", lIdx+1)
 		}
@@ -1179,17 +1417,27 @@ func checkQuery(s int, fq Query,
 		}
 
 		pair := []commitment.PairBase{{layer.LeafPBase, layer.LeafQBase}}
-		leaf := p.LeafHasher.HashLeaf(pair, nil)
+		leaf := commitment.HashSaltedLeaf(p.LeafHasher, pair, layer.Salt, nil)
 		if !merkle.Verify(roots[j], layer.Path, leaf, p.NodeHasher) {
 			return fmt.Errorf("round %d: Merkle proof invalid (base=%d)", j, base)
 		}
 
+		// In zero-knowledge mode, round 0 folds F + β·R.
+		leafP, leafQ := layer.LeafPBase, layer.LeafQBase
+		if j == 0 && mask != nil {
+			var term koalabear.Element
+			term.Mul(&mask.p, &mask.beta)
+			leafP.Add(&leafP, &term)
+			term.Mul(&mask.q, &mask.beta)
+			leafQ.Add(&leafQ, &term)
+		}
+
 		// Fold: expected = (LeafP+LeafQ)/2 + α*(LeafP-LeafQ)/(2·ωⱼ^base).
 		var xInv, sum, diff, expected koalabear.Element
 		xInv.Exp(p.domainsLight[j].generator, big.NewInt(int64(Nj-base)))
-		sum.Add(&layer.LeafPBase, &layer.LeafQBase)
+		sum.Add(&leafP, &leafQ)
 		sum.Mul(&sum, &p.invTwo)
-		diff.Sub(&layer.LeafPBase, &layer.LeafQBase)
+		diff.Sub(&leafP, &leafQ)
 		diff.Mul(&diff, &p.invTwo)
 		diff.Mul(&diff, &xInv)
 		diff.Mul(&diff, &alphas[j])
@@ -1246,6 +1494,7 @@ func checkQueryExt(s int, fq Query,
 	roots []hash.Digest,
 	finalPoly []ext.E6,
 	alphas []ext.E6,
+	mask *maskOpeningExt,
 	p Params) error {
 
 	for lIdx, ld := range levelQueriesForQuery {
@@ -1253,7 +1502,7 @@ func checkQueryExt(s int, fq Query,
 			return fmt.Errorf("level %d: expected ext query layer, got %s", lIdx+1, ld.Field)
 		}
 		pair := []commitment.PairExt{{ld.LeafPExt, ld.LeafQExt}}
-		leaf := p.LeafHasher.HashLeaf(nil, pair)
+		leaf := commitment.HashSaltedLeaf(p.LeafHasher, nil, ld.Salt, pair)
 		if !merkle.Verify(levelRoots[lIdx], ld.Path, leaf, p.NodeHasher) {
 			return fmt.Errorf("level %d: Merkle proof invalid", lIdx+1)
 		}
@@ -1268,18 +1517,27 @@ func checkQueryExt(s int, fq Query,
 		}
 
 		pair := []commitment.PairExt{{layer.LeafPExt, layer.LeafQExt}}
-		leaf := p.LeafHasher.HashLeaf(nil, pair)
+		leaf := commitment.HashSaltedLeaf(p.LeafHasher, nil, layer.Salt, pair)
 		if !merkle.Verify(roots[j], layer.Path, leaf, p.NodeHasher) {
 			return fmt.Errorf("round %d: Merkle proof invalid (base=%d)", j, base)
 		}
 
+		leafP, leafQ := layer.LeafPExt, layer.LeafQExt
+		if j == 0 && mask != nil {
+			var term ext.E6
+			term.Mul(&mask.p, &mask.beta)
+			leafP.Add(&leafP, &term)
+			term.Mul(&mask.q, &mask.beta)
+			leafQ.Add(&leafQ, &term)
+		}
+
 		var xInv koalabear.Element
 		xInv.Exp(p.domainsLight[j].generator, big.NewInt(int64(Nj-base)))
 
 		var sum, diff, expected ext.E6
-		sum.Add(&layer.LeafPExt, &layer.LeafQExt)
+		sum.Add(&leafP, &leafQ)
 		sum.MulByElement(&sum, &p.invTwo)
-		diff.Sub(&layer.LeafPExt, &layer.LeafQExt)
+		diff.Sub(&leafP, &leafQ)
 		diff.MulByElement(&diff, &p.invTwo)
 		diff.MulByElement(&diff, &xInv)
 		diff.Mul(&diff, &alphas[j])
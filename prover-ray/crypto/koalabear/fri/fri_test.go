@@ -243,3 +243,183 @@ func TestVerifyRejectsFlippedExtLeaf(t *testing.T) {
 		t.Fatal("Verify accepted a proof with a corrupted ext leaf")
 	}
 }
+
+// TestProveVerifyZeroKnowledge checks that masked proofs verify on both rails
+// and with an extra level, and that two proofs of the same polynomial differ.
+func TestProveVerifyZeroKnowledge(t *testing.T) {
+	newParams := func(t *testing.T, N, D int) fri.Params {
+		t.Helper()
+		p, err := fri.NewParams(N, D, 4, commitment.DefaultLeafHasher, commitment.DefaultNodeHasher, fri.WithZeroKnowledge())
+		if err != nil {
+			t.Fatalf("NewParams: %v", err)
+		}
+		return p
+	}
+
+	t.Run("base", func(t *testing.T) {
+		p := newParams(t, 64, 8)
+		evals, _ := p.Encode(randomPoly(p.D))
+		tree := buildLevelTree(t, p, evals)
+
+		var finals [2][]koalabear.Element
+		for run := range finals {
+			prf, _, err := fri.Prove(p, []fri.Level{{D: p.D, Evals: fri.LevelEvals{Base: evals}, Tree: tree}}, freshTS())
+			if err != nil {
+				t.Fatalf("Prove: %v", err)
+			}
+			if err := fri.Verify(p, []hash.Digest{tree.Root()}, []int{p.D}, prf, freshTS()); err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			finals[run] = prf.FinalPolyBase
+		}
+		if finals[0][0].Equal(&finals[1][0]) {
+			t.Fatal("two masked proofs of the same polynomial share their final polynomial")
+		}
+	})
+
+	t.Run("ext with extra level", func(t *testing.T) {
+		p := newParams(t, 64, 16)
+		pSmall := testParams(t, 16, 4, 4)
+		evals0, _ := p.EncodeExt(randomExtPoly(p.D))
+		evals1, _ := pSmall.EncodeExt(randomExtPoly(pSmall.D))
+		tree0 := buildLevelTreeExt(t, p, evals0)
+		tree1 := buildLevelTreeExt(t, p, evals1)
+
+		prf, _, err := fri.Prove(p, []fri.Level{
+			{D: p.D, Evals: fri.LevelEvals{Ext: evals0}, Tree: tree0},
+			{D: pSmall.D, Evals: fri.LevelEvals{Ext: evals1}, Tree: tree1},
+		}, freshTS())
+		if err != nil {
+			t.Fatalf("Prove: %v", err)
+		}
+		roots := []hash.Digest{tree0.Root(), tree1.Root()}
+		if err := fri.Verify(p, roots, []int{p.D, pSmall.D}, prf, freshTS()); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+
+		prf.MaskQueries[0].LeafPExt.MustSetRandom()
+		if err := fri.Verify(p, roots, []int{p.D, pSmall.D}, prf, freshTS()); err == nil {
+			t.Fatal("Verify accepted a proof with a corrupted mask opening")
+		}
+	})
+}
+
+// TestVerifyRejectsZeroKnowledgeMismatch ensures masked and unmasked proofs
+// are only accepted by parameters built with the matching option.
+func TestVerifyRejectsZeroKnowledgeMismatch(t *testing.T) {
+	plain := testParams(t, 64, 4, 4)
+	zk, err := fri.NewParams(64, 4, 4, commitment.DefaultLeafHasher, commitment.DefaultNodeHasher, fri.WithZeroKnowledge())
+	if err != nil {
+		t.Fatalf("NewParams: %v", err)
+	}
+	evals, _ := plain.Encode(randomPoly(plain.D))
+	tree := buildLevelTree(t, plain, evals)
+	levels := func() []fri.Level {
+		return []fri.Level{{D: plain.D, Evals: fri.LevelEvals{Base: evals}, Tree: tree}}
+	}
+
+	masked, _, err := fri.Prove(zk, levels(), freshTS())
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	if err := fri.Verify(plain, []hash.Digest{tree.Root()}, []int{plain.D}, masked, freshTS()); err == nil {
+		t.Fatal("non-ZK parameters accepted a masked proof")
+	}
+
+	unmasked, _, err := fri.Prove(plain, levels(), freshTS())
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	if err := fri.Verify(zk, []hash.Digest{tree.Root()}, []int{zk.D}, unmasked, freshTS()); err == nil {
+		t.Fatal("ZK parameters accepted an unmasked proof")
+	}
+}
+
+// TestProveVerifyZeroKnowledgeSalted runs masked proofs over salted level
+// trees: the queries open the salt of the queried leaves, a tampered salt is
+// rejected and a salted tree does not share the root of the unsalted one.
+func TestProveVerifyZeroKnowledgeSalted(t *testing.T) {
+	const saltSize = 2
+
+	p, err := fri.NewParams(64, 16, 4, commitment.DefaultLeafHasher, commitment.DefaultNodeHasher, fri.WithZeroKnowledge())
+	if err != nil {
+		t.Fatalf("NewParams: %v", err)
+	}
+
+	t.Run("base", func(t *testing.T) {
+		evals, _ := p.Encode(randomPoly(p.D))
+		tree, salt, err := p.BuildSaltedLevelTree(evals, saltSize)
+		if err != nil {
+			t.Fatalf("BuildSaltedLevelTree: %v", err)
+		}
+		if tree.Root() == buildLevelTree(t, p, evals).Root() {
+			t.Fatal("the salted tree has the root of the unsalted one")
+		}
+
+		prf, _, err := fri.Prove(p, []fri.Level{{D: p.D, Evals: fri.LevelEvals{Base: evals}, Tree: tree, Salt: salt}}, freshTS())
+		if err != nil {
+			t.Fatalf("Prove: %v", err)
+		}
+		roots := []hash.Digest{tree.Root()}
+		if err := fri.Verify(p, roots, []int{p.D}, prf, freshTS()); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+		for k, q := range prf.FRIQueries {
+			if len(q.Layers[0].Salt) != saltSize {
+				t.Fatalf("query %d opens %d salt pairs, want %d", k, len(q.Layers[0].Salt), saltSize)
+			}
+		}
+
+		prf.FRIQueries[0].Layers[0].Salt[1][0].SetOne()
+		if err := fri.Verify(p, roots, []int{p.D}, prf, freshTS()); err == nil {
+			t.Fatal("Verify accepted a proof with a tampered salt")
+		}
+		prf.FRIQueries[0].Layers[0].Salt = nil
+		if err := fri.Verify(p, roots, []int{p.D}, prf, freshTS()); err == nil {
+			t.Fatal("Verify accepted a proof without the salt of a salted tree")
+		}
+	})
+
+	t.Run("ext with salted extra level", func(t *testing.T) {
+		pSmall := testParams(t, 16, 4, 4)
+		evals0, _ := p.EncodeExt(randomExtPoly(p.D))
+		evals1, _ := pSmall.EncodeExt(randomExtPoly(pSmall.D))
+		tree0, salt0, err := p.BuildSaltedLevelTreeExt(evals0, saltSize)
+		if err != nil {
+			t.Fatalf("BuildSaltedLevelTreeExt: %v", err)
+		}
+		tree1, salt1, err := p.BuildSaltedLevelTreeExt(evals1, saltSize)
+		if err != nil {
+			t.Fatalf("BuildSaltedLevelTreeExt: %v", err)
+		}
+
+		prf, _, err := fri.Prove(p, []fri.Level{
+			{D: p.D, Evals: fri.LevelEvals{Ext: evals0}, Tree: tree0, Salt: salt0},
+			{D: pSmall.D, Evals: fri.LevelEvals{Ext: evals1}, Tree: tree1, Salt: salt1},
+		}, freshTS())
+		if err != nil {
+			t.Fatalf("Prove: %v", err)
+		}
+		roots := []hash.Digest{tree0.Root(), tree1.Root()}
+		if err := fri.Verify(p, roots, []int{p.D, pSmall.D}, prf, freshTS()); err != nil {
+			t.Fatalf("Verify: %v", err)
+		}
+
+		prf.LevelQueries[0][0].Salt[0][1].SetOne()
+		if err := fri.Verify(p, roots, []int{p.D, pSmall.D}, prf, freshTS()); err == nil {
+			t.Fatal("Verify accepted a proof with a tampered extra-level salt")
+		}
+	})
+
+	t.Run("salt length", func(t *testing.T) {
+		evals, _ := p.Encode(randomPoly(p.D))
+		tree, salt, err := p.BuildSaltedLevelTree(evals, saltSize)
+		if err != nil {
+			t.Fatalf("BuildSaltedLevelTree: %v", err)
+		}
+		salt[0] = salt[0][:len(salt[0])/2]
+		if _, _, err := fri.Prove(p, []fri.Level{{D: p.D, Evals: fri.LevelEvals{Base: evals}, Tree: tree, Salt: salt}}, freshTS()); err == nil {
+			t.Fatal("Prove accepted a salt shorter than the evaluations")
+		}
+	})
+}
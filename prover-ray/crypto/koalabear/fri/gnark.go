@@ -0,0 +1,578 @@
+package fri
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/koalabear"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/selector"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/commitment"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/fiatshamirrefactor"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/poseidon2"
+	smtkoalabear "github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/smt"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/circuit"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// queryChallengeBits is the number of bits of the challenge limb from which
+// the query index is sampled.
+const queryChallengeBits = 31
+
+// GnarkQueryLayer mirrors [QueryLayer] in a gnark circuit whose native field
+// is KoalaBear. LeafP and LeafQ hold one coordinate on the base rail and
+// hash.ExtDegree coordinates on the extension rail. The leaf index of the
+// Merkle path is not part of the witness: the circuit derives it from the
+// query challenge.
+type GnarkQueryLayer struct {
+	LeafP, LeafQ []frontend.Variable
+	Siblings     []poseidon2.GnarkOctuplet
+}
+
+// GnarkProof mirrors [Proof] in a gnark circuit. Its shape is fixed by
+// [AllocateGnarkProof] and its values are set by [AssignGnarkProof].
+type GnarkProof struct {
+	// LevelQueries[l-1][k] = opening for levels[l] at query k.
+	LevelQueries [][]GnarkQueryLayer
+
+	FRIRoots   []poseidon2.GnarkOctuplet
+	FinalField field.Kind            `gnark:"-"`
+	FinalPoly  [][]frontend.Variable // FinalPoly[i] holds the coordinates of the i-th value
+	FRIQueries [][]GnarkQueryLayer   // FRIQueries[k][j] = opening of round j at query k
+	PoWSalts   []frontend.Variable   // PoWSalts[j] = salt of the fold challenge j, only with grinding
+}
+
+// AllocateGnarkProof returns a [GnarkProof] with the shape of the proofs
+// produced by [Prove] for the given level sizes and final field.
+func AllocateGnarkProof(p Params, levelDs []int, finalField field.Kind) (GnarkProof, error) {
+	if len(levelDs) == 0 {
+		return GnarkProof{}, fmt.Errorf("fri: AllocateGnarkProof: at least one level required")
+	}
+	if p.zk {
+		return GnarkProof{}, fmt.Errorf("fri: AllocateGnarkProof: zero-knowledge proofs are not supported in-circuit")
+	}
+	levelAtRound, err := levelIntroRounds(p, levelDs)
+	if err != nil {
+		return GnarkProof{}, fmt.Errorf("fri: AllocateGnarkProof: %w", err)
+	}
+	deg, err := gnarkDegree(finalField)
+	if err != nil {
+		return GnarkProof{}, fmt.Errorf("fri: AllocateGnarkProof: %w", err)
+	}
+
+	allocLayer := func(j int) GnarkQueryLayer {
+		return GnarkQueryLayer{
+			LeafP:    make([]frontend.Variable, deg),
+			LeafQ:    make([]frontend.Variable, deg),
+			Siblings: make([]poseidon2.GnarkOctuplet, p.pathDepth(j)),
+		}
+	}
+
+	res := GnarkProof{
+		LevelQueries: make([][]GnarkQueryLayer, len(levelDs)-1),
+		FRIRoots:     make([]poseidon2.GnarkOctuplet, max(p.numRounds-1, 0)),
+		FinalField:   finalField,
+		FinalPoly:    make([][]frontend.Variable, p.N>>p.numRounds),
+		FRIQueries:   make([][]GnarkQueryLayer, p.NumQueries),
+	}
+	for jl, l := range levelAtRound {
+		res.LevelQueries[l-1] = make([]GnarkQueryLayer, p.NumQueries)
+		for k := range res.LevelQueries[l-1] {
+			res.LevelQueries[l-1][k] = allocLayer(jl)
+		}
+	}
+	for i := range res.FinalPoly {
+		res.FinalPoly[i] = make([]frontend.Variable, deg)
+	}
+	for k := range res.FRIQueries {
+		res.FRIQueries[k] = make([]GnarkQueryLayer, p.numRounds)
+		for j := range res.FRIQueries[k] {
+			res.FRIQueries[k][j] = allocLayer(j)
+		}
+	}
+	if p.grinding > 0 {
+		res.PoWSalts = make([]frontend.Variable, p.numRounds)
+	}
+	return res, nil
+}
+
+// AssignGnarkProof returns the assignment of a [GnarkProof] allocated with
+// [AllocateGnarkProof] for prf.
+func AssignGnarkProof(p Params, prf Proof) (GnarkProof, error) {
+	if p.zk || len(prf.MaskQueries) > 0 {
+		return GnarkProof{}, fmt.Errorf("fri: AssignGnarkProof: zero-knowledge proofs are not supported in-circuit")
+	}
+	if isSalted(prf) {
+		return GnarkProof{}, fmt.Errorf("fri: AssignGnarkProof: salted level trees are not supported in-circuit")
+	}
+
+	res := GnarkProof{
+		LevelQueries: make([][]GnarkQueryLayer, len(prf.LevelQueries)),
+		FRIRoots:     make([]poseidon2.GnarkOctuplet, len(prf.FRIRoots)),
+		FinalField:   prf.FinalField,
+		FRIQueries:   make([][]GnarkQueryLayer, len(prf.FRIQueries)),
+	}
+
+	for l, qs := range prf.LevelQueries {
+		res.LevelQueries[l] = make([]GnarkQueryLayer, len(qs))
+		for k := range qs {
+			res.LevelQueries[l][k] = assignGnarkQueryLayer(qs[k])
+		}
+	}
+	for j := range prf.FRIRoots {
+		res.FRIRoots[j] = assignGnarkDigest(prf.FRIRoots[j])
+	}
+	if prf.FinalField == field.KindExt {
+		res.FinalPoly = make([][]frontend.Variable, len(prf.FinalPolyExt))
+		for i := range prf.FinalPolyExt {
+			res.FinalPoly[i] = assignGnarkElements(hash.ExtToElements(prf.FinalPolyExt[i])...)
+		}
+	} else {
+		res.FinalPoly = make([][]frontend.Variable, len(prf.FinalPolyBase))
+		for i := range prf.FinalPolyBase {
+			res.FinalPoly[i] = assignGnarkElements(prf.FinalPolyBase[i])
+		}
+	}
+	for k, q := range prf.FRIQueries {
+		res.FRIQueries[k] = make([]GnarkQueryLayer, len(q.Layers))
+		for j := range q.Layers {
+			res.FRIQueries[k][j] = assignGnarkQueryLayer(q.Layers[j])
+		}
+	}
+	if p.grinding > 0 {
+		res.PoWSalts = make([]frontend.Variable, p.numRounds)
+		for j := range res.PoWSalts {
+			pow, ok := prf.PoW[foldName(j)]
+			if !ok {
+				return GnarkProof{}, fmt.Errorf("fri: AssignGnarkProof: missing proof of work for %s", foldName(j))
+			}
+			res.PoWSalts[j] = pow.Salt.String()
+		}
+	}
+	return res, nil
+}
+
+// AssignGnarkDigests returns the assignment of the level roots passed to
+// [GnarkVerify].
+func AssignGnarkDigests(digests []hash.Digest) []poseidon2.GnarkOctuplet {
+	res := make([]poseidon2.GnarkOctuplet, len(digests))
+	for i := range digests {
+		res[i] = assignGnarkDigest(digests[i])
+	}
+	return res
+}
+
+// GnarkVerify mirrors [Verify] in a gnark circuit whose native field is
+// KoalaBear: it replays the transcript with ts, which must be in the same
+// state as the native transcript passed to Prove, and asserts the validity of
+// prf. The Merkle openings are checked with the smt gadget, which requires the
+// leaf and node hashers of p to implement [commitment.GnarkLeafHasher] and
+// [commitment.GnarkNodeHasher].
+//
+// The returned errors are raised while defining the circuit and report a
+// mismatch between p, levelDs and the shape of prf.
+func GnarkVerify(
+	api frontend.API,
+	p Params,
+	levelRoots []poseidon2.GnarkOctuplet,
+	levelDs []int,
+	prf GnarkProof,
+	ts *fiatshamirrefactor.GnarkTranscript,
+) error {
+	if len(levelDs) == 0 {
+		return fmt.Errorf("fri: GnarkVerify: at least one level required")
+	}
+	if p.zk {
+		return fmt.Errorf("fri: GnarkVerify: zero-knowledge proofs are not supported in-circuit")
+	}
+	if len(levelRoots) != len(levelDs) {
+		return fmt.Errorf("fri: GnarkVerify: levelRoots has %d entries, levelDs has %d", len(levelRoots), len(levelDs))
+	}
+	levelAtRound, err := levelIntroRounds(p, levelDs)
+	if err != nil {
+		return fmt.Errorf("fri: GnarkVerify: %w", err)
+	}
+	if err := checkGnarkProofShape(p, levelDs, prf); err != nil {
+		return fmt.Errorf("fri: GnarkVerify: %w", err)
+	}
+
+	lh, ok := p.LeafHasher.(commitment.GnarkLeafHasher)
+	if !ok {
+		return fmt.Errorf("fri: GnarkVerify: the leaf hasher %T has no gnark counterpart", p.LeafHasher)
+	}
+	nh, ok := p.NodeHasher.(commitment.GnarkNodeHasher)
+	if !ok {
+		return fmt.Errorf("fri: GnarkVerify: the node hasher %T has no gnark counterpart", p.NodeHasher)
+	}
+
+	numLevels := len(levelDs)
+	numExtraLevels := numLevels - 1
+
+	if err := registerChallenges(p, numExtraLevels, ts); err != nil {
+		return err
+	}
+
+	v := gnarkVerifier{
+		api:          api,
+		f:            circuit.NewAPI(api),
+		p:            p,
+		lh:           lh,
+		nh:           nh,
+		levelAtRound: levelAtRound,
+		levelRounds:  make([]int, numExtraLevels),
+		isExt:        prf.FinalField == field.KindExt,
+	}
+	for jl, l := range levelAtRound {
+		v.levelRounds[l-1] = jl
+	}
+
+	// ── Replay commit phase ───────────────────────────────────────────────────
+	gammas := make([]circuit.Ext, numLevels)
+	if numExtraLevels > 0 {
+		for l := 0; l < numLevels; l++ {
+			if err := ts.Bind(gammaName(), levelRoots[l][:]); err != nil {
+				return fmt.Errorf("fri: GnarkVerify: bind level l=%d: %w", l, err)
+			}
+		}
+		challenge, err := ts.ComputeChallenge(gammaName())
+		if err != nil {
+			return fmt.Errorf("fri: GnarkVerify: compute gamma: %w", err)
+		}
+		gammas[1] = v.challengeToField(challenge)
+		for l := 2; l < numLevels; l++ {
+			gammas[l] = v.f.MulExt(gammas[l-1], gammas[1])
+		}
+	}
+
+	// roots[0] is the level-0 root; roots[1..r-1] come from prf.FRIRoots.
+	roots := make([]poseidon2.GnarkOctuplet, p.numRounds)
+	roots[0] = levelRoots[0]
+	copy(roots[1:], prf.FRIRoots)
+
+	alphas := make([]circuit.Ext, p.numRounds)
+	for j := 0; j < p.numRounds; j++ {
+		name := foldName(j)
+		if err := ts.Bind(name, roots[j][:]); err != nil {
+			return fmt.Errorf("fri: GnarkVerify: bind fold %d: %w", j, err)
+		}
+		var challenge poseidon2.GnarkOctuplet
+		if p.grinding > 0 {
+			challenge, err = ts.ComputeChallengeWithProofOfWork(name, p.grinding, prf.PoWSalts[j])
+		} else {
+			challenge, err = ts.ComputeChallenge(name)
+		}
+		if err != nil {
+			return fmt.Errorf("fri: GnarkVerify: compute fold challenge %d: %w", j, err)
+		}
+		alphas[j] = v.challengeToField(challenge)
+	}
+
+	finalPolyTag := finalPolyBaseTag
+	if v.isExt {
+		finalPolyTag = finalPolyExtTag
+	}
+	finalPoly := make([]circuit.Ext, len(prf.FinalPoly))
+	bindings := []frontend.Variable{finalPolyTag, len(prf.FinalPoly)}
+	for i := range prf.FinalPoly {
+		finalPoly[i] = v.toExt(prf.FinalPoly[i])
+		bindings = append(bindings, prf.FinalPoly[i]...)
+	}
+	if err := ts.Bind(queryName(0), bindings); err != nil {
+		return fmt.Errorf("fri: GnarkVerify: bind final poly: %w", err)
+	}
+
+	// ── Query phase ───────────────────────────────────────────────────────────
+	levelRootsExtra := levelRoots[1:]
+	for k := 0; k < p.NumQueries; k++ {
+		challenge, err := ts.ComputeChallenge(queryName(k))
+		if err != nil {
+			return fmt.Errorf("fri: GnarkVerify: compute query challenge %d: %w", k, err)
+		}
+
+		if k < p.NumQueries-1 {
+			if err := ts.Bind(queryName(k+1), challenge[:]); err != nil {
+				return fmt.Errorf("fri: GnarkVerify: bind query chain %d: %w", k+1, err)
+			}
+		}
+
+		// The native query index is ((c[0] << 31) ^ c[1]) % (N/2). N/2 is a
+		// power of two smaller than 2^31, so its bits are the low bits of c[1].
+		sBits := api.ToBinary(challenge[1], queryChallengeBits)[:log2(p.N/2)]
+
+		levelQueriesForQuery := make([]GnarkQueryLayer, numExtraLevels)
+		for l := range levelQueriesForQuery {
+			levelQueriesForQuery[l] = prf.LevelQueries[l][k]
+		}
+
+		v.checkQuery(sBits, prf.FRIQueries[k], levelQueriesForQuery, levelRootsExtra, gammas, roots, finalPoly, alphas)
+	}
+
+	return nil
+}
+
+// gnarkVerifier holds the data shared by the query checks of GnarkVerify.
+type gnarkVerifier struct {
+	api          frontend.API
+	f            *circuit.API
+	p            Params
+	lh           commitment.GnarkLeafHasher
+	nh           commitment.GnarkNodeHasher
+	levelAtRound map[int]int
+	levelRounds  []int // levelRounds[l-1] = intro round of levels[l]
+	isExt        bool
+}
+
+// checkQuery is the in-circuit counterpart of checkQuery and checkQueryExt,
+// sBits are the bits of the query index s.
+func (v *gnarkVerifier) checkQuery(
+	sBits []frontend.Variable,
+	fq []GnarkQueryLayer,
+	levelQueriesForQuery []GnarkQueryLayer,
+	levelRoots []poseidon2.GnarkOctuplet,
+	gammas []circuit.Ext,
+	roots []poseidon2.GnarkOctuplet,
+	finalPoly []circuit.Ext,
+	alphas []circuit.Ext,
+) {
+	p := v.p
+
+	// Verify Merkle proofs for all level polynomial openings.
+	for lIdx, ld := range levelQueriesForQuery {
+		v.verifyOpening(levelRoots[lIdx], sBits, ld, v.levelRounds[lIdx])
+	}
+
+	// Verify running-polynomial fold path with batching consistency checks.
+	invTwo := new(big.Int)
+	p.invTwo.BigInt(invTwo)
+	for j := 0; j < p.numRounds; j++ {
+		layer := fq[j]
+		v.verifyOpening(roots[j], sBits, layer, j)
+
+		// Fold: expected = (LeafP+LeafQ)/2 + α*(LeafP-LeafQ)/(2·ωⱼ^base).
+		leafP, leafQ := v.toExt(layer.LeafP), v.toExt(layer.LeafQ)
+		sum := v.f.MulConstExt(v.f.AddExt(leafP, leafQ), invTwo)
+		diff := v.f.MulConstExt(v.f.SubExt(leafP, leafQ), invTwo)
+		diff = v.f.MulByFpExt(diff, v.xInv(sBits, j))
+		diff = v.f.MulExt(diff, alphas[j])
+		expected := v.f.AddExt(sum, diff)
+
+		if j < p.numRounds-1 {
+			// base < Nⱼ₊₁/2 iff the top bit of base is zero
+			isLeafQ := sBits[p.pathDepth(j)-1]
+			nextLayer := fq[j+1]
+
+			if li, ok := v.levelAtRound[j+1]; ok {
+				ld := levelQueriesForQuery[li-1]
+				leafVal := v.f.SelectExt(isLeafQ, v.toExt(ld.LeafQ), v.toExt(ld.LeafP))
+				expected = v.f.AddExt(expected, v.f.MulExt(leafVal, gammas[li]))
+			}
+
+			next := v.f.SelectExt(isLeafQ, v.toExt(nextLayer.LeafQ), v.toExt(nextLayer.LeafP))
+			v.f.AssertIsEqualExt(expected, next)
+		} else {
+			v.f.AssertIsEqualExt(expected, v.finalValue(sBits, finalPoly))
+		}
+	}
+}
+
+// verifyOpening asserts that the leaf of layer is at index s % (Nⱼ/2) in the
+// Merkle tree of round j with the given root.
+func (v *gnarkVerifier) verifyOpening(root poseidon2.GnarkOctuplet, sBits []frontend.Variable, layer GnarkQueryLayer, j int) {
+	leaf := v.lh.GnarkHashPair(v.api, layer.LeafP, layer.LeafQ)
+	proof := smtkoalabear.GnarkProof{
+		Path:     v.api.FromBinary(sBits[:v.p.pathDepth(j)]...),
+		Siblings: layer.Siblings,
+	}
+	// can't error, the node hasher is infallible
+	_ = smtkoalabear.GnarkVerifyMerkleProofWithHasher(v.api, proof, leaf, root, v.nh.GnarkHashNode)
+}
+
+// xInv returns ωⱼ^(-base) where base = s % (Nⱼ/2), as the product of the
+// constants ωⱼ^(-2^i) selected by the bits of base.
+func (v *gnarkVerifier) xInv(sBits []frontend.Variable, j int) circuit.Element {
+	var pow koalabear.Element
+	pow.Inverse(&v.p.domainsLight[j].generator)
+
+	res := frontend.Variable(1)
+	for i := 0; i < v.p.pathDepth(j); i++ {
+		res = v.api.Mul(res, v.api.Select(sBits[i], pow.Uint64(), 1))
+		pow.Square(&pow)
+	}
+	return v.f.FromFrontendVar(res)
+}
+
+// finalValue returns finalPoly[s % len(finalPoly)].
+func (v *gnarkVerifier) finalValue(sBits []frontend.Variable, finalPoly []circuit.Ext) circuit.Ext {
+	if len(finalPoly) == 1 {
+		return finalPoly[0]
+	}
+
+	sel := v.api.FromBinary(sBits[:log2(len(finalPoly))]...)
+	coords := make([][]frontend.Variable, hash.ExtDegree)
+	for i := range finalPoly {
+		b0a0, b0a1, b1a0, b1a1, b2a0, b2a1 := finalPoly[i].Coordinates()
+		for c, e := range []circuit.Element{b0a0, b0a1, b1a0, b1a1, b2a0, b2a1} {
+			coords[c] = append(coords[c], e.Native())
+		}
+	}
+	var res [hash.ExtDegree]frontend.Variable
+	for c := range res {
+		res[c] = selector.Mux(v.api, sel, coords[c]...)
+	}
+	return circuit.NewExtFrom6FrontendVars(res[0], res[1], res[2], res[3], res[4], res[5])
+}
+
+// challengeToField maps a challenge to the field of the proof, as the native
+// verifier does with challenge[0] or hash.OutputToExt.
+func (v *gnarkVerifier) challengeToField(challenge poseidon2.GnarkOctuplet) circuit.Ext {
+	if v.isExt {
+		return circuit.NewExtFrom6FrontendVars(challenge[0], challenge[1], challenge[2], challenge[3], challenge[4], challenge[5])
+	}
+	return circuit.NewExtFromFrontendVar(challenge[0])
+}
+
+// toExt embeds the coordinates of a value of the proof in the extension
+// field; the zero coordinates of the base values are constants, so the
+// extension arithmetic on them is almost free.
+func (v *gnarkVerifier) toExt(coords []frontend.Variable) circuit.Ext {
+	if len(coords) == 1 {
+		return circuit.NewExtFromFrontendVar(coords[0])
+	}
+	return circuit.NewExtFrom6FrontendVars(coords[0], coords[1], coords[2], coords[3], coords[4], coords[5])
+}
+
+// pathDepth returns the depth log₂(Nⱼ/2) of the Merkle trees of round j.
+func (p Params) pathDepth(j int) int {
+	return log2(p.N) - j - 1
+}
+
+// checkGnarkProofShape checks that prf has the shape allocated by
+// AllocateGnarkProof, which GnarkVerify relies on.
+func checkGnarkProofShape(p Params, levelDs []int, prf GnarkProof) error {
+
+	deg, err := gnarkDegree(prf.FinalField)
+	if err != nil {
+		return err
+	}
+	if log2(p.N/2) > queryChallengeBits {
+		return fmt.Errorf("N=%d is too large for the query indices", p.N)
+	}
+
+	checkLayer := func(layer GnarkQueryLayer, j int) error {
+		if len(layer.LeafP) != deg || len(layer.LeafQ) != deg {
+			return fmt.Errorf("leaves have %d and %d coordinates, want %d", len(layer.LeafP), len(layer.LeafQ), deg)
+		}
+		if len(layer.Siblings) != p.pathDepth(j) {
+			return fmt.Errorf("Merkle path has %d siblings, want %d", len(layer.Siblings), p.pathDepth(j))
+		}
+		return nil
+	}
+
+	if want := max(p.numRounds-1, 0); len(prf.FRIRoots) != want {
+		return fmt.Errorf("proof has %d FRI roots, want %d", len(prf.FRIRoots), want)
+	}
+	if want := p.N >> p.numRounds; len(prf.FinalPoly) != want {
+		return fmt.Errorf("final polynomial has %d values, want %d", len(prf.FinalPoly), want)
+	}
+	for i := range prf.FinalPoly {
+		if len(prf.FinalPoly[i]) != deg {
+			return fmt.Errorf("final polynomial value %d has %d coordinates, want %d", i, len(prf.FinalPoly[i]), deg)
+		}
+	}
+	if p.grinding > 0 && len(prf.PoWSalts) != p.numRounds {
+		return fmt.Errorf("proof has %d proof of work salts, want %d", len(prf.PoWSalts), p.numRounds)
+	}
+	if len(prf.FRIQueries) != p.NumQueries {
+		return fmt.Errorf("proof has %d FRI queries, want %d", len(prf.FRIQueries), p.NumQueries)
+	}
+	for k, q := range prf.FRIQueries {
+		if len(q) != p.numRounds {
+			return fmt.Errorf("FRI query %d has %d layers, want %d", k, len(q), p.numRounds)
+		}
+		for j := range q {
+			if err := checkLayer(q[j], j); err != nil {
+				return fmt.Errorf("FRI query %d, round %d: %w", k, j, err)
+			}
+		}
+	}
+
+	if len(prf.LevelQueries) != len(levelDs)-1 {
+		return fmt.Errorf("proof has %d level query sets, want %d", len(prf.LevelQueries), len(levelDs)-1)
+	}
+	for l, qs := range prf.LevelQueries {
+		if len(qs) != p.NumQueries {
+			return fmt.Errorf("proof has %d queries for extra level %d, want %d", len(qs), l+1, p.NumQueries)
+		}
+		jl := log2(p.D / levelDs[l+1])
+		for k := range qs {
+			if err := checkLayer(qs[k], jl); err != nil {
+				return fmt.Errorf("extra level %d, query %d: %w", l+1, k, err)
+			}
+		}
+	}
+	return nil
+}
+
+// gnarkDegree returns the number of coordinates of the values of the given
+// field in a GnarkProof.
+func gnarkDegree(kind field.Kind) (int, error) {
+	switch kind {
+	case field.KindBase:
+		return 1, nil
+	case field.KindExt:
+		return hash.ExtDegree, nil
+	default:
+		return 0, fmt.Errorf("invalid final field %s", kind)
+	}
+}
+
+// isSalted reports whether a query of prf opens a leaf of a salted tree.
+func isSalted(prf Proof) bool {
+	for _, qs := range prf.LevelQueries {
+		for _, q := range qs {
+			if len(q.Salt) > 0 {
+				return true
+			}
+		}
+	}
+	for _, q := range prf.FRIQueries {
+		for _, layer := range q.Layers {
+			if len(layer.Salt) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func assignGnarkQueryLayer(layer QueryLayer) GnarkQueryLayer {
+	res := GnarkQueryLayer{Siblings: make([]poseidon2.GnarkOctuplet, len(layer.Path.Siblings))}
+	if layer.Field == field.KindExt {
+		res.LeafP = assignGnarkElements(hash.ExtToElements(layer.LeafPExt)...)
+		res.LeafQ = assignGnarkElements(hash.ExtToElements(layer.LeafQExt)...)
+	} else {
+		res.LeafP = assignGnarkElements(layer.LeafPBase)
+		res.LeafQ = assignGnarkElements(layer.LeafQBase)
+	}
+	for i := range layer.Path.Siblings {
+		res.Siblings[i] = assignGnarkDigest(layer.Path.Siblings[i])
+	}
+	return res
+}
+
+func assignGnarkDigest(d hash.Digest) poseidon2.GnarkOctuplet {
+	var res poseidon2.GnarkOctuplet
+	for i := range d {
+		res[i] = d[i].String()
+	}
+	return res
+}
+
+func assignGnarkElements(elmts ...koalabear.Element) []frontend.Variable {
+	res := make([]frontend.Variable, len(elmts))
+	for i := range elmts {
+		res[i] = elmts[i].String()
+	}
+	return res
+}
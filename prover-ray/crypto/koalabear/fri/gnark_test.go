@@ -0,0 +1,211 @@
+package fri_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/koalabear"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/commitment"
+	fiatshamir "github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/fiatshamirrefactor"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/fri"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/poseidon2"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// gnarkVerifierCircuit verifies a FRI proof for fixed parameters and level
+// sizes.
+type gnarkVerifierCircuit struct {
+	LevelRoots []poseidon2.GnarkOctuplet
+	Proof      fri.GnarkProof
+
+	params  fri.Params
+	levelDs []int
+}
+
+func (c *gnarkVerifierCircuit) Define(api frontend.API) error {
+	ts := fiatshamir.NewGnarkTranscript(api, poseidon2.NewGnarkSpongeHasher(api))
+	return fri.GnarkVerify(api, c.params, c.LevelRoots, c.levelDs, c.Proof, ts)
+}
+
+// gnarkTestCase describes the levels of the proven polynomials: levelDs[0] is
+// the degree of the first level, the other ones are the extra levels.
+type gnarkTestCase struct {
+	name     string
+	N, Q     int
+	levelDs  []int
+	field    field.Kind
+	grinding int
+}
+
+// proveForGnark returns a fresh proof of the test case and the level roots.
+func proveForGnark(t *testing.T, p fri.Params, tc gnarkTestCase) (fri.Proof, []hash.Digest) {
+	t.Helper()
+
+	levels := make([]fri.Level, len(tc.levelDs))
+	roots := make([]hash.Digest, len(tc.levelDs))
+	for l, d := range tc.levelDs {
+		// levels[l] is evaluated on the domain of the folding round introducing it
+		pl := testParams(t, tc.N*d/tc.levelDs[0], d, tc.Q)
+		levels[l].D = d
+		if tc.field == field.KindExt {
+			evals, err := pl.EncodeExt(randomExtPoly(d))
+			if err != nil {
+				t.Fatalf("EncodeExt level %d: %v", l, err)
+			}
+			levels[l].Evals = fri.LevelEvals{Ext: evals}
+			levels[l].Tree = buildLevelTreeExt(t, p, evals)
+		} else {
+			evals, err := pl.Encode(randomPoly(d))
+			if err != nil {
+				t.Fatalf("Encode level %d: %v", l, err)
+			}
+			levels[l].Evals = fri.LevelEvals{Base: evals}
+			levels[l].Tree = buildLevelTree(t, p, evals)
+		}
+		roots[l] = levels[l].Tree.Root()
+	}
+
+	prf, _, err := fri.Prove(p, levels, freshTS())
+	if err != nil {
+		t.Fatalf("Prove: %v", err)
+	}
+	return prf, roots
+}
+
+// TestGnarkVerify checks that the gnark verifier agrees with Verify on valid
+// and tampered proofs.
+func TestGnarkVerify(t *testing.T) {
+
+	cases := []gnarkTestCase{
+		{name: "Base", N: 64, Q: 3, levelDs: []int{8}, field: field.KindBase},
+		{name: "Ext", N: 64, Q: 2, levelDs: []int{4}, field: field.KindExt},
+		{name: "BaseExtraLevel", N: 64, Q: 2, levelDs: []int{16, 4}, field: field.KindBase},
+		{name: "ExtExtraLevel", N: 64, Q: 2, levelDs: []int{16, 4}, field: field.KindExt},
+		{name: "BaseGrinding", N: 32, Q: 2, levelDs: []int{4}, field: field.KindBase, grinding: 4},
+	}
+
+	tampers := []struct {
+		name   string
+		tamper func(prf *fri.Proof, roots []hash.Digest) bool // returns false if it does not apply
+	}{
+		{name: "Valid", tamper: func(*fri.Proof, []hash.Digest) bool { return true }},
+		{name: "WrongRoot", tamper: func(_ *fri.Proof, roots []hash.Digest) bool {
+			_, _ = roots[0][0].SetRandom()
+			return true
+		}},
+		{name: "FlippedLeaf", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			layer := &prf.FRIQueries[0].Layers[0]
+			_, _ = layer.LeafPBase.SetRandom()
+			layer.LeafPExt.MustSetRandom()
+			return true
+		}},
+		{name: "FlippedLastLeaf", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			layers := prf.FRIQueries[len(prf.FRIQueries)-1].Layers
+			layer := &layers[len(layers)-1]
+			_, _ = layer.LeafQBase.SetRandom()
+			layer.LeafQExt.MustSetRandom()
+			return true
+		}},
+		{name: "FlippedSibling", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			_, _ = prf.FRIQueries[0].Layers[1].Path.Siblings[0][3].SetRandom()
+			return true
+		}},
+		{name: "FlippedFRIRoot", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			_, _ = prf.FRIRoots[0][0].SetRandom()
+			return true
+		}},
+		{name: "FlippedFinalPoly", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			for i := range prf.FinalPolyBase {
+				_, _ = prf.FinalPolyBase[i].SetRandom()
+			}
+			for i := range prf.FinalPolyExt {
+				prf.FinalPolyExt[i].MustSetRandom()
+			}
+			return true
+		}},
+		{name: "FlippedLevelLeaf", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			if len(prf.LevelQueries) == 0 {
+				return false
+			}
+			layer := &prf.LevelQueries[0][0]
+			_, _ = layer.LeafPBase.SetRandom()
+			_, _ = layer.LeafQBase.SetRandom()
+			layer.LeafPExt.MustSetRandom()
+			layer.LeafQExt.MustSetRandom()
+			return true
+		}},
+		{name: "WrongProofOfWork", tamper: func(prf *fri.Proof, _ []hash.Digest) bool {
+			if len(prf.PoW) == 0 {
+				return false
+			}
+			for name, pow := range prf.PoW {
+				pow.Salt.Add(&pow.Salt, new(koalabear.Element).SetOne())
+				prf.PoW[name] = pow
+			}
+			return true
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			var opts []fri.Option
+			if tc.grinding > 0 {
+				opts = append(opts, fri.WithGrinding(tc.grinding))
+			}
+			p, err := fri.NewParams(tc.N, tc.levelDs[0], tc.Q, commitment.DefaultLeafHasher, commitment.DefaultNodeHasher, opts...)
+			if err != nil {
+				t.Fatalf("NewParams: %v", err)
+			}
+
+			gnarkProof, err := fri.AllocateGnarkProof(p, tc.levelDs, tc.field)
+			if err != nil {
+				t.Fatalf("AllocateGnarkProof: %v", err)
+			}
+			circuit := gnarkVerifierCircuit{
+				LevelRoots: make([]poseidon2.GnarkOctuplet, len(tc.levelDs)),
+				Proof:      gnarkProof,
+				params:     p,
+				levelDs:    tc.levelDs,
+			}
+			ccs, err := frontend.CompileU32(koalabear.Modulus(), scs.NewBuilder, &circuit)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+
+			for _, tm := range tampers {
+				t.Run(tm.name, func(t *testing.T) {
+
+					prf, roots := proveForGnark(t, p, tc)
+					if !tm.tamper(&prf, roots) {
+						t.Skip("the tampering does not apply to this proof")
+					}
+
+					nativeErr := fri.Verify(p, roots, tc.levelDs, prf, freshTS())
+
+					assignment, err := fri.AssignGnarkProof(p, prf)
+					if err != nil {
+						t.Fatalf("AssignGnarkProof: %v", err)
+					}
+					w, err := frontend.NewWitness(&gnarkVerifierCircuit{
+						LevelRoots: fri.AssignGnarkDigests(roots),
+						Proof:      assignment,
+					}, koalabear.Modulus())
+					if err != nil {
+						t.Fatalf("witness: %v", err)
+					}
+					gnarkErr := ccs.IsSolved(w)
+
+					if tm.name == "Valid" && nativeErr != nil {
+						t.Fatalf("Verify rejected a valid proof: %v", nativeErr)
+					}
+					if (nativeErr == nil) != (gnarkErr == nil) {
+						t.Fatalf("the verifiers disagree: native=%v, gnark=%v", nativeErr, gnarkErr)
+					}
+				})
+			}
+		})
+	}
+}
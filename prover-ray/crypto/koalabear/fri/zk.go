@@ -0,0 +1,118 @@
+package fri
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/koalabear"
+	ext "github.com/consensys/gnark-crypto/field/koalabear/extensions"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/commitment"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/fiatshamirrefactor"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/merkle"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// maskOpeningBase is an authenticated opening of the zero-knowledge mask at
+// a query, together with the mixing challenge β.
+type maskOpeningBase struct {
+	p, q koalabear.Element
+	beta koalabear.Element
+}
+
+// maskOpeningExt is the extension-field counterpart of maskOpeningBase.
+type maskOpeningExt struct {
+	p, q ext.E6
+	beta ext.E6
+}
+
+// sampleMaskBase draws a uniformly random polynomial of degree < D, returns
+// its evaluations on the full domain and the FRI-style tree committing to
+// them. The randomness comes from crypto/rand.
+func sampleMaskBase(p Params) ([]koalabear.Element, *merkle.Tree, error) {
+	coeffs := make([]koalabear.Element, p.D)
+	for i := range coeffs {
+		if _, err := coeffs[i].SetRandom(); err != nil {
+			return nil, nil, fmt.Errorf("fri: Prove: sample mask: %w", err)
+		}
+	}
+	evals, err := p.Encode(coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := buildTreeBase(evals, nil, p.LeafHasher, p.NodeHasher)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fri: Prove: build mask tree: %w", err)
+	}
+	return evals, tree, nil
+}
+
+// sampleMaskExt is the extension-field counterpart of sampleMaskBase.
+func sampleMaskExt(p Params) ([]ext.E6, *merkle.Tree, error) {
+	coeffs := make([]ext.E6, p.D)
+	for i := range coeffs {
+		if _, err := coeffs[i].SetRandom(); err != nil {
+			return nil, nil, fmt.Errorf("fri: Prove: sample mask: %w", err)
+		}
+	}
+	evals, err := p.EncodeExt(coeffs)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := buildTreeExt(evals, nil, p.LeafHasher, p.NodeHasher)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fri: Prove: build mask tree: %w", err)
+	}
+	return evals, tree, nil
+}
+
+// computeMaskChallenge binds the level-0 and mask roots and returns the
+// mixing challenge β. Both roots must be bound: β has to be unpredictable
+// when the prover picks the mask, or the mask could cancel a far-from-
+// low-degree level 0.
+func computeMaskChallenge(ts *fiatshamirrefactor.Transcript, root0, maskRoot hash.Digest) ([8]koalabear.Element, error) {
+	if err := ts.Bind(maskName(), root0[:]); err != nil {
+		return [8]koalabear.Element{}, err
+	}
+	if err := ts.Bind(maskName(), maskRoot[:]); err != nil {
+		return [8]koalabear.Element{}, err
+	}
+	return ts.ComputeChallenge(maskName())
+}
+
+// addScaledBase returns layer + beta·mask in a new slice.
+func addScaledBase(layer, mask []koalabear.Element, beta koalabear.Element) []koalabear.Element {
+	res := make([]koalabear.Element, len(layer))
+	for i := range layer {
+		res[i].Mul(&mask[i], &beta)
+		res[i].Add(&res[i], &layer[i])
+	}
+	return res
+}
+
+// addScaledExt is the extension-field counterpart of addScaledBase.
+func addScaledExt(layer, mask []ext.E6, beta ext.E6) []ext.E6 {
+	res := make([]ext.E6, len(layer))
+	for i := range layer {
+		res[i].Mul(&mask[i], &beta)
+		res[i].Add(&res[i], &layer[i])
+	}
+	return res
+}
+
+// verifyMaskOpening checks the Merkle opening of the mask at one query
+// against the mask root of the proof.
+func verifyMaskOpening(p Params, root hash.Digest, ml QueryLayer, rail field.Kind) error {
+	if ml.Field != rail {
+		return fmt.Errorf("mask: expected %s query layer, got %s", rail, ml.Field)
+	}
+	var leaf hash.Digest
+	if rail == field.KindExt {
+		leaf = commitment.HashSaltedLeaf(p.LeafHasher, nil, ml.Salt, []commitment.PairExt{{ml.LeafPExt, ml.LeafQExt}})
+	} else {
+		leaf = commitment.HashSaltedLeaf(p.LeafHasher, []commitment.PairBase{{ml.LeafPBase, ml.LeafQBase}}, ml.Salt, nil)
+	}
+	if !merkle.Verify(root, ml.Path, leaf, p.NodeHasher) {
+		return fmt.Errorf("mask: Merkle proof invalid")
+	}
+	return nil
+}
@@ -135,12 +135,14 @@ var (
 	// ErrInvalidSizebuffer is returned when the input size does not match the hash buffer size.
 	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
 	compressPerm         permutation
+	spongePerm           permutation
 	once                 sync.Once
 )
 
 func init() {
 	once.Do(func() {
 		compressPerm = NewPermutation()
+		spongePerm = NewSpongePermutation()
 	})
 }
 
@@ -153,6 +155,14 @@ func NewPermutation() permutation {
 	return permutation{params: params}
 }
 
+// NewSpongePermutation creates the width-24 permutation used by the sponge and
+// the Merkle node compression of the hash package.
+// nolint -- same as NewPermutation.
+func NewSpongePermutation() permutation {
+	params := poseidon2.NewParameters(24, 6, 21)
+	return permutation{params: params}
+}
+
 type permutation struct {
 	params *poseidon2.Parameters
 }
@@ -229,11 +239,14 @@ func (h *permutation) matMulExternalInPlace(api frontend.API, input []frontend.V
 // when t=2,3 the matrix are respectively [[2,1][1,3]] and [[2,1,1][1,2,1][1,1,3]]
 // otherwise the matrix is filled with ones except on the diagonal,
 func (h *permutation) matMulInternalInPlace(api frontend.API, input []frontend.Variable) {
-	// width = 16
 	sum := input[0]
 	for i := 1; i < h.params.Width; i++ {
 		sum = api.Add(sum, input[i])
 	}
+	if h.params.Width == 24 {
+		h.matMulInternal24InPlace(api, sum, input)
+		return
+	}
 	// mul by diag16:
 	// [-2, 1, 2, 1/2, 3, 4, -1/2, -3, -4, 1/2^8, 1/8, 1/2^24, -1/2^8, -1/8, -1/16, -1/2^24]
 	v := 2
@@ -282,6 +295,46 @@ func (h *permutation) matMulInternalInPlace(api frontend.API, input []frontend.V
 	input[15] = api.Sub(sum, temp)
 }
 
+// matMulInternal24InPlace is the width-24 counterpart of the internal matrix,
+// sum is the sum of the input elements.
+func (h *permutation) matMulInternal24InPlace(api frontend.API, sum frontend.Variable, input []frontend.Variable) {
+	// mul by diag24:
+	// [-2, 1, 2, 1/2, 3, 4, -1/2, -3, -4, 1/2^8, 1/4, 1/8, 1/16, 1/32, 1/64, 1/2^24,
+	//  -1/2^8, -1/8, -1/16, -1/32, -1/64, -1/2^7, -1/2^9, -1/2^24]
+	var temp frontend.Variable
+	temp = api.Add(input[0], input[0])
+	input[0] = api.Sub(sum, temp)
+	input[1] = api.Add(sum, input[1])
+	temp = api.Add(input[2], input[2])
+	input[2] = api.Add(sum, temp)
+	temp = api.Div(input[3], 2)
+	input[3] = api.Add(sum, temp)
+	temp = api.Add(input[4], input[4])
+	temp = api.Add(temp, input[4])
+	input[4] = api.Add(sum, temp)
+	temp = api.Add(input[5], input[5])
+	temp = api.Add(temp, temp)
+	input[5] = api.Add(sum, temp)
+	temp = api.Div(input[6], 2)
+	input[6] = api.Sub(sum, temp)
+	temp = api.Add(input[7], input[7])
+	temp = api.Add(temp, input[7])
+	input[7] = api.Sub(sum, temp)
+	temp = api.Add(input[8], input[8])
+	temp = api.Add(temp, temp)
+	input[8] = api.Sub(sum, temp)
+
+	// the remaining entries are ±1/2^k
+	for i, k := range [...]int{8, 2, 3, 4, 5, 6, 24} {
+		temp = api.Div(input[9+i], 1<<k)
+		input[9+i] = api.Add(sum, temp)
+	}
+	for i, k := range [...]int{8, 3, 4, 5, 6, 7, 9, 24} {
+		temp = api.Div(input[16+i], 1<<k)
+		input[16+i] = api.Sub(sum, temp)
+	}
+}
+
 // addRoundKeyInPlace adds the round-th key to the buffer
 func (h *permutation) addRoundKeyInPlace(api frontend.API, round int, input []frontend.Variable) {
 	var rk frontend.Variable
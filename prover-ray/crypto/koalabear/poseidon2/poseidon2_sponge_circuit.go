@@ -0,0 +1,97 @@
+package poseidon2
+
+import (
+	"github.com/consensys/gnark/frontend"
+)
+
+const (
+	// spongeWidth and spongeRate mirror hash.SpongeWidth and hash.SpongeRate.
+	spongeWidth = 24
+	spongeRate  = 16
+)
+
+// GnarkSpongeHasher mirrors hash.Poseidon2SpongeHasher in a gnark circuit: a
+// padding-free overwrite-mode sponge with width 24, rate 16 and an 8-element
+// digest. The written elements are absorbed when [GnarkSpongeHasher.Sum] is
+// called, each block of up to 16 elements overwrites the beginning of the
+// state and is followed by one permutation.
+type GnarkSpongeHasher struct {
+	api frontend.API
+
+	// sponge state
+	state [spongeWidth]frontend.Variable
+
+	// data to hash
+	buffer []frontend.Variable
+}
+
+// NewGnarkSpongeHasher returns a new sponge hasher with a zero state.
+func NewGnarkSpongeHasher(api frontend.API) *GnarkSpongeHasher {
+	res := &GnarkSpongeHasher{api: api}
+	res.Reset()
+	return res
+}
+
+// Reset clears the buffer and resets the state to zero.
+func (h *GnarkSpongeHasher) Reset() {
+	h.buffer = h.buffer[:0]
+	for i := range h.state {
+		h.state[i] = 0
+	}
+}
+
+// Write appends data to the buffer of the hasher.
+func (h *GnarkSpongeHasher) Write(data ...frontend.Variable) {
+	h.buffer = append(h.buffer, data...)
+}
+
+// WriteOctuplet appends the elements of the octuplets to the buffer of the
+// hasher.
+func (h *GnarkSpongeHasher) WriteOctuplet(data ...GnarkOctuplet) {
+	for i := range data {
+		h.Write(data[i][:]...)
+	}
+}
+
+// Sum absorbs the buffer and returns the first 8 elements of the state. As
+// for the native hasher, it returns zero if nothing has been written since
+// the last reset.
+func (h *GnarkSpongeHasher) Sum() GnarkOctuplet {
+	for len(h.buffer) > 0 {
+		n := min(len(h.buffer), spongeRate)
+		copy(h.state[:n], h.buffer[:n])
+		h.buffer = h.buffer[n:]
+
+		if err := spongePerm.Permutation(h.api, h.state[:]); err != nil {
+			// can't error (size is correct)
+			panic(err)
+		}
+	}
+
+	var res GnarkOctuplet
+	copy(res[:], h.state[:len(res)])
+	return res
+}
+
+// GnarkNodeCompress mirrors hash.Poseidon2NodeCompress in a gnark circuit: the
+// domain tag, left and right are placed in a width-24 state as
+// [tag, 0 x 7, left, right] and the digest is the first 8 elements of the
+// state after one permutation.
+func GnarkNodeCompress(api frontend.API, nodeDomainTag uint64, left, right GnarkOctuplet) GnarkOctuplet {
+	var state [spongeWidth]frontend.Variable
+	state[0] = nodeDomainTag
+	for i := 1; i < 8; i++ {
+		state[i] = 0
+	}
+	copy(state[8:16], left[:])
+	copy(state[16:], right[:])
+
+	if err := spongePerm.Permutation(api, state[:]); err != nil {
+		// can't error (size is correct)
+		panic(err)
+	}
+
+	var res GnarkOctuplet
+	copy(res[:], state[:len(res)])
+	return res
+}
@@ -0,0 +1,98 @@
+package poseidon2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/koalabear"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/hash"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/stretchr/testify/require"
+)
+
+type GnarkSpongeHasherCircuit struct {
+	Inputs []frontend.Variable
+	Output GnarkOctuplet
+}
+
+func (c *GnarkSpongeHasherCircuit) Define(api frontend.API) error {
+	h := NewGnarkSpongeHasher(api)
+	h.Write(c.Inputs...)
+	res := h.Sum()
+	for i := range res {
+		api.AssertIsEqual(c.Output[i], res[i])
+	}
+	return nil
+}
+
+type GnarkNodeCompressCircuit struct {
+	Left, Right, Output GnarkOctuplet
+}
+
+func (c *GnarkNodeCompressCircuit) Define(api frontend.API) error {
+	res := GnarkNodeCompress(api, 0x4e4f4445, c.Left, c.Right)
+	for i := range res {
+		api.AssertIsEqual(c.Output[i], res[i])
+	}
+	return nil
+}
+
+func assertSolved(t *testing.T, circuit, witness frontend.Circuit) {
+	t.Helper()
+
+	ccs, err := frontend.CompileU32(koalabear.Modulus(), scs.NewBuilder, circuit)
+	require.NoError(t, err)
+
+	fullWitness, err := frontend.NewWitness(witness, koalabear.Modulus())
+	require.NoError(t, err)
+	require.NoError(t, ccs.IsSolved(fullWitness))
+}
+
+func TestGnarkSpongeHasher(t *testing.T) {
+
+	for _, nbElmts := range []int{0, 1, 15, 16, 17, 40} {
+		t.Run(fmt.Sprintf("nbElmts=%d", nbElmts), func(t *testing.T) {
+
+			vals := make([]field.Element, nbElmts)
+			for i := range vals {
+				_, _ = vals[i].SetRandom()
+			}
+
+			h := hash.NewPoseidon2SpongeHasher()
+			h.WriteElements(vals...)
+			res := h.Sum()
+
+			circuit := GnarkSpongeHasherCircuit{Inputs: make([]frontend.Variable, nbElmts)}
+			witness := GnarkSpongeHasherCircuit{Inputs: make([]frontend.Variable, nbElmts)}
+			for i := range vals {
+				witness.Inputs[i] = vals[i].String()
+			}
+			for i := range res {
+				witness.Output[i] = res[i].String()
+			}
+
+			assertSolved(t, &circuit, &witness)
+		})
+	}
+}
+
+func TestGnarkNodeCompress(t *testing.T) {
+
+	var left, right hash.Digest
+	for i := range left {
+		_, _ = left[i].SetRandom()
+		_, _ = right[i].SetRandom()
+	}
+	res := hash.Poseidon2NodeCompress(0x4e4f4445, left, right)
+
+	var witness GnarkNodeCompressCircuit
+	for i := range res {
+		witness.Left[i] = left[i].String()
+		witness.Right[i] = right[i].String()
+		witness.Output[i] = res[i].String()
+	}
+
+	assertSolved(t, &GnarkNodeCompressCircuit{}, &witness)
+}
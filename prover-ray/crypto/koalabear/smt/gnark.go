@@ -20,26 +20,42 @@ func selectOcuplet(api frontend.API, b frontend.Variable, l, r poseidon2.GnarkOc
 	return res
 }
 
+// GnarkNodeHasher compresses two sibling nodes into their parent in a gnark
+// circuit.
+type GnarkNodeHasher func(api frontend.API, left, right poseidon2.GnarkOctuplet) poseidon2.GnarkOctuplet
+
+// gnarkMDNodeHasher hashes the nodes as the sparse Merkle tree does.
+func gnarkMDNodeHasher(api frontend.API, left, right poseidon2.GnarkOctuplet) poseidon2.GnarkOctuplet {
+	h, _ := poseidon2.NewGnarkMDHasher(api) // can't error
+	h.WriteOctuplet(left, right)
+	return h.Sum()
+}
+
 // GnarkRecoverRoot computes the root form the proof and the leaf
 func GnarkRecoverRoot(
 	api frontend.API,
 	proof GnarkProof,
 	leaf poseidon2.GnarkOctuplet) (poseidon2.GnarkOctuplet, error) {
 
-	h, err := poseidon2.NewGnarkMDHasher(api)
-	if err != nil {
-		return poseidon2.GnarkOctuplet{}, err
-	}
+	return GnarkRecoverRootWithHasher(api, proof, leaf, gnarkMDNodeHasher)
+}
+
+// GnarkRecoverRootWithHasher is as [GnarkRecoverRoot] but compresses the nodes
+// with nh. It opens the Merkle trees which are not hashed like the sparse
+// Merkle tree, e.g. the ones of the FRI commitments.
+func GnarkRecoverRootWithHasher(
+	api frontend.API,
+	proof GnarkProof,
+	leaf poseidon2.GnarkOctuplet,
+	nh GnarkNodeHasher) (poseidon2.GnarkOctuplet, error) {
 
 	current := leaf
 	nbBits := len(proof.Siblings)
 	b := api.ToBinary(proof.Path, nbBits)
 	for i := 0; i < len(proof.Siblings); i++ {
-		h.Reset()
 		left := selectOcuplet(api, b[i], proof.Siblings[i], current)
 		right := selectOcuplet(api, b[i], current, proof.Siblings[i])
-		h.WriteOctuplet(left, right)
-		current = h.Sum()
+		current = nh(api, left, right)
 	}
 
 	return current, nil
@@ -52,7 +68,19 @@ func GnarkVerifyMerkleProof(
 	leaf poseidon2.GnarkOctuplet,
 	root poseidon2.GnarkOctuplet) error {
 
-	r, err := GnarkRecoverRoot(api, proof, leaf)
+	return GnarkVerifyMerkleProofWithHasher(api, proof, leaf, root, gnarkMDNodeHasher)
+}
+
+// GnarkVerifyMerkleProofWithHasher is as [GnarkVerifyMerkleProof] but
+// compresses the nodes with nh.
+func GnarkVerifyMerkleProofWithHasher(
+	api frontend.API,
+	proof GnarkProof,
+	leaf poseidon2.GnarkOctuplet,
+	root poseidon2.GnarkOctuplet,
+	nh GnarkNodeHasher) error {
+
+	r, err := GnarkRecoverRootWithHasher(api, proof, leaf, nh)
 	if err != nil {
 		return err
 	}
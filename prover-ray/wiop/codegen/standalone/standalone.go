@@ -0,0 +1,306 @@
+// Package standalone is the runtime of the verifiers written by
+// [codegen.GenerateVerifier]. The generator copies this file verbatim into
+// the generated package, renames its package clause and appends the [Spec]
+// of one compiled system, so a generated verifier links the field and
+// Fiat-Shamir packages but nothing from wiop.
+//
+// Keep this file self-contained: no other file of the package is copied, and
+// it must not import wiop, directly or not.
+package standalone
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover-ray/crypto/koalabear/fiatshamir"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// Spec is the verifier-visible description of a compiled system.
+type Spec struct {
+	// Name is the path of the source system, for error messages.
+	Name string
+	// Digest is the system digest carried by the header of its proofs.
+	Digest [32]byte
+	// Columns lists the committed columns by increasing object ID, i.e. in
+	// the order of the proof encoding.
+	Columns []Column
+	// Cells lists the cells by increasing object ID.
+	Cells []Cell
+	// Rounds describes the Fiat-Shamir transcript, one entry per round.
+	Rounds []Round
+	// Nodes is the expression graph of the constraints. Operands always
+	// precede the nodes using them.
+	Nodes []Node
+	// Constraints lists the nodes that must evaluate to zero.
+	Constraints []Constraint
+}
+
+// Column is a committed column of the source system.
+type Column struct {
+	ID   uint64
+	Size int
+	Name string
+}
+
+// Cell is a cell of the source system.
+type Cell struct {
+	ID   uint64
+	Name string
+}
+
+// Round lists what the transcript absorbs at the end of a round, as indices
+// into [Spec.Columns] and [Spec.Cells] in absorption order, and how many
+// coins it squeezes at its start. Coins are numbered across rounds in the
+// order they are squeezed.
+type Round struct {
+	Columns []int
+	Cells   []int
+	Coins   int
+}
+
+// Op is the operation of a [Node].
+type Op uint8
+
+const (
+	// OpCell reads the cell Spec.Cells[A].
+	OpCell Op = iota
+	// OpCoin reads the A-th coin.
+	OpCoin
+	// OpConst is the base-field constant of canonical value A.
+	OpConst
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpDouble
+	OpSquare
+	OpNeg
+	OpInverse
+)
+
+// Node is an expression node. For leaves, A is the operand described by Op;
+// otherwise A and B are node indices, B being unused by unary operations.
+type Node struct {
+	Op   Op
+	A, B uint64
+}
+
+// Constraint is a node that must evaluate to zero.
+type Constraint struct {
+	Node int
+	Name string
+}
+
+// proofVersion and proofKind match the header of a wiop wire-encoded proof.
+const (
+	proofVersion   = 1
+	proofKind      = 1
+	proofHeaderLen = 40
+)
+
+// ErrMalformedProof is wrapped by every error reporting a proof that does not
+// decode against the Spec.
+var ErrMalformedProof = errors.New("malformed proof")
+
+// Verify decodes a wire-encoded proof and checks it: it replays the
+// Fiat-Shamir transcript to derive the coins and evaluates every constraint.
+func (s *Spec) Verify(proof []byte) error {
+	columns, cells, err := s.decode(proof)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.Name, err)
+	}
+
+	fs := fiatshamir.NewFiatShamir()
+	var coins []field.Gen
+	for r, round := range s.Rounds {
+		for i := 0; i < round.Coins; i++ {
+			coins = append(coins, field.ElemFromExt(fs.RandomFext()))
+		}
+		if r == len(s.Rounds)-1 {
+			break
+		}
+		for _, c := range round.Columns {
+			fs.UpdateSV(columns[c])
+		}
+		for _, c := range round.Cells {
+			fs.UpdateGeneric(cells[c])
+		}
+	}
+
+	values := make([]field.Gen, len(s.Nodes))
+	for i, n := range s.Nodes {
+		switch n.Op {
+		case OpCell:
+			values[i] = cells[n.A]
+		case OpCoin:
+			values[i] = coins[n.A]
+		case OpConst:
+			var e field.Element
+			e.SetUint64(n.A)
+			values[i] = field.ElemFromBase(e)
+		case OpAdd:
+			values[i] = values[n.A].Add(values[n.B])
+		case OpSub:
+			values[i] = values[n.A].Sub(values[n.B])
+		case OpMul:
+			values[i] = values[n.A].Mul(values[n.B])
+		case OpDiv:
+			values[i] = values[n.A].Div(values[n.B])
+		case OpDouble:
+			values[i] = values[n.A].Add(values[n.A])
+		case OpSquare:
+			values[i] = values[n.A].Square()
+		case OpNeg:
+			values[i] = values[n.A].Neg()
+		case OpInverse:
+			values[i] = values[n.A].Inverse()
+		default:
+			return fmt.Errorf("%s: node %d has unknown op %d", s.Name, i, n.Op)
+		}
+	}
+
+	for _, c := range s.Constraints {
+		if !values[c.Node].IsZero() {
+			return fmt.Errorf("%s: constraint %s does not hold", s.Name, c.Name)
+		}
+	}
+	return nil
+}
+
+// decode parses proof and checks that it carries exactly the columns and
+// cells of s, in the canonical order of the wire format.
+func (s *Spec) decode(proof []byte) ([]field.Vec, []field.Gen, error) {
+	if len(proof) < proofHeaderLen || string(proof[:4]) != "WIOP" {
+		return nil, nil, fmt.Errorf("%w: bad header", ErrMalformedProof)
+	}
+	if v, k := binary.BigEndian.Uint16(proof[4:]), binary.BigEndian.Uint16(proof[6:]); v != proofVersion || k != proofKind {
+		return nil, nil, fmt.Errorf("%w: version %d kind %d, expected %d and %d", ErrMalformedProof, v, k, proofVersion, proofKind)
+	}
+	if [32]byte(proof[8:proofHeaderLen]) != s.Digest {
+		return nil, nil, fmt.Errorf("%w: the proof is for another system", ErrMalformedProof)
+	}
+
+	d := decoder{data: proof, off: proofHeaderLen}
+	if n := d.u32(); n != 0 {
+		return nil, nil, fmt.Errorf("%w: %d dynamic module sizes, expected none", ErrMalformedProof, n)
+	}
+
+	if n := d.u32(); d.err == nil && int(n) != len(s.Columns) {
+		return nil, nil, fmt.Errorf("%w: %d columns, expected %d", ErrMalformedProof, n, len(s.Columns))
+	}
+	columns := make([]field.Vec, len(s.Columns))
+	for i, col := range s.Columns {
+		if id := d.u64(); d.err == nil && id != col.ID {
+			return nil, nil, fmt.Errorf("%w: column %d has ID %#x, expected %q", ErrMalformedProof, i, id, col.Name)
+		}
+		ext := d.tag()
+		d.elem() // Padding: not absorbed, and constraints only read cells.
+		n := d.u32()
+		if d.err == nil && int(n) > col.Size {
+			return nil, nil, fmt.Errorf("%w: column %q has %d values, its module has %d rows", ErrMalformedProof, col.Name, n, col.Size)
+		}
+		if ext {
+			vals := make([]field.Ext, n)
+			for j := range vals {
+				vals[j] = d.ext()
+			}
+			columns[i] = field.VecFromExt(vals)
+		} else {
+			vals := make([]field.Element, n)
+			for j := range vals {
+				vals[j] = d.elem()
+			}
+			columns[i] = field.VecFromBase(vals)
+		}
+	}
+
+	if n := d.u32(); d.err == nil && int(n) != len(s.Cells) {
+		return nil, nil, fmt.Errorf("%w: %d cells, expected %d", ErrMalformedProof, n, len(s.Cells))
+	}
+	cells := make([]field.Gen, len(s.Cells))
+	for i, cell := range s.Cells {
+		if id := d.u64(); d.err == nil && id != cell.ID {
+			return nil, nil, fmt.Errorf("%w: cell %d has ID %#x, expected %q", ErrMalformedProof, i, id, cell.Name)
+		}
+		if d.tag() {
+			cells[i] = field.ElemFromExt(d.ext())
+		} else {
+			cells[i] = field.ElemFromBase(d.elem())
+		}
+	}
+
+	if d.err == nil && d.off != len(d.data) {
+		d.err = fmt.Errorf("%w: %d trailing bytes", ErrMalformedProof, len(d.data)-d.off)
+	}
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return columns, cells, nil
+}
+
+// decoder reads big-endian values from a proof. Its first error is sticky
+// and every later read returns zero.
+type decoder struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data)-d.off < n {
+		d.err = fmt.Errorf("%w: truncated at offset %d", ErrMalformedProof, d.off)
+		return nil
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *decoder) u32() uint32 {
+	if b := d.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *decoder) u64() uint64 {
+	if b := d.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// tag reads a field tag and reports whether it announces extension values.
+func (d *decoder) tag() bool {
+	b := d.take(1)
+	if b == nil {
+		return false
+	}
+	if b[0] > 1 {
+		d.err = fmt.Errorf("%w: unknown field tag %d at offset %d", ErrMalformedProof, b[0], d.off-1)
+	}
+	return b[0] == 1
+}
+
+func (d *decoder) elem() field.Element {
+	var e field.Element
+	if b := d.take(field.Bytes); b != nil {
+		if err := e.SetBytesCanonical(b); err != nil {
+			d.err = fmt.Errorf("%w: non-canonical field element at offset %d", ErrMalformedProof, d.off-field.Bytes)
+		}
+	}
+	return e
+}
+
+func (d *decoder) ext() field.Ext {
+	var e field.Ext
+	e.B0.A0, e.B0.A1 = d.elem(), d.elem()
+	e.B1.A0, e.B1.A1 = d.elem(), d.elem()
+	e.B2.A0, e.B2.A1 = d.elem(), d.elem()
+	return e
+}
@@ -0,0 +1,353 @@
+package codegen
+
+import (
+	"cmp"
+	_ "embed"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/codegen/standalone"
+)
+
+// standaloneSource is the runtime copied into every generated verifier.
+//
+//go:embed standalone/standalone.go
+var standaloneSource string
+
+// VerifierOptions controls the output of [GenerateVerifier].
+type VerifierOptions struct {
+	// Package is the Go package name of the generated file (default:
+	// "verifier").
+	Package string
+}
+
+// GenerateVerifier writes to w a self-contained Go package verifying the
+// wire-encoded proofs (see package wiop/wire) of sys. The package exports
+//
+//	func Verify(proof []byte) error
+//
+// which mirrors [wiop.System.Verify]: it checks the proof header against
+// sys.Digest(), replays the Fiat-Shamir transcript and evaluates the
+// constraints of every verifier action. The round structure, the coin routing
+// and the constraints are emitted as a [standalone.Spec] literal, next to a
+// copy of the standalone runtime, so the generated code imports the field and
+// Fiat-Shamir packages but not wiop.
+//
+// sys must satisfy the requirements of [BuildVerifierSpec]. The output is
+// deterministic.
+func GenerateVerifier(sys *wiop.System, opts VerifierOptions, w io.Writer) error {
+	if opts.Package == "" {
+		opts.Package = "verifier"
+	}
+	spec, err := BuildVerifierSpec(sys)
+	if err != nil {
+		return err
+	}
+
+	const clause = "\npackage standalone\n"
+	i := strings.Index(standaloneSource, clause)
+	if i < 0 {
+		return fmt.Errorf("codegen: the standalone runtime has no package clause")
+	}
+
+	cw := &CodeWriter{}
+	cw.Line("// Code generated by wiop/codegen from system %q. DO NOT EDIT.", sys.Context.Path())
+	cw.Blank()
+	cw.Line("package %s", opts.Package)
+	cw.buf.WriteString(standaloneSource[i+len(clause):])
+	cw.Blank()
+	cw.Line("// Verify checks a wire-encoded proof of system %q.", sys.Context.Path())
+	cw.Line("func Verify(proof []byte) error { return system.Verify(proof) }")
+	cw.Blank()
+	writeSpec(cw, spec)
+
+	_, err = cw.WriteTo(w)
+	return err
+}
+
+// BuildVerifierSpec extracts the [standalone.Spec] of a compiled system. It is
+// the data [GenerateVerifier] renders; tests run it in-process.
+//
+// sys must be fully compiled: every query is reduced except the
+// [wiop.LagrangeEval] claims, which are left to the commitment scheme exactly
+// as [wiop.System.Verify] leaves them, and every verifier action implements
+// [wiop.ArithmeticVerifierAction]. Dynamic modules and coins in the first
+// round are rejected.
+func BuildVerifierSpec(sys *wiop.System) (*standalone.Spec, error) {
+	if err := checkVerifiable(sys); err != nil {
+		return nil, err
+	}
+
+	spec := &standalone.Spec{Name: sys.Context.Path(), Digest: sys.Digest()}
+
+	var committed []*wiop.Column
+	var cells []*wiop.Cell
+	for _, r := range sys.Rounds {
+		for _, col := range r.Columns {
+			if col.Visibility >= wiop.VisibilityOracle {
+				committed = append(committed, col)
+			}
+		}
+		cells = append(cells, r.Cells...)
+	}
+	slices.SortFunc(committed, func(a, b *wiop.Column) int { return cmp.Compare(a.Context.ID, b.Context.ID) })
+	slices.SortFunc(cells, func(a, b *wiop.Cell) int { return cmp.Compare(a.Context.ID, b.Context.ID) })
+
+	b := &specBuilder{
+		spec:    spec,
+		columns: make(map[wiop.ObjectID]int, len(committed)),
+		cells:   make(map[wiop.ObjectID]int, len(cells)),
+		coins:   make(map[wiop.ObjectID]int),
+		nodes:   make(map[wiop.Expression]int),
+	}
+	for i, col := range committed {
+		b.columns[col.Context.ID] = i
+		spec.Columns = append(spec.Columns, standalone.Column{
+			ID: uint64(col.Context.ID), Size: col.Module.Size(), Name: col.Context.Path(),
+		})
+	}
+	for i, cell := range cells {
+		b.cells[cell.Context.ID] = i
+		spec.Cells = append(spec.Cells, standalone.Cell{ID: uint64(cell.Context.ID), Name: cell.Context.Path()})
+	}
+
+	for _, r := range sys.Rounds {
+		round := standalone.Round{Columns: []int{}, Cells: []int{}, Coins: len(r.Coins)}
+		for _, coin := range r.Coins {
+			b.coins[coin.Context.ID] = len(b.coins)
+		}
+		for _, col := range r.Columns {
+			if col.Visibility >= wiop.VisibilityOracle {
+				round.Columns = append(round.Columns, b.columns[col.Context.ID])
+			}
+		}
+		for _, cell := range r.Cells {
+			round.Cells = append(round.Cells, b.cells[cell.Context.ID])
+		}
+		spec.Rounds = append(spec.Rounds, round)
+	}
+
+	for _, r := range sys.Rounds {
+		for _, va := range r.VerifierActions {
+			constraints, err := va.(wiop.ArithmeticVerifierAction).Constraints()
+			if err != nil {
+				return nil, fmt.Errorf("codegen: round %d, %T: %w", r.ID, va, err)
+			}
+			for j, c := range constraints {
+				n, err := b.node(c)
+				if err != nil {
+					return nil, fmt.Errorf("codegen: round %d, %T, constraint %d: %w", r.ID, va, j, err)
+				}
+				spec.Constraints = append(spec.Constraints, standalone.Constraint{
+					Node: n,
+					Name: fmt.Sprintf("round %d, %T, constraint %d", r.ID, va, j),
+				})
+			}
+		}
+	}
+	return spec, nil
+}
+
+// checkVerifiable returns an error if sys is out of the scope of
+// [BuildVerifierSpec].
+func checkVerifiable(sys *wiop.System) error {
+	if len(sys.Rounds) == 0 {
+		return fmt.Errorf("codegen: system %q has no rounds", sys.Context.Path())
+	}
+	if len(sys.Rounds[0].Coins) > 0 {
+		return fmt.Errorf("codegen: system %q has coins in its first round, the transcript never derives them", sys.Context.Path())
+	}
+
+	var queries []wiop.Query
+	for _, m := range sys.Modules {
+		if m.IsDynamic() {
+			return fmt.Errorf("codegen: dynamic module %q is not supported", m.Context.Path())
+		}
+		for _, v := range m.Vanishings {
+			queries = append(queries, v)
+		}
+		for _, rc := range m.RangeChecks {
+			queries = append(queries, rc)
+		}
+	}
+	for _, tr := range sys.TableRelations {
+		queries = append(queries, tr)
+	}
+	for _, ld := range sys.LogDerivativeSums {
+		queries = append(queries, ld)
+	}
+	for _, mb := range sys.MessageBuses {
+		queries = append(queries, mb)
+	}
+	for _, q := range queries {
+		if !q.IsReduced() {
+			return fmt.Errorf("codegen: query %q is not reduced, compile the system first", q.Context().Path())
+		}
+	}
+
+	for _, r := range sys.Rounds {
+		for _, va := range r.VerifierActions {
+			if _, ok := va.(wiop.ArithmeticVerifierAction); !ok {
+				return fmt.Errorf("codegen: verifier action %T of round %d does not implement wiop.ArithmeticVerifierAction", va, r.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// specBuilder flattens the constraint expressions into [standalone.Spec.Nodes],
+// sharing the nodes of sub-expressions reused across constraints.
+type specBuilder struct {
+	spec    *standalone.Spec
+	columns map[wiop.ObjectID]int
+	cells   map[wiop.ObjectID]int
+	coins   map[wiop.ObjectID]int
+	nodes   map[wiop.Expression]int
+}
+
+var operatorOps = map[wiop.ArithmeticOperator]standalone.Op{
+	wiop.ArithmeticOperatorAdd:     standalone.OpAdd,
+	wiop.ArithmeticOperatorSub:     standalone.OpSub,
+	wiop.ArithmeticOperatorMul:     standalone.OpMul,
+	wiop.ArithmeticOperatorDiv:     standalone.OpDiv,
+	wiop.ArithmeticOperatorDouble:  standalone.OpDouble,
+	wiop.ArithmeticOperatorSquare:  standalone.OpSquare,
+	wiop.ArithmeticOperatorNegate:  standalone.OpNeg,
+	wiop.ArithmeticOperatorInverse: standalone.OpInverse,
+}
+
+// node returns the index of the node computing expr, appending it and its
+// operands if needed.
+func (b *specBuilder) node(expr wiop.Expression) (int, error) {
+	if i, ok := b.nodes[expr]; ok {
+		return i, nil
+	}
+
+	var n standalone.Node
+	switch e := expr.(type) {
+	case *wiop.Cell:
+		n = standalone.Node{Op: standalone.OpCell, A: uint64(b.cells[e.Context.ID])}
+	case *wiop.CoinField:
+		idx, ok := b.coins[e.Context.ID]
+		if !ok {
+			return 0, fmt.Errorf("coin %q is not declared in a round", e.Context.Path())
+		}
+		n = standalone.Node{Op: standalone.OpCoin, A: uint64(idx)}
+	case *wiop.Constant:
+		if e.IsMultiValued() {
+			return 0, fmt.Errorf("vector constants cannot appear in a verifier constraint")
+		}
+		n = standalone.Node{Op: standalone.OpConst, A: e.Value.Uint64()}
+	case *wiop.ArithmeticOperation:
+		op, ok := operatorOps[e.Operator]
+		if !ok {
+			return 0, fmt.Errorf("unknown operator %d", int(e.Operator))
+		}
+		n.Op = op
+		for k, operand := range e.Operands {
+			i, err := b.node(operand)
+			if err != nil {
+				return 0, err
+			}
+			if k == 0 {
+				n.A = uint64(i)
+			} else {
+				n.B = uint64(i)
+			}
+		}
+	default:
+		return 0, fmt.Errorf("unsupported leaf %T in a verifier constraint", expr)
+	}
+
+	b.spec.Nodes = append(b.spec.Nodes, n)
+	i := len(b.spec.Nodes) - 1
+	b.nodes[expr] = i
+	return i, nil
+}
+
+var opNames = [...]string{
+	standalone.OpCell:    "OpCell",
+	standalone.OpCoin:    "OpCoin",
+	standalone.OpConst:   "OpConst",
+	standalone.OpAdd:     "OpAdd",
+	standalone.OpSub:     "OpSub",
+	standalone.OpMul:     "OpMul",
+	standalone.OpDiv:     "OpDiv",
+	standalone.OpDouble:  "OpDouble",
+	standalone.OpSquare:  "OpSquare",
+	standalone.OpNeg:     "OpNeg",
+	standalone.OpInverse: "OpInverse",
+}
+
+// writeSpec renders spec as the package-level variable system.
+func writeSpec(cw *CodeWriter, spec *standalone.Spec) {
+	ints := func(v []int) string {
+		s := make([]string, len(v))
+		for i, x := range v {
+			s[i] = fmt.Sprint(x)
+		}
+		return strings.Join(s, ", ")
+	}
+
+	cw.Line("var system = &Spec{")
+	cw.In()
+	cw.Line("Name: %q,", spec.Name)
+
+	digest := make([]string, len(spec.Digest))
+	for i, x := range spec.Digest {
+		digest[i] = fmt.Sprintf("0x%02x", x)
+	}
+	cw.Line("Digest: [32]byte{%s},", strings.Join(digest, ", "))
+
+	cw.Line("Columns: []Column{")
+	cw.In()
+	for _, c := range spec.Columns {
+		cw.Line("{ID: %#016x, Size: %d, Name: %q},", c.ID, c.Size, c.Name)
+	}
+	cw.Out()
+	cw.Line("},")
+
+	cw.Line("Cells: []Cell{")
+	cw.In()
+	for _, c := range spec.Cells {
+		cw.Line("{ID: %#016x, Name: %q},", c.ID, c.Name)
+	}
+	cw.Out()
+	cw.Line("},")
+
+	cw.Line("Rounds: []Round{")
+	cw.In()
+	for i, r := range spec.Rounds {
+		cw.Line("// Round %d", i)
+		cw.Line("{Columns: []int{%s}, Cells: []int{%s}, Coins: %d},", ints(r.Columns), ints(r.Cells), r.Coins)
+	}
+	cw.Out()
+	cw.Line("},")
+
+	cw.Line("Nodes: []Node{")
+	cw.In()
+	for i, n := range spec.Nodes {
+		switch n.Op {
+		case standalone.OpCell, standalone.OpCoin, standalone.OpConst,
+			standalone.OpDouble, standalone.OpSquare, standalone.OpNeg, standalone.OpInverse:
+			cw.Line("{Op: %s, A: %d}, // %d", opNames[n.Op], n.A, i)
+		default:
+			cw.Line("{Op: %s, A: %d, B: %d}, // %d", opNames[n.Op], n.A, n.B, i)
+		}
+	}
+	cw.Out()
+	cw.Line("},")
+
+	cw.Line("Constraints: []Constraint{")
+	cw.In()
+	for _, c := range spec.Constraints {
+		cw.Line("{Node: %d, Name: %q},", c.Node, c.Name)
+	}
+	cw.Out()
+	cw.Line("},")
+	cw.Out()
+	cw.Line("}")
+}
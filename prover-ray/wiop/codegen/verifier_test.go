@@ -0,0 +1,184 @@
+package codegen_test
+
+import (
+	"bytes"
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/codegen"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/codegen/standalone"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/codegen/testdata/fibverifier"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/localvanishing"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/logderivativesum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/messagebus"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/mpts"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compileForVerifier(sys *wiop.System) {
+	messagebus.Compile(sys)
+	logderivativesum.Compile(sys)
+	localvanishing.Compile(sys)
+	global.Compile(sys)
+	mpts.Compile(sys)
+}
+
+func hasDynamicModule(sys *wiop.System) bool {
+	for _, m := range sys.Modules {
+		if m.IsDynamic() {
+			return true
+		}
+	}
+	return false
+}
+
+// TestBuildVerifierSpec_AgreesWithVerify checks that the standalone verifier
+// reaches the same verdict as [wiop.System.Verify] on honest and invalid
+// proofs of every static-size scenario.
+func TestBuildVerifierSpec_AgreesWithVerify(t *testing.T) {
+	type scenario struct {
+		name            string
+		sys             *wiop.System
+		honest, invalid func(rt *wiop.Runtime)
+	}
+	var scenarios []func() scenario
+	for _, build := range wioptest.VanishingScenarios() {
+		scenarios = append(scenarios, func() scenario {
+			sc := build()
+			return scenario{"Vanishing/" + sc.Name, sc.Sys, sc.AssignHonest, sc.AssignInvalid}
+		})
+	}
+	for _, build := range wioptest.MessageBusScenarios() {
+		scenarios = append(scenarios, func() scenario {
+			sc := build()
+			return scenario{"MessageBus/" + sc.Name, sc.Sys, sc.AssignHonest, sc.AssignInvalid}
+		})
+	}
+
+	for _, build := range scenarios {
+		sc := build()
+		if hasDynamicModule(sc.sys) {
+			continue
+		}
+		t.Run(sc.name, func(t *testing.T) {
+			for _, invalid := range []bool{false, true} {
+				sc := build()
+				assign := sc.honest
+				if invalid {
+					assign = sc.invalid
+				}
+				compileForVerifier(sc.sys)
+				spec, err := codegen.BuildVerifierSpec(sc.sys)
+				require.NoError(t, err)
+
+				proof := sc.sys.Prove(assign)
+				want := sc.sys.Verify(proof)
+				got := spec.Verify(wire.MarshalProof(sc.sys, proof))
+				assert.Equal(t, want == nil, got == nil,
+					"invalid=%v: System.Verify returned %v, the standalone verifier %v", invalid, want, got)
+			}
+		})
+	}
+}
+
+func fibProofAndSpec(t *testing.T) (*standalone.Spec, []byte) {
+	t.Helper()
+	sys, _ := compiledFibSystem(t)
+	sc := wioptest.NewFibonacciVanishingScenario()
+	global.Compile(sc.Sys)
+	spec, err := codegen.BuildVerifierSpec(sc.Sys)
+	require.NoError(t, err)
+	require.Equal(t, sys.Digest(), spec.Digest, "the digest must not depend on the instance")
+	return spec, wire.MarshalProof(sc.Sys, sc.Sys.Prove(sc.AssignHonest))
+}
+
+func TestBuildVerifierSpec_RejectsTampering(t *testing.T) {
+	spec, proof := fibProofAndSpec(t)
+	require.NoError(t, spec.Verify(proof))
+
+	tampered := bytes.Clone(proof)
+	tampered[len(tampered)-1] ^= 1
+	assert.Error(t, spec.Verify(tampered), "a modified cell must be rejected")
+
+	otherSystem := bytes.Clone(proof)
+	otherSystem[8] ^= 1
+	assert.ErrorIs(t, spec.Verify(otherSystem), standalone.ErrMalformedProof)
+
+	assert.ErrorIs(t, spec.Verify(proof[:len(proof)-1]), standalone.ErrMalformedProof)
+	assert.ErrorIs(t, spec.Verify(append(bytes.Clone(proof), 0)), standalone.ErrMalformedProof)
+}
+
+func TestBuildVerifierSpec_RejectsUncompiledSystem(t *testing.T) {
+	sc := wioptest.NewFibonacciVanishingScenario()
+	_, err := codegen.BuildVerifierSpec(sc.Sys)
+	assert.ErrorContains(t, err, "not reduced")
+}
+
+func TestGenerateVerifier_Source(t *testing.T) {
+	sys, _ := compiledFibSystem(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, codegen.GenerateVerifier(sys, codegen.VerifierOptions{Package: "fibverifier"}, &buf))
+	src := buf.String()
+
+	f, err := parser.ParseFile(token.NewFileSet(), "verifier_gen.go", src, parser.ParseComments)
+	require.NoError(t, err, "generated source must be valid Go:\n%s", src)
+	assert.Equal(t, "fibverifier", f.Name.Name)
+	for _, imp := range f.Imports {
+		assert.False(t, strings.Contains(imp.Path.Value, "prover-ray/wiop"),
+			"the generated verifier must not import wiop, got %s", imp.Path.Value)
+	}
+	assert.Contains(t, src, "func Verify(proof []byte) error")
+	assert.Contains(t, src, "var system = &Spec{")
+
+	var again bytes.Buffer
+	require.NoError(t, codegen.GenerateVerifier(sys, codegen.VerifierOptions{Package: "fibverifier"}, &again))
+	assert.Equal(t, src, again.String(), "GenerateVerifier must be deterministic")
+}
+
+var updateGolden = flag.Bool("update", false, "rewrite "+fibVerifierPath)
+
+// fibVerifierPath holds the output of [codegen.GenerateVerifier] for the
+// compiled Fibonacci scenario. The test binary links it, so the generated
+// code is compiled and run, not only parsed. Regenerate it with
+// `go test ./wiop/codegen -run TestGenerateVerifier_Golden -update`.
+const fibVerifierPath = "testdata/fibverifier/verifier_gen.go"
+
+func TestGenerateVerifier_Golden(t *testing.T) {
+	sys, _ := compiledFibSystem(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, codegen.GenerateVerifier(sys, codegen.VerifierOptions{Package: "fibverifier"}, &buf))
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(fibVerifierPath, buf.Bytes(), 0o600))
+	}
+	golden, err := os.ReadFile(fibVerifierPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), buf.String(), "%s is stale, rerun with -update", fibVerifierPath)
+}
+
+// TestGenerateVerifier_Compiled runs the generated verifier of
+// [fibVerifierPath] on honest and tampered proofs.
+func TestGenerateVerifier_Compiled(t *testing.T) {
+	_, proof := fibProofAndSpec(t)
+	require.NoError(t, fibverifier.Verify(proof))
+
+	tampered := bytes.Clone(proof)
+	tampered[len(tampered)-1] ^= 1
+	assert.Error(t, fibverifier.Verify(tampered), "a modified cell must be rejected")
+
+	otherSystem := bytes.Clone(proof)
+	otherSystem[8] ^= 1
+	assert.Error(t, fibverifier.Verify(otherSystem), "a proof of another system must be rejected")
+
+	assert.Error(t, fibverifier.Verify(proof[:len(proof)-1]), "a truncated proof must be rejected")
+}
@@ -2,6 +2,10 @@
 // [wiop.System]. The output makes the prover's round-by-round structure
 // explicit and human-readable, and eliminates the generic action-dispatch loop.
 //
+// [GenerateVerifier] is the verifier-side counterpart: it writes a standalone
+// Go package that checks wire-encoded proofs of a compiled System without
+// linking wiop.
+//
 // Usage: go run ./wiop/codegen -pkg <pkg> -out <file> [-func <name>]
 // The caller is responsible for building and compiling the System before
 // invoking Generate.
@@ -2,6 +2,8 @@ package global
 
 import (
 	"fmt"
+	"math/bits"
+	"sync"
 
 	"github.com/consensys/gnark-crypto/field/koalabear/fft"
 	gnarkutils "github.com/consensys/gnark-crypto/utils"
@@ -27,7 +29,9 @@ import (
 // This compiler supports dynamic-size modules. The quotient ratio is computed
 // from the expression's DegreeFactor() which doesn't require knowing the module
 // size at compile time. Size-dependent data (FFT domains, annihilator inverses,
-// cancellation cosets) is computed at runtime using RuntimeSize.
+// cancellation cosets) is built on the first run at each RuntimeSize and
+// cached, and the verifier rejects runtime sizes the constraints cannot be
+// checked on (see [minModuleSize]).
 func Compile(sys *wiop.System) {
 	var hasWork bool
 	for _, m := range sys.Modules {
@@ -67,40 +71,81 @@ type rawBucket struct {
 	shares     []*wiop.Column
 }
 
-// proverVanishingEntry bundles a Vanishing with the precomputed base-field
-// evaluations of its cancellation polynomial on the large coset.
-// cancellationCoset[j] = C(g · ω_{N}^j) where g is the multiplicative
-// generator and N = n · ratio. Only populated for static modules.
-type proverVanishingEntry struct {
-	v                 *wiop.Vanishing
-	cancellationCoset []field.Element // length N = n*ratio; nil if no cancellation
-}
-
 // proverBucket holds all compilation artefacts needed by the prover to compute
 // the quotient shares for one ratio bucket.
 //
-// For static modules, size-dependent data (FFT domains, annihilator inverses,
-// cancellation cosets) is precomputed at compile time. For dynamic modules,
-// these fields are nil and the data is computed at runtime using RuntimeSize.
+// For static modules, the size-dependent data is precomputed at compile time
+// into static. For dynamic modules, it is built on the first run at each
+// runtime size and memoised in domains.
 type proverBucket struct {
-	ratio    int
-	rootCols []*wiop.Column // deduplicated root columns from all expressions
-	shares   []*wiop.Column // quotient share columns (length = ratio)
-
-	// --- Static-module fields (nil for dynamic modules) ---
-	entries     []proverVanishingEntry // precomputed cancellation cosets
-	smallDomain *fft.Domain            // FFT domain of size n
-	largeDomain *fft.Domain            // FFT domain of size n*ratio
-	annInv      []field.Element        // 1/(g^n · ω_ratio^j − 1) for j = 0..ratio-1
+	ratio      int
+	vanishings []*wiop.Vanishing
+	rootCols   []*wiop.Column // deduplicated root columns from all expressions
+	shares     []*wiop.Column // quotient share columns (length = ratio)
+	selectors  []int          // distinct LagrangeSelector positions in vanishings
 
-	// --- Dynamic-module fields (nil for static modules) ---
-	vanishings []*wiop.Vanishing // raw vanishings for runtime computation
+	static  *bucketDomain // nil for dynamic modules
+	domains *domainCache  // nil for static modules
 
 	// Pre-allocated scratch slices populated by Plan; nil until Plan is called.
 	// When non-nil, Run uses these instead of allocating fresh memory.
 	scratchAgg []field.Ext // aggregate[j], length N = n*ratio
 }
 
+// bucketDomain is the size-dependent data of one ratio bucket for a module
+// size n, with N = n · ratio.
+type bucketDomain struct {
+	smallDomain *fft.Domain     // FFT domain of size n
+	largeDomain *fft.Domain     // FFT domain of size N
+	annInv      []field.Element // 1/(g^n · ω_ratio^j − 1) for j = 0..ratio-1
+	// cancellations[i] = C_i(g · ω_N^j) for the i-th vanishing of the bucket,
+	// or nil when it cancels no row.
+	cancellations [][]field.Element
+	// selectors maps each LagrangeSelector position to L_position(g · ω_N^j).
+	selectors map[int][]field.Element
+}
+
+// newBucketDomain builds the [bucketDomain] of bkt for the module size n.
+func newBucketDomain(bkt *proverBucket, n int) *bucketDomain {
+	N := n * bkt.ratio
+	d := &bucketDomain{
+		smallDomain:   fft.NewDomain(uint64(n)),
+		largeDomain:   fft.NewDomain(uint64(N)),
+		annInv:        make([]field.Element, bkt.ratio),
+		cancellations: make([][]field.Element, len(bkt.vanishings)),
+		selectors:     make(map[int][]field.Element, len(bkt.selectors)),
+	}
+	field.VecBatchInvBase(d.annInv, polynomials.EvalXnMinusOneOnCoset(n, N))
+	for i, v := range bkt.vanishings {
+		d.cancellations[i] = computeCancellationCoset(v.CancelledPositions, n, N)
+	}
+	for _, pos := range bkt.selectors {
+		d.selectors[pos] = computeLagrangeSelectorCoset(pos, n, N)
+	}
+	return d
+}
+
+// domainCache memoises the [bucketDomain] of a dynamic bucket per runtime
+// size, so that proving many traces of the same height builds the FFT domains
+// once. Sizes are powers of two bounded by the maximal column size, so the
+// cache holds a few dozen entries at most.
+type domainCache struct {
+	mu  sync.Mutex
+	byN map[int]*bucketDomain
+}
+
+// get returns the bucketDomain of bkt for the size n, building it on a miss.
+func (c *domainCache) get(bkt *proverBucket, n int) *bucketDomain {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.byN[n]
+	if !ok {
+		d = newBucketDomain(bkt, n)
+		c.byN[n] = d
+	}
+	return d
+}
+
 // VerifierBucket holds everything the verifier needs for one ratio bucket.
 type VerifierBucket struct {
 	Ratio          int
@@ -135,6 +180,7 @@ func compileModule(
 		if v.IsReduced() {
 			continue
 		}
+		v.MarkAsReduced()
 		r := computeRatio(v)
 		if _, exists := ratioToEntries[r]; !exists {
 			ratioOrder = append(ratioOrder, r)
@@ -153,6 +199,9 @@ func compileModule(
 		for k := range ratio {
 			shareCtx := ctx.Childf("q-r%d-s%d", ratio, k)
 			shares[k] = m.NewExtensionColumn(shareCtx, wiop.VisibilityOracle, quotientRound)
+			// The shares are bound by Q·(X^n − 1) = P at the evaluation point,
+			// not row by row, so random masking rows would break them.
+			shares[k].SkipMasking = true
 		}
 		rawBuckets = append(rawBuckets, rawBucket{ratio: ratio, vanishings: vs, shares: shares})
 	}
@@ -217,12 +266,14 @@ func compileModule(
 	// For static modules, precompute size-dependent data (FFT domains, annihilator
 	// inverses, cancellation cosets). For dynamic modules, defer to runtime.
 	proverBuckets := buildProverBuckets(rawBuckets, m)
+	bounds := newSizeBounds(rawBuckets)
 
 	// --- Step 9: register prover actions ---
 	quotientRound.RegisterAction(&QuotientProverAction{
 		m:         m,
 		mergeCoin: mergeCoin,
 		buckets:   proverBuckets,
+		bounds:    bounds,
 	})
 	evalRound.RegisterAction(&EvalProverAction{
 		lagrangeEvals: allLagrangeEvals,
@@ -245,66 +296,49 @@ func compileModule(
 		WitnessClaims: witnessClaims,
 		viewKeyToIdx:  viewKeyToIdx,
 		Buckets:       vBuckets,
+		bounds:        bounds,
 	})
 }
 
 // buildProverBuckets constructs the prover buckets from the raw bucket
-// descriptions. For static modules, size-dependent data (FFT domains,
-// annihilator inverses, cancellation cosets) is precomputed. For dynamic
-// modules, these are left nil and computed at runtime using RuntimeSize.
+// descriptions. For static modules, the size-dependent data (FFT domains,
+// annihilator inverses, cancellation and selector cosets) is precomputed. For
+// dynamic modules, each bucket gets an empty cache filled at runtime.
 func buildProverBuckets(rawBuckets []rawBucket, m *wiop.Module) []proverBucket {
 	result := make([]proverBucket, len(rawBuckets))
 
-	// For static modules, get n now; for dynamic, n=0 signals runtime computation.
-	var n int
-	if !m.IsDynamic() {
-		n = m.Size()
-	}
-
 	for i, bkt := range rawBuckets {
-		ratio := bkt.ratio
-
-		// Collect deduplicated root columns from all expressions.
+		// Collect deduplicated root columns and selector positions from all
+		// expressions.
 		rootColsSeen := make(map[wiop.ObjectID]*wiop.Column)
+		selectorsSeen := make(map[int]struct{})
 		for _, v := range bkt.vanishings {
 			for _, col := range collectRootColumns(v.Expression) {
 				rootColsSeen[col.Context.ID] = col
 			}
+			collectLagrangeSelectorPositions(v.Expression, selectorsSeen)
 		}
 		rootCols := make([]*wiop.Column, 0, len(rootColsSeen))
 		for _, col := range rootColsSeen {
 			rootCols = append(rootCols, col)
 		}
+		selectors := make([]int, 0, len(selectorsSeen))
+		for pos := range selectorsSeen {
+			selectors = append(selectors, pos)
+		}
 
 		pb := proverBucket{
-			ratio:    ratio,
-			rootCols: rootCols,
-			shares:   bkt.shares,
+			ratio:      bkt.ratio,
+			vanishings: bkt.vanishings,
+			rootCols:   rootCols,
+			shares:     bkt.shares,
+			selectors:  selectors,
 		}
 
 		if m.IsDynamic() {
-			// Dynamic module: store vanishings for runtime computation.
-			pb.vanishings = bkt.vanishings
+			pb.domains = &domainCache{byN: make(map[int]*bucketDomain)}
 		} else {
-			// Static module: precompute size-dependent data.
-			N := n * ratio
-
-			pb.smallDomain = fft.NewDomain(uint64(n))
-			pb.largeDomain = fft.NewDomain(uint64(N))
-
-			// Precompute annihilator inverses: 1/(g^n · ω_ratio^j − 1) for j=0..ratio-1.
-			annVals := polynomials.EvalXnMinusOneOnCoset(n, N)
-			pb.annInv = make([]field.Element, ratio)
-			field.VecBatchInvBase(pb.annInv, annVals)
-
-			// Precompute cancellation polynomial coset evaluations.
-			pb.entries = make([]proverVanishingEntry, len(bkt.vanishings))
-			for j, v := range bkt.vanishings {
-				pb.entries[j] = proverVanishingEntry{
-					v:                 v,
-					cancellationCoset: computeCancellationCoset(v.CancelledPositions, n, N),
-				}
-			}
+			pb.static = newBucketDomain(&pb, m.Size())
 		}
 
 		result[i] = pb
@@ -312,6 +346,67 @@ func buildProverBuckets(rawBuckets []rawBucket, m *wiop.Module) []proverBucket {
 	return result
 }
 
+// sizeBounds are the runtime sizes a dynamic module can take for its
+// vanishings to be compiled by this pass.
+type sizeBounds struct {
+	// min is the smallest size, a power of two. Every cancelled row must be
+	// a distinct row of the domain and every Lagrange selector must point
+	// inside it. Non-negative positions count from the first row and negative
+	// ones from the last, so cancelling rows {0, 1} and {−1} takes 4 rows.
+	// This also covers the bound assumed by [computeRatio]: n is at least the
+	// number of cancelled rows of any vanishing.
+	min int
+	// maxRatio is the largest quotient ratio of the module. The coset of size
+	// n · maxRatio must fit in the 2-adic subgroup of the field.
+	maxRatio int
+}
+
+func newSizeBounds(buckets []rawBucket) sizeBounds {
+	var head, tail, selector, maxRatio int
+	for _, bkt := range buckets {
+		maxRatio = max(maxRatio, bkt.ratio)
+		for _, v := range bkt.vanishings {
+			for _, pos := range v.CancelledPositions {
+				if pos < 0 {
+					tail = max(tail, -pos)
+				} else {
+					head = max(head, pos+1)
+				}
+			}
+			positions := make(map[int]struct{})
+			collectLagrangeSelectorPositions(v.Expression, positions)
+			for pos := range positions {
+				if pos < 0 {
+					selector = max(selector, -pos)
+				} else {
+					selector = max(selector, pos+1)
+				}
+			}
+		}
+	}
+	return sizeBounds{
+		min:      utils.NextPowerOfTwo(max(1, head+tail, selector)),
+		maxRatio: maxRatio,
+	}
+}
+
+// check returns an error if n is not a valid runtime size of m.
+func (b sizeBounds) check(m *wiop.Module, n int) error {
+	if n < b.min {
+		return fmt.Errorf(
+			"wiop/compilers: global quotient: module %q has runtime size %d, but its constraints need at least %d rows",
+			m.Context.Path(), n, b.min,
+		)
+	}
+	if n*b.maxRatio > 1<<field.MaxOrderRoot {
+		return fmt.Errorf(
+			"wiop/compilers: global quotient: module %q has runtime size %d, its quotient coset of size %d exceeds the 2-adicity of the field",
+			m.Context.Path(), n, n*b.maxRatio,
+		)
+	}
+	return nil
+}
+
 // computeCancellationCoset returns the base-field evaluation of the
 // cancellation polynomial C(X) = Π_{k ∈ cancelled} (X − ω_n^{norm(k)}) at
 // all N = n·ratio coset points {g · ω_N^j : j = 0…N-1}. Returns nil when
@@ -431,20 +526,6 @@ func collectLagrangeSelectorPositions(expr wiop.Expression, out map[int]struct{}
 	}
 }
 
-// bktVanishings returns the Vanishing constraints of a bucket, regardless of
-// whether it is a static bucket (size-dependent data precomputed into entries)
-// or a dynamic bucket (raw vanishings deferred to runtime).
-func bktVanishings(bkt *proverBucket) []*wiop.Vanishing {
-	if bkt.entries != nil {
-		vs := make([]*wiop.Vanishing, len(bkt.entries))
-		for i, e := range bkt.entries {
-			vs[i] = e.v
-		}
-		return vs
-	}
-	return bkt.vanishings
-}
-
 // ---------------------------------------------------------------------------
 // Prover actions
 // ---------------------------------------------------------------------------
@@ -455,6 +536,7 @@ type QuotientProverAction struct {
 	m         *wiop.Module
 	mergeCoin *wiop.CoinField
 	buckets   []proverBucket
+	bounds    sizeBounds
 }
 
 // Plan pre-allocates scratch buffers for each ratio bucket from the planning
@@ -475,7 +557,8 @@ func (a *QuotientProverAction) Plan(ctx *wiop.PlanningContext) {
 
 // Run executes the quotient polynomial computation and assigns quotient share columns.
 // For static modules, uses precomputed domains and scratch buffers. For dynamic
-// modules, computes size-dependent data at runtime using RuntimeSize.
+// modules, uses the domains cached for the RuntimeSize, building them on the
+// first run at that size.
 func (a *QuotientProverAction) Run(rt wiop.Runtime) {
 	n := a.m.RuntimeSize(rt)
 
@@ -486,29 +569,23 @@ func (a *QuotientProverAction) Run(rt wiop.Runtime) {
 			a.m.Size(),
 		))
 	}
+	if a.m.IsDynamic() {
+		if err := a.bounds.check(a.m, n); err != nil {
+			panic(err.Error())
+		}
+	}
 	coinExt := rt.GetCoinValue(a.mergeCoin).Ext
 
-	for _, bkt := range a.buckets {
+	for i := range a.buckets {
+		bkt := &a.buckets[i]
 		ratio := bkt.ratio
 		N := n * ratio
 
-		// Get or compute FFT domains and annihilator inverses.
-		var smallDomain, largeDomain *fft.Domain
-		var annInv []field.Element
-
-		if bkt.smallDomain != nil {
-			// Static module: use precomputed values.
-			smallDomain = bkt.smallDomain
-			largeDomain = bkt.largeDomain
-			annInv = bkt.annInv
-		} else {
-			// Dynamic module: compute at runtime.
-			smallDomain = fft.NewDomain(uint64(n))
-			largeDomain = fft.NewDomain(uint64(N))
-			annVals := polynomials.EvalXnMinusOneOnCoset(n, N)
-			annInv = make([]field.Element, ratio)
-			field.VecBatchInvBase(annInv, annVals)
+		dom := bkt.static
+		if dom == nil {
+			dom = bkt.domains.get(bkt, n)
 		}
+		smallDomain, largeDomain := dom.smallDomain, dom.largeDomain
 
 		// --- Evaluate all root columns on the large coset ---
 		// cosetEvals[colID][j] = col evaluated at coset point j (base-field
@@ -529,18 +606,6 @@ func (a *QuotientProverAction) Run(rt wiop.Runtime) {
 			}
 		}
 
-		// --- Evaluate every distinct Lagrange selector on the large coset ---
-		// Selectors are not committed columns, so they are computed analytically
-		// rather than re-FFT'd. selectorCosets[position][j] = L_position(coset_j).
-		selectorPositions := make(map[int]struct{})
-		for _, v := range bktVanishings(&bkt) {
-			collectLagrangeSelectorPositions(v.Expression, selectorPositions)
-		}
-		selectorCosets := make(map[int][]field.Element, len(selectorPositions))
-		for pos := range selectorPositions {
-			selectorCosets[pos] = computeLagrangeSelectorCoset(pos, n, N)
-		}
-
 		// --- Compute the aggregate extension-field polynomial on the coset ---
 		// aggregate[j] = Σ_i coin^i · P_i(coset_j) · C_i(coset_j)
 		//
@@ -556,32 +621,22 @@ func (a *QuotientProverAction) Run(rt wiop.Runtime) {
 		var coinPow field.Ext
 		coinPow.SetOne()
 
-		if bkt.entries != nil {
-			// Static module: use precomputed cancellation cosets.
-			for _, entry := range bkt.entries {
-				accumulateOnCoset(
-					rt, entry.v.Expression, cosetEvals, cosetEvalsExt, selectorCosets,
-					entry.cancellationCoset, &coinPow, aggregate, ratio, N,
-				)
-				// advance coinPow: coinPow *= coinExt
-				coinPow.Mul(&coinPow, &coinExt)
-			}
-		} else {
-			// Dynamic module: compute cancellation cosets at runtime.
-			for _, v := range bkt.vanishings {
-				cancellationCoset := computeCancellationCoset(v.CancelledPositions, n, N)
-				accumulateOnCoset(
-					rt, v.Expression, cosetEvals, cosetEvalsExt, selectorCosets,
-					cancellationCoset, &coinPow, aggregate, ratio, N,
-				)
-				coinPow.Mul(&coinPow, &coinExt)
-			}
+		// Selectors are not committed columns: their coset evaluations are
+		// computed analytically with the rest of the bucket domain.
+		// dom.selectors[position][j] = L_position(coset_j).
+		for k, v := range bkt.vanishings {
+			accumulateOnCoset(
+				rt, v.Expression, cosetEvals, cosetEvalsExt, dom.selectors,
+				dom.cancellations[k], &coinPow, aggregate, ratio, N,
+			)
+			// advance coinPow: coinPow *= coinExt
+			coinPow.Mul(&coinPow, &coinExt)
 		}
 
 		// --- Divide by annihilator (x^n − 1) at each coset point ---
 		// annihilator at point j is annInv[j % ratio] (already inverted).
 		for j := 0; j < N; j++ {
-			aggregate[j].MulByElement(&aggregate[j], &annInv[j%ratio])
+			aggregate[j].MulByElement(&aggregate[j], &dom.annInv[j%ratio])
 		}
 
 		// --- IFFT on the large coset: coset evals → canonical coefficients ---
@@ -733,9 +788,12 @@ type Verifier struct {
 	WitnessClaims []*wiop.Cell
 	viewKeyToIdx  map[colViewKey]int
 	Buckets       []VerifierBucket
+	bounds        sizeBounds
 }
 
 // Check verifies the PLONK quotient identity for the module using the runtime's claimed values.
+// For a dynamic module, it first checks that the size declared by the proof
+// is one the constraints can be checked on.
 func (gv *Verifier) Check(rt wiop.Runtime) error {
 	n := gv.Module.RuntimeSize(rt)
 
@@ -746,6 +804,11 @@ func (gv *Verifier) Check(rt wiop.Runtime) error {
 			gv.Module.Size(),
 		))
 	}
+	if gv.Module.IsDynamic() {
+		if err := gv.bounds.check(gv.Module, n); err != nil {
+			return err
+		}
+	}
 	r := rt.GetCoinValue(gv.EvalCoin)
 	coinExt := rt.GetCoinValue(gv.MergeCoin).Ext
 
@@ -808,6 +871,118 @@ func (gv *Verifier) Check(rt wiop.Runtime) error {
 	return nil
 }
 
+// Constraints implements [wiop.ArithmeticVerifierAction]. It spells out the
+// identity verified by Check, P_agg(r) − (r^n − 1)·Q(r), once per bucket, with
+// the witness evaluations read from the claim cells. The exponent n is baked
+// into the expressions, so an error is returned for a dynamic module.
+func (gv *Verifier) Constraints() ([]wiop.Expression, error) {
+	if gv.Module.IsDynamic() {
+		return nil, fmt.Errorf(
+			"wiop/compilers: global quotient constraints require a statically sized module, got %q",
+			gv.Module.Context.Path(),
+		)
+	}
+
+	var (
+		n           = gv.Module.Size()
+		r           = wiop.Expression(gv.EvalCoin)
+		rPowN       = r
+		viewClaims  = make(map[colViewKey]*wiop.Cell, len(gv.WitnessViews))
+		constraints = make([]wiop.Expression, 0, len(gv.Buckets))
+	)
+	for i := 0; i < bits.TrailingZeros(uint(n)); i++ {
+		rPowN = wiop.Square(rPowN)
+	}
+	annihilator := wiop.Sub(rPowN, wiop.NewConstantField(field.One()))
+
+	for i, cv := range gv.WitnessViews {
+		key := colViewKey{id: cv.Column.Context.ID, shift: cv.ShiftingOffset}
+		viewClaims[key] = gv.WitnessClaims[i]
+	}
+
+	for _, bkt := range gv.Buckets {
+		// Q(r) = Σ_k r^{kn} · Q_k(r), in Horner form.
+		var qr wiop.Expression
+		for k := len(bkt.QuotientClaims) - 1; k >= 0; k-- {
+			if qr == nil {
+				qr = bkt.QuotientClaims[k]
+				continue
+			}
+			qr = wiop.Add(wiop.Mul(qr, rPowN), bkt.QuotientClaims[k])
+		}
+
+		// P_agg(r) = Σ_i coin^i · P_i(r) · C_i(r), in Horner form.
+		var pagg wiop.Expression
+		for i := len(bkt.Vanishings) - 1; i >= 0; i-- {
+			v := bkt.Vanishings[i]
+			term := exprAtPoint(v.Expression, viewClaims, r, annihilator, n)
+			if len(v.CancelledPositions) > 0 {
+				term = wiop.Mul(term, cancellationAtPoint(v.CancelledPositions, n, r))
+			}
+			if pagg == nil {
+				pagg = term
+				continue
+			}
+			pagg = wiop.Add(wiop.Mul(pagg, gv.MergeCoin), term)
+		}
+
+		constraints = append(constraints, wiop.Sub(pagg, wiop.Mul(annihilator, qr)))
+	}
+	return constraints, nil
+}
+
+// exprAtPoint is the symbolic counterpart of evalExprAtPoint: column views are
+// replaced by their claim cells and Lagrange selectors by their closed form
+// ω^k · (r^n − 1) / (n · (r − ω^k)). annihilator is the expression of r^n − 1.
+func exprAtPoint(
+	expr wiop.Expression,
+	viewClaims map[colViewKey]*wiop.Cell,
+	r, annihilator wiop.Expression,
+	n int,
+) wiop.Expression {
+	return wiop.EditExpression(expr, func(curr wiop.Expression, newChildren []wiop.Expression) wiop.Expression {
+		switch e := curr.(type) {
+		case *wiop.ColumnView:
+			claim, ok := viewClaims[colViewKey{id: e.Column.Context.ID, shift: e.ShiftingOffset}]
+			if !ok {
+				panic(fmt.Sprintf(
+					"wiop/compilers: ColumnView (%v, shift=%d) not in witness eval map",
+					e.Column.Context.ID, e.ShiftingOffset,
+				))
+			}
+			return claim
+		case *wiop.LagrangeSelector:
+			var omegaPos, nElem field.Element
+			omegaPos.ExpInt64(field.RootOfUnityBy(n), int64(e.Position))
+			nElem.SetUint64(uint64(n))
+			return wiop.Div(
+				wiop.Mul(wiop.NewConstantField(omegaPos), annihilator),
+				wiop.Mul(wiop.NewConstantField(nElem), wiop.Sub(r, wiop.NewConstantField(omegaPos))),
+			)
+		case *wiop.Constant:
+			// A vector constant is the constant polynomial.
+			return wiop.NewConstantField(e.Value)
+		}
+		return wiop.DefaultConstruct(curr, newChildren)
+	})
+}
+
+// cancellationAtPoint is the symbolic counterpart of evalCancellationAtPoint.
+func cancellationAtPoint(cancelled []int, n int, r wiop.Expression) wiop.Expression {
+	omega := field.RootOfUnityBy(n)
+	factors := make([]wiop.Expression, len(cancelled))
+	for i, pos := range cancelled {
+		k := pos
+		if k < 0 {
+			k = n + pos
+		}
+		var omegaK field.Element
+		field.ExpToInt(&omegaK, omega, k)
+		factors[i] = wiop.Sub(r, wiop.NewConstantField(omegaK))
+	}
+	return wiop.Product(factors...)
+}
+
 // computeAnnihilator computes r^n − 1.
 func computeAnnihilator(r field.Gen, n int) field.Gen {
 	return expFieldElem(r, n).Sub(field.ElemOne())
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/utils"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
@@ -118,3 +119,81 @@ func TestCompile_GlobalConstraintWithLagrangeSelector(t *testing.T) {
 		assert.Error(t, sys.Verify(proof), "invalid: (a·b)[pos]=15 must be rejected")
 	})
 }
+
+// newCounter builds the columns of a counter of the given length: d holds the
+// increments and a the running count. Both are padded past their data so
+// that the counter stays constant on the padded rows.
+func newCounter(length int, increments func(i int) uint64) (a, d *wiop.ConcreteVector) {
+	as := make([]field.Element, length)
+	ds := make([]field.Element, length)
+	var count uint64
+	for i := range length {
+		if i > 0 {
+			count += increments(i)
+		}
+		ds[i].SetUint64(increments(i))
+		as[i].SetUint64(count)
+	}
+	a = &wiop.ConcreteVector{Plain: field.VecFromBase(as), Padding: as[length-1]}
+	d = &wiop.ConcreteVector{Plain: field.VecFromBase(ds)}
+	return a, d
+}
+
+// TestCompile_DynamicModuleSizes compiles one system with a dynamic module
+// and proves it at several heights, including heights that are not powers of
+// two and repeated ones. The module carries a shifted constraint (ratio 1) and
+// a cubic one (ratio 2), so both quotient chunkings are exercised at every
+// size.
+func TestCompile_DynamicModuleSizes(t *testing.T) {
+	sys := wiop.NewSystemf("gl-dyn-sizes")
+	r0 := sys.NewRound()
+	mod := sys.NewDynamicModule(sys.Context.Childf("mod"), wiop.PaddingDirectionRight)
+	a := mod.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	d := mod.NewColumn(sys.Context.Childf("d"), wiop.VisibilityOracle, r0)
+	one := wiop.NewConstantField(field.NewFromString("1"))
+	// a[i] − a[i−1] − d[i] = 0 for every row but the first.
+	mod.NewVanishing(sys.Context.Childf("count"), wiop.Sub(wiop.Sub(a.View(), a.View().Shift(-1)), d.View()))
+	// d · (d − 1) · (d + 1) = 0: d is 0 or 1 (or -1).
+	mod.NewVanishing(sys.Context.Childf("bool"), wiop.Mul(wiop.Mul(d.View(), wiop.Sub(d.View(), one)), wiop.Add(d.View(), one)))
+	global.Compile(sys)
+
+	for _, length := range []int{2, 3, 4, 5, 7, 16, 100} {
+		assign := func(incr func(int) uint64) func(*wiop.Runtime) {
+			return func(rt *wiop.Runtime) {
+				av, dv := newCounter(length, incr)
+				rt.AssignColumn(a, av)
+				rt.AssignColumn(d, dv)
+			}
+		}
+
+		proof := sys.Prove(assign(func(i int) uint64 { return uint64(i % 2) }))
+		require.Equal(t, utils.NextPowerOfTwo(length), proof.DynamicSizes[0], "length=%d", length)
+		require.NoError(t, sys.Verify(proof), "length=%d: honest counter must verify", length)
+
+		invalid := sys.Prove(assign(func(i int) uint64 { return uint64(2 * (i % 2)) }))
+		assert.Error(t, sys.Verify(invalid), "length=%d: an increment of 2 must be rejected", length)
+
+		proof.DynamicSizes = map[int]int{0: 2 * proof.DynamicSizes[0]}
+		assert.Error(t, sys.Verify(proof), "length=%d: a proof checked on another domain must be rejected", length)
+	}
+}
+
+// TestCompile_DynamicModuleMinimalSize checks that a dynamic module whose
+// constraints cancel more rows than its runtime size holds is rejected rather
+// than proven on a domain where the cancelled rows overlap.
+func TestCompile_DynamicModuleMinimalSize(t *testing.T) {
+	sys := wiop.NewSystemf("gl-dyn-min")
+	r0 := sys.NewRound()
+	mod := sys.NewDynamicModule(sys.Context.Childf("mod"), wiop.PaddingDirectionRight)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	// Shifts −2 and +1 cancel rows {0, 1} and {−1}: at least 4 rows.
+	mod.NewVanishing(sys.Context.Childf("v"), wiop.Sub(col.View().Shift(-2), col.View().Shift(1)))
+	global.Compile(sys)
+
+	assert.Panics(t, func() {
+		sys.Prove(func(rt *wiop.Runtime) { rt.AssignColumn(col, colVec(2, 0, 9)) })
+	}, "the prover cannot run on 2 rows")
+
+	proof := sys.Prove(func(rt *wiop.Runtime) { rt.AssignColumn(col, colVec(4, 0, 9)) })
+	require.NoError(t, sys.Verify(proof))
+}
@@ -17,7 +17,9 @@
 //     packingArity fractions whose vector-valued sides live on the same module;
 //   - a vanishing recurrence per Z column linking it to its source fractions;
 //   - a local constraint pinning the row-0 boundary of each Z column;
-//   - an opening of Z[n-1] (column endpoint) per Z column;
+//   - an opening of Z[u-1] (column endpoint) per Z column, where u is the
+//     number of usable rows of its module: n, or n − k on a module with k
+//     masking rows;
 //   - a verifier action that checks the sum of endpoints matches the query's
 //     claimed Result cell.
 //
@@ -30,6 +32,11 @@
 // callers should ensure denominators are non-zero on every row (typically by
 // binding them to a randomness coin) so that the recurrence uniquely pins
 // down Z.
+//
+// On a module with masking rows (see [wiop.Module.SetMaskingRows]), the running
+// sum stops at the last usable row: the recurrence is cancelled on the masking
+// rows, which the prover leaves to [wiop.System.Prove] to fill with randomness,
+// and the endpoint is opened before them.
 package logderivativesum
 
 import (
@@ -133,12 +140,16 @@ func fractionModule(f wiop.Fraction) *wiop.Module {
 type zEntry struct {
 	zCol   *wiop.Column
 	packed []wiop.Fraction // raw fractions used by the prover for filter-aware evaluation
-	zFinal *wiop.Cell      // lazily-assigned opening of Z[n-1]
+	zFinal *wiop.Cell      // lazily-assigned opening of Z[u-1]
 }
 
 // buildZ allocates one Z column for a packed fraction group, registers the
 // recurrence Vanishing, pins the row-0 boundary with a local constraint, and
 // opens the column endpoint.
+//
+// On a dynamic module, n is only known at runtime: the recurrence is always
+// registered and the endpoint is opened at row −1−k, which the opening
+// resolves against the runtime size.
 func buildZ(
 	m *wiop.Module,
 	packed []wiop.Fraction,
@@ -146,8 +157,11 @@ func buildZ(
 	ctx *wiop.ContextFrame,
 	bIdx, kIdx int,
 ) zEntry {
-	n := m.Size()
-	if n <= 0 {
+	n, last := m.Size(), m.Size()-m.MaskingRows()-1
+	switch {
+	case m.IsDynamic():
+		last = -m.MaskingRows() - 1
+	case n <= 0:
 		panic(fmt.Sprintf(
 			"wiop/compilers/logderivativesum: module %q must be sized before Compile",
 			m.Context.Path(),
@@ -163,9 +177,12 @@ func buildZ(
 	)
 
 	// The recurrence zNum − (Z − Z<<−1)·zDen carries a −1 shift on Z, so
-	// NewVanishing automatically cancels row 0. For a single-row module the
-	// recurrence is vacuous: skip it.
-	if n > 1 {
+	// NewVanishing automatically cancels row 0, and the masking rows if any.
+	// For a single-row module the
+	// recurrence is vacuous: skip it. A dynamic module may have a single row
+	// at runtime, where the recurrence holds trivially since row 0 is
+	// cancelled.
+	if n > 1 || m.IsDynamic() {
 		zView := zCol.View()
 		recurrence := wiop.Sub(
 			zNum,
@@ -191,7 +208,7 @@ func buildZ(
 		0,
 	)
 
-	zFinal := zCol.At(n - 1).Open(ctx.Childf("z-final-b%d-k%d", bIdx, kIdx))
+	zFinal := zCol.At(last).Open(ctx.Childf("z-final-b%d-k%d", bIdx, kIdx))
 
 	return zEntry{
 		zCol:   zCol,
@@ -246,16 +263,19 @@ func (a *proverAction) Run(rt wiop.Runtime) {
 	var total field.Ext
 
 	for _, e := range a.entries {
-		n := e.zCol.Module.RuntimeSize(rt)
-		z := computeFilteredPrefixSum(rt, e.packed, n)
+		var (
+			m = e.zCol.Module
+			u = m.UsableSize(rt)
+			z = computeFilteredPrefixSum(rt, e.packed, m.RuntimeSize(rt), u)
+		)
 
 		rt.AssignColumn(e.zCol, &wiop.ConcreteVector{Plain: field.VecFromExt(z)})
 
-		// zFinal is a lazy opening of Z[n-1]; it resolves from this column
+		// zFinal is a lazy opening of Z[u-1]; it resolves from this column
 		// assignment on first read (or at round advance), so no explicit
 		// assignment is needed here.
 
-		total.Add(&total, &z[n-1])
+		total.Add(&total, &z[u-1])
 	}
 
 	if !rt.HasCellAssignment(a.ld.Result) {
@@ -267,14 +287,15 @@ func (a *proverAction) Run(rt wiop.Runtime) {
 //
 //	Z[i] = Σ_{k≤i, j} F_j[k] · N_j[k] / D_j[k]
 //
-// over the rows of a packed fraction group, skipping rows where the
-// fraction's filter is zero. Each fraction's denominator is batch-inverted
+// over the first u of the n rows of a packed fraction group, skipping rows
+// where the fraction's filter is zero. The remaining rows are the masking
+// rows of the module and are left at zero. Each fraction's denominator is batch-inverted
 // once; the inverse is consulted only at active rows so a zero denominator at
 // a filtered-out row is benign.
 //
 // Panics if a fraction's denominator is zero on a row where its filter is
 // non-zero, since that input is malformed.
-func computeFilteredPrefixSum(rt wiop.Runtime, packed []wiop.Fraction, n int) []field.Ext {
+func computeFilteredPrefixSum(rt wiop.Runtime, packed []wiop.Fraction, n, u int) []field.Ext {
 	type evalFrac struct {
 		filter []field.Ext // nil ⇒ filter is the constant 1 on every row
 		num    []field.Ext
@@ -295,7 +316,7 @@ func computeFilteredPrefixSum(rt wiop.Runtime, packed []wiop.Fraction, n int) []
 
 	z := make([]field.Ext, n)
 	var running, term field.Ext
-	for i := 0; i < n; i++ {
+	for i := 0; i < u; i++ {
 		for j := range fracs {
 			if fracs[j].filter != nil && fracs[j].filter[i].IsZero() {
 				continue
@@ -375,7 +396,7 @@ func genToExt(v field.Gen) field.Ext {
 }
 
 // verifierAction enforces the only boundary identity that is not already
-// pinned in-circuit: the sum of all Z[n-1] endpoint openings equals the
+// pinned in-circuit: the sum of all Z[u-1] endpoint openings equals the
 // claimed Result cell value. The per-Z initial condition is enforced by the
 // row-0 local constraint registered in buildZ, so this action reads only
 // local openings (cells) — never the oracle witness columns.
@@ -404,3 +425,14 @@ func (a *verifierAction) Check(rt wiop.Runtime) error {
 	}
 	return nil
 }
+
+// Constraints implements [wiop.ArithmeticVerifierAction]: the sum of the
+// endpoint openings minus the claimed Result.
+func (a *verifierAction) Constraints() ([]wiop.Expression, error) {
+	terms := make([]wiop.Expression, 0, len(a.entries)+1)
+	for _, e := range a.entries {
+		terms = append(terms, e.zFinal)
+	}
+	terms = append(terms, wiop.Negate(a.ld.Result))
+	return []wiop.Expression{wiop.Sum(terms...)}, nil
+}
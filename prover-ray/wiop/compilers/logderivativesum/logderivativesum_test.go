@@ -281,6 +281,37 @@ func TestCompile_NoQueries(t *testing.T) {
 	}
 }
 
+// TestCompile_MaskedModule checks that the running sum of fractions on a
+// module with masking rows stops at the last usable row, whatever the values
+// of the masking rows.
+func TestCompile_MaskedModule(t *testing.T) {
+	sys := wiop.NewSystemf("ld2-masked")
+	r0 := sys.NewRound()
+	sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 8, wiop.PaddingDirectionNone)
+	mod.SetMaskingRows(2)
+	c := mod.NewColumn(sys.Context.Childf("c"), wiop.VisibilityOracle, r0)
+	one := wiop.NewConstantVector(mod, field.NewFromString("1"))
+	ld := sys.NewLogDerivativeSum(sys.Context.Childf("ld2"), []wiop.Fraction{{Numerator: c.View(), Denominator: one}})
+
+	logderivativesum.Compile(sys)
+	require.True(t, ld.IsReduced())
+
+	// Rows 0..5 sum to 6; the masking rows 6 and 7 must not count.
+	proof := sys.Prove(func(rt *wiop.Runtime) {
+		vals := make([]field.Element, 8)
+		for i := range vals {
+			vals[i].SetUint64(1)
+		}
+		rt.AssignColumn(c, &wiop.ConcreteVector{Plain: field.VecFromBase(vals)})
+	})
+	require.NoError(t, sys.Verify(proof))
+
+	var want field.Element
+	want.SetUint64(6)
+	assert.Equal(t, field.ElemFromBase(want).AsExt(), proof.Cells[ld.Result.Context.ID].AsExt())
+}
+
 // TestCompile_PacksFractions verifies that 4 filtered fractions on the same
 // module are packed into ⌈4/3⌉ = 2 Z columns.
 func TestCompile_PacksFractions(t *testing.T) {
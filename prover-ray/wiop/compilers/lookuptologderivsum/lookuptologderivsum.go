@@ -431,3 +431,8 @@ func (a *resultIsZeroVerifierAction) Check(rt wiop.Runtime) error {
 	}
 	return nil
 }
+
+// Constraints implements [wiop.ArithmeticVerifierAction].
+func (a *resultIsZeroVerifierAction) Constraints() ([]wiop.Expression, error) {
+	return []wiop.Expression{a.ld.Result}, nil
+}
@@ -18,7 +18,9 @@ import (
 // the matching B row. If multiple B rows hash to the same value, the
 // highest-index row gets the count — matching the linea/logderivativesum
 // "preserve the latest occurrence" convention. Filtered-out B rows
-// (selector = 0) keep M = 0 by construction.
+// (selector = 0) keep M = 0 by construction. The masking rows of the A and B
+// modules (see [wiop.Module.SetMaskingRows]) are skipped, as they are by the
+// LogDerivativeSum reduction.
 //
 // Hash collisions in the internal hash function would only mis-direct
 // multiplicity counts within the prover; they cannot break soundness because
@@ -60,7 +62,7 @@ func (t *mAssignmentTask) Run(rt wiop.Runtime) {
 	// "head" is the selector value itself, so a filtered-out B row hashes
 	// differently from any A row whose head is 1.
 	bMap := make(map[field.Ext]int, n)
-	for i := 0; i < n; i++ {
+	for i := range t.m.Module.UsableSize(rt) {
 		var head field.Ext
 		if t.prependOneOnAOk {
 			// B-side head is the selector value (0 for filtered rows).
@@ -82,7 +84,7 @@ func (t *mAssignmentTask) Run(rt wiop.Runtime) {
 			aSelectorExt = evaluateColumnViewAsExt(rt, inc.selector, an)
 		}
 
-		for j := 0; j < an; j++ {
+		for j := range inc.cols[0].Module().UsableSize(rt) {
 			if inc.selector != nil {
 				if aSelectorExt[j].IsZero() {
 					continue
@@ -0,0 +1,168 @@
+// Package messagebus compiles every unreduced [wiop.MessageBus] into a
+// [wiop.LogDerivativeSum] whose result is asserted to be zero, following the
+// same log-derivative argument as the lookuptologderivsum pass.
+//
+// For a bus of width w, two extension-field coins are sampled once every
+// column of the bus is committed:
+//
+//   - α — only when w > 1, to fold each tuple into a single field element via
+//     the random linear combination t_0 + α·t_1 + … + α^{w-1}·t_{w-1};
+//   - γ — to randomise the denominators γ + RLC(tuple).
+//
+// Every participant then contributes one fraction over its own module:
+//
+//	Σ_row  m(row) / (γ + RLC(tuple(row)))   for a sender
+//	Σ_row −m(row) / (γ + RLC(tuple(row)))   for a receiver
+//
+// where m is the participant's multiplicity (1 when unset). The fractions sum
+// to zero, with overwhelming probability over α and γ, iff every tuple is
+// sent and received the same number of times. Unlike a lookup, the
+// multiplicities are part of the statement, so the pass needs no prover
+// action of its own: the LogDerivativeSum is assigned by the downstream
+// logderivativesum pass.
+//
+// Each bus gets its own coins, LogDerivativeSum and verifier action, so buses
+// committed in different rounds do not delay one another.
+//
+// Caller order: invoke messagebus.Compile(sys) BEFORE
+// logderivativesum.Compile(sys), which consumes the emitted queries.
+package messagebus
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// Compile reduces every unreduced [wiop.MessageBus] in sys to a
+// [wiop.LogDerivativeSum] plus a verifier action asserting that its result is
+// zero, and marks the bus as reduced. If sys contains no eligible queries the
+// function is a no-op.
+//
+// Panics if a bus only involves precomputed columns and sys has no
+// interactive round to sample the coins after.
+func Compile(sys *wiop.System) {
+	compCtx := sys.Context.Childf("messagebus")
+	for i, bus := range sys.MessageBuses {
+		if bus.IsReduced() {
+			continue
+		}
+		compileBus(sys, compCtx.Childf("bus[%d]", i), bus)
+		bus.MarkAsReduced()
+	}
+}
+
+// compileBus emits the coins, fractions, LogDerivativeSum and verifier action
+// of a single bus.
+func compileBus(sys *wiop.System, ctx *wiop.ContextFrame, bus *wiop.MessageBus) {
+	// The coins must be sampled after every column of the bus is committed.
+	// Columns of the precomputed round are committed before the first
+	// interactive round.
+	witnessRound := bus.Round()
+	if witnessRound == nil || isPrecomputedRound(witnessRound) {
+		if len(sys.Rounds) == 0 {
+			panic(fmt.Sprintf(
+				"wiop/compilers/messagebus: cannot compile bus %q against a system with no interactive rounds; "+
+					"call sys.NewRound() first",
+				bus.Context().Path(),
+			))
+		}
+		witnessRound = sys.Rounds[0]
+	}
+	coinRound := ensureNextRound(sys, witnessRound)
+	ensureNextRound(sys, coinRound) // result round; the LogDerivativeSum constructor finds it on its own.
+
+	gamma := coinRound.NewCoinField(ctx.Childf("gamma"))
+	var alpha *wiop.CoinField
+	if bus.Width() > 1 {
+		alpha = coinRound.NewCoinField(ctx.Childf("alpha"))
+	}
+
+	fractions := make([]wiop.Fraction, 0, len(bus.Sends)+len(bus.Receives))
+	for _, msg := range bus.Sends {
+		fractions = append(fractions, messageFraction(msg, alpha, gamma, false))
+	}
+	for _, msg := range bus.Receives {
+		fractions = append(fractions, messageFraction(msg, alpha, gamma, true))
+	}
+
+	ld := sys.NewLogDerivativeSum(ctx.Childf("logderiv"), fractions)
+	ld.Result.Round().RegisterVerifierAction(&resultIsZeroVerifierAction{bus: bus, ld: ld})
+}
+
+// messageFraction returns the fraction ±m / (γ + RLC(tuple)) of a participant,
+// negated for receivers. An unset multiplicity is broadcast as a constant
+// vector over the participant's module, so the fraction stays vector-valued
+// on its numerator side.
+func messageFraction(msg wiop.BusMessage, alpha, gamma *wiop.CoinField, receive bool) wiop.Fraction {
+	num := msg.Multiplicity
+	if num == nil {
+		one := field.One()
+		if receive {
+			one.Neg(&one)
+		}
+		num = wiop.NewConstantVector(msg.Module(), one)
+	} else if receive {
+		num = wiop.Negate(num)
+	}
+	return wiop.Fraction{
+		Numerator:   num,
+		Denominator: wiop.Add(gamma, rlcExpression(alpha, msg.Tuple)),
+	}
+}
+
+// rlcExpression returns exprs[0] + α·exprs[1] + α²·exprs[2] + … in Horner
+// form. When alpha is nil the slice must have exactly one element, which is
+// returned directly.
+func rlcExpression(alpha *wiop.CoinField, exprs []wiop.Expression) wiop.Expression {
+	if alpha == nil {
+		if len(exprs) != 1 {
+			panic("wiop/compilers/messagebus: alpha is nil but width > 1")
+		}
+		return exprs[0]
+	}
+	acc := exprs[len(exprs)-1]
+	for i := len(exprs) - 2; i >= 0; i-- {
+		acc = wiop.Add(wiop.Mul(alpha, acc), exprs[i])
+	}
+	return acc
+}
+
+// ensureNextRound returns the round immediately following r, allocating one
+// via [wiop.System.NewRound] if necessary.
+func ensureNextRound(sys *wiop.System, r *wiop.Round) *wiop.Round {
+	if next, ok := r.Next(); ok {
+		return next
+	}
+	return sys.NewRound()
+}
+
+// isPrecomputedRound reports whether r is the PrecomputedRound of its owning
+// system.
+func isPrecomputedRound(r *wiop.Round) bool {
+	return r.System() != nil && r == &r.System().PrecomputedRound.Round
+}
+
+// resultIsZeroVerifierAction asserts that the LogDerivativeSum of a bus sums
+// to zero, i.e. that the bus is balanced.
+type resultIsZeroVerifierAction struct {
+	bus *wiop.MessageBus
+	ld  *wiop.LogDerivativeSum
+}
+
+// Check implements [wiop.VerifierAction].
+func (a *resultIsZeroVerifierAction) Check(rt wiop.Runtime) error {
+	if v := rt.GetCellValue(a.ld.Result); !v.IsZero() {
+		return fmt.Errorf(
+			"wiop/compilers/messagebus: bus %q is unbalanced: its log-derivative sum is not zero",
+			a.bus.Context().Path(),
+		)
+	}
+	return nil
+}
+
+// Constraints implements [wiop.ArithmeticVerifierAction].
+func (a *resultIsZeroVerifierAction) Constraints() ([]wiop.Expression, error) {
+	return []wiop.Expression{a.ld.Result}, nil
+}
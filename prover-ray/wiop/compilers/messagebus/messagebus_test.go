@@ -0,0 +1,74 @@
+package messagebus_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/logderivativesum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/messagebus"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compile(sys *wiop.System) {
+	messagebus.Compile(sys)
+	logderivativesum.Compile(sys)
+}
+
+// TestCompile_WioptestScenarios runs every [wioptest.MessageBusScenarios]
+// fixture through the messagebus → logderivativesum pipeline. The verifier
+// must accept the honest witness and reject the unbalanced one.
+func TestCompile_WioptestScenarios(t *testing.T) {
+	for _, build := range wioptest.MessageBusScenarios() {
+		sc := build()
+		t.Run(sc.Name, func(t *testing.T) {
+			compile(sc.Sys)
+			proof := sc.Sys.Prove(sc.AssignHonest)
+			require.NoError(t, sc.Sys.Verify(proof),
+				"compiled verifier must accept an honest witness")
+		})
+
+		t.Run(sc.Name+"/Soundness", func(t *testing.T) {
+			sc := build()
+			compile(sc.Sys)
+			proof := sc.Sys.Prove(sc.AssignInvalid)
+			assert.Error(t, sc.Sys.Verify(proof),
+				"compiled verifier must reject an unbalanced bus")
+		})
+	}
+}
+
+func TestCompile_ReducesBus(t *testing.T) {
+	sc := wioptest.NewBusTaggedSendersScenario()
+	messagebus.Compile(sc.Sys)
+
+	assert.True(t, sc.Bus.IsReduced())
+	require.Len(t, sc.Sys.LogDerivativeSums, 1)
+	ld := sc.Sys.LogDerivativeSums[0]
+	assert.Len(t, ld.Fractions, len(sc.Bus.Sends)+len(sc.Bus.Receives))
+
+	// r0 witness, r1 α/γ, r2 result.
+	require.Len(t, sc.Sys.Rounds, 3)
+	assert.Len(t, sc.Sys.Rounds[1].Coins, 2, "a width-2 bus needs α and γ")
+	assert.Equal(t, sc.Sys.Rounds[2], ld.Result.Round())
+	assert.Len(t, sc.Sys.Rounds[2].VerifierActions, 1)
+
+	// A second run must not compile the bus again.
+	messagebus.Compile(sc.Sys)
+	assert.Len(t, sc.Sys.LogDerivativeSums, 1)
+}
+
+func TestCompile_SingleColumnHasNoAlpha(t *testing.T) {
+	sc := wioptest.NewBusSingleColumnScenario()
+	messagebus.Compile(sc.Sys)
+	assert.Len(t, sc.Sys.Rounds[1].Coins, 1, "a width-1 bus only needs γ")
+}
+
+func TestCompile_NoBusIsNoOp(t *testing.T) {
+	sc := wioptest.VanishingScenarios()[0]()
+	rounds := len(sc.Sys.Rounds)
+	messagebus.Compile(sc.Sys)
+	assert.Len(t, sc.Sys.Rounds, rounds)
+	assert.Empty(t, sc.Sys.LogDerivativeSums)
+}
@@ -0,0 +1,263 @@
+// Package mpts implements the multi-point-to-single-point compiler pass for
+// the wiop protocol framework. It mirrors the legacy
+// prover/protocol/compiler/mpts pass: after the global and local compilers
+// have run, a System is left with [wiop.LagrangeEval] claims at many different
+// points, whereas a polynomial commitment opens all its polynomials at one.
+//
+// Every unreduced LagrangeEval is normalised into claims P_k(x_i) = y_ik on
+// unshifted columns: a view shifted by s of a column of size n evaluated at x
+// is the column itself evaluated at x_i = ω_n^s · x. The pass then:
+//
+//  1. appends a round with two coins λ and ρ and an extension-field quotient
+//     column
+//
+//     Q(X) = Σ_i λ^i Σ_j ρ^j (P_ij(X) − y_ij) / (X − x_i)
+//
+//     where j runs over the claims at the point x_i. Q is a polynomial iff
+//     every claim holds. It lives in a dedicated module whose size N is the
+//     largest size among the columns involved: the columns of smaller modules
+//     are re-evaluated on the size-N domain, so mixed module sizes are
+//     supported;
+//
+//  2. appends a round with an evaluation coin r and opens, at r alone, every
+//     involved column and Q. The openings are grouped into one LagrangeEval
+//     per commitment round and module, precomputed columns forming their own
+//     round;
+//
+//  3. registers a verifier action checking
+//
+//     Q(r) = Σ_i λ^i Σ_j ρ^j (P_ij(r) − y_ij) / (r − x_i)
+//
+//     from the opening claims.
+//
+// The original queries are marked as reduced. As for the global compiler, the
+// new single-point openings are left to the polynomial commitment scheme.
+//
+// Caller order: invoke mpts.Compile(sys) AFTER global.Compile(sys), whose
+// evaluation claims it batches.
+//
+// Zero-knowledge: when a batched column belongs to a module with masking rows
+// (see [wiop.Module.SetMaskingRows]), Q itself would leak witness data. The
+// pass then also commits, in the quotient round, a uniformly random column M
+// of the quotient module and sends Q + M in place of Q. M is opened at r next
+// to the quotient, and the verifier checks (Q + M)(r) − M(r) instead.
+//
+// Dynamic modules are supported: the size of the quotient module is then the
+// largest runtime size, and the verifier checks it. The constraints exposed
+// through [wiop.ArithmeticVerifierAction] bake the module sizes in and are
+// only available for statically sized modules.
+package mpts
+
+import (
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// evalPoint is one of the distinct evaluation points x_i of the batched
+// claims: the point of a LagrangeEval query rotated by the shift of the views
+// it evaluates.
+type evalPoint struct {
+	// point is the EvaluationPoint of the originating query.
+	point wiop.FieldPromise
+	// shift is the shift of the views evaluated at this point.
+	shift int
+	// module is the module of the views, whose size fixes the rotation.
+	module *wiop.Module
+	// polys are the indices, in compilation.polys, of the evaluated columns.
+	polys []int
+	// claims is parallel to polys and holds the claimed evaluations.
+	claims []*wiop.Cell
+}
+
+// at returns x_i = ω_n^shift · point.
+func (p *evalPoint) at(rt wiop.Runtime) field.Gen {
+	x := p.point.EvaluateSingle(rt).Value
+	if p.shift == 0 {
+		return x
+	}
+	var omegaS field.Element
+	omegaS.ExpInt64(field.RootOfUnityBy(p.module.RuntimeSize(rt)), int64(p.shift))
+	return x.Mul(field.ElemFromBase(omegaS))
+}
+
+// compilation gathers the artefacts shared by the prover and verifier
+// actions.
+type compilation struct {
+	// polys are the distinct columns involved in the claims, in order of
+	// first appearance.
+	polys  []*wiop.Column
+	points []*evalPoint
+
+	lambda, rho *wiop.CoinField
+	quotient    *wiop.Column
+	// mask is the random column M added to the quotient in zero-knowledge
+	// mode. It is nil otherwise.
+	mask *wiop.Column
+
+	evalCoin *wiop.CoinField
+	// polyClaims[k] is the claimed value of polys[k] at evalCoin.
+	polyClaims    []*wiop.Cell
+	quotientClaim *wiop.Cell
+	// maskClaim is the claimed value of mask at evalCoin, or nil.
+	maskClaim *wiop.Cell
+	openings  []*wiop.LagrangeEval
+}
+
+// openingGroup collects the columns opened by one LagrangeEval.
+type openingGroup struct {
+	round  *wiop.Round
+	module *wiop.Module
+	polys  []int
+}
+
+// Compile batches every unreduced [wiop.LagrangeEval] of sys into
+// single-point openings. See the package documentation for the reduction.
+func Compile(sys *wiop.System) {
+	var queries []*wiop.LagrangeEval
+	for _, le := range sys.LagrangeEvals {
+		if !le.IsReduced() {
+			queries = append(queries, le)
+		}
+	}
+	if len(queries) == 0 {
+		return
+	}
+
+	c := &compilation{}
+	compCtx := sys.Context.Childf("mpts")
+
+	// A shifted view is evaluated at ω_n^shift · x, where n is the size of
+	// its module, so views of different sizes sharing a query and a shift do
+	// not share a point.
+	type pointKey struct {
+		query, shift int
+		module       *wiop.Module
+	}
+	var (
+		polyIdx  = make(map[wiop.ObjectID]int)
+		pointIdx = make(map[pointKey]int)
+		isDyn    bool
+		isZK     bool
+		maxSize  int
+	)
+	for qIdx, le := range queries {
+		for j, pv := range le.Polynomials {
+			col := pv.Column
+			k, ok := polyIdx[col.Context.ID]
+			if !ok {
+				k = len(c.polys)
+				polyIdx[col.Context.ID] = k
+				c.polys = append(c.polys, col)
+				isDyn = isDyn || col.Module.IsDynamic()
+				isZK = isZK || col.Module.MaskingRows() > 0
+				maxSize = max(maxSize, col.Module.Size())
+			}
+
+			key := pointKey{query: qIdx, shift: pv.ShiftingOffset}
+			if pv.ShiftingOffset != 0 {
+				key.module = col.Module
+			}
+			i, ok := pointIdx[key]
+			if !ok {
+				i = len(c.points)
+				pointIdx[key] = i
+				c.points = append(c.points, &evalPoint{
+					point:  le.EvaluationPoint,
+					shift:  pv.ShiftingOffset,
+					module: col.Module,
+				})
+			}
+			c.points[i].polys = append(c.points[i].polys, k)
+			c.points[i].claims = append(c.points[i].claims, le.EvaluationClaims[j])
+		}
+	}
+
+	// --- Quotient round ---
+	quotientRound := sys.NewRound()
+	c.lambda = quotientRound.NewCoinField(compCtx.Childf("lambda"))
+	c.rho = quotientRound.NewCoinField(compCtx.Childf("rho"))
+
+	var qModule *wiop.Module
+	if isDyn {
+		qModule = sys.NewDynamicModule(compCtx.Childf("quotient-module"), wiop.PaddingDirectionRight)
+	} else {
+		qModule = sys.NewSizedModule(compCtx.Childf("quotient-module"), maxSize, wiop.PaddingDirectionNone)
+	}
+	c.quotient = qModule.NewExtensionColumn(compCtx.Childf("quotient"), wiop.VisibilityOracle, quotientRound)
+	if isZK {
+		c.mask = qModule.NewExtensionColumn(compCtx.Childf("quotient-mask"), wiop.VisibilityOracle, quotientRound)
+	}
+	quotientRound.RegisterAction(&quotientProverAction{c: c})
+
+	// --- Evaluation round ---
+	evalRound := sys.NewRound()
+	c.evalCoin = evalRound.NewCoinField(compCtx.Childf("r"))
+
+	type groupKey struct {
+		round  *wiop.Round
+		module *wiop.Module
+	}
+	var (
+		groups   []*openingGroup
+		groupIdx = make(map[groupKey]int)
+	)
+	for k, col := range c.polys {
+		key := groupKey{round: col.Round(), module: col.Module}
+		g, ok := groupIdx[key]
+		if !ok {
+			g = len(groups)
+			groupIdx[key] = g
+			groups = append(groups, &openingGroup{round: key.round, module: key.module})
+		}
+		groups[g].polys = append(groups[g].polys, k)
+	}
+
+	c.polyClaims = make([]*wiop.Cell, len(c.polys))
+	for g, grp := range groups {
+		gCtx := compCtx.Childf("opening[%d]", g)
+		views := make([]*wiop.ColumnView, len(grp.polys))
+		claims := make([]*wiop.Cell, len(grp.polys))
+		for j, k := range grp.polys {
+			views[j] = c.polys[k].View()
+			claims[j] = evalRound.NewCell(gCtx.Childf("claim[%d]", j), true)
+			c.polyClaims[k] = claims[j]
+		}
+		c.openings = append(c.openings, sys.NewLagrangeEvalFrom(gCtx, views, c.evalCoin, claims))
+	}
+
+	qCtx := compCtx.Childf("opening-quotient")
+	c.quotientClaim = evalRound.NewCell(qCtx.Childf("claim"), true)
+	qViews := []*wiop.ColumnView{c.quotient.View()}
+	qClaims := []*wiop.Cell{c.quotientClaim}
+	if c.mask != nil {
+		c.maskClaim = evalRound.NewCell(qCtx.Childf("mask-claim"), true)
+		qViews = append(qViews, c.mask.View())
+		qClaims = append(qClaims, c.maskClaim)
+	}
+	c.openings = append(c.openings, sys.NewLagrangeEvalFrom(qCtx, qViews, c.evalCoin, qClaims))
+
+	evalRound.RegisterAction(&openingProverAction{c: c})
+	evalRound.RegisterVerifierAction(&verifierAction{c: c})
+
+	for _, le := range queries {
+		le.MarkAsReduced()
+	}
+}
+
+// domainSize returns the size of the quotient domain for rt: the largest
+// runtime size among the modules of the batched columns.
+func (c *compilation) domainSize(rt wiop.Runtime) int {
+	n := 0
+	for _, col := range c.polys {
+		n = max(n, col.Module.RuntimeSize(rt))
+	}
+	return n
+}
+
+// genToExt lifts a field.Gen into the extension field.
+func genToExt(v field.Gen) field.Ext {
+	if v.IsBase() {
+		return field.Lift(v.AsBase())
+	}
+	return v.AsExt()
+}
@@ -0,0 +1,137 @@
+package mpts
+
+import (
+	"github.com/consensys/gnark-crypto/field/koalabear/fft"
+	gnarkutils "github.com/consensys/gnark-crypto/utils"
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// quotientProverAction assigns the quotient column
+//
+//	Q(X) = Σ_i λ^i Σ_j ρ^j (P_ij(X) − y_ij) / (X − x_i)
+//
+// in Lagrange form over the size-N domain. It runs in the quotient round. In
+// zero-knowledge mode, it also draws the random mask M and assigns Q + M in
+// place of Q.
+type quotientProverAction struct {
+	c *compilation
+}
+
+// Run implements [wiop.ProverAction].
+func (a *quotientProverAction) Run(rt wiop.Runtime) {
+	var (
+		c      = a.c
+		N      = c.domainSize(rt)
+		lambda = genToExt(rt.GetCoinValue(c.lambda))
+		rho    = genToExt(rt.GetCoinValue(c.rho))
+		omega  = field.RootOfUnityBy(N)
+		evals  = make([][]field.Ext, len(c.polys))
+		domain = make([]field.Ext, N)
+		q      = make([]field.Ext, N)
+		num    = make([]field.Ext, N)
+		den    = make([]field.Ext, N)
+		denInv = make([]field.Ext, N)
+	)
+
+	for k, col := range c.polys {
+		evals[k] = evalOnDomain(rt, col, N)
+	}
+
+	omegaJ := field.One()
+	for j := range domain {
+		domain[j] = field.Lift(omegaJ)
+		omegaJ.Mul(&omegaJ, &omega)
+	}
+
+	lambdaPowI := field.OneExt()
+	for _, p := range c.points {
+		// num[j] = Σ_t ρ^t (P_t(ω^j) − y_t), in Horner form.
+		for j := range num {
+			num[j].SetZero()
+		}
+		for t := len(p.polys) - 1; t >= 0; t-- {
+			y := genToExt(rt.GetCellValue(p.claims[t]))
+			pt := evals[p.polys[t]]
+			for j := range num {
+				var diff field.Ext
+				diff.Sub(&pt[j], &y)
+				num[j].Mul(&num[j], &rho)
+				num[j].Add(&num[j], &diff)
+			}
+		}
+
+		x := genToExt(p.at(rt))
+		for j := range den {
+			den[j].Sub(&domain[j], &x)
+		}
+		field.BatchInvertExtInto(den, denInv)
+
+		for j := range q {
+			num[j].Mul(&num[j], &denInv[j])
+			num[j].Mul(&num[j], &lambdaPowI)
+			q[j].Add(&q[j], &num[j])
+		}
+		lambdaPowI.Mul(&lambdaPowI, &lambda)
+	}
+
+	if c.mask != nil {
+		mask := make([]field.Ext, N)
+		for j := range mask {
+			mask[j] = field.RandomElementExt()
+			q[j].Add(&q[j], &mask[j])
+		}
+		rt.AssignColumn(c.mask, &wiop.ConcreteVector{Plain: field.VecFromExt(mask)})
+	}
+
+	rt.AssignColumn(c.quotient, &wiop.ConcreteVector{Plain: field.VecFromExt(q)})
+}
+
+// evalOnDomain returns the evaluations of the polynomial interpolating col on
+// the size-N roots-of-unity domain, in natural order. The domain of col's
+// module is a subgroup of it, so the columns of smaller modules go through
+// the iFFT → zero-pad → FFT route.
+func evalOnDomain(rt wiop.Runtime, col *wiop.Column, N int) []field.Ext {
+	var (
+		m    = col.Module
+		n    = m.RuntimeSize(rt)
+		cv   = rt.GetColumnAssignment(col)
+		vals = make([]field.Ext, N)
+	)
+	for i := range n {
+		vals[i] = genToExt(cv.ElementAtN(m.Padding, n, i))
+	}
+
+	switch {
+	case n == N:
+		return vals
+	case n == 1:
+		// Constant polynomial.
+		for i := range vals {
+			vals[i] = vals[0]
+		}
+		return vals
+	}
+
+	// FFTInverseExt6(DIF) leaves the coefficients in bit-reversed-of-n order.
+	// They are brought back to natural order, zero-padded, and re-bit-reversed
+	// over N for FFTExt6(DIT), which returns natural-order evaluations.
+	fft.NewDomain(uint64(n)).FFTInverseExt6(vals[:n], fft.DIF)
+	gnarkutils.BitReverse(vals[:n])
+	gnarkutils.BitReverse(vals[:N])
+	fft.NewDomain(uint64(N)).FFTExt6(vals, fft.DIT)
+	return vals
+}
+
+// openingProverAction self-assigns the single-point openings. It runs in the
+// evaluation round.
+type openingProverAction struct {
+	c *compilation
+}
+
+// Run implements [wiop.ProverAction].
+func (a *openingProverAction) Run(rt wiop.Runtime) {
+	for _, le := range a.c.openings {
+		le.SelfAssign(rt)
+	}
+}
@@ -0,0 +1,115 @@
+package mpts
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// verifierAction checks the opening of the quotient column at the evaluation
+// coin r against the opening claims of the batched columns:
+//
+//	Q(r) = Σ_i λ^i Σ_j ρ^j (P_ij(r) − y_ij) / (r − x_i)
+//
+// In zero-knowledge mode, Q(r) is recovered as (Q + M)(r) − M(r). The
+// openings themselves are left to the polynomial commitment scheme.
+type verifierAction struct {
+	c *compilation
+}
+
+// Check implements [wiop.VerifierAction].
+func (a *verifierAction) Check(rt wiop.Runtime) error {
+	c := a.c
+
+	if n, N := c.quotient.Module.RuntimeSize(rt), c.domainSize(rt); n != N {
+		return fmt.Errorf(
+			"wiop/compilers: mpts quotient has size %d, expected the largest batched module size %d",
+			n, N,
+		)
+	}
+
+	var (
+		r         = rt.GetCoinValue(c.evalCoin)
+		lambda    = rt.GetCoinValue(c.lambda)
+		rho       = rt.GetCoinValue(c.rho)
+		expected  = field.ElemZero()
+		lambdaPow = field.ElemOne()
+	)
+	for _, p := range c.points {
+		// Σ_j ρ^j (P_j(r) − y_j), in Horner form.
+		acc := field.ElemZero()
+		for t := len(p.polys) - 1; t >= 0; t-- {
+			pr := rt.GetCellValue(c.polyClaims[p.polys[t]])
+			y := rt.GetCellValue(p.claims[t])
+			acc = acc.Mul(rho).Add(pr.Sub(y))
+		}
+		den := r.Sub(p.at(rt))
+		if den.IsZero() {
+			return fmt.Errorf("wiop/compilers: mpts evaluation coin collides with a batched evaluation point")
+		}
+		expected = expected.Add(lambdaPow.Mul(acc.Div(den)))
+		lambdaPow = lambdaPow.Mul(lambda)
+	}
+
+	qr := rt.GetCellValue(c.quotientClaim)
+	if c.maskClaim != nil {
+		qr = qr.Sub(rt.GetCellValue(c.maskClaim))
+	}
+	if diff := qr.Sub(expected); !diff.IsZero() {
+		return fmt.Errorf("wiop/compilers: mpts check failed: Q(r) ≠ Σ_i λ^i Σ_j ρ^j (P_ij(r) − y_ij) / (r − x_i)")
+	}
+	return nil
+}
+
+// Constraints implements [wiop.ArithmeticVerifierAction]. It returns the
+// identity verified by Check as a single expression. The rotations ω_n^shift
+// of the evaluation points are baked in as constants, hence an error is
+// returned for dynamic modules.
+func (a *verifierAction) Constraints() ([]wiop.Expression, error) {
+	c := a.c
+	if c.quotient.Module.IsDynamic() {
+		return nil, fmt.Errorf(
+			"wiop/compilers: mpts constraints require statically sized modules, got %q",
+			c.quotient.Module.Context.Path(),
+		)
+	}
+
+	var (
+		r        = wiop.Expression(c.evalCoin)
+		expected wiop.Expression
+	)
+	for i := len(c.points) - 1; i >= 0; i-- {
+		p := c.points[i]
+
+		var acc wiop.Expression
+		for t := len(p.polys) - 1; t >= 0; t-- {
+			diff := wiop.Sub(c.polyClaims[p.polys[t]], p.claims[t])
+			if acc == nil {
+				acc = diff
+				continue
+			}
+			acc = wiop.Add(wiop.Mul(acc, c.rho), diff)
+		}
+
+		x := wiop.Expression(p.point)
+		if p.shift != 0 {
+			var omegaS field.Element
+			omegaS.ExpInt64(field.RootOfUnityBy(p.module.Size()), int64(p.shift))
+			x = wiop.Mul(wiop.NewConstantField(omegaS), x)
+		}
+		term := wiop.Div(acc, wiop.Sub(r, x))
+
+		if expected == nil {
+			expected = term
+			continue
+		}
+		expected = wiop.Add(wiop.Mul(expected, c.lambda), term)
+	}
+
+	qr := wiop.Expression(c.quotientClaim)
+	if c.maskClaim != nil {
+		qr = wiop.Sub(qr, c.maskClaim)
+	}
+	return []wiop.Expression{wiop.Sub(qr, expected)}, nil
+}
@@ -0,0 +1,66 @@
+package compilers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/localvanishing"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/logderivativesum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/lookuptologderivsum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/messagebus"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/mpts"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/rangecheck"
+)
+
+// Pass is a compiler pass that command-line tools can refer to by name.
+type Pass struct {
+	Name    string
+	Compile func(*wiop.System)
+}
+
+// Passes lists the compiler passes in their canonical order, in which each
+// pass consumes the output of the previous ones.
+var Passes = []Pass{
+	{"rangecheck", rangecheck.Compile},
+	{"lookuptologderivsum", lookuptologderivsum.Compile},
+	{"messagebus", messagebus.Compile},
+	{"logderivativesum", logderivativesum.Compile},
+	{"localvanishing", localvanishing.Compile},
+	{"global", global.Compile},
+	{"mpts", mpts.Compile},
+}
+
+// Pipelines maps the name of each predefined pipeline to its passes.
+var Pipelines = map[string][]Pass{
+	"full": Passes,
+	"none": nil,
+}
+
+// ParsePipeline resolves spec to a list of passes. spec is either the name of
+// one of [Pipelines] or a comma-separated list of pass names from [Passes].
+func ParsePipeline(spec string) ([]Pass, error) {
+	if p, ok := Pipelines[spec]; ok {
+		return p, nil
+	}
+	var res []Pass
+	for _, name := range strings.Split(spec, ",") {
+		i := slices.IndexFunc(Passes, func(p Pass) bool { return p.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown compiler pass %q, expected a pipeline (full, none) or passes among %s", name, PassNames())
+		}
+		res = append(res, Passes[i])
+	}
+	return res, nil
+}
+
+// PassNames returns the names of [Passes], comma-separated.
+func PassNames() string {
+	names := make([]string, len(Passes))
+	for i, p := range Passes {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ",")
+}
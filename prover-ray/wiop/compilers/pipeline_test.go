@@ -1,13 +1,18 @@
 package compilers_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/localvanishing"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/logderivativesum"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/lookuptologderivsum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/messagebus"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/mpts"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/rangecheck"
 	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
 	"github.com/stretchr/testify/assert"
@@ -19,9 +24,11 @@ import (
 //
 //  1. rangecheck:           RangeCheck → Inclusion TableRelation
 //  2. lookuptologderivsum:  Inclusion → LogDerivativeSum
-//  3. logderivativesum:     LogDerivativeSum → recurrence Vanishings + endpoint openings
-//  4. localvanishing:       scalar Vanishings → multi-valued Vanishings via the Lagrange lift
-//  5. global:               multi-valued Vanishings → quotient shares + LagrangeEval claims
+//  3. messagebus:           MessageBus → LogDerivativeSum
+//  4. logderivativesum:     LogDerivativeSum → recurrence Vanishings + endpoint openings
+//  5. localvanishing:       scalar Vanishings → multi-valued Vanishings via the Lagrange lift
+//  6. global:               multi-valued Vanishings → quotient shares + LagrangeEval claims
+//  7. mpts:                 multi-point LagrangeEval claims → single-point openings
 //
 // Each pass is a no-op when its input queries are absent, so this ordering
 // is safe to apply uniformly to every wioptest scenario regardless of which
@@ -29,16 +36,18 @@ import (
 func compileFullPipeline(sys *wiop.System) {
 	rangecheck.Compile(sys)
 	lookuptologderivsum.Compile(sys)
+	messagebus.Compile(sys)
 	logderivativesum.Compile(sys)
 	localvanishing.Compile(sys)
 	global.Compile(sys)
+	mpts.Compile(sys)
 }
 
-// These tests drive every scenario through the full
-// range → lookup → logderivative → local → global pipeline using the explicit
-// prover/verifier split: sys.Prove(assign) produces a strict, public-only
-// [wiop.Proof], and sys.Verify(proof) re-checks it without access to the oracle
-// witness columns. Because the Proof carries only public columns, cells, and
+// These tests drive every scenario through the full range → lookup →
+// message-bus → logderivative → local → global → mpts pipeline using the
+// explicit prover/verifier split: sys.Prove(assign) produces a strict,
+// public-only [wiop.Proof], and sys.Verify(proof) re-checks it without access
+// to the oracle witness columns. Because the Proof carries only public columns, cells, and
 // coins, these tests fail loudly if any verifier action reads an oracle or
 // internal column.
 
@@ -125,6 +134,30 @@ func TestFullPipeline_LookupScenarios(t *testing.T) {
 	}
 }
 
+// TestFullPipeline_MessageBusScenarios runs the full pipeline on every
+// [wioptest.MessageBusScenarios] fixture. The message-bus pass turns each bus
+// into a log-derivative sum spanning all its participating modules, which the
+// recurrence and quotient passes then discharge.
+func TestFullPipeline_MessageBusScenarios(t *testing.T) {
+	for _, build := range wioptest.MessageBusScenarios() {
+		sc := build()
+		t.Run(sc.Name, func(t *testing.T) {
+			compileFullPipeline(sc.Sys)
+			proof := sc.Sys.Prove(sc.AssignHonest)
+			require.NoError(t, sc.Sys.Verify(proof),
+				"full pipeline must accept an honest witness")
+		})
+
+		t.Run(sc.Name+"/Soundness", func(t *testing.T) {
+			sc := build()
+			compileFullPipeline(sc.Sys)
+			proof := sc.Sys.Prove(sc.AssignInvalid)
+			assert.Error(t, sc.Sys.Verify(proof),
+				"full pipeline must reject an unbalanced bus")
+		})
+	}
+}
+
 // TestFullPipeline_RangeCheckScenarios runs the full pipeline on every
 // [wioptest.RangeCheckCompilerScenarios] fixture. Every step contributes:
 // rangecheck → lookup → log-derivative → recurrence vanishings → global
@@ -140,3 +173,335 @@ func TestFullPipeline_RangeCheckScenarios(t *testing.T) {
 		})
 	}
 }
+
+// checkSinglePointOpenings asserts that every LagrangeEval left unreduced by
+// the pipeline is evaluated at the same point, and that the openings assigned
+// by an honest run hold. The openings are not checked by sys.Verify: they are
+// left to the polynomial commitment.
+func checkSinglePointOpenings(t *testing.T, sys *wiop.System, assign func(rt *wiop.Runtime)) {
+	t.Helper()
+
+	var (
+		point    wiop.FieldPromise
+		openings []*wiop.LagrangeEval
+	)
+	for _, le := range sys.LagrangeEvals {
+		if le.IsReduced() {
+			continue
+		}
+		if point == nil {
+			point = le.EvaluationPoint
+		}
+		require.Same(t, point, le.EvaluationPoint,
+			"every remaining opening must be at the same point")
+		openings = append(openings, le)
+	}
+	require.NotEmpty(t, openings)
+
+	rt := wiop.NewRuntime(sys)
+	assign(&rt)
+	require.NoError(t, wioptest.RunAndVerify(&rt))
+	for _, le := range openings {
+		require.NoError(t, le.Check(rt))
+	}
+}
+
+// TestFullPipeline_MPTS checks the output of the multi-point-to-single-point
+// pass on every vanishing scenario. MultiModule and MultiModuleHighRatio mix
+// modules of sizes 4 and 8, so the smaller columns go through the
+// re-evaluation on the larger domain.
+func TestFullPipeline_MPTS(t *testing.T) {
+	for _, build := range wioptest.VanishingScenarios() {
+		sc := build()
+		t.Run(sc.Name, func(t *testing.T) {
+			compileFullPipeline(sc.Sys)
+			checkSinglePointOpenings(t, sc.Sys, sc.AssignHonest)
+		})
+	}
+	for _, build := range wioptest.LocalVanishingScenarios() {
+		sc := build()
+		t.Run("Local"+sc.Name, func(t *testing.T) {
+			compileFullPipeline(sc.Sys)
+			checkSinglePointOpenings(t, sc.Sys, sc.AssignHonest)
+		})
+	}
+	for _, build := range wioptest.LogDerivativeSumCompilerScenarios() {
+		sc := build()
+		t.Run("LDS"+sc.Name, func(t *testing.T) {
+			compileFullPipeline(sc.Sys)
+			checkSinglePointOpenings(t, sc.Sys, sc.AssignWitness)
+		})
+	}
+}
+
+// TestFullPipeline_MPTS_ForgedOpening checks that the verifier rejects a
+// proof whose single-point opening claims do not match the original claims,
+// on a mixed-size system. The claims of the last round are only read by the
+// MPTS verifier action.
+func TestFullPipeline_MPTS_ForgedOpening(t *testing.T) {
+	sc := wioptest.NewMultiModuleVanishingScenario()
+	compileFullPipeline(sc.Sys)
+	proof := sc.Sys.Prove(sc.AssignHonest)
+	require.NoError(t, sc.Sys.Verify(proof))
+
+	last := sc.Sys.Rounds[len(sc.Sys.Rounds)-1]
+	for _, cell := range last.Cells {
+		id := cell.Context.ID
+		honest := proof.Cells[id]
+		proof.Cells[id] = honest.Add(field.ElemFromBase(field.One()))
+		assert.Error(t, sc.Sys.Verify(proof), "forged opening claim %v must be rejected", cell.Context.Path())
+		proof.Cells[id] = honest
+	}
+}
+
+// lagrangeEvalAssignment assigns the claims of a LagrangeEval registered
+// directly by a test.
+type lagrangeEvalAssignment struct {
+	le *wiop.LagrangeEval
+}
+
+// Run implements [wiop.ProverAction].
+func (a lagrangeEvalAssignment) Run(rt wiop.Runtime) {
+	a.le.SelfAssign(rt)
+}
+
+// TestMPTS_MixedSizeShiftedQuery checks a single LagrangeEval over views of
+// modules of sizes 4 and 8 shifted by the same offset: the two views are
+// evaluated at different rotations of the point, so the pass must not batch
+// them as claims at the same point.
+func TestMPTS_MixedSizeShiftedQuery(t *testing.T) {
+	sys := wiop.NewSystemf("mpts-mixed-shift")
+	r0 := sys.NewRound()
+	r1 := sys.NewRound()
+	small := sys.NewSizedModule(sys.Context.Childf("small"), 4, wiop.PaddingDirectionNone)
+	large := sys.NewSizedModule(sys.Context.Childf("large"), 8, wiop.PaddingDirectionNone)
+	a := small.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	b := large.NewColumn(sys.Context.Childf("b"), wiop.VisibilityOracle, r0)
+	x := r1.NewCoinField(sys.Context.Childf("x"))
+	le := sys.NewLagrangeEval(sys.Context.Childf("le"), []*wiop.ColumnView{a.View().Shift(1), b.View().Shift(1)}, x)
+	r1.RegisterAction(lagrangeEvalAssignment{le: le})
+
+	mpts.Compile(sys)
+	require.True(t, le.IsReduced())
+
+	assign := func(rt *wiop.Runtime) {
+		for _, col := range []*wiop.Column{a, b} {
+			n := col.Module.Size()
+			vals := make([]field.Element, n)
+			for i := range n {
+				vals[i].SetUint64(uint64(n + 3*i + 1))
+			}
+			rt.AssignColumn(col, &wiop.ConcreteVector{Plain: field.VecFromBase(vals)})
+		}
+	}
+	checkSinglePointOpenings(t, sys, assign)
+
+	proof := sys.Prove(assign)
+	require.NoError(t, sys.Verify(proof))
+	for _, claim := range le.EvaluationClaims {
+		id := claim.Context.ID
+		honest := proof.Cells[id]
+		proof.Cells[id] = honest.Add(field.ElemFromBase(field.One()))
+		assert.Error(t, sys.Verify(proof), "forged claim %v must be rejected", claim.Context.Path())
+		proof.Cells[id] = honest
+	}
+}
+
+// TestParsePipeline checks the named pipelines and pass lists accepted by the
+// command-line tools, and that "full" matches compileFullPipeline.
+func TestParsePipeline(t *testing.T) {
+	full, err := compilers.ParsePipeline("full")
+	require.NoError(t, err)
+	assert.Equal(t, "rangecheck,lookuptologderivsum,messagebus,logderivativesum,localvanishing,global,mpts",
+		compilers.PassNames())
+	assert.Len(t, full, len(compilers.Passes))
+
+	none, err := compilers.ParsePipeline("none")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	some, err := compilers.ParsePipeline("global,mpts")
+	require.NoError(t, err)
+	require.Len(t, some, 2)
+	assert.Equal(t, "global", some[0].Name)
+	assert.Equal(t, "mpts", some[1].Name)
+
+	_, err = compilers.ParsePipeline("global,selfrecursion")
+	assert.ErrorContains(t, err, `"selfrecursion"`)
+	_, err = compilers.ParsePipeline("")
+	assert.Error(t, err)
+
+	// The full pipeline must compile a scenario exactly like the explicit
+	// sequence used by the other tests of this file.
+	sc, ref := wioptest.NewFibonacciVanishingScenario(), wioptest.NewFibonacciVanishingScenario()
+	for _, p := range full {
+		p.Compile(sc.Sys)
+	}
+	compileFullPipeline(ref.Sys)
+	assert.Equal(t, ref.Sys.Digest(), sc.Sys.Digest())
+}
+
+// TestFullPipeline_DynamicModuleSizes compiles a single system holding a
+// dynamic module once, and proves it at several heights. The module carries a
+// shifted vanishing, a local constraint on its first row and a log-derivative
+// sum, so that the global quotient, the Lagrange lift and the log-derivative
+// recurrence all pick their domains from the runtime size.
+func TestFullPipeline_DynamicModuleSizes(t *testing.T) {
+	sys := wiop.NewSystemf("pipeline-dyn")
+	r0 := sys.NewRound()
+	sys.NewRound()
+	mod := sys.NewDynamicModule(sys.Context.Childf("mod"), wiop.PaddingDirectionRight)
+	a := mod.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	d := mod.NewColumn(sys.Context.Childf("d"), wiop.VisibilityOracle, r0)
+	one := wiop.NewConstantVector(mod, field.NewFromString("1"))
+	// a[0] = 0 and a[i] = a[i−1] + d[i]: a counts the increments.
+	mod.NewVanishing(sys.Context.Childf("count"), wiop.Sub(wiop.Sub(a.View(), a.View().Shift(-1)), d.View()))
+	mod.NewLocalConstraint(sys.Context.Childf("start"), a.View(), 0)
+	sys.NewLogDerivativeSum(
+		sys.Context.Childf("ld"),
+		[]wiop.Fraction{{Filter: d.View(), Numerator: a.View(), Denominator: one}},
+	)
+	compileFullPipeline(sys)
+
+	for _, length := range []int{3, 4, 5, 13, 64} {
+		assign := func(start uint64) func(rt *wiop.Runtime) {
+			return func(rt *wiop.Runtime) {
+				as := make([]field.Element, length)
+				ds := make([]field.Element, length)
+				for i := range length {
+					as[i].SetUint64(start + uint64(i))
+					if i > 0 {
+						ds[i].SetUint64(1)
+					}
+				}
+				rt.AssignColumn(a, &wiop.ConcreteVector{Plain: field.VecFromBase(as), Padding: as[length-1]})
+				rt.AssignColumn(d, &wiop.ConcreteVector{Plain: field.VecFromBase(ds)})
+			}
+		}
+
+		proof := sys.Prove(assign(0))
+		require.NoError(t, sys.Verify(proof), "length=%d: honest witness must verify", length)
+
+		invalid := sys.Prove(assign(1))
+		assert.Error(t, sys.Verify(invalid), "length=%d: a counter starting at 1 must be rejected", length)
+	}
+}
+
+// TestFullPipeline_ZeroKnowledge compiles a system whose static and dynamic
+// modules both have masking rows. Honest proofs must verify, an invalid
+// witness must be rejected, and two proofs of the same witness must not share
+// any committed column: the masking rows randomise the witness columns and,
+// through Fiat-Shamir, every later column, and the mpts mask randomises the
+// quotient.
+func TestFullPipeline_ZeroKnowledge(t *testing.T) {
+	sys := wiop.NewSystemf("pipeline-zk")
+	r0 := sys.NewRound()
+	static := sys.NewSizedModule(sys.Context.Childf("static"), 16, wiop.PaddingDirectionRight)
+	static.SetMaskingRows(3)
+	dyn := sys.NewDynamicModule(sys.Context.Childf("dyn"), wiop.PaddingDirectionRight)
+	dyn.SetMaskingRows(3)
+
+	type counter struct{ a, d *wiop.Column }
+	var counters []counter
+	for _, mod := range []*wiop.Module{static, dyn} {
+		ctx := mod.Context
+		a := mod.NewColumn(ctx.Childf("a"), wiop.VisibilityOracle, r0)
+		d := mod.NewColumn(ctx.Childf("d"), wiop.VisibilityOracle, r0)
+		// a[0] = 0 and a[i] = a[i−1] + d[i], read forward from a[i+1].
+		mod.NewVanishing(ctx.Childf("count"), wiop.Sub(wiop.Sub(a.View().Shift(1), a.View()), d.View().Shift(1)))
+		mod.NewLocalConstraint(ctx.Childf("start"), a.View(), 0)
+		counters = append(counters, counter{a, d})
+	}
+	compileFullPipeline(sys)
+	hasMask := false
+	for _, r := range sys.Rounds {
+		for _, col := range r.Columns {
+			hasMask = hasMask || strings.HasSuffix(col.Context.Path(), "quotient-mask")
+		}
+	}
+	require.True(t, hasMask, "mpts must add a quotient mask for masked modules")
+
+	assign := func(start uint64, length int) func(rt *wiop.Runtime) {
+		return func(rt *wiop.Runtime) {
+			for _, c := range counters {
+				as := make([]field.Element, length)
+				ds := make([]field.Element, length)
+				for i := range length {
+					as[i].SetUint64(start + uint64(i))
+					if i > 0 {
+						ds[i].SetUint64(1)
+					}
+				}
+				rt.AssignColumn(c.a, &wiop.ConcreteVector{Plain: field.VecFromBase(as), Padding: as[length-1]})
+				rt.AssignColumn(c.d, &wiop.ConcreteVector{Plain: field.VecFromBase(ds)})
+			}
+		}
+	}
+
+	for _, length := range []int{5, 13} {
+		p1 := sys.Prove(assign(0, length))
+		require.NoError(t, sys.Verify(p1), "length=%d: honest witness must verify", length)
+		p2 := sys.Prove(assign(0, length))
+		require.NoError(t, sys.Verify(p2), "length=%d: honest witness must verify", length)
+
+		require.Equal(t, p1.DynamicSizes, p2.DynamicSizes)
+		for id, v := range p1.Columns {
+			assert.NotEqual(t, v.Plain, p2.Columns[id].Plain,
+				"length=%d: column %v is identical in two proofs", length, sys.LookupColumn(id).Context.Path())
+		}
+
+		invalid := sys.Prove(assign(1, length))
+		assert.Error(t, sys.Verify(invalid), "length=%d: a counter starting at 1 must be rejected", length)
+	}
+}
+
+// TestFullPipeline_ZeroKnowledgeLookups compiles a range check and a lookup
+// between two masked modules. The multiplicity and running-sum columns are
+// assigned by prover actions and are masked by Prove as well, so the proofs
+// must verify whatever the masking rows hold, and two proofs of the same
+// witness must not share any committed column of a masked module. The
+// multiplicities of the precomputed range table are not masked and only
+// depend on the witness.
+func TestFullPipeline_ZeroKnowledgeLookups(t *testing.T) {
+	sys := wiop.NewSystemf("pipeline-zk-lookups")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 16, wiop.PaddingDirectionRight)
+	mod.SetMaskingRows(3)
+	table := sys.NewSizedModule(sys.Context.Childf("table"), 16, wiop.PaddingDirectionRight)
+	table.SetMaskingRows(2)
+	a := mod.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	tc := table.NewColumn(sys.Context.Childf("t"), wiop.VisibilityOracle, r0)
+	mod.NewRangeCheck(sys.Context.Childf("rc"), a, 16)
+	sys.NewInclusion(
+		sys.Context.Childf("inc"),
+		[]wiop.Table{wiop.NewTable(a.View())},
+		[]wiop.Table{wiop.NewTable(tc.View())},
+	)
+	compileFullPipeline(sys)
+
+	assign := func(rt *wiop.Runtime) {
+		as := make([]field.Element, 10)
+		for i := range as {
+			as[i].SetUint64(uint64(i % 5))
+		}
+		ts := make([]field.Element, 13)
+		for i := range ts {
+			ts[i].SetUint64(uint64(i))
+		}
+		rt.AssignColumn(a, &wiop.ConcreteVector{Plain: field.VecFromBase(as), Padding: as[9]})
+		rt.AssignColumn(tc, &wiop.ConcreteVector{Plain: field.VecFromBase(ts), Padding: ts[12]})
+	}
+
+	p1, p2 := sys.Prove(assign), sys.Prove(assign)
+	require.NoError(t, sys.Verify(p1))
+	require.NoError(t, sys.Verify(p2))
+	for id, v := range p1.Columns {
+		col := sys.LookupColumn(id)
+		// The quotient shares are not masked row by row (see SkipMasking)
+		if col.Module.MaskingRows() == 0 || col.SkipMasking {
+			continue
+		}
+		assert.NotEqual(t, v.Plain, p2.Columns[id].Plain,
+			"column %v is identical in two proofs", col.Context.Path())
+	}
+}
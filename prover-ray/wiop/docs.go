@@ -11,7 +11,7 @@
 // by the verifier.
 //
 // Constraints and verifier predicates are expressed as [Query] values:
-// [Vanishing], [LagrangeEval], [LookupQuery], and
+// [Vanishing], [LagrangeEval], [LookupQuery], [MessageBus], and
 // [LogDerivativeSum]. Each query references symbolic [Expression] objects that
 // form an arithmetic AST evaluated at runtime.
 //
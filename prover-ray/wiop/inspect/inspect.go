@@ -0,0 +1,192 @@
+// Package inspect exports the structure of a [wiop.System]: its modules,
+// columns, rounds, coins, cells and queries. A [Snapshot] is a plain value
+// taken at one point of the compilation; a [Recorder] drives a compiler
+// pipeline, snapshots the system after every pass and attributes each object
+// to the pass that created it.
+//
+// Snapshots and reports are rendered as JSON ([WriteJSON]), Graphviz DOT
+// ([WriteDOT]), Mermaid ([WriteMermaid]) or as a per-pass text diff
+// ([WriteDiff]).
+package inspect
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// PrecomputedRound is the round number reported for the objects of the
+// system's precomputed round, and for queries that only reference them.
+const PrecomputedRound = -1
+
+// Snapshot describes the shape of a [wiop.System] at one point of its
+// compilation. It carries no assignment.
+type Snapshot struct {
+	// System is the path of the root context of the system.
+	System string `json:"system"`
+	// Digest is the hex-encoded [wiop.System.Digest].
+	Digest  string   `json:"digest"`
+	Modules []Module `json:"modules"`
+	Columns []Column `json:"columns"`
+	Rounds  []Round  `json:"rounds"`
+	Queries []Query  `json:"queries"`
+}
+
+// Module describes a [wiop.Module].
+type Module struct {
+	// Index is the position of the module in [wiop.System.Modules].
+	Index int    `json:"index"`
+	Path  string `json:"path"`
+	// Size is zero for unsized and dynamic modules.
+	Size    int    `json:"size"`
+	Dynamic bool   `json:"dynamic,omitempty"`
+	Padding string `json:"padding"`
+	// NumColumns is the number of columns of the module.
+	NumColumns int `json:"numColumns"`
+	// Area is Size × NumColumns, the number of field elements the prover
+	// assigns to the module.
+	Area int    `json:"area"`
+	Pass string `json:"pass,omitempty"`
+}
+
+// Column describes a [wiop.Column].
+type Column struct {
+	ID         wiop.ObjectID `json:"id"`
+	Path       string        `json:"path"`
+	Module     int           `json:"module"`
+	Round      int           `json:"round"`
+	Visibility string        `json:"visibility"`
+	Extension  bool          `json:"extension,omitempty"`
+	Pass       string        `json:"pass,omitempty"`
+}
+
+// Round describes a [wiop.Round]. Its columns are listed in
+// [Snapshot.Columns].
+type Round struct {
+	ID    int    `json:"id"`
+	Coins []Item `json:"coins"`
+	Cells []Item `json:"cells"`
+	// NumColumns is the number of columns committed in the round.
+	NumColumns int `json:"numColumns"`
+	// ProverActions and VerifierActions list the dynamic types of the
+	// round's actions, in registration order.
+	ProverActions   []string `json:"proverActions"`
+	VerifierActions []string `json:"verifierActions"`
+}
+
+// Item describes a coin or a cell.
+type Item struct {
+	ID        wiop.ObjectID `json:"id"`
+	Path      string        `json:"path"`
+	Extension bool          `json:"extension,omitempty"`
+	Pass      string        `json:"pass,omitempty"`
+}
+
+// Query describes a query registered with the system or one of its modules.
+type Query struct {
+	// Kind is the query type, e.g. "Vanishing" or "LagrangeEval".
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+	// Module is the index of the owning module for module-bound queries
+	// (vanishings and range checks) and -1 otherwise.
+	Module  int    `json:"module"`
+	Round   int    `json:"round"`
+	Reduced bool   `json:"reduced"`
+	Pass    string `json:"pass,omitempty"`
+}
+
+// Take snapshots sys. The Pass fields are left empty; use a [Recorder] to
+// fill them.
+func Take(sys *wiop.System) *Snapshot {
+	digest := sys.Digest()
+	s := &Snapshot{
+		System: sys.Context.Path(),
+		Digest: hex.EncodeToString(digest[:]),
+	}
+
+	for i, m := range sys.Modules {
+		s.Modules = append(s.Modules, Module{
+			Index:      i,
+			Path:       m.Context.Path(),
+			Size:       m.Size(),
+			Dynamic:    m.IsDynamic(),
+			Padding:    m.Padding.String(),
+			NumColumns: len(m.Columns),
+			Area:       m.Size() * len(m.Columns),
+		})
+		for _, c := range m.Columns {
+			s.Columns = append(s.Columns, Column{
+				ID:         c.Context.ID,
+				Path:       c.Context.Path(),
+				Module:     i,
+				Round:      roundID(sys, c.Round()),
+				Visibility: c.Visibility.String(),
+				Extension:  c.IsExtension,
+			})
+		}
+		for _, v := range m.Vanishings {
+			s.Queries = append(s.Queries, query(sys, "Vanishing", i, v))
+		}
+		for _, rc := range m.RangeChecks {
+			s.Queries = append(s.Queries, query(sys, "RangeCheck", i, rc))
+		}
+	}
+
+	for _, r := range sys.Rounds {
+		round := Round{
+			ID:              r.ID,
+			Coins:           []Item{},
+			Cells:           []Item{},
+			NumColumns:      len(r.Columns),
+			ProverActions:   []string{},
+			VerifierActions: []string{},
+		}
+		for _, c := range r.Coins {
+			round.Coins = append(round.Coins, Item{ID: c.Context.ID, Path: c.Context.Path(), Extension: true})
+		}
+		for _, c := range r.Cells {
+			round.Cells = append(round.Cells, Item{ID: c.Context.ID, Path: c.Context.Path(), Extension: c.IsExtension()})
+		}
+		for _, a := range r.ProverActions {
+			round.ProverActions = append(round.ProverActions, fmt.Sprintf("%T", a))
+		}
+		for _, a := range r.VerifierActions {
+			round.VerifierActions = append(round.VerifierActions, fmt.Sprintf("%T", a))
+		}
+		s.Rounds = append(s.Rounds, round)
+	}
+
+	for _, q := range sys.LagrangeEvals {
+		s.Queries = append(s.Queries, query(sys, "LagrangeEval", -1, q))
+	}
+	for _, q := range sys.TableRelations {
+		s.Queries = append(s.Queries, query(sys, "TableRelation", -1, q))
+	}
+	for _, q := range sys.LogDerivativeSums {
+		s.Queries = append(s.Queries, query(sys, "LogDerivativeSum", -1, q))
+	}
+	for _, q := range sys.MessageBuses {
+		s.Queries = append(s.Queries, query(sys, "MessageBus", -1, q))
+	}
+	return s
+}
+
+func query(sys *wiop.System, kind string, module int, q wiop.Query) Query {
+	return Query{
+		Kind:    kind,
+		Path:    q.Context().Path(),
+		Module:  module,
+		Round:   roundID(sys, q.Round()),
+		Reduced: q.IsReduced(),
+	}
+}
+
+// roundID returns the ID of r, or [PrecomputedRound] when r is the precomputed
+// round or nil.
+func roundID(sys *wiop.System, r *wiop.Round) int {
+	if r == nil || r == &sys.PrecomputedRound.Round {
+		return PrecomputedRound
+	}
+	return r.ID
+}
@@ -0,0 +1,133 @@
+package inspect_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/mpts"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/rangecheck"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/inspect"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordFib(t *testing.T) *inspect.Recorder {
+	t.Helper()
+	sc := wioptest.NewFibonacciVanishingScenario()
+	rec := inspect.NewRecorder(sc.Sys)
+	rec.Run("rangecheck", rangecheck.Compile)
+	rec.Run("global", global.Compile)
+	rec.Run("mpts", mpts.Compile)
+	require.Len(t, rec.Passes(), 3)
+	return rec
+}
+
+func TestTake(t *testing.T) {
+	sc := wioptest.NewFibonacciVanishingScenario()
+	s := inspect.Take(sc.Sys)
+
+	assert.Equal(t, "fib", s.System)
+	require.Len(t, s.Modules, 1)
+	assert.Equal(t, inspect.Module{
+		Index: 0, Path: "fib/mod", Size: 8, Padding: "None", NumColumns: 1, Area: 8,
+	}, s.Modules[0])
+
+	require.Len(t, s.Columns, 1)
+	assert.Equal(t, "fib/col", s.Columns[0].Path)
+	assert.Equal(t, "Oracle", s.Columns[0].Visibility)
+	assert.Equal(t, 0, s.Columns[0].Round)
+
+	require.Len(t, s.Queries, 1)
+	assert.Equal(t, inspect.Query{Kind: "Vanishing", Path: "fib/fib", Module: 0, Round: 0}, s.Queries[0])
+}
+
+func TestRecorder_Attribution(t *testing.T) {
+	rec := recordFib(t)
+	passes := rec.Passes()
+
+	noop := passes[0]
+	assert.Equal(t, "rangecheck", noop.Pass)
+	assert.Empty(t, noop.Rounds)
+	assert.Empty(t, noop.Columns)
+	assert.Empty(t, noop.Queries)
+	assert.Empty(t, noop.Reduced)
+	assert.Zero(t, noop.Area)
+
+	g := passes[1]
+	assert.Equal(t, []int{1, 2}, g.Rounds)
+	assert.NotEmpty(t, g.Columns)
+	require.Len(t, g.Reduced, 1)
+	assert.Equal(t, "Vanishing", g.Reduced[0].Kind)
+	assert.True(t, g.Reduced[0].Reduced)
+	for _, q := range g.Queries {
+		assert.Equal(t, "global", q.Pass)
+	}
+	assert.Positive(t, g.VerifierActions)
+
+	s := rec.Snapshot()
+	for _, c := range s.Columns {
+		if c.Path == "fib/col" {
+			assert.Equal(t, inspect.Declared, c.Pass)
+		} else {
+			assert.Contains(t, []string{"global", "mpts"}, c.Pass, "column %s", c.Path)
+		}
+	}
+	for _, q := range s.Queries {
+		if q.Kind == "LagrangeEval" && q.Pass == "global" {
+			assert.True(t, q.Reduced, "mpts must reduce %s", q.Path)
+		}
+	}
+}
+
+// TestRecorder_DuplicatePaths checks that queries sharing a path are told
+// apart, so that a pass adding one more is attributed correctly.
+func TestRecorder_DuplicatePaths(t *testing.T) {
+	sys := wiop.NewSystemf("dup")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 4, wiop.PaddingDirectionNone)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	mod.NewVanishing(sys.Context.Childf("v"), col.View())
+
+	rec := inspect.NewRecorder(sys)
+	rec.Run("again", func(sys *wiop.System) {
+		sys.Modules[0].NewVanishing(sys.Context.Childf("v"), col.View())
+	})
+
+	d := rec.Passes()[0]
+	require.Len(t, d.Queries, 1)
+	assert.Equal(t, "again", d.Queries[0].Pass)
+	assert.Equal(t, inspect.Declared, rec.Snapshot().Queries[0].Pass)
+}
+
+func TestWriters(t *testing.T) {
+	rec := recordFib(t)
+
+	var js bytes.Buffer
+	require.NoError(t, inspect.WriteJSON(&js, rec.Report()))
+	var decoded inspect.Report
+	require.NoError(t, json.Unmarshal(js.Bytes(), &decoded))
+	assert.Equal(t, rec.Snapshot(), decoded.Snapshot)
+	assert.Len(t, decoded.Passes, 3)
+
+	var dot bytes.Buffer
+	require.NoError(t, inspect.WriteDOT(&dot, rec.Snapshot()))
+	assert.True(t, strings.HasPrefix(dot.String(), `digraph "fib" {`), dot.String())
+	assert.Contains(t, dot.String(), `label="declared"`)
+	assert.Contains(t, dot.String(), "m0 -> r0")
+	assert.Contains(t, dot.String(), "r1 -> r2")
+
+	var mmd bytes.Buffer
+	require.NoError(t, inspect.WriteMermaid(&mmd, rec.Snapshot()))
+	assert.True(t, strings.HasPrefix(mmd.String(), "flowchart LR\n"), mmd.String())
+	assert.Contains(t, mmd.String(), `subgraph pass_0["declared"]`)
+
+	var diff bytes.Buffer
+	require.NoError(t, inspect.WriteDiff(&diff, rec.Passes()))
+	assert.Contains(t, diff.String(), "pass global (")
+	assert.Contains(t, diff.String(), "1 Vanishing reduced")
+}
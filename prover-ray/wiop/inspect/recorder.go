@@ -0,0 +1,232 @@
+package inspect
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// Declared is the pass name attributed to the objects that exist when a
+// [Recorder] is created, i.e. those declared by the user rather than by a
+// compiler pass.
+const Declared = "declared"
+
+// Recorder runs compiler passes on a system and keeps, after every pass, a
+// snapshot in which each object is attributed to the pass that created it.
+//
+//	rec := inspect.NewRecorder(sys)
+//	rec.Run("global", global.Compile)
+//	rec.Run("mpts", mpts.Compile)
+//	inspect.WriteDiff(os.Stdout, rec.Passes())
+type Recorder struct {
+	sys *wiop.System
+	// origin maps the key of every object seen so far to the pass that
+	// created it.
+	origin  map[string]string
+	current *Snapshot
+	passes  []PassDiff
+}
+
+// Report is the full output of a [Recorder]: the final snapshot and the diff
+// of every pass, in execution order.
+type Report struct {
+	Snapshot *Snapshot  `json:"snapshot"`
+	Passes   []PassDiff `json:"passes"`
+}
+
+// PassDiff is what a single compiler pass changed in the system. Added
+// objects carry the state they had right after the pass.
+type PassDiff struct {
+	Pass string `json:"pass"`
+	// Duration is the wall-clock time of the pass, in nanoseconds once
+	// encoded.
+	Duration time.Duration `json:"duration"`
+	// Rounds lists the IDs of the rounds the pass appended.
+	Rounds  []int    `json:"rounds"`
+	Modules []Module `json:"modules"`
+	// Resized lists the pre-existing modules whose size the pass changed.
+	Resized []Resize `json:"resized"`
+	Columns []Column `json:"columns"`
+	Coins   []Item   `json:"coins"`
+	Cells   []Item   `json:"cells"`
+	Queries []Query  `json:"queries"`
+	// Reduced lists the pre-existing queries the pass marked as reduced.
+	Reduced []Query `json:"reduced"`
+	// ProverActions and VerifierActions count the actions the pass
+	// registered, across all rounds.
+	ProverActions   int `json:"proverActions"`
+	VerifierActions int `json:"verifierActions"`
+	// Area is the change of the summed [Module.Area] of the system.
+	Area int `json:"area"`
+}
+
+// Resize records a module size change.
+type Resize struct {
+	Module int    `json:"module"`
+	Path   string `json:"path"`
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+}
+
+// NewRecorder snapshots sys and attributes all its objects to [Declared].
+func NewRecorder(sys *wiop.System) *Recorder {
+	r := &Recorder{sys: sys, origin: make(map[string]string)}
+	r.current = r.attribute(Take(sys), Declared)
+	return r
+}
+
+// Run applies pass to the recorded system under the given name and records
+// its diff. Names are free-form but should be unique within a pipeline for
+// the attribution to be readable.
+func (r *Recorder) Run(name string, pass func(*wiop.System)) {
+	start := time.Now()
+	pass(r.sys)
+	elapsed := time.Since(start)
+
+	next := r.attribute(Take(r.sys), name)
+	d := diff(r.current, next)
+	d.Pass, d.Duration = name, elapsed
+	r.passes = append(r.passes, d)
+	r.current = next
+}
+
+// Snapshot returns the snapshot taken after the last pass, with attribution.
+func (r *Recorder) Snapshot() *Snapshot { return r.current }
+
+// Passes returns the diffs of the passes run so far, in execution order.
+func (r *Recorder) Passes() []PassDiff { return r.passes }
+
+// Report bundles [Recorder.Snapshot] and [Recorder.Passes].
+func (r *Recorder) Report() *Report {
+	return &Report{Snapshot: r.current, Passes: r.passes}
+}
+
+// attribute sets the Pass field of every object of s, recording pass as the
+// origin of the objects not seen before.
+func (r *Recorder) attribute(s *Snapshot, pass string) *Snapshot {
+	origin := func(key string) string {
+		if p, ok := r.origin[key]; ok {
+			return p
+		}
+		r.origin[key] = pass
+		return pass
+	}
+	for i := range s.Modules {
+		s.Modules[i].Pass = origin(moduleKey(s.Modules[i]))
+	}
+	for i := range s.Columns {
+		s.Columns[i].Pass = origin(idKey(s.Columns[i].ID))
+	}
+	for i := range s.Rounds {
+		for j := range s.Rounds[i].Coins {
+			s.Rounds[i].Coins[j].Pass = origin(idKey(s.Rounds[i].Coins[j].ID))
+		}
+		for j := range s.Rounds[i].Cells {
+			s.Rounds[i].Cells[j].Pass = origin(idKey(s.Rounds[i].Cells[j].ID))
+		}
+	}
+	for i, key := range queryKeys(s.Queries) {
+		s.Queries[i].Pass = origin(key)
+	}
+	return s
+}
+
+// diff returns the changes from prev to next. Objects are matched by their
+// keys, which are stable because passes only ever append to the system.
+func diff(prev, next *Snapshot) PassDiff {
+	d := PassDiff{
+		Rounds:  []int{},
+		Modules: []Module{},
+		Resized: []Resize{},
+		Columns: []Column{},
+		Coins:   []Item{},
+		Cells:   []Item{},
+		Queries: []Query{},
+		Reduced: []Query{},
+	}
+
+	for _, m := range next.Modules {
+		if m.Index >= len(prev.Modules) {
+			d.Modules = append(d.Modules, m)
+		} else if old := prev.Modules[m.Index]; old.Size != m.Size {
+			d.Resized = append(d.Resized, Resize{Module: m.Index, Path: m.Path, From: old.Size, To: m.Size})
+		}
+		d.Area += m.Area
+	}
+	for _, m := range prev.Modules {
+		d.Area -= m.Area
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range prev.Columns {
+		seen[idKey(c.ID)] = true
+	}
+	for _, rd := range prev.Rounds {
+		for _, it := range rd.Coins {
+			seen[idKey(it.ID)] = true
+		}
+		for _, it := range rd.Cells {
+			seen[idKey(it.ID)] = true
+		}
+		d.ProverActions -= len(rd.ProverActions)
+		d.VerifierActions -= len(rd.VerifierActions)
+	}
+	for _, c := range next.Columns {
+		if !seen[idKey(c.ID)] {
+			d.Columns = append(d.Columns, c)
+		}
+	}
+	for _, rd := range next.Rounds {
+		if rd.ID >= len(prev.Rounds) {
+			d.Rounds = append(d.Rounds, rd.ID)
+		}
+		for _, it := range rd.Coins {
+			if !seen[idKey(it.ID)] {
+				d.Coins = append(d.Coins, it)
+			}
+		}
+		for _, it := range rd.Cells {
+			if !seen[idKey(it.ID)] {
+				d.Cells = append(d.Cells, it)
+			}
+		}
+		d.ProverActions += len(rd.ProverActions)
+		d.VerifierActions += len(rd.VerifierActions)
+	}
+
+	wasReduced := make(map[string]bool)
+	for i, key := range queryKeys(prev.Queries) {
+		wasReduced[key] = prev.Queries[i].Reduced
+	}
+	for i, key := range queryKeys(next.Queries) {
+		q := next.Queries[i]
+		reduced, existed := wasReduced[key]
+		switch {
+		case !existed:
+			d.Queries = append(d.Queries, q)
+		case q.Reduced && !reduced:
+			d.Reduced = append(d.Reduced, q)
+		}
+	}
+	return d
+}
+
+func moduleKey(m Module) string { return fmt.Sprintf("module/%d", m.Index) }
+
+func idKey(id wiop.ObjectID) string { return fmt.Sprintf("id/%d", uint64(id)) }
+
+// queryKeys returns a key per query. Queries have no ID, so the key is their
+// kind and path, disambiguated by the number of earlier queries sharing both;
+// [Take] lists queries in registration order, so keys are stable across
+// snapshots.
+func queryKeys(queries []Query) []string {
+	keys := make([]string, len(queries))
+	count := make(map[string]int)
+	for i, q := range queries {
+		base := fmt.Sprintf("query/%s/%d/%s", q.Kind, q.Module, q.Path)
+		keys[i] = fmt.Sprintf("%s#%d", base, count[base])
+		count[base]++
+	}
+	return keys
+}
@@ -0,0 +1,257 @@
+package inspect
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// WriteJSON writes v, typically a [Snapshot] or a [Report], as indented JSON.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteDOT writes s as a Graphviz digraph. Modules are grouped in one cluster
+// per creating pass and linked to the rounds in which their columns are
+// committed; rounds form a chain labelled with their coins, cells and
+// queries.
+func WriteDOT(w io.Writer, s *Snapshot) error {
+	g := summarize(s)
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %s {\n", dotQuote([]string{s.System}))
+	b.WriteString("  rankdir=LR;\n  node [shape=box, fontname=\"monospace\"];\n")
+
+	b.WriteString("  subgraph cluster_rounds {\n    label=\"rounds\";\n")
+	for _, r := range g.rounds {
+		fmt.Fprintf(b, "    %s [label=%s];\n", r.node, dotQuote(r.label))
+	}
+	b.WriteString("  }\n")
+	for i := 1; i < len(g.rounds); i++ {
+		fmt.Fprintf(b, "  %s -> %s [style=dashed];\n", g.rounds[i-1].node, g.rounds[i].node)
+	}
+
+	for i, p := range g.passes {
+		fmt.Fprintf(b, "  subgraph cluster_pass_%d {\n    label=%s;\n", i, dotQuote([]string{p.name}))
+		for _, m := range p.modules {
+			fmt.Fprintf(b, "    %s [label=%s];\n", m.node, dotQuote(m.label))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(b, "  %s -> %s [label=\"%d\"];\n", e.from, e.to, e.columns)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes s as a Mermaid flowchart with the same layout as
+// [WriteDOT].
+func WriteMermaid(w io.Writer, s *Snapshot) error {
+	g := summarize(s)
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+
+	b.WriteString("  subgraph rounds\n")
+	for _, r := range g.rounds {
+		fmt.Fprintf(b, "    %s[%s]\n", r.node, mermaidQuote(r.label))
+	}
+	b.WriteString("  end\n")
+	for i := 1; i < len(g.rounds); i++ {
+		fmt.Fprintf(b, "  %s -.-> %s\n", g.rounds[i-1].node, g.rounds[i].node)
+	}
+
+	for i, p := range g.passes {
+		fmt.Fprintf(b, "  subgraph pass_%d[%s]\n", i, mermaidQuote([]string{p.name}))
+		for _, m := range p.modules {
+			fmt.Fprintf(b, "    %s[%s]\n", m.node, mermaidQuote(m.label))
+		}
+		b.WriteString("  end\n")
+	}
+	for _, e := range g.edges {
+		fmt.Fprintf(b, "  %s -- %d --> %s\n", e.from, e.columns, e.to)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteDiff writes a human-readable summary of every pass. Added modules are
+// listed by decreasing area so that unexpectedly large ones stand out.
+func WriteDiff(w io.Writer, passes []PassDiff) error {
+	b := &strings.Builder{}
+	for _, d := range passes {
+		fmt.Fprintf(b, "pass %s (%s): +%d rounds, +%d modules, +%d columns, +%d coins, +%d cells, +%d queries, %d reduced, area %+d\n",
+			d.Pass, d.Duration, len(d.Rounds), len(d.Modules), len(d.Columns), len(d.Coins), len(d.Cells),
+			len(d.Queries), len(d.Reduced), d.Area)
+
+		modules := slices.Clone(d.Modules)
+		slices.SortStableFunc(modules, func(a, b Module) int { return cmp.Compare(b.Area, a.Area) })
+		for _, m := range modules {
+			fmt.Fprintf(b, "  + module %s: %s, %d columns, area %d\n", m.Path, sizeString(m), m.NumColumns, m.Area)
+		}
+		for _, r := range d.Resized {
+			fmt.Fprintf(b, "  ~ module %s: size %d -> %d\n", r.Path, r.From, r.To)
+		}
+		for _, c := range countBy(d.Columns, func(c Column) string {
+			return fmt.Sprintf("%s columns in round %d", c.Visibility, c.Round)
+		}) {
+			fmt.Fprintf(b, "  + %s\n", c)
+		}
+		for _, c := range countBy(d.Queries, func(q Query) string { return q.Kind }) {
+			fmt.Fprintf(b, "  + %s\n", c)
+		}
+		for _, c := range countBy(d.Reduced, func(q Query) string { return q.Kind + " reduced" }) {
+			fmt.Fprintf(b, "  - %s\n", c)
+		}
+		if d.ProverActions != 0 || d.VerifierActions != 0 {
+			fmt.Fprintf(b, "  + %d prover actions, %d verifier actions\n", d.ProverActions, d.VerifierActions)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graph is the module-level view of a snapshot shared by the DOT and Mermaid
+// writers. Individual columns are aggregated into edges: real systems have
+// far too many to draw.
+type graph struct {
+	rounds []graphNode
+	passes []graphPass
+	edges  []graphEdge
+}
+
+type graphNode struct {
+	node  string
+	label []string
+}
+
+type graphPass struct {
+	name    string
+	modules []graphNode
+}
+
+// graphEdge links a module to a round and counts the module's columns
+// committed in that round.
+type graphEdge struct {
+	from, to string
+	columns  int
+}
+
+func summarize(s *Snapshot) graph {
+	var g graph
+
+	queries := make(map[int][]Query)
+	for _, q := range s.Queries {
+		queries[q.Round] = append(queries[q.Round], q)
+	}
+	roundNode := func(id int) string {
+		if id == PrecomputedRound {
+			return "precomputed"
+		}
+		return fmt.Sprintf("r%d", id)
+	}
+	addRound := func(id int, title string, extra ...string) {
+		label := append([]string{title}, extra...)
+		label = append(label, countBy(queries[id], func(q Query) string {
+			if q.Reduced {
+				return q.Kind + " (reduced)"
+			}
+			return q.Kind
+		})...)
+		g.rounds = append(g.rounds, graphNode{node: roundNode(id), label: label})
+	}
+	hasPrecomputed := len(queries[PrecomputedRound]) > 0
+	for _, c := range s.Columns {
+		hasPrecomputed = hasPrecomputed || c.Round == PrecomputedRound
+	}
+	if hasPrecomputed {
+		addRound(PrecomputedRound, "precomputed")
+	}
+	for _, r := range s.Rounds {
+		addRound(r.ID, fmt.Sprintf("round %d", r.ID),
+			fmt.Sprintf("%d coins, %d cells", len(r.Coins), len(r.Cells)),
+			fmt.Sprintf("%d prover, %d verifier actions", len(r.ProverActions), len(r.VerifierActions)))
+	}
+
+	passIndex := make(map[string]int)
+	for _, m := range s.Modules {
+		i, ok := passIndex[m.Pass]
+		if !ok {
+			i = len(g.passes)
+			passIndex[m.Pass] = i
+			g.passes = append(g.passes, graphPass{name: cmp.Or(m.Pass, Declared)})
+		}
+		g.passes[i].modules = append(g.passes[i].modules, graphNode{
+			node: fmt.Sprintf("m%d", m.Index),
+			label: []string{
+				m.Path,
+				fmt.Sprintf("%s × %d columns", sizeString(m), m.NumColumns),
+				"padding " + m.Padding,
+			},
+		})
+	}
+
+	type edgeKey struct{ module, round int }
+	counts := make(map[edgeKey]int)
+	var order []edgeKey
+	for _, c := range s.Columns {
+		k := edgeKey{c.Module, c.Round}
+		if counts[k] == 0 {
+			order = append(order, k)
+		}
+		counts[k]++
+	}
+	for _, k := range order {
+		g.edges = append(g.edges, graphEdge{from: fmt.Sprintf("m%d", k.module), to: roundNode(k.round), columns: counts[k]})
+	}
+	return g
+}
+
+// countBy groups items by key and returns one "n key" line per key, in order
+// of first appearance.
+func countBy[T any](items []T, key func(T) string) []string {
+	counts := make(map[string]int)
+	var keys []string
+	for _, it := range items {
+		k := key(it)
+		if counts[k] == 0 {
+			keys = append(keys, k)
+		}
+		counts[k]++
+	}
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%d %s", counts[k], k)
+	}
+	return lines
+}
+
+func sizeString(m Module) string {
+	switch {
+	case m.Dynamic:
+		return "dynamic size"
+	case m.Size == 0:
+		return "unsized"
+	default:
+		return fmt.Sprintf("%d rows", m.Size)
+	}
+}
+
+// dotQuote returns a DOT string literal whose lines are the given lines.
+func dotQuote(lines []string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(strings.Join(lines, "\n")) + `"`
+}
+
+// mermaidQuote returns a quoted Mermaid label whose lines are the given lines.
+func mermaidQuote(lines []string) string {
+	r := strings.NewReplacer(`"`, "#quot;", "\n", "<br/>")
+	return `"` + r.Replace(strings.Join(lines, "\n")) + `"`
+}
@@ -26,9 +26,11 @@ import "fmt"
 // This keeps a single Query type for both "global" (multi-valued) and "local"
 // (scalar) vanishing predicates.
 //
-// Reading row −1 (or any negative resolved row) requires m to be statically
-// sized so the row can be normalised; an unsized or dynamic module cannot
-// resolve negative rows at construction time.
+// Reading row −1 (or any negative resolved row) on a statically sized module
+// normalises the row modulo n at construction time. On a dynamic module the
+// row is kept negative and counted from the end of the domain once the runtime
+// size is known (see [ColumnPosition.Position]). An unsized static module
+// cannot resolve negative rows.
 //
 // Column ownership is not validated: in line with [Module.NewVanishing], the
 // caller is responsible for ensuring that the expression references columns
@@ -36,8 +38,13 @@ import "fmt"
 // correctly (each [*ColumnPosition] resolves through its own column's module)
 // but is outside the intended use.
 //
-// Panics if ctx or expr is nil, if position is not in {−1, 0, 1}, or if a
-// resolved row is negative on an unsized/dynamic module.
+// On a module with masking rows (see [Module.SetMaskingRows]), expr may not
+// read a masking row: position −1 is then rejected, as is any shift reaching
+// the end of the domain.
+//
+// Panics if ctx or expr is nil, if position is not in {−1, 0, 1}, if a
+// resolved row is negative on an unsized static module, or if it is a masking
+// row.
 func (m *Module) NewLocalConstraint(ctx *ContextFrame, expr Expression, position int) *Vanishing {
 	if ctx == nil {
 		panic("wiop: Module.NewLocalConstraint requires a non-nil ContextFrame")
@@ -93,12 +100,26 @@ func (m *Module) lowerToRow(expr Expression, position int) Expression {
 	})
 }
 
+// isMaskingRow reports whether row is one of the masking rows of m. A
+// non-negative row of a dynamic module is resolved at runtime and never
+// reported.
+func (m *Module) isMaskingRow(row int) bool {
+	if m.maskingRows == 0 {
+		return false
+	}
+	if row < 0 {
+		return -row <= m.maskingRows
+	}
+	return m.IsSized() && row >= m.size-m.maskingRows
+}
+
 // columnViewAtRow converts a [*ColumnView] into the [*ColumnPosition] it
 // evaluates to at logical row `position`. The resolved row is
 // (position + cv.ShiftingOffset) and is normalised modulo the module size
 // when the module is sized. For an unsized or dynamic module the resolved
-// row is passed through directly; a negative resolved row is rejected because
-// it cannot be normalised without knowing n.
+// row is passed through directly. A negative resolved row is end-relative on
+// a dynamic module and rejected on an unsized static one, which has no
+// runtime size to resolve it against.
 func columnViewAtRow(cv *ColumnView, position int) *ColumnPosition {
 	target := position + cv.ShiftingOffset
 	m := cv.Column.Module
@@ -108,11 +129,17 @@ func columnViewAtRow(cv *ColumnView, position int) *ColumnPosition {
 		if target < 0 {
 			target += n
 		}
-	} else if target < 0 {
+	} else if target < 0 && !m.IsDynamic() {
 		panic(fmt.Sprintf(
-			"wiop: NewLocalConstraint: resolved row %d (position %d + shift %d) on column %q requires a sized module",
+			"wiop: NewLocalConstraint: resolved row %d (position %d + shift %d) on column %q requires a sized or dynamic module",
 			target, position, cv.ShiftingOffset, cv.Column.Context.Path(),
 		))
 	}
+	if m.isMaskingRow(target) {
+		panic(fmt.Sprintf(
+			"wiop: NewLocalConstraint: resolved row %d (position %d + shift %d) on column %q is a masking row of module %q",
+			target, position, cv.ShiftingOffset, cv.Column.Context.Path(), m.Context.Path(),
+		))
+	}
 	return &ColumnPosition{Column: cv.Column, Position: target}
 }
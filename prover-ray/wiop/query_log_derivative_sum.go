@@ -47,6 +47,9 @@ type Fraction struct {
 // Filter_k[row] = 0 should not contribute to the running sum even if their
 // numerator/denominator would otherwise be ill-defined on those rows.
 //
+// The rows range over [Module.UsableSize]: the masking rows of a module in
+// zero-knowledge mode (see [Module.SetMaskingRows]) do not contribute.
+//
 // LogDerivativeSum implements [AssignableQuery] but not [GnarkCheckableQuery]:
 // a compiler pass must reduce it before gnark verification.
 //
@@ -101,8 +104,9 @@ func (rr *LogDerivativeSum) Check(rt Runtime) error {
 // runtime assignments. It is the shared core of [SelfAssign] and [Check].
 //
 // Rows where Filter_k[row] is zero are skipped — neither the inversion of
-// Den_k[row] nor the multiplication by Num_k[row] is performed. Panics on a
-// zero denominator only when the corresponding filter value is non-zero.
+// Den_k[row] nor the multiplication by Num_k[row] is performed, and so are the
+// masking rows. Panics on a zero denominator only when the corresponding
+// filter value is non-zero.
 func (rr *LogDerivativeSum) reduce(rt Runtime) field.Gen {
 	acc := field.ElemZero()
 
@@ -146,9 +150,9 @@ func (rr *LogDerivativeSum) reduce(rt Runtime) field.Gen {
 		var n int
 		switch {
 		case numIsVec:
-			n = numVec.Plain.Len()
+			n = min(numVec.Plain.Len(), f.Numerator.Module().UsableSize(rt))
 		case denIsVec:
-			n = denVec.Plain.Len()
+			n = min(denVec.Plain.Len(), f.Denominator.Module().UsableSize(rt))
 		}
 
 		readGen := func(scalar ConcreteField, vec ConcreteVector, isVec bool, row int) field.Gen {
@@ -0,0 +1,259 @@
+package wiop
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// BusMessage is one participant of a [MessageBus]: a module emitting, at every
+// row, Multiplicity copies of the tuple (Tuple[0][row], Tuple[1][row], …).
+//
+// Tuple entries may be vector-valued expressions or scalars (e.g. a constant
+// tag distinguishing several kinds of messages on the same bus). Multiplicity
+// may be either as well; a nil Multiplicity stands for the constant 1, so
+// every row sends or receives its tuple exactly once. Rows with a zero
+// multiplicity do not take part in the bus.
+//
+// All vector-valued expressions of a BusMessage must share one module, and at
+// least one of them must be vector-valued. These invariants are enforced by
+// [System.NewMessageBus].
+type BusMessage struct {
+	// Multiplicity is the number of copies of the row's tuple carried on the
+	// bus. Nil means 1.
+	Multiplicity Expression
+	// Tuple is the ordered list of expressions forming the message. Contains
+	// at least one entry.
+	Tuple []Expression
+}
+
+// Module returns the module shared by the vector-valued expressions of the
+// message.
+func (bm BusMessage) Module() *Module {
+	if bm.Multiplicity != nil {
+		if m := bm.Multiplicity.Module(); m != nil {
+			return m
+		}
+	}
+	for _, e := range bm.Tuple {
+		if m := e.Module(); m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// Width returns the number of entries of the tuple.
+func (bm BusMessage) Width() int { return len(bm.Tuple) }
+
+// MessageBus is a [Query] stating cross-module communication: the multiset of
+// tuples sent by the Sends participants, each counted with its multiplicity,
+// equals the multiset of tuples received by the Receives participants.
+//
+// It lets modules of different shapes and sizes — for instance a ZkC-driven
+// module and a native accelerator module — exchange rows without either side
+// committing to the layout of the other. The masking rows of a module in
+// zero-knowledge mode (see [Module.SetMaskingRows]) carry no message.
+//
+// MessageBus does not implement [GnarkCheckableQuery]: the messagebus
+// compiler pass reduces it to a [LogDerivativeSum].
+//
+// Use [System.NewMessageBus] to construct and register an instance.
+type MessageBus struct {
+	baseQuery
+	// Sends are the participants pushing tuples onto the bus.
+	Sends []BusMessage
+	// Receives are the participants pulling tuples from the bus.
+	Receives []BusMessage
+}
+
+// Round implements [Query]. Returns the latest round across every expression
+// of every participant.
+func (mb *MessageBus) Round() *Round {
+	var best *Round
+	update := func(e Expression) {
+		if e == nil {
+			return
+		}
+		if r := maxRoundInExpr(e); r != nil && (best == nil || r.ID > best.ID) {
+			best = r
+		}
+	}
+	for _, msgs := range [2][]BusMessage{mb.Sends, mb.Receives} {
+		for _, msg := range msgs {
+			update(msg.Multiplicity)
+			for _, e := range msg.Tuple {
+				update(e)
+			}
+		}
+	}
+	return best
+}
+
+// Width returns the width shared by every tuple on the bus.
+func (mb *MessageBus) Width() int {
+	if len(mb.Sends) > 0 {
+		return mb.Sends[0].Width()
+	}
+	return mb.Receives[0].Width()
+}
+
+// Check implements [Query]. Verifies that every tuple is sent and received
+// the same number of times.
+//
+// Tuples are hashed via Horner's rule with a random extension-field scalar,
+// and the multiplicities are accumulated per hash: sends add, receives
+// subtract. The check passes iff every balance is zero. As for
+// [LookupQuery.Check], a hash collision can only cause a false outcome with
+// negligible probability.
+func (mb *MessageBus) Check(rt Runtime) error {
+	var (
+		alpha   = field.RandomElemExt()
+		balance = make(map[field.Ext]field.Gen)
+	)
+	for _, msg := range mb.Sends {
+		msg.accumulate(rt, alpha, balance, false)
+	}
+	for _, msg := range mb.Receives {
+		msg.accumulate(rt, alpha, balance, true)
+	}
+	for _, v := range balance {
+		if !v.IsZero() {
+			return fmt.Errorf(
+				"wiop: MessageBus(%s).Check: a tuple is not sent and received the same number of times",
+				mb.context.Path(),
+			)
+		}
+	}
+	return nil
+}
+
+// accumulate adds (or subtracts, if negate is set) the multiplicity of every
+// usable row of the message to the balance of the row's tuple hash.
+func (bm BusMessage) accumulate(rt Runtime, alpha field.Gen, balance map[field.Ext]field.Gen, negate bool) {
+	var (
+		m    = bm.Module()
+		n    = m.RuntimeSize(rt)
+		mult = busOperand(rt, bm.Multiplicity)
+		tup  = make([]busOperandValue, len(bm.Tuple))
+	)
+	for i, e := range bm.Tuple {
+		tup[i] = busOperand(rt, e)
+	}
+
+	for row := range m.UsableSize(rt) {
+		mu := mult.at(m.Padding, n, row)
+		if mu.IsZero() {
+			continue
+		}
+		var h field.Gen
+		for _, t := range tup {
+			h = h.Mul(alpha).Add(t.at(m.Padding, n, row))
+		}
+		if negate {
+			balance[h.Ext] = balance[h.Ext].Sub(mu)
+		} else {
+			balance[h.Ext] = balance[h.Ext].Add(mu)
+		}
+	}
+}
+
+// busOperandValue is the evaluation of one expression of a [BusMessage]:
+// either a vector or a scalar broadcast over the rows.
+type busOperandValue struct {
+	isVec  bool
+	vec    ConcreteVector
+	scalar field.Gen
+}
+
+// busOperand evaluates e against rt. A nil e evaluates to the constant 1.
+func busOperand(rt Runtime, e Expression) busOperandValue {
+	switch {
+	case e == nil:
+		return busOperandValue{scalar: field.ElemOne()}
+	case e.IsMultiValued():
+		return busOperandValue{isVec: true, vec: e.EvaluateVector(rt)}
+	default:
+		return busOperandValue{scalar: e.EvaluateSingle(rt).Value}
+	}
+}
+
+// at returns the value of the operand at logical row pos of a module of size
+// n with the given padding direction.
+func (v *busOperandValue) at(padding PaddingDirection, n, pos int) field.Gen {
+	if !v.isVec {
+		return v.scalar
+	}
+	return v.vec.ElementAtN(padding, n, pos)
+}
+
+// NewMessageBus constructs and registers a [MessageBus] query on sys.
+//
+// Invariants enforced at construction:
+//   - at least one participant is given, across sends and receives.
+//   - every tuple is non-empty and all tuples have the same width.
+//   - within a participant, the vector-valued expressions (multiplicity and
+//     tuple entries) share one module, and there is at least one of them.
+//
+// Panics if ctx is nil or any invariant is violated.
+func (sys *System) NewMessageBus(ctx *ContextFrame, sends, receives []BusMessage) *MessageBus {
+	if ctx == nil {
+		panic("wiop: System.NewMessageBus requires a non-nil ContextFrame")
+	}
+	if len(sends)+len(receives) == 0 {
+		panic("wiop: System.NewMessageBus requires at least one participant")
+	}
+
+	width := -1
+	validate := func(side string, msgs []BusMessage) {
+		for i, msg := range msgs {
+			if msg.Width() == 0 {
+				panic(fmt.Sprintf("wiop: System.NewMessageBus: %s[%d] has an empty tuple", side, i))
+			}
+			if width < 0 {
+				width = msg.Width()
+			}
+			if msg.Width() != width {
+				panic(fmt.Sprintf(
+					"wiop: System.NewMessageBus: %s[%d] has width %d but expected %d; all tuples must have the same width",
+					side, i, msg.Width(), width,
+				))
+			}
+
+			m := msg.Module()
+			if m == nil {
+				panic(fmt.Sprintf(
+					"wiop: System.NewMessageBus: %s[%d] has no vector-valued expression; "+
+						"at least one of its multiplicity or tuple entries must be vector-valued",
+					side, i,
+				))
+			}
+			exprs := append([]Expression{msg.Multiplicity}, msg.Tuple...)
+			for _, e := range exprs {
+				if e == nil {
+					continue
+				}
+				if em := e.Module(); em != nil && em != m {
+					panic(fmt.Sprintf(
+						"wiop: System.NewMessageBus: %s[%d] mixes modules %q and %q; "+
+							"all vector-valued expressions of a participant must share a module",
+						side, i, m.Context.Path(), em.Context.Path(),
+					))
+				}
+			}
+		}
+	}
+	validate("sends", sends)
+	validate("receives", receives)
+
+	mb := &MessageBus{
+		baseQuery: baseQuery{
+			context:     ctx,
+			Annotations: make(Annotations),
+		},
+		Sends:    sends,
+		Receives: receives,
+	}
+	sys.MessageBuses = append(sys.MessageBuses, mb)
+	return mb
+}
@@ -0,0 +1,91 @@
+package wiop_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBus_Scenarios(t *testing.T) {
+	for _, build := range wioptest.MessageBusScenarios() {
+		sc := build()
+		t.Run(sc.Name, func(t *testing.T) {
+			rt := wiop.NewRuntime(sc.Sys)
+			sc.AssignHonest(&rt)
+			require.NoError(t, sc.Bus.Check(rt), "honest witness must pass Check")
+
+			sc := build()
+			rt = wiop.NewRuntime(sc.Sys)
+			sc.AssignInvalid(&rt)
+			assert.Error(t, sc.Bus.Check(rt), "invalid witness must be rejected by Check")
+		})
+	}
+}
+
+func TestMessageBus_RoundAndWidth(t *testing.T) {
+	sys := wiop.NewSystemf("s")
+	r0 := sys.NewRound()
+	r1 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("a"), 4, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("b"), 4, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("colA"), wiop.VisibilityOracle, r0)
+	b := modB.NewColumn(sys.Context.Childf("colB"), wiop.VisibilityOracle, r1)
+	tag := wiop.NewConstantField(field.NewFromString("3"))
+
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{tag, a.View()}}},
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{tag, b.View()}}},
+	)
+	assert.Equal(t, r1, bus.Round())
+	assert.Equal(t, 2, bus.Width())
+	require.Len(t, sys.MessageBuses, 1)
+	assert.Same(t, bus, sys.MessageBuses[0])
+}
+
+func TestNewMessageBus_Panics(t *testing.T) {
+	sys := wiop.NewSystemf("s")
+	r0 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("a"), 4, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("b"), 4, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("colA"), wiop.VisibilityOracle, r0)
+	b := modB.NewColumn(sys.Context.Childf("colB"), wiop.VisibilityOracle, r0)
+	one := wiop.NewConstantField(field.One())
+
+	msg := func(exprs ...wiop.Expression) []wiop.BusMessage {
+		return []wiop.BusMessage{{Tuple: exprs}}
+	}
+
+	cases := map[string]func(){
+		"NilContext": func() { sys.NewMessageBus(nil, msg(a.View()), msg(b.View())) },
+		"NoParticipant": func() {
+			sys.NewMessageBus(sys.Context.Childf("bus"), nil, nil)
+		},
+		"EmptyTuple": func() {
+			sys.NewMessageBus(sys.Context.Childf("bus"), msg(), msg(b.View()))
+		},
+		"WidthMismatch": func() {
+			sys.NewMessageBus(sys.Context.Childf("bus"), msg(a.View(), a.View()), msg(b.View()))
+		},
+		"ScalarOnly": func() {
+			sys.NewMessageBus(sys.Context.Childf("bus"), msg(one), msg(b.View()))
+		},
+		"MixedModules": func() {
+			sys.NewMessageBus(sys.Context.Childf("bus"), msg(a.View(), b.View()), msg(b.View(), b.View()))
+		},
+		"MultiplicityOnOtherModule": func() {
+			sys.NewMessageBus(
+				sys.Context.Childf("bus"),
+				[]wiop.BusMessage{{Multiplicity: b.View(), Tuple: []wiop.Expression{a.View()}}},
+				msg(b.View()),
+			)
+		},
+	}
+	for name, fn := range cases {
+		t.Run(name, func(t *testing.T) { assert.Panics(t, fn) })
+	}
+}
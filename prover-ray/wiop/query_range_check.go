@@ -20,13 +20,14 @@ type RangeCheck struct {
 // Round implements [Query]. Returns the round of the checked column.
 func (rc *RangeCheck) Round() *Round { return rc.Handle.Round() }
 
-// Check implements [Query]. Verifies that every row of Handle lies in [0, B).
+// Check implements [Query]. Verifies that every usable row of Handle (see
+// [Module.UsableSize]) lies in [0, B).
 func (rc *RangeCheck) Check(rt Runtime) error {
 	m := rc.Handle.Module
-	n := m.Size()
+	n := m.RuntimeSize(rt)
 	cv := rt.GetColumnAssignment(rc.Handle)
-	for row := range n {
-		elem := cv.ElementAt(m, row)
+	for row := range m.UsableSize(rt) {
+		elem := cv.ElementAtN(m.Padding, n, row)
 		if !elem.IsBase() {
 			return fmt.Errorf(
 				"wiop: RangeCheck(%s).Check: extension-field value at row %d",
@@ -101,6 +101,9 @@ func (t Table) Width() int { return len(t.Columns) }
 //   - Inclusion: every selected row of A appears in the union of selected
 //     rows across all B fragments.
 //
+// The masking rows of a module in zero-knowledge mode (see
+// [Module.SetMaskingRows]) take part in neither side.
+//
 // LookupQuery does not implement [GnarkCheckableQuery]: neither predicate
 // can be verified inside a gnark circuit. A compiler pass must reduce them
 // before gnark verification.
@@ -168,8 +171,10 @@ func inclusionBuildSet(bSet map[field.Ext]struct{}, alpha field.Gen, rt Runtime,
 	n := tab.Module().RuntimeSize(rt)
 	m := tab.Module()
 
-	if m.Padding == PaddingDirectionNone || !tableHasZeroShift(tab) {
-		for row := range n {
+	// The masking rows are random and left out of the relation; the masked
+	// data spans the module, so there is no padding anchor to probe.
+	if m.Padding == PaddingDirectionNone || m.maskingRows > 0 || !tableHasZeroShift(tab) {
+		for row := range m.UsableSize(rt) {
 			if tab.Selector != nil {
 				if sel := tableElemAt(rt, tab.Selector, row, n); sel.IsZero() {
 					continue
@@ -216,8 +221,8 @@ func inclusionCheckSet(bSet map[field.Ext]struct{}, alpha field.Gen, rt Runtime,
 	n := tab.Module().RuntimeSize(rt)
 	m := tab.Module()
 
-	if m.Padding == PaddingDirectionNone || !tableHasZeroShift(tab) {
-		for row := range n {
+	if m.Padding == PaddingDirectionNone || m.maskingRows > 0 || !tableHasZeroShift(tab) {
+		for row := range m.UsableSize(rt) {
 			if tab.Selector != nil {
 				if sel := tableElemAt(rt, tab.Selector, row, n); sel.IsZero() {
 					continue
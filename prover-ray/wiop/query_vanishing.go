@@ -104,6 +104,10 @@ func (v *Vanishing) CheckGnark(_ frontend.API, _ GnarkRuntime) {
 //   - positive k: the last k rows (represented as −k, …, −1) are cancelled.
 //   - negative k: the first |k| rows (represented as 0, …, |k|−1) are cancelled.
 //
+// On a module with masking rows (see [Module.SetMaskingRows]), the masking
+// rows are cancelled as well, and so are the rows whose shifted reads land in
+// them.
+//
 // The resulting position list is sorted and deduplicated before storage.
 //
 // Panics if ctx or expr is nil.
@@ -124,7 +128,9 @@ func (m *Module) NewVanishing(ctx *ContextFrame, expr Expression) *Vanishing {
 //   - a non-empty list gives the exact rows to skip.
 //
 // Positive positions index from the start; negative positions index from the
-// end. The list is sorted and deduplicated before storage.
+// end. The list is sorted and deduplicated before storage. The masking rows of
+// the module, if any, are always added to it since they hold random values,
+// together with the rows whose shifted reads land in them.
 //
 // Panics if ctx or expr is nil.
 func (m *Module) NewVanishingManual(ctx *ContextFrame, expr Expression, positions ...int) *Vanishing {
@@ -142,6 +148,9 @@ func (m *Module) NewVanishingManual(ctx *ContextFrame, expr Expression, position
 // [Module.NewVanishingManual]. It constructs the [Vanishing], appends it to
 // the module's Vanishings list, and returns it.
 func (m *Module) newVanishing(ctx *ContextFrame, expr Expression, positions []int) *Vanishing {
+	if m.maskingRows > 0 && expr.IsMultiValued() {
+		positions = m.withMaskingRows(expr, positions)
+	}
 	v := &Vanishing{
 		baseQuery: baseQuery{
 			context:     ctx,
@@ -154,6 +163,34 @@ func (m *Module) newVanishing(ctx *ContextFrame, expr Expression, positions []in
 	return v
 }
 
+// withMaskingRows adds to positions the rows of a masked module on which expr
+// reads a masking row: the k masking rows themselves, for a largest positive
+// shift s, the s rows before them and, for a negative shift −s, the first
+// rows whose reads wrap around onto the masking rows, i.e. the rows
+// max(0, s−k), …, s−1.
+func (m *Module) withMaskingRows(expr Expression, positions []int) []int {
+	shifts := make(map[int]struct{})
+	collectShifts(expr, shifts)
+	var (
+		k     = m.maskingRows
+		reach = k
+		res   = slices.Clone(positions)
+	)
+	for off := range shifts {
+		if off > 0 {
+			reach = max(reach, k+off)
+			continue
+		}
+		for i := max(0, -off-k); i < -off; i++ {
+			res = append(res, i)
+		}
+	}
+	for i := 1; i <= reach; i++ {
+		res = append(res, -i)
+	}
+	return dedupSortedInts(res)
+}
+
 // cancelledPositionsFromExpr collects all non-zero [ColumnView.ShiftingOffset]
 // values from the expression tree, converts each shift to a set of cancelled
 // row positions, and returns the merged set in sorted order.
@@ -20,6 +20,21 @@ type VerifierAction interface {
 	Check(Runtime) error
 }
 
+// ArithmeticVerifierAction is an optional extension of [VerifierAction] for
+// checks that reduce to a conjunction of scalar constraints. Each returned
+// expression is built from [Cell], [CoinField] and scalar [Constant] leaves
+// only, and the check passes iff every expression evaluates to zero. The
+// verifier code generator relies on it to re-express the verifier of a
+// compiled system.
+type ArithmeticVerifierAction interface {
+	VerifierAction
+	// Constraints returns the scalar expressions that must all evaluate to
+	// zero for Check to pass. It returns an error if the check of this
+	// particular action cannot be spelled out, e.g. because it depends on
+	// the runtime size of a dynamic module.
+	Constraints() ([]Expression, error)
+}
+
 // Planner is an optional extension of [ProverAction] for actions that
 // pre-allocate scratch memory. [Materialize] calls Plan once on every action
 // that implements this interface, after all compiler passes complete. The arena
@@ -33,10 +33,15 @@ type Module struct {
 	// size is zero when the module has not yet been sized. Use [Module.Size]
 	// and [Module.SetSize] rather than accessing this field directly.
 	size int
-	// isDynamic indicates that this module's domain size is supplied per-Runtime
-	// via [WithModuleSize] instead of being fixed once via [Module.SetSize].
+	// isDynamic indicates that this module's domain size is chosen per-Runtime
+	// (see [Runtime.AssignColumn] and [Runtime.SetModuleSize]) instead of being
+	// fixed once via [Module.SetSize].
 	// Dynamic modules always report IsSized() == false from the static API.
 	isDynamic bool
+	// maskingRows is the number of trailing rows reserved for random masking
+	// values, see [Module.SetMaskingRows]. Zero when the module is not in
+	// zero-knowledge mode.
+	maskingRows int
 	// index is the position of this module in [System.Modules]. Set once at
 	// registration time by [System.NewModule] and used to construct column IDs.
 	index int
@@ -49,8 +54,8 @@ type Module struct {
 // Module.
 func (m *Module) System() *System { return m.system }
 
-// IsDynamic reports whether this module's domain size is supplied per-Runtime
-// via [WithModuleSize] rather than fixed statically via [Module.SetSize].
+// IsDynamic reports whether this module's domain size is chosen per-Runtime
+// rather than fixed statically via [Module.SetSize].
 func (m *Module) IsDynamic() bool { return m.isDynamic }
 
 // Size returns the declared domain size of the module. Returns 0 if the module
@@ -64,8 +69,8 @@ func (m *Module) IsSized() bool { return m.size > 0 }
 
 // RuntimeSize returns the effective domain size for the given Runtime.
 // For static modules it delegates to [Module.Size] (panics if not yet sized).
-// For dynamic modules it reads the size registered via [WithModuleSize]
-// (panics if no size was provided for this Runtime).
+// For dynamic modules it reads the size recorded in the Runtime by
+// [Runtime.AssignColumn] or [Runtime.SetModuleSize] (panics if there is none).
 func (m *Module) RuntimeSize(rt Runtime) int {
 	if !m.isDynamic {
 		return m.Size()
@@ -73,15 +78,23 @@ func (m *Module) RuntimeSize(rt Runtime) int {
 	return rt.dynamicModuleSize(m)
 }
 
+// UsableSize returns the number of rows of the module in the given Runtime
+// that are not masking rows (see [Module.SetMaskingRows]). The row-wise
+// queries (vanishings, lookups, range checks, log-derivative sums and message
+// buses) only range over these rows.
+func (m *Module) UsableSize(rt Runtime) int {
+	return m.RuntimeSize(rt) - m.maskingRows
+}
+
 // SetSize fixes the domain size of the module. It may be called at any point
 // after construction but only once; subsequent calls panic.
 //
 // Panics if size is not positive, if the module is already sized, or if the
-// module is dynamic (dynamic modules are sized via [WithModuleSize] on each
-// [Runtime]).
+// module is dynamic (dynamic modules are sized on each [Runtime], see
+// [Runtime.SetModuleSize]).
 func (m *Module) SetSize(size int) {
 	if m.isDynamic {
-		panic(fmt.Sprintf("wiop: module %q is dynamic; set its size via WithModuleSize on each Runtime", m.Context.Path()))
+		panic(fmt.Sprintf("wiop: module %q is dynamic; set its size via Runtime.SetModuleSize", m.Context.Path()))
 	}
 	if size <= 0 {
 		panic(fmt.Sprintf("wiop: Module.SetSize requires a positive size, got %d", size))
@@ -90,9 +103,50 @@ func (m *Module) SetSize(size int) {
 		panic(fmt.Sprintf("wiop: module %q is already sized to %d; cannot resize to %d",
 			m.Context.Path(), m.size, size))
 	}
+	if size <= m.maskingRows {
+		panic(fmt.Sprintf("wiop: module %q has %d masking rows; size %d leaves no usable row",
+			m.Context.Path(), m.maskingRows, size))
+	}
 	m.size = size
 }
 
+// MaskingRows returns the number of masking rows of the module, zero unless
+// it was put in zero-knowledge mode with [Module.SetMaskingRows].
+func (m *Module) MaskingRows() int { return m.maskingRows }
+
+// SetMaskingRows puts the module in zero-knowledge mode: the last k rows of
+// its domain are reserved for masking. [System.Prove] overwrites them with
+// uniformly random values in every column of the module, whether assigned by
+// the witness hook or by the prover actions of a later round, so that up to k
+// evaluations of each column outside the domain (as opened by the global and
+// mpts compilers) are independent of the witness. Columns flagged with
+// [Column.SkipMasking] are left as is.
+//
+// The constraints of the module only hold on the usable rows (see
+// [Module.UsableSize]): every [Vanishing] registered afterwards cancels the
+// masking rows, together with the rows whose shifted reads land in them,
+// local constraints may not read them, and the lookups, range checks,
+// log-derivative sums and message buses skip them.
+//
+// Panics if k is negative, if the module is left-padded (its data would sit
+// on the masking rows), if constraints were already registered on it, or if
+// it is sized and k leaves no usable row.
+func (m *Module) SetMaskingRows(k int) {
+	if k < 0 {
+		panic(fmt.Sprintf("wiop: Module.SetMaskingRows requires a non-negative count, got %d", k))
+	}
+	if m.Padding == PaddingDirectionLeft {
+		panic(fmt.Sprintf("wiop: module %q is left-padded; its data would overlap the masking rows", m.Context.Path()))
+	}
+	if len(m.Vanishings) > 0 {
+		panic(fmt.Sprintf("wiop: module %q already has constraints; set its masking rows first", m.Context.Path()))
+	}
+	if m.IsSized() && k >= m.size {
+		panic(fmt.Sprintf("wiop: module %q of size %d cannot have %d masking rows", m.Context.Path(), m.size, k))
+	}
+	m.maskingRows = k
+}
+
 // newColumn is the shared constructor used by [Module.NewColumn] and
 // [Module.NewExtensionColumn]. It creates the column, appends it to both the
 // module's and the round's column lists, and returns it.
@@ -205,6 +259,11 @@ type Column struct {
 	// IsExtension indicates that this column is evaluated over an extended
 	// domain rather than the standard domain.
 	IsExtension bool
+	// SkipMasking exempts the column from the masking rows of its module
+	// (see [Module.SetMaskingRows]). Compilers set it on the columns that
+	// are not constrained row by row, such as quotient shares, whose
+	// masking rows are not free.
+	SkipMasking bool
 	// Annotations holds arbitrary metadata attached to this column.
 	Annotations Annotations
 	// Module is the owning module. It is always non-nil for a well-formed
@@ -373,12 +432,17 @@ func (cv *ColumnView) Visibility() Visibility { return cv.Column.Visibility }
 type ColumnPosition struct {
 	// Column is the parent column.
 	Column *Column
-	// Position is the zero-based row index into the column.
+	// Position is the zero-based row index into the column. A negative
+	// Position counts from the end of the domain: -1 is the last row. It is
+	// resolved against the runtime size, which is how rows near the end of a
+	// dynamic module are addressed.
 	Position int
 }
 
 // At constructs a [ColumnPosition] for this column at the given zero-based
 // row index. The result is a scalar [FieldPromise] evaluating to Column[pos].
+// A negative pos counts from the end of the domain, see
+// [ColumnPosition.Position].
 //
 // Panics if the receiver is nil.
 func (c *Column) At(pos int) *ColumnPosition {
@@ -436,9 +500,19 @@ func (cp *ColumnPosition) EvaluateVector(_ Runtime) ConcreteVector {
 // EvaluateSingle implements [Expression]. Returns the value of the parent
 // column at Position in the given runtime.
 func (cp *ColumnPosition) EvaluateSingle(rt Runtime) ConcreteField {
+	return ConcreteField{Value: cp.value(rt), promise: cp}
+}
+
+// value reads the parent column at Position, resolving a negative Position
+// against the runtime size of the module.
+func (cp *ColumnPosition) value(rt Runtime) field.Gen {
 	m := cp.Column.Module
-	elem := rt.GetColumnAssignment(cp.Column).ElementAtN(m.Padding, m.RuntimeSize(rt), cp.Position)
-	return ConcreteField{Value: elem, promise: cp}
+	n := m.RuntimeSize(rt)
+	pos := cp.Position
+	if pos < 0 {
+		pos += n
+	}
+	return rt.GetColumnAssignment(cp.Column).ElementAtN(m.Padding, n, pos)
 }
 
 // Open creates a "local opening" of this column position: a prover-supplied
@@ -475,11 +549,7 @@ func (cp *ColumnPosition) Open(ctx *ContextFrame) *Cell {
 	result := col.Round().NewLazyCell(
 		ctx.Childf("result"),
 		col.IsExtension,
-		func(rt Runtime) field.Gen {
-			m := col.Module
-			return rt.GetColumnAssignment(col).
-				ElementAtN(m.Padding, m.RuntimeSize(rt), cp.Position)
-		},
+		cp.value,
 	)
 
 	col.Module.NewVanishing(ctx, Sub(result, cp))
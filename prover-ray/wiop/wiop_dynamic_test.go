@@ -152,3 +152,92 @@ func TestDynamicModule_VanishingCheckFailure(t *testing.T) {
 		require.Error(t, v.Check(rt))
 	}
 }
+
+// TestDynamicModule_RoundsUpToPowerOfTwo verifies that a column whose length
+// is not a power of two sizes its module to the next power of two, and that
+// it reads as padded beyond its data.
+func TestDynamicModule_RoundsUpToPowerOfTwo(t *testing.T) {
+	sys, r0, _, dyn := newDynamicTestSystem(t)
+	col := dyn.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+
+	rt := wiop.NewRuntime(sys)
+	rt.AssignColumn(col, makeVec(5, 1))
+	require.Equal(t, 8, dyn.RuntimeSize(rt))
+
+	got := col.View().EvaluateVector(rt).Plain.AsBase()
+	require.Len(t, got, 8)
+	assert.Equal(t, field.NewFromString("1"), got[4])
+	assert.Equal(t, field.Zero(), got[5], "rows past the data hold the padding")
+}
+
+// TestDynamicModule_SetModuleSize verifies that the prover can pick a domain
+// larger than its columns, and that invalid sizes are rejected.
+func TestDynamicModule_SetModuleSize(t *testing.T) {
+	sys, r0, _, dyn := newDynamicTestSystem(t)
+	col := dyn.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	static := sys.NewSizedModule(sys.Context.Childf("static"), 4, wiop.PaddingDirectionNone)
+
+	rt := wiop.NewRuntime(sys)
+	rt.SetModuleSize(dyn, 16)
+	rt.AssignColumn(col, makeVec(5, 1))
+	assert.Equal(t, 16, dyn.RuntimeSize(rt), "a shorter column does not shrink the module")
+
+	assert.Panics(t, func() { rt.SetModuleSize(dyn, 8) }, "shrinking below the recorded size")
+	assert.Panics(t, func() { rt.SetModuleSize(dyn, 24) }, "not a power of two")
+	assert.Panics(t, func() { rt.SetModuleSize(dyn, 1<<23) }, "above the maximal column size")
+	assert.Panics(t, func() { rt.SetModuleSize(static, 8) }, "static module")
+}
+
+// TestDynamicModule_EndRelativePosition verifies that a negative position on
+// a dynamic module reads from the end of the runtime domain, across sizes.
+func TestDynamicModule_EndRelativePosition(t *testing.T) {
+	sys, r0, _, dyn := newDynamicTestSystem(t)
+	col := dyn.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	lc := dyn.NewLocalConstraint(sys.Context.Childf("lc"), wiop.Sub(col.View(), col.View().Shift(-1)), -1)
+
+	for _, n := range []int{2, 4, 16} {
+		elems := make([]field.Element, n)
+		for i := range elems {
+			elems[i].SetUint64(uint64(i / 2))
+		}
+		rt := wiop.NewRuntime(sys)
+		rt.AssignColumn(col, &wiop.ConcreteVector{Plain: field.VecFromBase(elems)})
+
+		last := col.At(-1).EvaluateSingle(rt).Value
+		assert.Equal(t, field.ElemFromBase(elems[n-1]), last, "n=%d", n)
+		// Rows n-2 and n-1 hold the same value since n is even.
+		require.NoError(t, lc.Check(rt), "n=%d", n)
+	}
+}
+
+// TestDynamicModule_VerifyDeclaredSizes verifies that System.Verify rejects
+// proofs whose declared dynamic sizes are malformed or smaller than the
+// committed columns, and accepts a larger declared size.
+func TestDynamicModule_VerifyDeclaredSizes(t *testing.T) {
+	sys, r0, _, dyn := newDynamicTestSystem(t)
+	col := dyn.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	proof := sys.Prove(func(rt *wiop.Runtime) { rt.AssignColumn(col, makeVec(5, 1)) })
+	require.Equal(t, map[int]int{0: 8}, proof.DynamicSizes)
+	require.NoError(t, sys.Verify(proof))
+
+	for _, tc := range []struct {
+		name  string
+		size  int
+		valid bool
+	}{
+		{"larger", 16, true},
+		{"not a power of two", 12, false},
+		{"smaller than the columns", 4, false},
+		{"above the maximal column size", 1 << 23, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tampered := proof
+			tampered.DynamicSizes = map[int]int{0: tc.size}
+			if tc.valid {
+				assert.NoError(t, sys.Verify(tampered))
+			} else {
+				assert.Error(t, sys.Verify(tampered))
+			}
+		})
+	}
+}
@@ -29,12 +29,16 @@ var _ VectorPromise = (*LagrangeSelector)(nil)
 // NewLagrangeSelector returns a new LagrangeSelector that is 1 at the given
 // row position and 0 elsewhere.
 //
-// Panics if position is negative, or — when the module is statically sized — if
-// position is outside [0, module.Size()). For dynamic modules the upper bound
-// cannot be checked at construction time and is enforced lazily by
-// [LagrangeSelector.EvaluateVector] against the runtime size.
+// On a dynamic module, a negative position counts from the end of the domain
+// (-1 is the last row) and is resolved against the runtime size, like
+// [ColumnPosition.Position].
+//
+// Panics if position is negative on a static module, or — when the module is
+// statically sized — if position is outside [0, module.Size()). For dynamic
+// modules the bounds cannot be checked at construction time and are enforced
+// lazily by [LagrangeSelector.EvaluateVector] against the runtime size.
 func NewLagrangeSelector(module *Module, position int) *LagrangeSelector {
-	if position < 0 {
+	if position < 0 && !module.IsDynamic() {
 		panic(fmt.Sprintf("wiop: NewLagrangeSelector: position must be non-negative, got %d", position))
 	}
 	if module.IsSized() && position >= module.Size() {
@@ -101,7 +105,11 @@ func (ls *LagrangeSelector) EvaluateVector(rt Runtime) ConcreteVector {
 		Padding: field.Zero(),
 	}
 
-	res.Plain.AsBase()[ls.Position] = field.One()
+	pos := ls.Position
+	if pos < 0 {
+		pos += size
+	}
+	res.Plain.AsBase()[pos] = field.One()
 	return res
 }
 
@@ -114,8 +122,9 @@ func (ls *LagrangeSelector) EvaluateVector(rt Runtime) ConcreteVector {
 //
 //	L_Position(X) = ω^Position · (X^n − 1) / (n · (X − ω^Position))
 //
-// where ω is the canonical n-th root of unity and n is the module size. The
-// result lies in the base field iff x does.
+// where ω is the canonical n-th root of unity and n is the module size. A
+// negative Position needs no normalisation since ω^Position = ω^(n+Position).
+// The result lies in the base field iff x does.
 //
 // Panics if x equals ω^Position (i.e. the point is in the domain at the
 // selector's own row): the denominator X−ω^Position vanishes there, so the
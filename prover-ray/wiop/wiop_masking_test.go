@@ -0,0 +1,214 @@
+package wiop_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaskingRows_SetPanics checks the preconditions of SetMaskingRows and
+// that a masked module cannot be sized below its masking rows.
+func TestMaskingRows_SetPanics(t *testing.T) {
+	sys := wiop.NewSystemf("mask-panics")
+	r0 := sys.NewRound()
+
+	sized := sys.NewSizedModule(sys.Context.Childf("sized"), 4, wiop.PaddingDirectionNone)
+	assert.Panics(t, func() { sized.SetMaskingRows(-1) }, "negative count")
+	assert.Panics(t, func() { sized.SetMaskingRows(4) }, "no usable row left")
+
+	left := sys.NewSizedModule(sys.Context.Childf("left"), 8, wiop.PaddingDirectionLeft)
+	assert.Panics(t, func() { left.SetMaskingRows(1) }, "left-padded module")
+
+	constrained := sys.NewSizedModule(sys.Context.Childf("constrained"), 8, wiop.PaddingDirectionNone)
+	col := constrained.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	constrained.NewVanishing(sys.Context.Childf("v"), col.View())
+	assert.Panics(t, func() { constrained.SetMaskingRows(1) }, "constraints registered first")
+
+	unsized := sys.NewModule(sys.Context.Childf("unsized"), wiop.PaddingDirectionNone)
+	unsized.SetMaskingRows(4)
+	assert.Equal(t, 4, unsized.MaskingRows())
+	assert.Panics(t, func() { unsized.SetSize(4) }, "size leaves no usable row")
+	unsized.SetSize(8)
+}
+
+// TestMaskingRows_CancelledPositions checks that vanishings on a masked
+// module skip the masking rows and the rows whose shifted reads land in them.
+func TestMaskingRows_CancelledPositions(t *testing.T) {
+	sys := wiop.NewSystemf("mask-cancel")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 16, wiop.PaddingDirectionNone)
+	mod.SetMaskingRows(2)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+
+	plain := mod.NewVanishing(sys.Context.Childf("plain"), col.View())
+	assert.Equal(t, []int{-2, -1}, plain.CancelledPositions)
+
+	// Shift +1 reads one row ahead: row n−3 reads the first masking row.
+	fwd := mod.NewVanishing(sys.Context.Childf("fwd"), wiop.Sub(col.View().Shift(1), col.View()))
+	assert.Equal(t, []int{-3, -2, -1}, fwd.CancelledPositions)
+
+	// Shift −1 wraps row 0 onto the last masking row; row 0 is cancelled
+	// by the shift itself.
+	bwd := mod.NewVanishing(sys.Context.Childf("bwd"), wiop.Sub(col.View().Shift(-1), col.View()))
+	assert.Equal(t, []int{-2, -1, 0}, bwd.CancelledPositions)
+
+	manual := mod.NewVanishingManual(sys.Context.Childf("manual"), col.View(), 3)
+	assert.Equal(t, []int{-2, -1, 3}, manual.CancelledPositions)
+
+	// A manual cancellation set does not cover the rows wrapping onto the
+	// masking rows: with shift −3, rows 1 and 2 read rows n−2 and n−1, while
+	// row 0 reads the usable row n−3.
+	manualBwd := mod.NewVanishingManual(sys.Context.Childf("manual-bwd"), wiop.Sub(col.View().Shift(-3), col.View()))
+	assert.Equal(t, []int{-2, -1, 1, 2}, manualBwd.CancelledPositions)
+}
+
+// TestMaskingRows_LocalConstraint checks that local constraints may not read a
+// masking row.
+func TestMaskingRows_LocalConstraint(t *testing.T) {
+	sys := wiop.NewSystemf("mask-local")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 8, wiop.PaddingDirectionNone)
+	mod.SetMaskingRows(2)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+
+	mod.NewLocalConstraint(sys.Context.Childf("first"), col.View(), 0)
+	mod.NewLocalConstraint(sys.Context.Childf("last-usable"), col.View().Shift(5), 0)
+	assert.Panics(t, func() {
+		mod.NewLocalConstraint(sys.Context.Childf("last"), col.View(), -1)
+	})
+	assert.Panics(t, func() {
+		mod.NewLocalConstraint(sys.Context.Childf("shifted"), col.View().Shift(6), 0)
+	})
+}
+
+// TestMaskingRows_Prove checks that Prove keeps the data of the masked
+// columns, randomises their masking rows anew on every run, and grows a
+// dynamic module whose data would reach the masking rows.
+func TestMaskingRows_Prove(t *testing.T) {
+	sys := wiop.NewSystemf("mask-prove")
+	r0 := sys.NewRound()
+	static := sys.NewSizedModule(sys.Context.Childf("static"), 8, wiop.PaddingDirectionNone)
+	static.SetMaskingRows(2)
+	dyn := sys.NewDynamicModule(sys.Context.Childf("dyn"), wiop.PaddingDirectionRight)
+	dyn.SetMaskingRows(3)
+	a := static.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	b := dyn.NewColumn(sys.Context.Childf("b"), wiop.VisibilityOracle, r0)
+
+	assign := func(rt *wiop.Runtime) {
+		rt.AssignColumn(a, makeVec(8, 7))
+		rt.AssignColumn(b, makeVec(6, 5))
+	}
+	p1, p2 := sys.Prove(assign), sys.Prove(assign)
+
+	// 6 rows of data and 3 masking rows do not fit in 8 rows.
+	require.Equal(t, 16, p1.DynamicSizes[1])
+
+	for _, tc := range []struct {
+		col       *wiop.Column
+		n, k, val int
+	}{
+		{a, 8, 2, 7},
+		{b, 16, 3, 5},
+	} {
+		v1 := p1.Columns[tc.col.Context.ID].Plain.AsBase()
+		v2 := p2.Columns[tc.col.Context.ID].Plain.AsBase()
+		require.Len(t, v1, tc.n)
+
+		var want field.Element
+		want.SetUint64(uint64(tc.val))
+		for i := range 6 {
+			assert.Equal(t, want, v1[i], "%v row %d", tc.col.Context.Path(), i)
+		}
+		assert.NotEqual(t, v1[tc.n-tc.k:], v2[tc.n-tc.k:], "%v masking rows", tc.col.Context.Path())
+	}
+}
+
+// columnAssignment assigns constant columns from a prover action.
+type columnAssignment struct {
+	cols []*wiop.Column
+	val  uint64
+}
+
+// Run implements [wiop.ProverAction].
+func (a columnAssignment) Run(rt wiop.Runtime) {
+	for _, col := range a.cols {
+		rt.AssignColumn(col, makeVec(col.Module.RuntimeSize(rt), a.val))
+	}
+}
+
+// TestMaskingRows_ProverActionColumns checks that Prove also masks the columns
+// assigned by the prover actions of a later round, except the ones flagged
+// with SkipMasking.
+func TestMaskingRows_ProverActionColumns(t *testing.T) {
+	sys := wiop.NewSystemf("mask-actions")
+	r0 := sys.NewRound()
+	r1 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 8, wiop.PaddingDirectionNone)
+	mod.SetMaskingRows(2)
+	a := mod.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	derived := mod.NewColumn(sys.Context.Childf("derived"), wiop.VisibilityOracle, r1)
+	kept := mod.NewColumn(sys.Context.Childf("kept"), wiop.VisibilityOracle, r1)
+	kept.SkipMasking = true
+	r1.RegisterAction(columnAssignment{cols: []*wiop.Column{derived, kept}, val: 4})
+
+	assign := func(rt *wiop.Runtime) { rt.AssignColumn(a, makeVec(8, 7)) }
+	p1, p2 := sys.Prove(assign), sys.Prove(assign)
+
+	var four field.Element
+	four.SetUint64(4)
+	d1 := p1.Columns[derived.Context.ID].Plain.AsBase()
+	d2 := p2.Columns[derived.Context.ID].Plain.AsBase()
+	for i := range 6 {
+		assert.Equal(t, four, d1[i], "derived row %d", i)
+	}
+	assert.NotEqual(t, d1[6:], d2[6:], "derived masking rows")
+
+	for i, v := range p1.Columns[kept.Context.ID].Plain.AsBase() {
+		assert.Equal(t, four, v, "kept row %d", i)
+	}
+}
+
+// TestMaskingRows_QueryChecks checks that the row-wise queries ignore the
+// values of the masking rows.
+func TestMaskingRows_QueryChecks(t *testing.T) {
+	sys := wiop.NewSystemf("mask-checks")
+	r0 := sys.NewRound()
+	sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 8, wiop.PaddingDirectionNone)
+	mod.SetMaskingRows(2)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	rc := mod.NewRangeCheck(sys.Context.Childf("rc"), col, 4)
+	one := wiop.NewConstantVector(mod, field.One())
+	ld := sys.NewLogDerivativeSum(sys.Context.Childf("ld"), []wiop.Fraction{{Numerator: col.View(), Denominator: one}})
+
+	rt := wiop.NewRuntime(sys)
+	rt.AssignColumn(col, makeVecU64(1, 2, 3, 0, 1, 2, 100, 200))
+	require.NoError(t, rc.Check(rt), "the masking rows are out of range but not checked")
+	rt.AdvanceRound()
+	ld.SelfAssign(rt)
+	var want field.Element
+	want.SetUint64(9)
+	assert.Equal(t, field.ElemFromBase(want), rt.GetCellValue(ld.Result), "the masking rows must not be summed")
+
+	rt = wiop.NewRuntime(sys)
+	rt.AssignColumn(col, makeVecU64(1, 2, 3, 0, 1, 4, 0, 0))
+	assert.Error(t, rc.Check(rt), "a usable row is out of range")
+}
+
+// TestMaskingRows_DataOnMaskingRows checks that Prove rejects a padded column
+// of a static module whose data reaches the masking rows.
+func TestMaskingRows_DataOnMaskingRows(t *testing.T) {
+	sys := wiop.NewSystemf("mask-overflow")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("mod"), 8, wiop.PaddingDirectionRight)
+	mod.SetMaskingRows(2)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+
+	sys.Prove(func(rt *wiop.Runtime) { rt.AssignColumn(col, makeVec(6, 1)) })
+	assert.Panics(t, func() {
+		sys.Prove(func(rt *wiop.Runtime) { rt.AssignColumn(col, makeVec(7, 1)) })
+	})
+}
@@ -47,17 +47,25 @@ type Proof struct {
 // then captures the committed columns and cells into the returned Proof. The
 // verifier coins are not captured; [System.Verify] re-derives them.
 //
+// The masking rows of the modules in zero-knowledge mode (see
+// [Module.SetMaskingRows]) are filled with fresh randomness once assign
+// returns, and again in the columns assigned by the prover actions of each
+// round before it is closed, so two proofs of the same witness differ.
+//
 // The caller is responsible for running the compiler passes (and, optionally,
 // [Materialize]) on sys before calling Prove.
 func (sys *System) Prove(assign func(rt *Runtime)) Proof {
 	rt := NewRuntime(sys)
 	assign(&rt)
+	masked := make(map[ObjectID]struct{})
+	rt.applyMaskingRows(masked, true)
 
 	// Runs all the prover action and advances the Fiat-Shamir transcript
 	for rt.currentRound.ID < len(sys.Rounds) {
 		for _, a := range rt.CurrentRound().ProverActions {
 			a.Run(rt)
 		}
+		rt.applyMaskingRows(masked, false)
 
 		if rt.currentRound.ID == len(sys.Rounds)-1 {
 			break
@@ -113,8 +121,10 @@ func (sys *System) Prove(assign func(rt *Runtime)) Proof {
 // challenge. Verifier actions read the re-derived coins, the cells, and the
 // public columns.
 //
-// Verify also checks that the provided sizes are non-zero powers of two. It
-// also checks that the sizes are compatible with the oracle-visible columns.
+// Verify also checks that the provided sizes are non-zero powers of two no
+// larger than the maximal column size, and that every oracle-visible column of
+// a dynamic module fits in the size declared for it. The compiled verifier
+// actions then check the sizes against what their constraints require.
 //
 // The function also panics if the proof contains any unexpected columns or
 // cells. For the column it will check that their visibility is correct.
@@ -214,11 +224,16 @@ func (sys *System) Verify(proof Proof) error {
 			return fmt.Errorf("wiop: dynamic module %d size must be a power of two: %v", k, v)
 		}
 
+		if v > columnSizeMaxSupported {
+			return fmt.Errorf("wiop: dynamic module %d size %v exceeds the maximal column size %v", k, v, columnSizeMaxSupported)
+		}
+
 		// If the system contains dynamic-size visible columns, then the column
-		// assignment function will set the dynamic size for the corresponding
-		// modules. We check that these sizes are consistents in that case.
-		if n, ok := rt.dynamicSizes[k]; ok && n != v {
-			return fmt.Errorf("wiop: dynamic module %d size mismatch: %v vs %v", k, n, v)
+		// assignment function sets the size of their modules to the smallest
+		// power of two holding them. The prover may have picked a larger
+		// domain (see [Runtime.SetModuleSize]), but never a smaller one.
+		if n, ok := rt.dynamicSizes[k]; ok && n > v {
+			return fmt.Errorf("wiop: dynamic module %d has columns of up to %v rows but a declared size of %v", k, n, v)
 		}
 
 		rt.dynamicSizes[k] = v
@@ -42,7 +42,7 @@ type Runtime struct {
 	state map[string]any
 	// dynamicSizes maps the index of each dynamic module to its domain size for
 	// this Runtime. Populated lazily by [Runtime.AssignColumn] on the first
-	// column assignment to each dynamic module.
+	// column assignment to each dynamic module, or by [Runtime.SetModuleSize].
 	dynamicSizes map[int]int
 	// lock is a concurrency lock to prevent concurrent access to the maps in
 	// the runtime.
@@ -85,7 +85,7 @@ func (run Runtime) dynamicModuleSize(m *Module) int {
 	size, ok := run.dynamicSizes[m.index]
 	if !ok {
 		panic(fmt.Sprintf(
-			"wiop: dynamic module %q has no size yet in this runtime; assign a column first",
+			"wiop: dynamic module %q has no size yet in this runtime; assign a column or call SetModuleSize first",
 			m.Context.Path(),
 		))
 	}
@@ -154,7 +154,9 @@ func (run *Runtime) AdvanceRound() {
 // Size semantics:
 //   - Static module: the data length must not exceed the module's declared size.
 //   - Dynamic module: each time we add a column we potentially grow the
-//     module's size. There is no upper bound.
+//     module's size to the smallest power of two holding the data, up to
+//     [columnSizeMaxSupported]. The column is padded to that size following
+//     the module's padding direction.
 func (run Runtime) AssignColumn(col *Column, v *ConcreteVector) {
 	run.lock.Lock()
 	defer run.lock.Unlock()
@@ -181,7 +183,7 @@ func (run Runtime) AssignColumn(col *Column, v *ConcreteVector) {
 
 	if m.IsDynamic() {
 		currSize := run.dynamicSizes[m.index]
-		run.dynamicSizes[m.index] = max(currSize, dataLen)
+		run.dynamicSizes[m.index] = max(currSize, utils.NextPowerOfTwo(dataLen))
 	} else if m.IsSized() && dataLen > m.Size() {
 		panic(fmt.Sprintf(
 			"wiop: AssignColumn: column %q has data length %d which overflows module %q size %d",
@@ -192,6 +194,106 @@ func (run Runtime) AssignColumn(col *Column, v *ConcreteVector) {
 	run.columns[id] = v
 }
 
+// SetModuleSize fixes the domain size of the dynamic module m for this
+// Runtime. Columns assigned to m afterwards may still grow it, as described in
+// [Runtime.AssignColumn]. It lets the prover pick a domain larger than its
+// longest column, or size a module before any of its columns is assigned.
+//
+// Panics if m is not dynamic, if n is not a power of two in
+// [1, columnSizeMaxSupported], or if n is smaller than the size already
+// recorded for m, which would truncate assigned columns.
+func (run Runtime) SetModuleSize(m *Module, n int) {
+	run.lock.Lock()
+	defer run.lock.Unlock()
+	if !m.IsDynamic() {
+		panic(fmt.Sprintf("wiop: SetModuleSize: module %q is not dynamic", m.Context.Path()))
+	}
+	if !utils.IsPowerOfTwo(n) || n > columnSizeMaxSupported {
+		panic(fmt.Sprintf(
+			"wiop: SetModuleSize: size of module %q must be a power of two in [1, %d], got %d",
+			m.Context.Path(), columnSizeMaxSupported, n,
+		))
+	}
+	if curr := run.dynamicSizes[m.index]; n < curr {
+		panic(fmt.Sprintf(
+			"wiop: SetModuleSize: module %q already has size %d; cannot shrink it to %d",
+			m.Context.Path(), curr, n,
+		))
+	}
+	run.dynamicSizes[m.index] = n
+}
+
+// applyMaskingRows overwrites the masking rows (see [Module.SetMaskingRows])
+// of every column assigned in the current round with uniformly random values,
+// except the columns flagged with [Column.SkipMasking] and the ones already in
+// masked, to which the masked columns are added. [System.Prove] calls it once
+// right after the witness hook and then after the prover actions of every
+// round.
+//
+// On the witness call, a dynamic module is grown first, if needed, so that its
+// longest column ends before the masking rows; on a static module, the data of
+// a padded column must already do so. The columns assigned by prover actions
+// span the module and are masked as they are.
+func (run Runtime) applyMaskingRows(masked map[ObjectID]struct{}, isWitness bool) {
+	for _, m := range run.System.Modules {
+		k := m.maskingRows
+		if k == 0 {
+			continue
+		}
+
+		var cols []*Column
+		dataLen := 0
+		for _, col := range m.Columns {
+			if col.round != run.currentRound || col.SkipMasking || !run.HasColumnAssignment(col) {
+				continue
+			}
+			if _, ok := masked[col.Context.ID]; ok {
+				continue
+			}
+			cols = append(cols, col)
+			dataLen = max(dataLen, run.GetColumnAssignment(col).Plain.Len())
+		}
+		if len(cols) == 0 {
+			continue
+		}
+
+		if isWitness && m.IsDynamic() {
+			if n := utils.NextPowerOfTwo(dataLen + k); n > run.dynamicModuleSize(m) {
+				run.SetModuleSize(m, n)
+			}
+		}
+		n := m.RuntimeSize(run)
+
+		for _, col := range cols {
+			masked[col.Context.ID] = struct{}{}
+			v := run.GetColumnAssignment(col)
+			if isWitness && m.Padding != PaddingDirectionNone && v.Plain.Len() > n-k {
+				panic(fmt.Sprintf(
+					"wiop: column %q has %d rows of data but module %q only has %d rows before its %d masking rows",
+					col.Context.Path(), v.Plain.Len(), m.Context.Path(), n-k, k,
+				))
+			}
+
+			full := col.View().EvaluateVector(run).Plain
+			if full.IsBase() {
+				base := full.AsBase()
+				for i := n - k; i < n; i++ {
+					base[i] = field.RandomElement()
+				}
+			} else {
+				ext := full.AsExt()
+				for i := n - k; i < n; i++ {
+					ext[i] = field.RandomElementExt()
+				}
+			}
+
+			run.lock.Lock()
+			run.columns[col.Context.ID] = &ConcreteVector{Plain: full, Padding: v.Padding}
+			run.lock.Unlock()
+		}
+	}
+}
+
 // GetColumnAssignment returns the concrete assignment of col. Panics if col
 // has not been assigned yet.
 func (run Runtime) GetColumnAssignment(col *Column) *ConcreteVector {
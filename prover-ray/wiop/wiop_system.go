@@ -30,6 +30,9 @@ type System struct {
 	// LogDerivativeSums holds all [LogDerivativeSum] queries registered with
 	// this system via [System.NewLogDerivativeSum], in declaration order.
 	LogDerivativeSums []*LogDerivativeSum
+	// MessageBuses holds all [MessageBus] queries registered with this system
+	// via [System.NewMessageBus], in declaration order.
+	MessageBuses []*MessageBus
 	// scratchArena backs the [PlanningContext] used by [Materialize]. It is
 	// nil until Materialize is called.
 	scratchArena *arena.VectorArena
@@ -107,10 +110,12 @@ func (sys *System) NewSizedModule(ctx *ContextFrame, size int, pd PaddingDirecti
 	return m
 }
 
-// NewDynamicModule creates a module whose domain size is provided per-Runtime
-// via [WithModuleSize] rather than fixed once via [Module.SetSize]. The same
-// System can therefore be reused across proving sessions that differ in trace
-// length.
+// NewDynamicModule creates a module whose domain size is chosen per-Runtime,
+// from its column assignments or via [Runtime.SetModuleSize], rather than fixed
+// once via [Module.SetSize]. The same System can therefore be reused across
+// proving sessions that differ in trace length: the compilers pick their FFT
+// domains from the runtime size, and [System.Verify] checks the sizes declared
+// in the proof.
 //
 // Panics if ctx is nil or if pd is [PaddingDirectionNone] (dynamic modules
 // require a padding direction so that shorter columns can be padded to the
@@ -0,0 +1,138 @@
+package wiop
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+)
+
+// Digest returns a SHA-256 fingerprint of the shape of sys: everything a
+// [Proof] of sys is laid out against. Two systems with the same digest accept
+// proofs with the same object IDs, field types and module sizes.
+//
+// The digest covers, in declaration order:
+//   - the modules: path, size, padding direction and whether they are dynamic;
+//   - the precomputed columns and their assignments;
+//   - for every interactive round, its coins, its columns (path, module,
+//     visibility and field), its cells (path and field), and the concrete
+//     types of its verifier actions;
+//   - the paths of the [LagrangeEval] queries left to the commitment scheme.
+//
+// It does not encode the constraints themselves, which are Go closures and
+// expressions: two systems differing only in a constraint that keeps every
+// object name unchanged share a digest. Object paths are part of the digest,
+// so a renamed column or query changes it.
+//
+// Digest must be called once every compiler pass has run, since passes add
+// rounds and objects.
+func (sys *System) Digest() [32]byte {
+	d := &digester{h: sha256.New()}
+
+	d.str("wiop/system/v1")
+	d.str(sys.Context.Path())
+
+	d.int(len(sys.Modules))
+	for _, m := range sys.Modules {
+		d.str(m.Context.Path())
+		d.int(m.Size())
+		d.int(int(m.Padding))
+		d.bool(m.IsDynamic())
+	}
+
+	pre := sys.PrecomputedRound
+	d.int(len(pre.Columns))
+	for i, col := range pre.Columns {
+		d.column(col)
+		cv := pre.PrecomputedValues[i]
+		d.elem(cv.Padding)
+		d.int(cv.Plain.Len())
+		d.bool(cv.Plain.IsBase())
+		if cv.Plain.IsBase() {
+			for _, e := range cv.Plain.AsBase() {
+				d.elem(e)
+			}
+		} else {
+			for _, e := range cv.Plain.AsExt() {
+				d.ext(e)
+			}
+		}
+	}
+
+	d.int(len(sys.Rounds))
+	for _, r := range sys.Rounds {
+		d.int(len(r.Coins))
+		for _, c := range r.Coins {
+			d.str(c.Context.Path())
+		}
+		d.int(len(r.Columns))
+		for _, col := range r.Columns {
+			d.column(col)
+		}
+		d.int(len(r.Cells))
+		for _, c := range r.Cells {
+			d.str(c.Context.Path())
+			d.bool(c.IsExtension())
+		}
+		d.int(len(r.VerifierActions))
+		for _, va := range r.VerifierActions {
+			d.str(fmt.Sprintf("%T", va))
+		}
+	}
+
+	d.int(len(sys.LagrangeEvals))
+	for _, le := range sys.LagrangeEvals {
+		d.str(le.Context().Path())
+		d.bool(le.IsReduced())
+	}
+
+	var out [32]byte
+	copy(out[:], d.h.Sum(nil))
+	return out
+}
+
+// digester writes length-prefixed values to a hash so that the concatenation
+// of distinct value sequences never collides.
+type digester struct {
+	h   hash.Hash
+	buf [8]byte
+}
+
+func (d *digester) int(v int) {
+	binary.BigEndian.PutUint64(d.buf[:], uint64(v))
+	d.h.Write(d.buf[:])
+}
+
+func (d *digester) bool(v bool) {
+	if v {
+		d.int(1)
+		return
+	}
+	d.int(0)
+}
+
+func (d *digester) bytes(b []byte) {
+	d.int(len(b))
+	d.h.Write(b)
+}
+
+func (d *digester) str(s string) { d.bytes([]byte(s)) }
+
+func (d *digester) elem(e field.Element) {
+	b := e.Bytes()
+	d.h.Write(b[:])
+}
+
+func (d *digester) ext(e field.Ext) {
+	b := field.ExtToBytes(&e)
+	d.h.Write(b[:])
+}
+
+func (d *digester) column(col *Column) {
+	d.str(col.Context.Path())
+	d.int(col.Context.ID.Slot())
+	d.int(int(col.Visibility))
+	d.bool(col.IsExtension)
+}
@@ -0,0 +1,32 @@
+package wiop_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/stretchr/testify/assert"
+)
+
+// digestSystem builds a one-module, one-column system; size and name are
+// the knobs the digest tests turn.
+func digestSystem(size int, colName string) *wiop.System {
+	sys := wiop.NewSystemf("s")
+	r0 := sys.NewRound()
+	mod := sys.NewSizedModule(sys.Context.Childf("m"), size, wiop.PaddingDirectionNone)
+	mod.NewColumn(sys.Context.Childf("%s", colName), wiop.VisibilityOracle, r0)
+	return sys
+}
+
+func TestSystemDigest_Deterministic(t *testing.T) {
+	assert.Equal(t, digestSystem(8, "a").Digest(), digestSystem(8, "a").Digest())
+}
+
+func TestSystemDigest_TracksShape(t *testing.T) {
+	base := digestSystem(8, "a").Digest()
+	assert.NotEqual(t, base, digestSystem(16, "a").Digest(), "module size must be bound")
+	assert.NotEqual(t, base, digestSystem(8, "b").Digest(), "column path must be bound")
+
+	sys := digestSystem(8, "a")
+	sys.Rounds[0].NewCell(sys.Context.Childf("c"), false)
+	assert.NotEqual(t, base, sys.Digest(), "cells must be bound")
+}
@@ -56,5 +56,6 @@ func LocalVanishingScenarios() []func() *LocalVanishingScenario {
 		NewLocalDynamicFirstRowZeroScenario,
 		NewLocalDynamicShiftedScenario,
 		NewLocalDynamicProductIsZeroScenario,
+		NewLocalDynamicLastRowZeroScenario,
 	}
 }
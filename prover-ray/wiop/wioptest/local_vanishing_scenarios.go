@@ -572,3 +572,29 @@ func NewLocalDynamicProductIsZeroScenario() *LocalVanishingScenario {
 		},
 	}
 }
+
+// NewLocalDynamicLastRowZeroScenario pins col at its last row to zero on a
+// dynamic-size module. The row is -1, counted from the end of the domain once
+// the runtime size is known. The honest column is shorter than the domain, so
+// the constrained row is read from the padding.
+//
+//   - Valid: col = [9, 9, 9]; the domain has 4 rows, col[3] is the padding 0.
+//   - Invalid: col = [9, 9, 9, 7]; col[3] != 0.
+func NewLocalDynamicLastRowZeroScenario() *LocalVanishingScenario {
+	sys := wiop.NewSystemf("lv-dyn-last")
+	r0 := sys.NewRound()
+	mod := sys.NewDynamicModule(sys.Context.Childf("dynmod"), wiop.PaddingDirectionRight)
+	col := mod.NewColumn(sys.Context.Childf("col"), wiop.VisibilityOracle, r0)
+	mod.NewLocalConstraint(sys.Context.Childf("lc"), col.View(), -1)
+
+	return &LocalVanishingScenario{
+		Name: "DynamicLastRowZero",
+		Sys:  sys,
+		AssignHonest: func(rt *wiop.Runtime) {
+			rt.AssignColumn(col, makeVec(9, 9, 9))
+		},
+		AssignInvalid: func(rt *wiop.Runtime) {
+			rt.AssignColumn(col, makeVec(9, 9, 9, 7))
+		},
+	}
+}
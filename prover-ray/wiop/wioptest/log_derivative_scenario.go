@@ -64,5 +64,6 @@ func LogDerivativeSumCompilerScenarios() []func() *LogDerivativeSumCompilerScena
 		NewLDSMultipleQueriesScenario,
 		NewLDSVectorDenominatorScenario,
 		NewLDSAllFiltersOnesPackedScenario,
+		NewLDSDynamicModuleScenario,
 	}
 }
@@ -225,6 +225,37 @@ func NewLDSSizeOneModuleScenario() *LogDerivativeSumCompilerScenario {
 	}
 }
 
+// NewLDSDynamicModuleScenario runs a filtered sum on a dynamic-size module.
+// The columns hold 5 rows, so the module takes 8 rows at runtime and the Z
+// endpoint is read at the last row of that domain, past the data.
+//
+//   - Witness: num = [1, 2, 3, 4, 5], filter = [1, 1, 0, 1, 1], padded with
+//     zeros → honest result = 12.
+//   - Tamper: Result cell pinned to a wrong value.
+func NewLDSDynamicModuleScenario() *LogDerivativeSumCompilerScenario {
+	sys := wiop.NewSystemf("lds-dynamic")
+	r0 := sys.NewRound()
+	sys.NewRound()
+	mod := sys.NewDynamicModule(sys.Context.Childf("mod"), wiop.PaddingDirectionRight)
+	num := mod.NewColumn(sys.Context.Childf("num"), wiop.VisibilityOracle, r0)
+	flt := mod.NewColumn(sys.Context.Childf("flt"), wiop.VisibilityOracle, r0)
+	one := wiop.NewConstantVector(mod, field.NewFromString("1"))
+	ld := sys.NewLogDerivativeSum(
+		sys.Context.Childf("ld"),
+		[]wiop.Fraction{{Filter: flt.View(), Numerator: num.View(), Denominator: one}},
+	)
+
+	return &LogDerivativeSumCompilerScenario{
+		Name: "DynamicModule",
+		Sys:  sys,
+		AssignWitness: func(rt *wiop.Runtime) {
+			rt.AssignColumn(num, makeVec(1, 2, 3, 4, 5))
+			rt.AssignColumn(flt, makeVec(1, 1, 0, 1, 1))
+		},
+		TamperResult: tamperResult(ld),
+	}
+}
+
 // NewLDSManyFractionsScenario registers seven fractions on a single
 // module. Packing arity is 3, so the compiler must allocate ⌈7/3⌉ = 3 Z
 // columns — the first scenario that goes beyond two packed Z chunks.
@@ -0,0 +1,43 @@
+package wioptest
+
+import "github.com/consensys/linea-monorepo/prover-ray/wiop"
+
+// MessageBusScenario is a fixture for testing the [wiop.MessageBus] query and
+// the messagebus → logderivativesum compiler pipeline.
+//
+// Every fixture declares its witness columns in r0 and no other round; the
+// messagebus compiler allocates one round for its α/γ coins and one for the
+// LogDerivativeSum result. A test typically calls
+//
+//	messagebus.Compile(sc.Sys)
+//	logderivativesum.Compile(sc.Sys)
+//	rt := wiop.NewRuntime(sc.Sys)
+//	sc.AssignHonest(&rt)
+//	err := RunAndVerify(&rt)
+type MessageBusScenario struct {
+	// Name identifies the scenario in test output.
+	Name string
+	// Sys is the pre-compilation System; each factory call returns an
+	// independent Sys.
+	Sys *wiop.System
+	// Bus is the message-bus query registered on Sys.
+	Bus *wiop.MessageBus
+	// AssignHonest assigns witness columns for which every tuple is sent and
+	// received the same number of times.
+	AssignHonest func(rt *wiop.Runtime)
+	// AssignInvalid assigns witness columns that unbalance the bus.
+	AssignInvalid func(rt *wiop.Runtime)
+}
+
+// MessageBusScenarios returns factory functions for the message-bus
+// scenarios. Each factory returns a fresh, uncompiled System.
+func MessageBusScenarios() []func() *MessageBusScenario {
+	return []func() *MessageBusScenario{
+		NewBusSingleColumnScenario,
+		NewBusMultiplicityScenario,
+		NewBusTaggedSendersScenario,
+		NewBusShiftedTupleScenario,
+		NewBusPrecomputedReceiverScenario,
+		NewBusDynamicModuleScenario,
+	}
+}
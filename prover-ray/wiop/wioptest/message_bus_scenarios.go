@@ -0,0 +1,215 @@
+package wioptest
+
+import (
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// NewBusSingleColumnScenario: module A sends every row of a, module B
+// receives every row of b. The bus balances iff b is a permutation of a.
+func NewBusSingleColumnScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-single")
+	r0 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("modA"), 4, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("modB"), 4, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	b := modB.NewColumn(sys.Context.Childf("b"), wiop.VisibilityOracle, r0)
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{a.View()}}},
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{b.View()}}},
+	)
+
+	return &MessageBusScenario{
+		Name: "SingleColumn",
+		Sys:  sys,
+		Bus:  bus,
+		AssignHonest: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4))
+			rt.AssignColumn(b, makeVec(4, 3, 2, 1))
+		},
+		AssignInvalid: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4))
+			rt.AssignColumn(b, makeVec(4, 3, 2, 2))
+		},
+	}
+}
+
+// NewBusMultiplicityScenario: a size-8 sender filtered by a selector column
+// and a size-4 receiver carrying the number of times each value is received.
+//
+//   - Sent: 5 three times, 6 and 7 once.
+//   - Invalid: the receiver claims 5 only twice.
+func NewBusMultiplicityScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-mult")
+	r0 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("modA"), 8, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("modB"), 4, wiop.PaddingDirectionNone)
+	x := modA.NewColumn(sys.Context.Childf("x"), wiop.VisibilityOracle, r0)
+	sel := modA.NewColumn(sys.Context.Childf("sel"), wiop.VisibilityOracle, r0)
+	y := modB.NewColumn(sys.Context.Childf("y"), wiop.VisibilityOracle, r0)
+	m := modB.NewColumn(sys.Context.Childf("m"), wiop.VisibilityOracle, r0)
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Multiplicity: sel.View(), Tuple: []wiop.Expression{x.View()}}},
+		[]wiop.BusMessage{{Multiplicity: m.View(), Tuple: []wiop.Expression{y.View()}}},
+	)
+
+	assign := func(rt *wiop.Runtime, mult *wiop.ConcreteVector) {
+		rt.AssignColumn(x, makeVec(5, 6, 5, 7, 9, 9, 5, 8))
+		rt.AssignColumn(sel, makeVec(1, 1, 1, 1, 0, 0, 1, 0))
+		rt.AssignColumn(y, makeVec(5, 6, 7, 8))
+		rt.AssignColumn(m, mult)
+	}
+	return &MessageBusScenario{
+		Name:          "Multiplicity",
+		Sys:           sys,
+		Bus:           bus,
+		AssignHonest:  func(rt *wiop.Runtime) { assign(rt, makeVec(3, 1, 1, 0)) },
+		AssignInvalid: func(rt *wiop.Runtime) { assign(rt, makeVec(2, 1, 1, 0)) },
+	}
+}
+
+// NewBusTaggedSendersScenario: two modules of different sizes send on the
+// same bus, each prefixing its messages with a constant tag, and a third
+// module receives (tag, value) pairs. The tag keeps equal values sent by
+// different modules apart.
+//
+//   - Invalid: the receiver files the value 10 of the second sender under the
+//     tag of the first one.
+func NewBusTaggedSendersScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-tagged")
+	r0 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("modA"), 4, wiop.PaddingDirectionNone)
+	modC := sys.NewSizedModule(sys.Context.Childf("modC"), 2, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("modB"), 8, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	c := modC.NewColumn(sys.Context.Childf("c"), wiop.VisibilityOracle, r0)
+	tag := modB.NewColumn(sys.Context.Childf("tag"), wiop.VisibilityOracle, r0)
+	v := modB.NewColumn(sys.Context.Childf("v"), wiop.VisibilityOracle, r0)
+	mB := modB.NewColumn(sys.Context.Childf("mB"), wiop.VisibilityOracle, r0)
+	tagA := wiop.NewConstantField(field.NewFromString("1"))
+	tagC := wiop.NewConstantField(field.NewFromString("2"))
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{
+			{Tuple: []wiop.Expression{tagA, a.View()}},
+			{Tuple: []wiop.Expression{tagC, c.View()}},
+		},
+		[]wiop.BusMessage{{Multiplicity: mB.View(), Tuple: []wiop.Expression{tag.View(), v.View()}}},
+	)
+
+	assign := func(rt *wiop.Runtime, tags *wiop.ConcreteVector) {
+		rt.AssignColumn(a, makeVec(10, 11, 12, 13))
+		rt.AssignColumn(c, makeVec(10, 20))
+		rt.AssignColumn(tag, tags)
+		rt.AssignColumn(v, makeVec(13, 12, 11, 10, 20, 10, 0, 0))
+		rt.AssignColumn(mB, makeVec(1, 1, 1, 1, 1, 1, 0, 0))
+	}
+	return &MessageBusScenario{
+		Name:          "TaggedSenders",
+		Sys:           sys,
+		Bus:           bus,
+		AssignHonest:  func(rt *wiop.Runtime) { assign(rt, makeVec(1, 1, 1, 1, 2, 2, 0, 0)) },
+		AssignInvalid: func(rt *wiop.Runtime) { assign(rt, makeVec(1, 1, 1, 1, 2, 1, 0, 0)) },
+	}
+}
+
+// NewBusShiftedTupleScenario: module A sends the cyclic pairs
+// (a[i], a[i+1]) through a shifted view, module B receives them as two
+// separate columns.
+func NewBusShiftedTupleScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-shift")
+	r0 := sys.NewRound()
+	modA := sys.NewSizedModule(sys.Context.Childf("modA"), 4, wiop.PaddingDirectionNone)
+	modB := sys.NewSizedModule(sys.Context.Childf("modB"), 4, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	p := modB.NewColumn(sys.Context.Childf("p"), wiop.VisibilityOracle, r0)
+	q := modB.NewColumn(sys.Context.Childf("q"), wiop.VisibilityOracle, r0)
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{a.View(), a.View().Shift(1)}}},
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{p.View(), q.View()}}},
+	)
+
+	return &MessageBusScenario{
+		Name: "ShiftedTuple",
+		Sys:  sys,
+		Bus:  bus,
+		AssignHonest: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4))
+			rt.AssignColumn(p, makeVec(4, 1, 2, 3))
+			rt.AssignColumn(q, makeVec(1, 2, 3, 4))
+		},
+		AssignInvalid: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4))
+			rt.AssignColumn(p, makeVec(4, 1, 2, 3))
+			rt.AssignColumn(q, makeVec(1, 2, 4, 3))
+		},
+	}
+}
+
+// NewBusPrecomputedReceiverScenario: a precomputed table of squares receives
+// (x, x²) requests from a sender module, with the number of requests per
+// entry held in an oracle multiplicity column.
+//
+//   - Invalid: the sender requests (0, 1), which is not a square pair.
+func NewBusPrecomputedReceiverScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-precomputed")
+	r0 := sys.NewRound()
+	modT := sys.NewSizedModule(sys.Context.Childf("modT"), 4, wiop.PaddingDirectionNone)
+	modS := sys.NewSizedModule(sys.Context.Childf("modS"), 4, wiop.PaddingDirectionNone)
+	in := modT.NewPrecomputedColumn(sys.Context.Childf("in"), wiop.VisibilityOracle, makeVec(0, 1, 2, 3))
+	out := modT.NewPrecomputedColumn(sys.Context.Childf("out"), wiop.VisibilityOracle, makeVec(0, 1, 4, 9))
+	m := modT.NewColumn(sys.Context.Childf("m"), wiop.VisibilityOracle, r0)
+	x := modS.NewColumn(sys.Context.Childf("x"), wiop.VisibilityOracle, r0)
+	y := modS.NewColumn(sys.Context.Childf("y"), wiop.VisibilityOracle, r0)
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{x.View(), y.View()}}},
+		[]wiop.BusMessage{{Multiplicity: m.View(), Tuple: []wiop.Expression{in.View(), out.View()}}},
+	)
+
+	assign := func(rt *wiop.Runtime, squares *wiop.ConcreteVector) {
+		rt.AssignColumn(m, makeVec(1, 0, 2, 1))
+		rt.AssignColumn(x, makeVec(2, 3, 2, 0))
+		rt.AssignColumn(y, squares)
+	}
+	return &MessageBusScenario{
+		Name:          "PrecomputedReceiver",
+		Sys:           sys,
+		Bus:           bus,
+		AssignHonest:  func(rt *wiop.Runtime) { assign(rt, makeVec(4, 9, 4, 0)) },
+		AssignInvalid: func(rt *wiop.Runtime) { assign(rt, makeVec(4, 9, 4, 1)) },
+	}
+}
+
+// NewBusDynamicModuleScenario: the sender lives in a dynamic module sized at
+// runtime, the receiver in a static one.
+func NewBusDynamicModuleScenario() *MessageBusScenario {
+	sys := wiop.NewSystemf("bus-dyn")
+	r0 := sys.NewRound()
+	modA := sys.NewDynamicModule(sys.Context.Childf("modA"), wiop.PaddingDirectionRight)
+	modB := sys.NewSizedModule(sys.Context.Childf("modB"), 8, wiop.PaddingDirectionNone)
+	a := modA.NewColumn(sys.Context.Childf("a"), wiop.VisibilityOracle, r0)
+	b := modB.NewColumn(sys.Context.Childf("b"), wiop.VisibilityOracle, r0)
+	bus := sys.NewMessageBus(
+		sys.Context.Childf("bus"),
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{a.View()}}},
+		[]wiop.BusMessage{{Tuple: []wiop.Expression{b.View()}}},
+	)
+
+	return &MessageBusScenario{
+		Name: "DynamicModule",
+		Sys:  sys,
+		Bus:  bus,
+		AssignHonest: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4, 5, 6, 7, 8))
+			rt.AssignColumn(b, makeVec(8, 7, 6, 5, 4, 3, 2, 1))
+		},
+		AssignInvalid: func(rt *wiop.Runtime) {
+			rt.AssignColumn(a, makeVec(1, 2, 3, 4, 5, 6, 7, 8))
+			rt.AssignColumn(b, makeVec(8, 7, 6, 5, 4, 3, 2, 2))
+		},
+	}
+}
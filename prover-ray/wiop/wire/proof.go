@@ -0,0 +1,331 @@
+package wire
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+)
+
+// Field tags prefixing every column and cell value.
+const (
+	tagBase uint8 = 0
+	tagExt  uint8 = 1
+)
+
+const (
+	elemSize = field.Bytes
+	extSize  = field.Bytes * field.ExtensionDegree
+)
+
+// MarshalProof encodes p as a [KindProof] payload bound to sys.
+func MarshalProof(sys *wiop.System, p wiop.Proof) []byte {
+	return EncodeProof(sys.Digest(), p)
+}
+
+// UnmarshalProof decodes a [KindProof] payload and checks that it was
+// produced against sys. It does not verify the proof: call
+// [wiop.System.Verify] on the result.
+func UnmarshalProof(sys *wiop.System, data []byte) (wiop.Proof, error) {
+	h, p, err := DecodeProof(data)
+	if err != nil {
+		return wiop.Proof{}, err
+	}
+	if h.SystemDigest != sys.Digest() {
+		return wiop.Proof{}, ErrDigestMismatch
+	}
+	return p, nil
+}
+
+// EncodeProof encodes p behind a [KindProof] header carrying digest. The body
+// is, with every integer big-endian:
+//
+//	u32 n, then n × (u32 module index, u32 size)        dynamic module sizes
+//	u32 n, then n × (u64 id, u8 tag, elem padding,      columns
+//	                 u32 len, len × value)
+//	u32 n, then n × (u64 id, u8 tag, value)             cells
+//
+// Entries of each section are sorted by strictly increasing key. tag is 0 for
+// base-field values (one 4-byte canonical element) and 1 for extension values
+// (six elements, in the order of [field.ExtToBytes]).
+//
+// Panics if p holds a module index, size or length that does not fit in a
+// u32, or a column or cell ID of the wrong [wiop.ObjectKind].
+func EncodeProof(digest [32]byte, p wiop.Proof) []byte {
+	buf := Header{Version: Version, Kind: KindProof, SystemDigest: digest}.appendTo(nil)
+
+	modules := sortedKeys(p.DynamicSizes)
+	buf = appendU32(buf, len(modules))
+	for _, m := range modules {
+		buf = appendU32(buf, m)
+		buf = appendU32(buf, p.DynamicSizes[m])
+	}
+
+	cols := sortedKeys(p.Columns)
+	buf = appendU32(buf, len(cols))
+	for _, id := range cols {
+		mustKind(id, wiop.KindColumn)
+		cv := p.Columns[id]
+		buf = binary.BigEndian.AppendUint64(buf, uint64(id))
+		if cv.Plain.IsBase() {
+			buf = append(buf, tagBase)
+			buf = appendElem(buf, cv.Padding)
+			buf = appendU32(buf, cv.Plain.Len())
+			for _, e := range cv.Plain.AsBase() {
+				buf = appendElem(buf, e)
+			}
+			continue
+		}
+		buf = append(buf, tagExt)
+		buf = appendElem(buf, cv.Padding)
+		buf = appendU32(buf, cv.Plain.Len())
+		if cv.Plain.Len() > 0 {
+			for _, e := range cv.Plain.AsExt() {
+				buf = appendExt(buf, e)
+			}
+		}
+	}
+
+	cells := sortedKeys(p.Cells)
+	buf = appendU32(buf, len(cells))
+	for _, id := range cells {
+		mustKind(id, wiop.KindCell)
+		v := p.Cells[id]
+		buf = binary.BigEndian.AppendUint64(buf, uint64(id))
+		if v.IsBase() {
+			buf = append(buf, tagBase)
+			buf = appendElem(buf, v.AsBase())
+			continue
+		}
+		buf = append(buf, tagExt)
+		buf = appendExt(buf, v.AsExt())
+	}
+
+	return buf
+}
+
+// DecodeProof decodes a [KindProof] payload without checking its system
+// digest; use [UnmarshalProof] when the system is at hand.
+func DecodeProof(data []byte) (Header, wiop.Proof, error) {
+	h, err := DecodeHeader(data)
+	if err != nil {
+		return h, wiop.Proof{}, err
+	}
+	if h.Kind != KindProof {
+		return h, wiop.Proof{}, fmt.Errorf("%w: %v", ErrUnexpectedKind, h.Kind)
+	}
+
+	r := &reader{data: data, off: HeaderSize}
+	p := wiop.Proof{
+		Columns:      make(map[wiop.ObjectID]*wiop.ConcreteVector),
+		Cells:        make(map[wiop.ObjectID]field.Gen),
+		DynamicSizes: make(map[int]int),
+	}
+
+	n := r.count(8)
+	prev := -1
+	for range n {
+		m, size := int(r.u32()), int(r.u32())
+		if r.err != nil {
+			break
+		}
+		if m <= prev {
+			r.fail("dynamic sizes not sorted by module index")
+			break
+		}
+		prev = m
+		p.DynamicSizes[m] = size
+	}
+
+	n = r.count(8 + 1 + elemSize + 4)
+	var prevID uint64
+	for i := range n {
+		id := r.id(wiop.KindColumn, prevID, i == 0)
+		tag := r.tag()
+		cv := &wiop.ConcreteVector{Padding: r.elem()}
+		switch tag {
+		case tagBase:
+			vals := make([]field.Element, r.count(elemSize))
+			for j := range vals {
+				vals[j] = r.elem()
+			}
+			cv.Plain = field.VecFromBase(vals)
+		case tagExt:
+			vals := make([]field.Ext, r.count(extSize))
+			for j := range vals {
+				vals[j] = r.ext()
+			}
+			cv.Plain = field.VecFromExt(vals)
+		}
+		if r.err != nil {
+			break
+		}
+		prevID = uint64(id)
+		p.Columns[id] = cv
+	}
+
+	n = r.count(8 + 1 + elemSize)
+	prevID = 0
+	for i := range n {
+		id := r.id(wiop.KindCell, prevID, i == 0)
+		var v field.Gen
+		switch r.tag() {
+		case tagBase:
+			v = field.ElemFromBase(r.elem())
+		case tagExt:
+			v = field.ElemFromExt(r.ext())
+		}
+		if r.err != nil {
+			break
+		}
+		prevID = uint64(id)
+		p.Cells[id] = v
+	}
+
+	if r.err == nil && r.off != len(r.data) {
+		r.fail("%d trailing bytes", len(r.data)-r.off)
+	}
+	if r.err != nil {
+		return h, wiop.Proof{}, r.err
+	}
+	return h, p, nil
+}
+
+// reader is a cursor over an encoded payload. The first error is sticky:
+// every later read returns a zero value, so decoding loops only need to check
+// r.err once per entry.
+type reader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (r *reader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: at offset %d: %s", ErrMalformed, r.off, fmt.Sprintf(format, args...))
+	}
+}
+
+// take returns the next n bytes, or nil once the input is exhausted.
+func (r *reader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data)-r.off < n {
+		r.err = fmt.Errorf("%w: need %d bytes at offset %d, have %d", ErrTruncated, n, r.off, len(r.data)-r.off)
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) u64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// count reads a u32 length prefix and checks that the remaining input can
+// hold that many entries of at least minSize bytes each, so a forged length
+// cannot trigger a large allocation.
+func (r *reader) count(minSize int) int {
+	n := int(r.u32())
+	if r.err == nil && n > (len(r.data)-r.off)/minSize {
+		r.err = fmt.Errorf("%w: %d entries announced at offset %d, only %d bytes left", ErrTruncated, n, r.off, len(r.data)-r.off)
+	}
+	if r.err != nil {
+		return 0
+	}
+	return n
+}
+
+// id reads an object ID and checks its kind and that it is strictly greater
+// than prev (unless it is the first of its section).
+func (r *reader) id(kind wiop.ObjectKind, prev uint64, first bool) wiop.ObjectID {
+	v := r.u64()
+	if r.err != nil {
+		return 0
+	}
+	id := wiop.ObjectID(v)
+	if id.Kind() != kind {
+		r.fail("object ID %#x has kind %v, expected %v", v, id.Kind(), kind)
+	} else if !first && v <= prev {
+		r.fail("object IDs not strictly increasing")
+	}
+	return id
+}
+
+func (r *reader) tag() uint8 {
+	b := r.take(1)
+	if b == nil {
+		return 0
+	}
+	if b[0] != tagBase && b[0] != tagExt {
+		r.fail("unknown field tag %d", b[0])
+	}
+	return b[0]
+}
+
+func (r *reader) elem() field.Element {
+	var e field.Element
+	b := r.take(elemSize)
+	if b == nil {
+		return e
+	}
+	if err := e.SetBytesCanonical(b); err != nil {
+		r.fail("non-canonical field element")
+	}
+	return e
+}
+
+func (r *reader) ext() field.Ext {
+	var e field.Ext
+	for _, c := range []*field.Element{&e.B0.A0, &e.B0.A1, &e.B1.A0, &e.B1.A1, &e.B2.A0, &e.B2.A1} {
+		*c = r.elem()
+	}
+	return e
+}
+
+func appendU32(dst []byte, v int) []byte {
+	if v < 0 || v > math.MaxUint32 {
+		panic(fmt.Sprintf("wire: value %d does not fit in a u32", v))
+	}
+	return binary.BigEndian.AppendUint32(dst, uint32(v))
+}
+
+func appendElem(dst []byte, e field.Element) []byte {
+	b := e.Bytes()
+	return append(dst, b[:]...)
+}
+
+func appendExt(dst []byte, e field.Ext) []byte {
+	b := field.ExtToBytes(&e)
+	return append(dst, b[:]...)
+}
+
+func mustKind(id wiop.ObjectID, kind wiop.ObjectKind) {
+	if id.Kind() != kind {
+		panic(fmt.Sprintf("wire: object ID %#x has kind %v, expected %v", uint64(id), id.Kind(), kind))
+	}
+}
+
+func sortedKeys[K int | wiop.ObjectID, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,195 @@
+package wire_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover-ray/maths/koalabear/field"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/global"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/localvanishing"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/logderivativesum"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/messagebus"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/compilers/mpts"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wioptest"
+	"github.com/consensys/linea-monorepo/prover-ray/wiop/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// minimalProofPath is the golden fixture described in
+// docs/proof-wire-format.md. verifier-ray/testdata/proofs/minimal.bin holds
+// the same bytes.
+const minimalProofPath = "testdata/minimal_proof.bin"
+
+func readMinimalProof(t testing.TB) []byte {
+	data, err := os.ReadFile(minimalProofPath)
+	require.NoError(t, err)
+	return data
+}
+
+func compile(sys *wiop.System) {
+	messagebus.Compile(sys)
+	logderivativesum.Compile(sys)
+	localvanishing.Compile(sys)
+	global.Compile(sys)
+	mpts.Compile(sys)
+}
+
+// TestRoundTrip_Scenarios encodes real proofs, decodes them against the same
+// system and checks that the verifier still accepts them and that re-encoding
+// is byte-identical.
+func TestRoundTrip_Scenarios(t *testing.T) {
+	type scenario struct {
+		name   string
+		sys    *wiop.System
+		assign func(rt *wiop.Runtime)
+	}
+	var scenarios []scenario
+	for _, build := range wioptest.VanishingScenarios() {
+		sc := build()
+		scenarios = append(scenarios, scenario{"Vanishing/" + sc.Name, sc.Sys, sc.AssignHonest})
+	}
+	for _, build := range wioptest.LocalVanishingScenarios() {
+		sc := build()
+		scenarios = append(scenarios, scenario{"LocalVanishing/" + sc.Name, sc.Sys, sc.AssignHonest})
+	}
+	for _, build := range wioptest.MessageBusScenarios() {
+		sc := build()
+		scenarios = append(scenarios, scenario{"MessageBus/" + sc.Name, sc.Sys, sc.AssignHonest})
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			compile(sc.sys)
+			proof := sc.sys.Prove(sc.assign)
+
+			data := wire.MarshalProof(sc.sys, proof)
+			decoded, err := wire.UnmarshalProof(sc.sys, data)
+			require.NoError(t, err)
+			require.NoError(t, sc.sys.Verify(decoded), "decoded proof must verify")
+
+			assert.Equal(t, data, wire.MarshalProof(sc.sys, decoded), "re-encoding must be byte-identical")
+			assert.Equal(t, data, wire.MarshalProof(sc.sys, proof), "encoding must be deterministic")
+		})
+	}
+}
+
+func TestUnmarshalProof_DigestMismatch(t *testing.T) {
+	sc := wioptest.VanishingScenarios()[0]()
+	compile(sc.Sys)
+	data := wire.MarshalProof(sc.Sys, sc.Sys.Prove(sc.AssignHonest))
+
+	other := wioptest.VanishingScenarios()[1]()
+	compile(other.Sys)
+	require.NotEqual(t, sc.Sys.Digest(), other.Sys.Digest())
+
+	_, err := wire.UnmarshalProof(other.Sys, data)
+	assert.ErrorIs(t, err, wire.ErrDigestMismatch)
+}
+
+// TestDecodeProof_Golden pins the byte layout: the fixture was written by
+// hand from the specification, not by [wire.EncodeProof].
+func TestDecodeProof_Golden(t *testing.T) {
+	data := readMinimalProof(t)
+
+	h, p, err := wire.DecodeProof(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, wire.Version, h.Version)
+	assert.Equal(t, wire.KindProof, h.Kind)
+	for i, b := range h.SystemDigest {
+		assert.Equal(t, byte(i), b)
+	}
+
+	assert.Equal(t, map[int]int{2: 8}, p.DynamicSizes)
+
+	require.Len(t, p.Columns, 2)
+	col := p.Columns[wiop.ObjectID(0x0100000000000000)]
+	require.NotNil(t, col)
+	assert.Equal(t, field.Zero(), col.Padding)
+	assert.Equal(t, []field.Element{field.NewFromString("1"), field.NewFromString("2"), field.NewFromString("3")}, col.Plain.AsBase())
+
+	col = p.Columns[wiop.ObjectID(0x0100010000000002)]
+	require.NotNil(t, col)
+	assert.Equal(t, field.NewFromString("5"), col.Padding)
+	assert.Equal(t, []field.Ext{field.UintsToExt(1, 2, 3, 4, 5, 6)}, col.Plain.AsExt())
+
+	assert.Equal(t, map[wiop.ObjectID]field.Gen{
+		wiop.ObjectID(0x0201000000000000): field.ElemFromBase(field.NewFromString("7")),
+		wiop.ObjectID(0x0201000000000001): field.ElemFromExt(field.IntsToExt(-1, 0, 0, 0, 0, 1)),
+	}, p.Cells)
+
+	assert.Equal(t, data, wire.EncodeProof(h.SystemDigest, p))
+}
+
+// Offsets into the golden fixture, see docs/proof-wire-format.md.
+const (
+	offDynCount   = 40
+	offColCount   = 52
+	offCol0ID     = 56
+	offCol0Tag    = 64
+	offCellCount  = 126
+	offCell0Value = 139
+	offCell1ID    = 143
+)
+
+func TestDecodeProof_Rejects(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func([]byte) []byte
+		want   error
+	}{
+		{"BadMagic", func(b []byte) []byte { b[0] = 'X'; return b }, wire.ErrBadMagic},
+		{"Version", func(b []byte) []byte { b[5] = 2; return b }, wire.ErrUnsupportedVersion},
+		{"Kind", func(b []byte) []byte { b[7] = 9; return b }, wire.ErrUnexpectedKind},
+		{"TrailingByte", func(b []byte) []byte { return append(b, 0) }, wire.ErrMalformed},
+		{"NonCanonicalElement", func(b []byte) []byte {
+			copy(b[offCell0Value:], []byte{0xff, 0xff, 0xff, 0xff})
+			return b
+		}, wire.ErrMalformed},
+		{"DuplicateCell", func(b []byte) []byte { b[offCell1ID+7] = 0; return b }, wire.ErrMalformed},
+		{"CellIDAsColumn", func(b []byte) []byte { b[offCol0ID] = byte(wiop.KindCell); return b }, wire.ErrMalformed},
+		{"UnknownTag", func(b []byte) []byte { b[offCol0Tag] = 7; return b }, wire.ErrMalformed},
+		{"HugeDynCount", func(b []byte) []byte {
+			copy(b[offDynCount:], []byte{0xff, 0xff, 0xff, 0xff})
+			return b
+		}, wire.ErrTruncated},
+		{"HugeColumnCount", func(b []byte) []byte {
+			copy(b[offColCount:], []byte{0xff, 0xff, 0xff, 0xff})
+			return b
+		}, wire.ErrTruncated},
+		{"MissingCell", func(b []byte) []byte { b[offCellCount+3] = 3; return b }, wire.ErrTruncated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := wire.DecodeProof(tc.mutate(readMinimalProof(t)))
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestDecodeProof_EveryPrefixIsTruncated(t *testing.T) {
+	data := readMinimalProof(t)
+	for n := range len(data) {
+		_, _, err := wire.DecodeProof(data[:n])
+		assert.ErrorIs(t, err, wire.ErrTruncated, "prefix of length %d", n)
+	}
+}
+
+// FuzzDecodeProof checks that decoding never panics and that every accepted
+// input is the unique encoding of what it decodes to.
+func FuzzDecodeProof(f *testing.F) {
+	f.Add(readMinimalProof(f))
+	sc := wioptest.NewBusTaggedSendersScenario()
+	compile(sc.Sys)
+	f.Add(wire.MarshalProof(sc.Sys, sc.Sys.Prove(sc.AssignHonest)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h, p, err := wire.DecodeProof(data)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, data, wire.EncodeProof(h.SystemDigest, p))
+	})
+}
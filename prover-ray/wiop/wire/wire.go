@@ -0,0 +1,108 @@
+// Package wire defines the binary encoding of wiop proof artefacts.
+//
+// Every encoded object starts with a fixed 40-byte [Header] that names the
+// format version, the kind of payload that follows and the
+// [wiop.System.Digest] of the system the payload was produced against. A
+// decoder holding a different system rejects the payload before reading it.
+//
+// The body layout is per-kind. Only [KindProof] is defined today; the FRI and
+// Merkle parts of a compiled proof will get their own kinds when they gain a
+// wire representation.
+//
+// The encoding is deterministic: maps are written in increasing key order and
+// every value has exactly one valid encoding, so a decode followed by an
+// encode reproduces the input bytes. The normative description lives in
+// prover-ray/docs/proof-wire-format.md, which the Zig verifier follows.
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Version is the format version written by this package. Decoders reject any
+// other version.
+const Version uint16 = 1
+
+// HeaderSize is the encoded size of a [Header] in bytes.
+const HeaderSize = 4 + 2 + 2 + 32
+
+// magic opens every encoded object.
+var magic = [4]byte{'W', 'I', 'O', 'P'}
+
+// Kind identifies the payload following a [Header].
+type Kind uint16
+
+const (
+	// KindProof is a [wiop.Proof]; see [EncodeProof].
+	KindProof Kind = 1
+)
+
+// String implements [fmt.Stringer].
+func (k Kind) String() string {
+	switch k {
+	case KindProof:
+		return "Proof"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint16(k))
+	}
+}
+
+var (
+	// ErrBadMagic is returned when the input does not start with "WIOP".
+	ErrBadMagic = errors.New("wire: bad magic")
+	// ErrUnsupportedVersion is returned for a header version other than
+	// [Version].
+	ErrUnsupportedVersion = errors.New("wire: unsupported version")
+	// ErrUnexpectedKind is returned when the header announces a different
+	// payload than the one being decoded.
+	ErrUnexpectedKind = errors.New("wire: unexpected payload kind")
+	// ErrDigestMismatch is returned when the payload was produced against a
+	// different system.
+	ErrDigestMismatch = errors.New("wire: system digest mismatch")
+	// ErrTruncated is returned when the input ends before the payload does.
+	ErrTruncated = errors.New("wire: truncated input")
+	// ErrMalformed is returned for any other structural violation: unsorted
+	// or duplicate keys, IDs of the wrong kind, non-canonical field elements,
+	// unknown tags and trailing bytes.
+	ErrMalformed = errors.New("wire: malformed input")
+)
+
+// Header opens every encoded object.
+type Header struct {
+	Version      uint16
+	Kind         Kind
+	SystemDigest [32]byte
+}
+
+// appendTo appends the encoding of h to dst.
+func (h Header) appendTo(dst []byte) []byte {
+	dst = append(dst, magic[:]...)
+	dst = binary.BigEndian.AppendUint16(dst, h.Version)
+	dst = binary.BigEndian.AppendUint16(dst, uint16(h.Kind))
+	return append(dst, h.SystemDigest[:]...)
+}
+
+// DecodeHeader reads the header at the start of data. It checks the magic and
+// the version but not the kind, so that tools can dispatch on it.
+func DecodeHeader(data []byte) (Header, error) {
+	if len(data) < HeaderSize {
+		if len(data) >= len(magic) && [4]byte(data[:4]) != magic {
+			return Header{}, ErrBadMagic
+		}
+		return Header{}, ErrTruncated
+	}
+	if [4]byte(data[:4]) != magic {
+		return Header{}, ErrBadMagic
+	}
+	h := Header{
+		Version: binary.BigEndian.Uint16(data[4:6]),
+		Kind:    Kind(binary.BigEndian.Uint16(data[6:8])),
+	}
+	copy(h.SystemDigest[:], data[8:HeaderSize])
+	if h.Version != Version {
+		return h, fmt.Errorf("%w: %d", ErrUnsupportedVersion, h.Version)
+	}
+	return h, nil
+}
@@ -32,8 +32,7 @@ import (
 const (
 	// Indicates the depth of a Merkle-tree for L2 messages, and implicitly how
 	// many messages can be stored in a single root hash.
-	l2MsgMerkleTreeDepth     = 5
-	l2MsgMerkleTreeMaxLeaves = 1 << l2MsgMerkleTreeDepth
+	l2MsgMerkleTreeDepth = 5
 )
 
 // Collect the fields, to make the aggregation proof
@@ -55,7 +54,8 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 
 	cf.ExecutionPI = make([]public_input.Execution, 0, len(req.ExecutionProofs))
 	cf.InvalidityPI = make([]public_input.Invalidity, 0, len(req.InvalidityProofs))
-	cf.InnerCircuitTypes = make([]pi_interconnection.InnerCircuitType, 0, len(req.ExecutionProofs)+len(req.DecompressionProofs)+len(req.InvalidityProofs))
+	cf.InvalidityBatchPI = make([]public_input.InvalidityBatch, 0, len(req.InvalidityBatchProofs))
+	cf.InnerCircuitTypes = make([]pi_interconnection.InnerCircuitType, 0, len(req.ExecutionProofs)+len(req.DecompressionProofs)+len(req.InvalidityProofs)+len(req.InvalidityBatchProofs))
 
 	for i, execReqFPath := range req.ExecutionProofs {
 
@@ -229,6 +229,10 @@ func collectFields(cfg *config.Config, req *Request) (*CollectedFields, error) {
 		return nil, fmt.Errorf("could not collect invalidity info: %w", err)
 	}
 
+	if err := cf.collectInvalidityBatchInfo(cfg, req); err != nil {
+		return nil, fmt.Errorf("could not collect batch invalidity info: %w", err)
+	}
+
 	return cf, nil
 }
 
@@ -274,6 +278,7 @@ func CraftResponse(cfg *config.Config, cf *CollectedFields) (resp *Response, err
 		BaseFee:              uint64(cfg.Layer2.BaseFee),
 		CoinBase:             types.EthAddress(cfg.Layer2.CoinBase),
 		L2MessageServiceAddr: types.EthAddress(cfg.Layer2.MsgSvcContract),
+		IsAllowedCircuitID:   uint64(cfg.Aggregation.IsAllowedCircuitID),
 
 		FilteredAddresses: filteredAddrs,
 	}
@@ -396,33 +401,49 @@ func PackOffsets(unpacked []bool) []byte {
 // `l2MsgMerkleTreeDepth`. The leaves are zero-padded on the right.
 func PackInMiniTrees(l2MsgHashes []string) []string {
 
-	paddedLen := utils.NextMultipleOf(len(l2MsgHashes), l2MsgMerkleTreeMaxLeaves)
+	trees, err := buildL2MsgMiniTrees(l2MsgHashes, l2MsgMerkleTreeDepth)
+	if err != nil {
+		panic(err)
+	}
+
+	res := make([]string, len(trees))
+	for i := range trees {
+		res[i] = trees[i].Root.Hex()
+	}
+
+	return res
+}
+
+// buildL2MsgMiniTrees packs the L2 message hashes into consecutive Keccak
+// Merkle trees of the given depth. The last tree is zero-padded on the right.
+func buildL2MsgMiniTrees(l2MsgHashes []string, depth int) ([]*smt.Tree, error) {
+
+	nbLeaves := 1 << depth
+	paddedLen := utils.NextMultipleOf(len(l2MsgHashes), nbLeaves)
 	paddedL2MsgHashes := make([]string, paddedLen)
 	copy(paddedL2MsgHashes, l2MsgHashes)
 
-	res := []string{}
+	res := []*smt.Tree{}
 
-	for i := 0; i < paddedLen; i += l2MsgMerkleTreeMaxLeaves {
+	for i := 0; i < paddedLen; i += nbLeaves {
 
-		digests := make([]types.Bls12377Fr, l2MsgMerkleTreeMaxLeaves)
+		digests := make([]types.Bls12377Fr, nbLeaves)
 
 		// Convert the leaves into digests that can be processed by the smt
 		// package.
 		for j := range digests {
 			leaf := paddedL2MsgHashes[i+j]
 			decoded, err := utils.HexDecodeString(leaf)
-			copy(digests[j][:], decoded)
-
 			if err != nil {
-				panic(err)
+				return nil, fmt.Errorf("could not decode the L2 message hash #%d: %w", i+j, err)
 			}
+			copy(digests[j][:], decoded)
 		}
 
-		tree := smt.BuildComplete(digests, hashtypes.Keccak)
-		res = append(res, tree.Root.Hex())
+		res = append(res, smt.BuildComplete(digests, hashtypes.Keccak))
 	}
 
-	return res
+	return res, nil
 }
 
 func parseProofClaim(
@@ -529,6 +550,89 @@ func (cf *CollectedFields) collectInvalidityInfo(cfg *config.Config, req *Reques
 	return nil
 }
 
+// collectInvalidityBatchInfo collects the batch invalidity proofs. They must be
+// called after [CollectedFields.collectInvalidityInfo] as the batches continue
+// the forced transactions of the single invalidity proofs and are proven
+// against the same simulated block.
+func (cf *CollectedFields) collectInvalidityBatchInfo(cfg *config.Config, req *Request) error {
+	var (
+		po              invalidity.BatchResponse
+		parentFtxNumber = uint64(req.ParentAggregationLastFtxNumber)
+		simBlockNumber  uint64
+		simBlockTime    uint64
+	)
+	logrus.Infof(" Collecting batch invalidity info and validating interconnection with the invalidity proofs")
+
+	if n := len(cf.InvalidityPI); n > 0 {
+		parentFtxNumber = cf.InvalidityPI[n-1].TxNumber
+		simBlockNumber = cf.InvalidityPI[0].SimulatedBlockNumber
+		simBlockTime = cf.InvalidityPI[0].SimulatedBlockTimestamp
+	}
+
+	for i, batchReqFPath := range req.InvalidityBatchProofs {
+
+		var (
+			fpath = path.Join(cfg.Invalidity.DirTo(), batchReqFPath)
+			f     = files.MustRead(fpath)
+		)
+
+		po = invalidity.BatchResponse{}
+		if err := json.NewDecoder(f).Decode(&po); err != nil {
+			return fmt.Errorf("fields collection, decoding %s, %w", batchReqFPath, err)
+		}
+		if i == 0 && len(cf.InvalidityPI) == 0 {
+			simBlockNumber = po.SimulatedExecutionBlockNumber
+			simBlockTime = po.SimulatedExecutionBlockTimestamp
+		}
+		cf.InnerCircuitTypes = append(cf.InnerCircuitTypes, pi_interconnection.InvalidityBatch)
+
+		pClaim, err := parseProofClaim(po.Proof, po.PublicInput.Hex(), po.VerifyingKeyShaSum)
+		if err != nil {
+			return fmt.Errorf("could not parse the proof claim %v for `%v` : %w", i, fpath, err)
+		}
+		cf.ProofClaims = append(cf.ProofClaims, *pClaim)
+
+		pi := po.FuncInput()
+		if recomputed := pi.Sum(nil); !bytes.Equal(recomputed, po.PublicInput[:]) {
+			return fmt.Errorf("invalidity batch #%d: public input mismatch: given %x, computed %x", i, po.PublicInput, recomputed)
+		}
+
+		if po.SimulatedExecutionBlockTimestamp != simBlockTime {
+			return fmt.Errorf("in the same aggregation, the invalidity proofs have different simulated block timestamps: %d vs %d", simBlockTime, po.SimulatedExecutionBlockTimestamp)
+		}
+		if po.SimulatedExecutionBlockNumber != simBlockNumber {
+			return fmt.Errorf("in the same aggregation, the invalidity proofs have different simulated block numbers: %d vs %d", simBlockNumber, po.SimulatedExecutionBlockNumber)
+		}
+		if po.FirstTxNumber != parentFtxNumber+1 {
+			return fmt.Errorf("forced transaction numbers should be consecutive: jumping from %d to %d instead of incrementing by 1", parentFtxNumber, po.FirstTxNumber)
+		}
+		if po.LastTxNumber < po.FirstTxNumber {
+			return fmt.Errorf("invalidity batch #%d: last forced transaction number %d is before the first one %d", i, po.LastTxNumber, po.FirstTxNumber)
+		}
+
+		if got, want := po.ChainID, cfg.Layer2.ChainID; got != want {
+			return fmt.Errorf("invalidity batch #%d fails CHECK_CHAIN_ID:\n\texpected %x, encountered %x", i, want, got)
+		}
+		if got, want := po.BaseFee, cfg.Layer2.BaseFee; got != want {
+			return fmt.Errorf("invalidity batch #%d fails CHECK_BASE_FEE:\n\texpected %x, encountered %x", i, want, got)
+		}
+		if got, want := po.CoinBase, cfg.Layer2.CoinBase; got != types.EthAddress(want) {
+			return fmt.Errorf("invalidity batch #%d fails CHECK_COIN_BASE:\n\texpected CoinBase %x, encountered %x", i, want, got)
+		}
+		if got, want := po.L2BridgeAddress, cfg.Layer2.MsgSvcContract; got != types.EthAddress(want) {
+			return fmt.Errorf("invalidity batch #%d fails CHECK_SVC_ADDR:\n\texpected L2 service address %x, encountered %x", i, want, got)
+		}
+
+		parentFtxNumber = po.LastTxNumber
+
+		cf.InvalidityBatchPI = append(cf.InvalidityBatchPI, *pi)
+
+		cf.FinalFtxNumber = uint(po.LastTxNumber)
+		cf.FinalFtxRollingHash = po.FtxRollingHash.Hex()
+	}
+	return nil
+}
+
 // collectFilteredAddresses extracts filtered addresses from invalidity public
 // inputs. For each invalidity PI, if FromIsFiltered is set the FromAddress is
 // collected, and if ToIsFiltered is set the ToAddress is collected.
@@ -260,6 +260,8 @@ func mockCircuitIDForType(invalidityType circInvalidity.InvalidityType) circuits
 		return circuits.MockCircuitIDInvalidityPrecompileLogs
 	case circInvalidity.FilteredAddressFrom, circInvalidity.FilteredAddressTo:
 		return circuits.MockCircuitIDInvalidityFilteredAddress
+	case circInvalidity.GasLimitTooLow, circInvalidity.GasLimitTooHigh:
+		return circuits.MockCircuitIDInvalidityGasLimit
 	default:
 		panic("unknown invalidity type")
 	}
@@ -415,6 +417,88 @@ func TestCollectInvalidityInfoMultipleTypes(t *testing.T) {
 	assert.Equal(t, cf.InvalidityPI[2].ToAddress, filteredAddrs[1])
 }
 
+// TestCollectInvalidityBatchInfo verifies that the batch invalidity proofs are
+// collected after the single ones and must continue their forced transactions.
+func TestCollectInvalidityBatchInfo(t *testing.T) {
+
+	srsProvider := circuits.NewUnsafeSRSProvider()
+
+	var (
+		responses       []backendInvalidity.Response
+		prevRollingHash types.Bls12377Fr
+	)
+	for i := range 2 {
+		tx := makeMockSignedTx(t, int64(i+1))
+		resp := makeMockInvalidityResponse(t, circInvalidity.BadNonce, uint64(i+1), prevRollingHash, tx, srsProvider)
+		prevRollingHash = resp.FtxRollingHash
+		responses = append(responses, resp)
+	}
+
+	setup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDInvalidityBatch, ecc.BLS12_377.ScalarField())
+	require.NoError(t, err)
+
+	batch := backendInvalidity.BatchResponse{
+		FirstTxNumber:                    3,
+		LastTxNumber:                     5,
+		PrevFtxRollingHash:               prevRollingHash,
+		FtxRollingHash:                   types.Bls12377Fr{31: 5},
+		SimulatedExecutionBlockNumber:    responses[0].SimulatedExecutionBlockNumber,
+		SimulatedExecutionBlockTimestamp: responses[0].SimulatedExecutionBlockTimestamp,
+	}
+	batch.PublicInput = types.Bls12377Fr(batch.FuncInput().Sum(nil))
+	batch.Proof = dummy.MakeProof(&setup, batch.FuncInput().SumAsField(), circuits.MockCircuitIDInvalidityBatch)
+	batch.VerifyingKeyShaSum = setup.VerifyingKeyDigest()
+
+	tmpDir := t.TempDir()
+	respDir := filepath.Join(tmpDir, "responses")
+	require.NoError(t, os.MkdirAll(respDir, 0o755))
+
+	writeResponse := func(name string, resp any) string {
+		f, err := os.Create(filepath.Join(respDir, name))
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(f).Encode(resp))
+		require.NoError(t, f.Close())
+		return name
+	}
+
+	cfg := &config.Config{
+		Invalidity: config.Invalidity{
+			WithRequestDir: config.WithRequestDir{
+				RequestsRootDir: tmpDir,
+			},
+		},
+	}
+
+	aggReq := &Request{
+		InvalidityProofs: []string{
+			writeResponse("single-1.json", responses[0]),
+			writeResponse("single-2.json", responses[1]),
+		},
+		InvalidityBatchProofs: []string{writeResponse("batch-3-5.json", batch)},
+	}
+
+	cf := &CollectedFields{}
+	require.NoError(t, cf.collectInvalidityInfo(cfg, aggReq))
+	require.NoError(t, cf.collectInvalidityBatchInfo(cfg, aggReq))
+
+	assert.Equal(t, []pi_interconnection.InnerCircuitType{
+		pi_interconnection.Invalidity,
+		pi_interconnection.Invalidity,
+		pi_interconnection.InvalidityBatch,
+	}, cf.InnerCircuitTypes)
+	assert.Len(t, cf.ProofClaims, 3)
+	require.Len(t, cf.InvalidityBatchPI, 1)
+	assert.Equal(t, *batch.FuncInput(), cf.InvalidityBatchPI[0])
+	assert.Equal(t, uint(5), cf.FinalFtxNumber)
+	assert.Equal(t, batch.FtxRollingHash.Hex(), cf.FinalFtxRollingHash)
+
+	// the batch must start right after the last single invalidity proof
+	aggReq.InvalidityProofs = aggReq.InvalidityProofs[:1]
+	cf = &CollectedFields{}
+	require.NoError(t, cf.collectInvalidityInfo(cfg, aggReq))
+	require.Error(t, cf.collectInvalidityBatchInfo(cfg, aggReq))
+}
+
 // TestCraftResponseWithInvalidityProofs verifies that CraftResponse correctly
 // propagates invalidity fields (filtered addresses, FTX rolling hash, etc.)
 // into the aggregation response.
@@ -467,3 +551,44 @@ func TestCraftResponseWithInvalidityProofs(t *testing.T) {
 	assert.Equal(t, otherFrom, resp.FilteredAddresses[0], "first filtered address should be the FromIsFiltered address")
 	assert.Equal(t, toAddr, resp.FilteredAddresses[1], "second filtered address should be the ToIsFiltered address")
 }
+
+// TestResponseFuncInput checks that the functional public inputs rebuilt from
+// a crafted response hash to the public input of the fields it was crafted
+// from.
+func TestResponseFuncInput(t *testing.T) {
+	zeroHash := "0x0000000000000000000000000000000000000000000000000000000000000000"
+	ftxHash := "0x1111111111111111111111111111111111111111111111111111111111111111"
+	msgRoot := "0x3333333333333333333333333333333333333333333333333333333333333333"
+
+	cf := &CollectedFields{
+		FinalShnarf:                             ftxHash,
+		ParentAggregationFinalShnarf:            zeroHash,
+		DataParentHash:                          zeroHash,
+		ParentAggregationLastBlockTimestamp:     1000,
+		FinalTimestamp:                          2000,
+		LastFinalizedBlockNumber:                10,
+		FinalBlockNumber:                        20,
+		L1RollingHash:                           ftxHash,
+		L1RollingHashMessageNumber:              4,
+		L2MessagingBlocksOffsets:                "0x",
+		L2MsgRootHashes:                         []string{msgRoot},
+		L2MsgTreeDepth:                          5,
+		FinalFtxRollingHash:                     ftxHash,
+		FinalFtxNumber:                          3,
+		LastFinalizedFtxRollingHash:             zeroHash,
+		LastFinalizedL1RollingHash:              zeroHash,
+		LastFinalizedL1RollingHashMessageNumber: 2,
+		IsProoflessJob:                          true,
+	}
+
+	cfg := &config.Config{}
+	cfg.Layer2.ChainID = 51
+	cfg.Layer2.BaseFee = 7
+	cfg.Aggregation.IsAllowedCircuitID = 44
+
+	resp, err := CraftResponse(cfg, cf)
+	require.NoError(t, err)
+
+	want := cf.AggregationPublicInput(cfg)
+	assert.Equal(t, want.GetPublicInputHex(), resp.FuncInput().GetPublicInputHex())
+}
@@ -0,0 +1,198 @@
+package aggregation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	smt "github.com/consensys/linea-monorepo/prover/crypto/state-management/smt_mimcbls12377"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// L2MessageClaimProof is the Merkle proof of inclusion of an L2 to L1 message
+// hash in one of the L2 Merkle roots finalized by an aggregation. It holds the
+// arguments expected by the L1 message service to claim the message.
+type L2MessageClaimProof struct {
+	MessageHash string `json:"messageHash"`
+	// BlockNumber is the number of the L2 block emitting the message.
+	BlockNumber uint `json:"blockNumber"`
+	// TreeIndex is the position of Root in the l2MerkleRoots of the
+	// aggregation response.
+	TreeIndex int    `json:"treeIndex"`
+	Root      string `json:"root"`
+	TreeDepth int    `json:"treeDepth"`
+	// LeafIndex is the position of the message in its tree.
+	LeafIndex int `json:"leafIndex"`
+	// Proof lists the siblings of the path from the leaf to the root, starting
+	// from the leaf level.
+	Proof []string `json:"proof"`
+}
+
+// L2MessageTrees are the Merkle trees of the L2 to L1 messages of an
+// aggregation, rebuilt the same way as the PI interconnection circuit does.
+type L2MessageTrees struct {
+	depth          int
+	trees          []*smt.Tree
+	messageHashes  []string
+	messageBlocks  []uint
+	positionByHash map[string]int
+}
+
+// BuildL2MessageTrees rebuilds the L2 message trees of a finalized
+// aggregation from the execution responses it covers. The executions may be
+// passed in any order but must exactly cover the blocks of the aggregation.
+// It returns an error if the recomputed roots differ from the ones of the
+// response or if the response roots are not the ones bound by its public
+// input.
+func BuildL2MessageTrees(agg *Response, executions []*execution.Response) (*L2MessageTrees, error) {
+
+	if agg.AggregatedProofPublicInput != "" {
+		computed := agg.FuncInput().GetPublicInputHex()
+		if !strings.EqualFold(computed, agg.AggregatedProofPublicInput) {
+			return nil, fmt.Errorf("the aggregation public input %v does not match its fields, recomputed %v", agg.AggregatedProofPublicInput, computed)
+		}
+	}
+
+	execs := make([]*execution.Response, len(executions))
+	copy(execs, executions)
+	sort.Slice(execs, func(i, j int) bool {
+		return execs[i].FirstBlockNumber < execs[j].FirstBlockNumber
+	})
+
+	if agg.L2MsgTreesDepth == 0 {
+		return nil, fmt.Errorf("the aggregation has no L2 message tree depth")
+	}
+
+	res := &L2MessageTrees{
+		depth:          utils.ToInt(agg.L2MsgTreesDepth),
+		positionByHash: map[string]int{},
+	}
+
+	nextBlock := agg.LastFinalizedBlockNumber + 1
+	for i, exec := range execs {
+
+		if len(exec.BlocksData) == 0 {
+			return nil, fmt.Errorf("execution #%d has no blocks", i)
+		}
+
+		if uint(exec.FirstBlockNumber) != nextBlock {
+			return nil, fmt.Errorf("execution #%d starts at block %d, expected %d", i, exec.FirstBlockNumber, nextBlock)
+		}
+
+		var nbMsgs int
+		for j, block := range exec.BlocksData {
+			blockNumber := uint(exec.FirstBlockNumber + j)
+			for _, h := range block.L2ToL1MsgHashes {
+				if nbMsgs >= len(exec.AllL2L1MessageHashes) || exec.AllL2L1MessageHashes[nbMsgs] != h {
+					return nil, fmt.Errorf("execution #%d: L2 message hash %v of block %d is not in the list of all the L2 message hashes", i, h.Hex(), blockNumber)
+				}
+				res.add(h.Hex(), blockNumber)
+				nbMsgs++
+			}
+		}
+
+		if nbMsgs != len(exec.AllL2L1MessageHashes) {
+			return nil, fmt.Errorf("execution #%d: %d L2 message hashes in the blocks but %d in total", i, nbMsgs, len(exec.AllL2L1MessageHashes))
+		}
+
+		nextBlock += uint(len(exec.BlocksData))
+	}
+
+	if nextBlock != agg.FinalBlockNumber+1 {
+		return nil, fmt.Errorf("the executions end at block %d but the aggregation ends at block %d", nextBlock-1, agg.FinalBlockNumber)
+	}
+
+	trees, err := buildL2MsgMiniTrees(res.messageHashes, res.depth)
+	if err != nil {
+		return nil, err
+	}
+	res.trees = trees
+
+	if len(trees) != len(agg.L2MerkleRoots) {
+		return nil, fmt.Errorf("rebuilt %d L2 message trees but the aggregation has %d roots", len(trees), len(agg.L2MerkleRoots))
+	}
+
+	for i := range trees {
+		if got := trees[i].Root.Hex(); !strings.EqualFold(got, agg.L2MerkleRoots[i]) {
+			return nil, fmt.Errorf("L2 message tree #%d: recomputed root %v, aggregation has %v", i, got, agg.L2MerkleRoots[i])
+		}
+	}
+
+	return res, nil
+}
+
+func (t *L2MessageTrees) add(hash string, blockNumber uint) {
+	if _, ok := t.positionByHash[hash]; !ok {
+		t.positionByHash[hash] = len(t.messageHashes)
+	}
+	t.messageHashes = append(t.messageHashes, hash)
+	t.messageBlocks = append(t.messageBlocks, blockNumber)
+}
+
+// MessageHashes returns the hashes of the L2 messages in the order of the
+// leaves of the trees.
+func (t *L2MessageTrees) MessageHashes() []string {
+	return t.messageHashes
+}
+
+// Prove returns the claim proof of the L2 message with the given hash. If the
+// same hash occurs several times, the first occurrence is proven.
+func (t *L2MessageTrees) Prove(messageHash string) (L2MessageClaimProof, error) {
+
+	decoded, err := utils.HexDecodeString(messageHash)
+	if err != nil || len(decoded) != 32 {
+		return L2MessageClaimProof{}, fmt.Errorf("invalid message hash %q", messageHash)
+	}
+
+	pos, ok := t.positionByHash[utils.HexEncodeToString(decoded)]
+	if !ok {
+		return L2MessageClaimProof{}, fmt.Errorf("message hash %v is not part of the aggregation", messageHash)
+	}
+
+	return t.proveAt(pos)
+}
+
+func (t *L2MessageTrees) proveAt(pos int) (L2MessageClaimProof, error) {
+
+	var (
+		nbLeaves  = 1 << t.depth
+		treeIndex = pos / nbLeaves
+		leafIndex = pos % nbLeaves
+		tree      = t.trees[treeIndex]
+	)
+
+	proof, err := tree.Prove(leafIndex)
+	if err != nil {
+		return L2MessageClaimProof{}, err
+	}
+
+	siblings := make([]string, len(proof.Siblings))
+	for i := range siblings {
+		siblings[i] = proof.Siblings[i].Hex()
+	}
+
+	return L2MessageClaimProof{
+		MessageHash: t.messageHashes[pos],
+		BlockNumber: t.messageBlocks[pos],
+		TreeIndex:   treeIndex,
+		Root:        tree.Root.Hex(),
+		TreeDepth:   t.depth,
+		LeafIndex:   leafIndex,
+		Proof:       siblings,
+	}, nil
+}
+
+// ProveAll returns the claim proofs of all the L2 messages of the
+// aggregation, in the order of the leaves.
+func (t *L2MessageTrees) ProveAll() ([]L2MessageClaimProof, error) {
+	res := make([]L2MessageClaimProof, len(t.messageHashes))
+	for i := range res {
+		p, err := t.proveAt(i)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = p
+	}
+	return res, nil
+}
@@ -0,0 +1,136 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	hashtypes "github.com/consensys/linea-monorepo/prover/crypto/state-management/hashtypes_legacy"
+	smt "github.com/consensys/linea-monorepo/prover/crypto/state-management/smt_mimcbls12377"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeL2MsgExecution returns an execution response starting at firstBlock
+// whose blocks emit the given number of L2 messages. The message hashes are
+// drawn from *counter.
+func makeL2MsgExecution(firstBlock int, nbMsgsPerBlock []int, counter *int) *execution.Response {
+	resp := &execution.Response{FirstBlockNumber: firstBlock}
+	for _, n := range nbMsgsPerBlock {
+		block := execution.BlockData{}
+		for range n {
+			*counter++
+			h := types.FullBytes32{31: byte(*counter), 30: 0xaa}
+			block.L2ToL1MsgHashes = append(block.L2ToL1MsgHashes, h)
+			resp.AllL2L1MessageHashes = append(resp.AllL2L1MessageHashes, h)
+		}
+		resp.BlocksData = append(resp.BlocksData, block)
+	}
+	return resp
+}
+
+func TestL2MessageClaimProofs(t *testing.T) {
+
+	var counter int
+	execs := []*execution.Response{
+		makeL2MsgExecution(11, []int{20, 0, 15}, &counter),
+		makeL2MsgExecution(14, []int{3}, &counter),
+	}
+
+	allHashes := allHashesOf(execs)
+	agg := &Response{
+		LastFinalizedBlockNumber: 10,
+		FinalBlockNumber:         14,
+		L2MerkleRoots:            PackInMiniTrees(allHashes),
+		L2MsgTreesDepth:          l2MsgMerkleTreeDepth,
+	}
+	agg.AggregatedProofPublicInput = agg.FuncInput().GetPublicInputHex()
+	require.Len(t, agg.L2MerkleRoots, 2)
+
+	// The executions may be given in any order.
+	trees, err := BuildL2MessageTrees(agg, []*execution.Response{execs[1], execs[0]})
+	require.NoError(t, err)
+	require.Equal(t, allHashes, trees.MessageHashes())
+
+	proofs, err := trees.ProveAll()
+	require.NoError(t, err)
+	require.Len(t, proofs, len(allHashes))
+
+	conf := &smt.Config{HashFunc: hashtypes.Keccak, Depth: l2MsgMerkleTreeDepth}
+	for i, p := range proofs {
+		assert.Equal(t, i/32, p.TreeIndex)
+		assert.Equal(t, i%32, p.LeafIndex)
+		assert.Equal(t, agg.L2MerkleRoots[p.TreeIndex], p.Root)
+
+		proof := smt.Proof{Path: p.LeafIndex, Siblings: make([]types.Bls12377Fr, len(p.Proof))}
+		for j := range p.Proof {
+			copy(proof.Siblings[j][:], utils.HexMustDecodeString(p.Proof[j]))
+		}
+
+		var leaf, root types.Bls12377Fr
+		copy(leaf[:], utils.HexMustDecodeString(p.MessageHash))
+		copy(root[:], utils.HexMustDecodeString(p.Root))
+		assert.True(t, proof.Verify(conf, leaf, root), "message #%d", i)
+	}
+
+	// The messages of the second, empty, block of the first execution are
+	// attributed to the right blocks.
+	assert.Equal(t, uint(11), proofs[19].BlockNumber)
+	assert.Equal(t, uint(13), proofs[20].BlockNumber)
+	assert.Equal(t, uint(14), proofs[37].BlockNumber)
+
+	p, err := trees.Prove(allHashes[33])
+	require.NoError(t, err)
+	assert.Equal(t, proofs[33], p)
+
+	_, err = trees.Prove(types.FullBytes32{1}.Hex())
+	require.Error(t, err)
+}
+
+func TestL2MessageTreesMismatch(t *testing.T) {
+
+	var counter int
+	execs := []*execution.Response{makeL2MsgExecution(11, []int{5}, &counter)}
+
+	newAgg := func() *Response {
+		agg := &Response{
+			LastFinalizedBlockNumber: 10,
+			FinalBlockNumber:         11,
+			L2MerkleRoots:            PackInMiniTrees([]string{execs[0].AllL2L1MessageHashes[0].Hex()}),
+			L2MsgTreesDepth:          l2MsgMerkleTreeDepth,
+		}
+		agg.AggregatedProofPublicInput = agg.FuncInput().GetPublicInputHex()
+		return agg
+	}
+
+	// The roots do not commit to the messages of the execution.
+	_, err := BuildL2MessageTrees(newAgg(), execs)
+	require.ErrorContains(t, err, "recomputed root")
+
+	// The roots are not the ones of the public input.
+	agg := newAgg()
+	agg.L2MerkleRoots = PackInMiniTrees(allHashesOf(execs))
+	_, err = BuildL2MessageTrees(agg, execs)
+	require.ErrorContains(t, err, "public input")
+
+	agg.AggregatedProofPublicInput = agg.FuncInput().GetPublicInputHex()
+	_, err = BuildL2MessageTrees(agg, execs)
+	require.NoError(t, err)
+
+	// The executions do not cover the aggregation.
+	agg.FinalBlockNumber = 12
+	agg.AggregatedProofPublicInput = agg.FuncInput().GetPublicInputHex()
+	_, err = BuildL2MessageTrees(agg, execs)
+	require.ErrorContains(t, err, "ends at block")
+}
+
+func allHashesOf(execs []*execution.Response) []string {
+	var res []string
+	for _, e := range execs {
+		for _, h := range e.AllL2L1MessageHashes {
+			res = append(res, h.Hex())
+		}
+	}
+	return res
+}
@@ -118,6 +118,7 @@ func makePiProof(cfg *config.Config, cf *CollectedFields) (plonk.Proof, witness.
 		DataAvailabilities: cf.DecompressionPI,
 		Executions:         cf.ExecutionPI,
 		Invalidity:         cf.InvalidityPI,
+		InvalidityBatches:  cf.InvalidityBatchPI,
 		Aggregation:        cf.AggregationPublicInput(cfg),
 	}, cfg.BlobDecompressionDictStore(string(circuits.DataAvailabilityV2CircuitID)))
 	if err != nil {
@@ -417,7 +418,7 @@ func doesBw6CircuitSupportVKeys(supportedVkeys []string, proofClaims []aggregati
 		found := false
 		requiredVKey := proofClaims[k].VerifyingKeyShasum
 		for i := range suppBytes32 {
-			found = found || (suppBytes32[i] == requiredVKey)
+			found = found || (!circuits.IsInfrastructureCircuitID(uint(i)) && suppBytes32[i] == requiredVKey)
 		}
 		if !found {
 			unSupportedIdxs = append(unSupportedIdxs, k)
@@ -477,6 +478,11 @@ func AssignCircuitIDToProofClaims(supportedVkeys []string, proofClaims []aggrega
 	for k := range proofClaims {
 		found := false
 		for i := range suppBytes32 {
+			// The slots of the infrastructure circuits hold placeholder keys
+			// that no sub-proof is ever verified against.
+			if circuits.IsInfrastructureCircuitID(uint(i)) {
+				continue
+			}
 			isThisOne := suppBytes32[i] == proofClaims[k].VerifyingKeyShasum
 			if isThisOne {
 				proofClaims[k].CircuitID = i
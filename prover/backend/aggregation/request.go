@@ -19,6 +19,11 @@ type Request struct {
 	// aggregate.
 	InvalidityProofs []string `json:"invalidityProofs"`
 
+	// List of the batch invalidity proofs prover responses containing the
+	// proofs to aggregate. The batches follow the single invalidity proofs in
+	// FTX order.
+	InvalidityBatchProofs []string `json:"invalidityBatchProofs"`
+
 	// List of the compression proofs prover responses containing the
 	// compression proofs to aggregate.
 	DecompressionProofs []string `json:"compressionProofs"`
@@ -130,6 +135,7 @@ type CollectedFields struct {
 	ExecutionPI       []public_input.Execution
 	DecompressionPI   []dataavailability.Request
 	InvalidityPI      []public_input.Invalidity
+	InvalidityBatchPI []public_input.InvalidityBatch
 	InnerCircuitTypes []pi_interconnection.InnerCircuitType // a hint to the aggregation circuit detailing which public input correspond to which actual public input
 
 	// last finalized (forced) transaction number
@@ -1,6 +1,10 @@
 package aggregation
 
-import "github.com/consensys/linea-monorepo/prover/utils/types"
+import (
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+)
 
 // Response contains all the fields returned by the prover to run the
 // aggregation. Reflects the data to be sent to the smart-contract for
@@ -88,3 +92,33 @@ type Response struct {
 	IsAllowedCircuitID   uint64             `json:"isAllowedCircuitID"`
 	FilteredAddresses    []types.EthAddress `json:"filteredAddresses"`
 }
+
+// FuncInput reconstructs the functional public inputs of the aggregation
+// proof from the response fields.
+func (resp *Response) FuncInput() *public_input.Aggregation {
+	return &public_input.Aggregation{
+		FinalShnarf:                             resp.FinalShnarf,
+		ParentAggregationFinalShnarf:            resp.ParentAggregationFinalShnarf,
+		ParentStateRootHash:                     resp.ParentStateRootHash,
+		ParentAggregationLastBlockTimestamp:     resp.ParentAggregationLastBlockTimestamp,
+		FinalTimestamp:                          resp.FinalTimestamp,
+		LastFinalizedBlockNumber:                resp.LastFinalizedBlockNumber,
+		FinalBlockNumber:                        resp.FinalBlockNumber,
+		LastFinalizedL1RollingHash:              resp.LastFinalizedL1RollingHash,
+		L1RollingHash:                           resp.L1RollingHash,
+		LastFinalizedL1RollingHashMessageNumber: resp.LastFinalizedL1RollingHashMessageNumber,
+		L1RollingHashMessageNumber:              resp.L1RollingHashMessageNumber,
+		LastFinalizedFtxRollingHash:             resp.ParentAggregationFtxRollingHash,
+		FinalFtxRollingHash:                     resp.FinalFtxRollingHash,
+		LastFinalizedFtxNumber:                  resp.ParentAggregationFtxNumber,
+		FinalFtxNumber:                          resp.FinalFtxNumber,
+		L2MsgRootHashes:                         resp.L2MerkleRoots,
+		L2MsgMerkleTreeDepth:                    utils.ToInt(resp.L2MsgTreesDepth),
+		ChainID:                                 resp.ChainID,
+		BaseFee:                                 resp.BaseFee,
+		CoinBase:                                resp.CoinBase,
+		L2MessageServiceAddr:                    resp.L2MessageServiceAddr,
+		IsAllowedCircuitID:                      resp.IsAllowedCircuitID,
+		FilteredAddresses:                       resp.FilteredAddresses,
+	}
+}
@@ -24,29 +24,11 @@ import (
 // Prove generates a concrete proof for the decompression of the blob
 func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
-	// Parsing / validating the request
-	blobBytes, err := base64.StdEncoding.DecodeString(req.CompressedData)
+	blobBytes, xBytes, y, err := parseRequest(req)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse the compressed data: %w", err)
+		return nil, err
 	}
 
-	var (
-		xBytes [32]byte
-		y      fr381.Element
-	)
-
-	if b, err := utils.HexDecodeString(req.ExpectedX); err != nil {
-		return nil, fmt.Errorf("could not parse the bytes of the expected x: %w", err)
-	} else {
-		copy(xBytes[:], b)
-	}
-
-	yBytes, err := utils.HexDecodeString(req.ExpectedY)
-	if err != nil {
-		return nil, fmt.Errorf("could not parse the bytes of the expected y: %w", err)
-	}
-	y.SetBytes(yBytes)
-
 	// First of all, we need to identify which setup-info to use
 	version := blob.GetVersion(blobBytes)
 	var (
@@ -172,3 +154,26 @@ func Prove(cfg *config.Config, req *Request) (*Response, error) {
 
 	return resp, nil
 }
+
+// parseRequest decodes the blob and the claimed evaluation (x, y) of its
+// polynomial from req.
+func parseRequest(req *Request) (blobBytes []byte, xBytes [32]byte, y fr381.Element, err error) {
+	blobBytes, err = base64.StdEncoding.DecodeString(req.CompressedData)
+	if err != nil {
+		return nil, xBytes, y, fmt.Errorf("could not parse the compressed data: %w", err)
+	}
+
+	b, err := utils.HexDecodeString(req.ExpectedX)
+	if err != nil {
+		return nil, xBytes, y, fmt.Errorf("could not parse the bytes of the expected x: %w", err)
+	}
+	copy(xBytes[:], b)
+
+	yBytes, err := utils.HexDecodeString(req.ExpectedY)
+	if err != nil {
+		return nil, xBytes, y, fmt.Errorf("could not parse the bytes of the expected y: %w", err)
+	}
+	y.SetBytes(yBytes)
+
+	return blobBytes, xBytes, y, nil
+}
@@ -1,5 +1,16 @@
 package dataavailability
 
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	v2 "github.com/consensys/linea-monorepo/prover/circuits/dataavailability/v2"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob"
+	blobv2 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v2"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
 // The decompression proof response contains all the fields of the requests
 // plus some prover related fields. We keep all the fields from the request so
 // that we can be sure that the prover will have all the relevant fields.
@@ -25,3 +36,25 @@ type Response struct {
 		PublicInput string `json:"publicInput"`
 	} `json:"debug"`
 }
+
+// FuncInput recomputes the functional public inputs of the decompression
+// proof from the blob and the evaluation claim carried by the response. The
+// blob is decompressed with the dictionaries configured in cfg.
+func (resp *Response) FuncInput(cfg *config.Config) (*v2.FunctionalPublicInput, error) {
+	blobBytes, xBytes, y, err := parseRequest(&resp.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	if version := blob.GetVersion(blobBytes); version != 2 {
+		return nil, fmt.Errorf("unsupported blob version: %v", version)
+	}
+
+	dictStore := cfg.BlobDecompressionDictStore(string(circuits.DataAvailabilityV2CircuitID))
+	fpi, _, err := v2.AssignFPI(utils.RightPad(blobBytes, blobv2.MaxUsableBytes), dictStore, resp.Eip4844Enabled, xBytes, y)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress the blob: %w", err)
+	}
+
+	return &fpi, nil
+}
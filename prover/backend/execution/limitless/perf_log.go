@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/consensys/linea-monorepo/prover/utils/profiling"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/sirupsen/logrus"
 )
@@ -61,7 +62,11 @@ func (pl *perfLogger) Close() {
 }
 
 // snap captures a point-in-time resource snapshot and writes a JSONL event.
+// The events are also heartbeats for the watchdog of the controller, even when
+// the perf log is disabled.
 func (pl *perfLogger) snap(event, phase string, index int, module string, elapsed time.Duration) {
+	profiling.Heartbeat(event + " " + phase)
+
 	if pl == nil {
 		return
 	}
@@ -2,6 +2,7 @@ package execution
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/circuits/execution"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/protocol/serde"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	public_input "github.com/consensys/linea-monorepo/prover/public-input"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	"github.com/consensys/linea-monorepo/prover/utils/exit"
@@ -132,77 +134,9 @@ func mustProveAndPass(
 
 	case config.ProverModeFull:
 
-		circuitID := circuits.ExecutionCircuitID
-		if large {
-			circuitID = circuits.ExecutionLargeCircuitID
-			logrus.Info("Running in large mode")
-		}
-
-		// Try loading serialized inner circuit, fall back to compilation
-		var fullZkEvm *zkevm.ZkEvm
-		enabled, innerPath := cfg.ExecutionCircuitBin(string(circuitID))
-		if enabled {
-			if _, err := os.Stat(innerPath); err == nil {
-				logrus.Infof("Loading serialized inner circuit from %s", innerPath)
-				var loaded zkevm.ZkEvm
-				closer, loadErr := serde.LoadFromDisk(innerPath, &loaded, false)
-				if loadErr == nil {
-					defer closer.Close()
-					fullZkEvm = &loaded
-				} else {
-					logrus.Warnf("Failed to load inner circuit: %v. Falling back to compilation.", loadErr)
-				}
-			} else {
-				logrus.Warnf("Serialization enabled but %s not found. Falling back to compilation.", innerPath)
-			}
-		}
-		if fullZkEvm == nil {
-			logrus.Info("Get Full IOP")
-			if large {
-				fullZkEvm = zkevm.FullZkEvmLarge(traces, cfg)
-			} else {
-				fullZkEvm = zkevm.FullZkEvm(traces, cfg)
-			}
-		}
-
-		var (
-			setup       circuits.Setup
-			errSetup    error
-			chSetupDone = make(chan struct{})
-		)
-
-		if !cfg.Execution.IgnoreCompatibilityCheck {
-			// Sanity-check trace limits checksum between setup and config
-			if err := SanityCheckTracesChecksum(circuitID, traces, cfg); err != nil {
-				utils.Panic("traces checksum in the setup manifest does not match the one in the config: %v", err)
-			}
-		}
-
-		// Start loading the setup
-		go func() {
-			logrus.Infof("Loading setup - circuitID: %s", circuitID)
-			setup, errSetup = circuits.LoadSetup(cfg, circuitID)
-			close(chSetupDone)
-		}()
-
-		// Generates the inner-proof and sanity-check it so that we ensure that
-		// the prover nevers outputs invalid proofs.
-		proof := fullZkEvm.ProveInner(w.ZkEVM)
-
-		logrus.Info("Sanity-checking the inner-proof")
-		if err := fullZkEvm.VerifyInner(proof); err != nil {
-			exit.OnUnsatisfiedConstraints(fmt.Errorf("the sanity-check of the inner-proof did not pass: %v", err))
-		}
-
-		// wait for setup to be loaded
-		<-chSetupDone
-		if errSetup != nil {
-			utils.Panic("could not load setup: %v", errSetup)
-		}
-
-		// TODO: implements the collection of the functional inputs from the prover response
-		return execution.MakeProof(traces, setup, fullZkEvm.RecursionCompiledIOP,
-			proof, *w.FuncInp, w.ZkEVM.ExecData), setup.VerifyingKeyDigest()
+		return mustProveFull(cfg, traces, large, func(fullZkEvm *zkevm.ZkEvm) wizard.Proof {
+			return fullZkEvm.ProveInner(w.ZkEVM)
+		}, *w.FuncInp, w.ZkEVM.ExecData)
 
 	case config.ProverModeBench:
 
@@ -264,6 +198,100 @@ func mustProveAndPass(
 	}
 }
 
+// loadFullZkEvm returns the zkEVM used to generate full proofs along with the
+// ID of its circuit. It loads the serialized inner circuit when it is enabled
+// in the config and falls back to compiling it otherwise. The returned closer
+// is nil if the circuit has been compiled and must be closed once the zkEVM
+// is no longer used otherwise.
+func loadFullZkEvm(cfg *config.Config, traces *config.TracesLimits, large bool) (*zkevm.ZkEvm, circuits.CircuitID, io.Closer) {
+
+	circuitID := circuits.ExecutionCircuitID
+	if large {
+		circuitID = circuits.ExecutionLargeCircuitID
+		logrus.Info("Running in large mode")
+	}
+
+	// Try loading serialized inner circuit, fall back to compilation
+	enabled, innerPath := cfg.ExecutionCircuitBin(string(circuitID))
+	if enabled {
+		if _, err := os.Stat(innerPath); err == nil {
+			logrus.Infof("Loading serialized inner circuit from %s", innerPath)
+			var loaded zkevm.ZkEvm
+			closer, loadErr := serde.LoadFromDisk(innerPath, &loaded, false)
+			if loadErr == nil {
+				return &loaded, circuitID, closer
+			}
+			logrus.Warnf("Failed to load inner circuit: %v. Falling back to compilation.", loadErr)
+		} else {
+			logrus.Warnf("Serialization enabled but %s not found. Falling back to compilation.", innerPath)
+		}
+	}
+
+	logrus.Info("Get Full IOP")
+	if large {
+		return zkevm.FullZkEvmLarge(traces, cfg), circuitID, nil
+	}
+	return zkevm.FullZkEvm(traces, cfg), circuitID, nil
+}
+
+// mustProveFull generates the inner-proof using proveInner, sanity-checks it
+// and wraps it in the outer execution proof.
+func mustProveFull(
+	cfg *config.Config,
+	traces *config.TracesLimits,
+	large bool,
+	proveInner func(*zkevm.ZkEvm) wizard.Proof,
+	funcInp public_input.Execution,
+	execData []byte,
+) (proofHexString string, vkeyShaSum string) {
+
+	fullZkEvm, circuitID, closer := loadFullZkEvm(cfg, traces, large)
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	var (
+		setup       circuits.Setup
+		errSetup    error
+		chSetupDone = make(chan struct{})
+	)
+
+	if !cfg.Execution.IgnoreCompatibilityCheck {
+		// Sanity-check trace limits checksum between setup and config
+		if err := SanityCheckTracesChecksum(circuitID, traces, cfg); err != nil {
+			utils.Panic("traces checksum in the setup manifest does not match the one in the config: %v", err)
+		}
+	}
+
+	// Start loading the setup
+	go func() {
+		logrus.Infof("Loading setup - circuitID: %s", circuitID)
+		setup, errSetup = circuits.LoadSetup(cfg, circuitID)
+		close(chSetupDone)
+	}()
+
+	// Generates the inner-proof and sanity-check it so that we ensure that
+	// the prover nevers outputs invalid proofs.
+	proof := proveInner(fullZkEvm)
+
+	logrus.Info("Sanity-checking the inner-proof")
+	if err := fullZkEvm.VerifyInner(proof); err != nil {
+		exit.OnUnsatisfiedConstraints(fmt.Errorf("the sanity-check of the inner-proof did not pass: %v", err))
+	}
+
+	// wait for setup to be loaded
+	<-chSetupDone
+	if errSetup != nil {
+		utils.Panic("could not load setup: %v", errSetup)
+	}
+
+	profiling.Heartbeat("outer-proof")
+
+	// TODO: implements the collection of the functional inputs from the prover response
+	return execution.MakeProof(traces, setup, fullZkEvm.RecursionCompiledIOP,
+		proof, funcInp, execData), setup.VerifyingKeyDigest()
+}
+
 // SanityCheckTraceChecksum ensures the checksum for the traces in the setup matches the one in the config
 func SanityCheckTracesChecksum(circuitID circuits.CircuitID, traces *config.TracesLimits, cfg *config.Config) error {
 
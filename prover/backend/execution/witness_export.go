@@ -0,0 +1,122 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/serde"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/utils/profiling"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/sirupsen/logrus"
+)
+
+// WitnessExport is the asset written in [config.ProverModeWitnessExport]. It
+// holds everything [ProveFromWitness] needs to complete the execution proof
+// without the request, the traces or the state-manager.
+type WitnessExport struct {
+	// Response is the JSON encoding of the prover output without the proof.
+	Response []byte
+	// ExecData are the bytes of the execution data, as in [zkevm.Witness].
+	ExecData []byte
+	// Large indicates whether the witness has been generated for the large
+	// execution circuit.
+	Large bool
+	// TracesChecksum is the checksum of the traces limits the witness has
+	// been generated with. The limits fix the columns of the zkEVM, so the
+	// witness can only be proven with a setup built for the same ones.
+	TracesChecksum string
+	// Inner is the assignment of the inner-proof.
+	Inner *wizard.ProverWitness
+}
+
+// ExportWitness runs the arithmetization, the state-manager and the
+// public-input assignment of the full prover for req and stores the resulting
+// witness in the chunked directory dirPath, compressed with lz4 by
+// [serde.StoreChunked].
+//
+// Only the witness generation runs here: the zkEVM is the one of the
+// check-only mode, which declares the same columns as the full prover but is
+// not compiled, so neither the compiled inner circuit nor the setup are
+// needed. The witness is then proven by [ProveFromWitness].
+func ExportWitness(cfg *config.Config, req *Request, large bool, dirPath string) error {
+
+	profiling.SetMonitorParams(cfg)
+	exit.SetIssueHandlingMode(exit.ExitAlways)
+
+	// WARN: CraftProverOutput calls functions that can panic.
+	out := CraftProverOutput(cfg, req)
+	w := NewWitness(cfg, req, &out)
+
+	traces := &cfg.TracesLimits
+	if large {
+		traces.SetLargeMode()
+	}
+
+	logrus.Info("Exporting the witness of the inner-proof")
+	inner := zkevm.FullZkEVMCheckOnly(traces, cfg).ExportWitness(w.ZkEVM)
+
+	resp, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("could not encode the prover output: %w", err)
+	}
+
+	export := &WitnessExport{
+		Response:       resp,
+		ExecData:       w.ZkEVM.ExecData,
+		Large:          large,
+		TracesChecksum: traces.Checksum(),
+		Inner:          inner,
+	}
+
+	if err := serde.StoreChunked(dirPath, export); err != nil {
+		return fmt.Errorf("could not store the witness in %v: %w", dirPath, err)
+	}
+
+	logrus.Infof("Stored the witness of %v columns in %v", len(inner.Columns), dirPath)
+	return nil
+}
+
+// ProveFromWitness loads a witness stored by [ExportWitness] in dirPath and
+// completes the execution proof as in [config.ProverModeFull].
+func ProveFromWitness(cfg *config.Config, dirPath string) (*Response, error) {
+
+	profiling.SetMonitorParams(cfg)
+	exit.SetIssueHandlingMode(exit.ExitAlways)
+
+	export := &WitnessExport{}
+	buf, err := serde.LoadChunkedMmapBacked(dirPath, export)
+	if err != nil {
+		return nil, fmt.Errorf("could not load the witness from %v: %w", dirPath, err)
+	}
+	defer buf.Release()
+
+	var out Response
+	if err := json.Unmarshal(export.Response, &out); err != nil {
+		return nil, fmt.Errorf("could not decode the prover output of the witness: %w", err)
+	}
+
+	traces := &cfg.TracesLimits
+	if export.Large {
+		traces.SetLargeMode()
+	}
+
+	if export.TracesChecksum != traces.Checksum() {
+		return nil, fmt.Errorf("the witness in %v has been generated with the traces limits %v, the config has %v", dirPath, export.TracesChecksum, traces.Checksum())
+	}
+
+	logrus.Infof("Running the FULL prover from the witness in %v", dirPath)
+
+	// The exported buffers are mmap-backed and released when returning, so
+	// they must not outlive the call.
+	out.Proof, out.VerifyingKeyShaSum = mustProveFull(cfg, traces, export.Large, func(fullZkEvm *zkevm.ZkEvm) wizard.Proof {
+		return fullZkEvm.ProveInnerFromWitness(export.Inner)
+	}, *out.FuncInput(), export.ExecData)
+
+	out.Version = cfg.Version
+	out.ProverMode = config.ProverModeFull
+	out.VerifierIndex = uint(cfg.Aggregation.VerifierID)
+	return &out, nil
+}
@@ -0,0 +1,318 @@
+package invalidity
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak"
+	"github.com/consensys/linea-monorepo/prover/config"
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/exit"
+	"github.com/consensys/linea-monorepo/prover/utils/profiling"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+// BatchRequest groups the requests of consecutive forced transactions proven
+// invalid by a single batched proof. All the requests must be handled by the
+// same invalidity circuit and be proven against the same state and simulated
+// block.
+//
+// The PrevFtxRollingHash is only needed for the first request; for the other
+// ones it is derived from the previous request and, if provided, must match.
+type BatchRequest struct {
+	Requests []Request `json:"ftxRequests"`
+}
+
+// Validate checks that the requests can be proven in a single batch of at
+// most maxBatchSize forced transactions.
+func (req *BatchRequest) Validate(proverMode config.ProverMode, maxBatchSize int) error {
+
+	if len(req.Requests) == 0 {
+		return fmt.Errorf("ftxRequests is empty")
+	}
+	if len(req.Requests) > maxBatchSize {
+		return fmt.Errorf("the batch has %d forced transactions, the maximum is %d", len(req.Requests), maxBatchSize)
+	}
+
+	first := &req.Requests[0]
+	circuitID, err := BatchCircuitID(first.InvalidityType)
+	if err != nil {
+		return err
+	}
+
+	for i := range req.Requests {
+		r := &req.Requests[i]
+
+		if err := r.Validate(proverMode); err != nil {
+			return fmt.Errorf("forced transaction %d: %w", r.ForcedTransactionNumber, err)
+		}
+		if id, err := BatchCircuitID(r.InvalidityType); err != nil || id != circuitID {
+			return fmt.Errorf("forced transaction %d: invalidity type %s can not be batched with %s", r.ForcedTransactionNumber, r.InvalidityType, first.InvalidityType)
+		}
+		if r.ForcedTransactionNumber != first.ForcedTransactionNumber+uint64(i) {
+			return fmt.Errorf("the FTX numbers are not consecutive: expected %d, got %d", first.ForcedTransactionNumber+uint64(i), r.ForcedTransactionNumber)
+		}
+		if r.ZkParentStateRootHash != first.ZkParentStateRootHash {
+			return fmt.Errorf("forced transaction %d: zkParentStateRootHash differs from the one of the batch", r.ForcedTransactionNumber)
+		}
+		if r.SimulatedExecutionBlockNumber != first.SimulatedExecutionBlockNumber ||
+			r.SimulatedExecutionBlockTimestamp != first.SimulatedExecutionBlockTimestamp {
+			return fmt.Errorf("forced transaction %d: the simulated execution block differs from the one of the batch", r.ForcedTransactionNumber)
+		}
+		if r.DeadlineBlockHeight < r.SimulatedExecutionBlockNumber {
+			return fmt.Errorf("forced transaction %d: the deadline %d is before the simulated execution block %d", r.ForcedTransactionNumber, r.DeadlineBlockHeight, r.SimulatedExecutionBlockNumber)
+		}
+	}
+
+	return nil
+}
+
+// BatchCircuitID returns the ID of the batch circuit handling the given
+// invalidity type.
+func BatchCircuitID(invalidityType invalidity.InvalidityType) (circuits.CircuitID, error) {
+	switch invalidityType {
+	case invalidity.BadNonce, invalidity.BadBalance:
+		return circuits.InvalidityNonceBalanceBatchCircuitID, nil
+	case invalidity.GasLimitTooLow, invalidity.GasLimitTooHigh:
+		return circuits.InvalidityGasLimitBatchCircuitID, nil
+	default:
+		return "", fmt.Errorf("invalidity type %s can not be batched", invalidityType)
+	}
+}
+
+// BatchResponseEntry holds the per-transaction fields of a [BatchResponse].
+type BatchResponseEntry struct {
+	// signer of the transaction
+	Signer types.EthAddress `json:"signer"`
+	// hash of the transaction (before signing)
+	TxHash string `json:"txHash"`
+	//Rlp encoding of signed transaction
+	RLPEncodedTx string `json:"rlpEncodedTx"`
+	// Transaction number assigned by L1 contract (decimal encoding)
+	ForcedTransactionNumber uint64 `json:"ftxNumber"`
+	// The block number deadline before which one expects to see the transaction (decimal encoding)
+	DeadlineBlockHeight uint64 `json:"ftxBlockNumberDeadline"`
+	// The type of invalidity for the forced transaction.
+	InvalidityType invalidity.InvalidityType `json:"invalidityType"`
+	// the FtxRollingHash after the forced transaction
+	FtxRollingHash types.Bls12377Fr `json:"ftxRollingHash"`
+}
+
+// BatchResponse is the response of a batched invalidity proof.
+type BatchResponse struct {
+	// The forced transactions of the batch, in FTX number order
+	Transactions []BatchResponseEntry `json:"ftxs"`
+
+	FirstTxNumber uint64 `json:"firstFtxNumber"`
+	LastTxNumber  uint64 `json:"lastFtxNumber"`
+	// The FTX rolling hash before the first forced transaction of the batch
+	PrevFtxRollingHash types.Bls12377Fr `json:"prevFtxRollingHash"`
+	// The FTX rolling hash after the last forced transaction of the batch
+	FtxRollingHash types.Bls12377Fr `json:"ftxRollingHash"`
+
+	// ZK parent state root hash
+	ZkParentStateRootHash            types.KoalaOctuplet `json:"zkParentStateRootHash"`
+	SimulatedExecutionBlockNumber    uint64              `json:"simulatedExecutionBlockNumber"`
+	SimulatedExecutionBlockTimestamp uint64              `json:"simulatedExecutionBlockTimestamp"`
+
+	// Dynamic chain configuration (mirrors execution Response fields)
+	ChainID  uint             `json:"chainID"`
+	BaseFee  uint             `json:"baseFee"`
+	CoinBase types.EthAddress `json:"coinBase"`
+
+	L2BridgeAddress types.EthAddress `json:"l2BridgeAddress"`
+
+	// PublicInput is the final value public input of the current proof.
+	PublicInput types.Bls12377Fr `json:"publicInput"`
+	// Proof in 0x prefixed hexstring format
+	Proof string `json:"proof"`
+	// The shasum of the verifier key to use to verify the proof.
+	VerifyingKeyShaSum string            `json:"verifyingKeyShaSum"`
+	ProverMode         config.ProverMode `json:"proverMode"`
+	ProverVersion      string            `json:"proverVersion"`
+}
+
+// FuncInput reconstructs the functional public inputs from the response fields.
+func (resp *BatchResponse) FuncInput() *public_input.InvalidityBatch {
+	return &public_input.InvalidityBatch{
+		FirstTxNumber:           resp.FirstTxNumber,
+		LastTxNumber:            resp.LastTxNumber,
+		PrevFtxRollingHash:      resp.PrevFtxRollingHash,
+		FtxRollingHash:          resp.FtxRollingHash,
+		StateRootHash:           resp.ZkParentStateRootHash,
+		CoinBase:                resp.CoinBase,
+		BaseFee:                 uint64(resp.BaseFee),
+		ChainID:                 uint64(resp.ChainID),
+		L2MessageServiceAddr:    resp.L2BridgeAddress,
+		SimulatedBlockTimestamp: resp.SimulatedExecutionBlockTimestamp,
+		SimulatedBlockNumber:    resp.SimulatedExecutionBlockNumber,
+	}
+}
+
+// BatchFuncInput returns the functional public inputs of a batch from those
+// of its forced transactions, given in FTX number order.
+func BatchFuncInput(prevFtxRollingHash types.Bls12377Fr, funcInputs []*public_input.Invalidity) *public_input.InvalidityBatch {
+	var (
+		first = funcInputs[0]
+		last  = funcInputs[len(funcInputs)-1]
+	)
+	return &public_input.InvalidityBatch{
+		FirstTxNumber:           first.TxNumber,
+		LastTxNumber:            last.TxNumber,
+		PrevFtxRollingHash:      prevFtxRollingHash,
+		FtxRollingHash:          last.FtxRollingHash,
+		StateRootHash:           first.StateRootHash,
+		CoinBase:                first.CoinBase,
+		BaseFee:                 first.BaseFee,
+		ChainID:                 first.ChainID,
+		L2MessageServiceAddr:    first.L2MessageServiceAddr,
+		SimulatedBlockTimestamp: first.SimulatedBlockTimestamp,
+		SimulatedBlockNumber:    first.SimulatedBlockNumber,
+	}
+}
+
+// ProveBatch generates a single proof for the invalidity of a batch of
+// consecutive forced transactions. The prover modes are the same as for
+// [Prove].
+func ProveBatch(cfg *config.Config, req *BatchRequest) (*BatchResponse, error) {
+	profiling.SetMonitorParams(cfg)
+	exit.SetIssueHandlingMode(exit.ExitAlways)
+
+	if err := req.Validate(cfg.Invalidity.ProverMode, cfg.Invalidity.MaxBatchSize); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	circuitID, err := BatchCircuitID(req.Requests[0].InvalidityType)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Starting batched invalidity proof for %d forced transactions with circuit: %s", len(req.Requests), circuitID)
+
+	var (
+		isPartial       = cfg.Invalidity.ProverMode == config.ProverModePartial
+		maxBatchSize    = cfg.Invalidity.MaxBatchSize
+		funcInputs      = make([]*public_input.Invalidity, len(req.Requests))
+		entries         = make([]invalidity.AssigningInputs, 0, len(req.Requests))
+		setup           circuits.Setup
+		serializedProof string
+	)
+
+	for i := range req.Requests {
+		r := &req.Requests[i]
+
+		if i > 0 {
+			prev := funcInputs[i-1].FtxRollingHash
+			if r.PrevFtxRollingHash != (types.Bls12377Fr{}) && r.PrevFtxRollingHash != prev {
+				return nil, fmt.Errorf("invalid request: prevFtxRollingHash of forced transaction %d does not match the previous forced transaction", r.ForcedTransactionNumber)
+			}
+			r.PrevFtxRollingHash = prev
+		}
+
+		tx, err := decodeForcedTx(cfg, r)
+		if err != nil {
+			return nil, err
+		}
+		funcInputs[i] = FuncInput(r, cfg)
+
+		if cfg.Invalidity.ProverMode == config.ProverModeDev {
+			continue
+		}
+
+		// the keccak proof is shared by the whole batch
+		assi := newAssigningInputs(cfg, r, tx, funcInputs[i])
+		if err := setAccountTrieInputs(r, &assi); err != nil {
+			return nil, err
+		}
+		entries = append(entries, assi)
+	}
+
+	batchInput := BatchFuncInput(req.Requests[0].PrevFtxRollingHash, funcInputs)
+
+	batchAssi := invalidity.BatchAssigningInputs{
+		Entries:    entries,
+		FuncInputs: *batchInput,
+		MaxNbTx:    maxBatchSize,
+	}
+
+	switch cfg.Invalidity.ProverMode {
+	case config.ProverModeDev:
+		logrus.Info("Running in DEV mode (generating mock proofs)")
+
+	case config.ProverModePartial:
+		logrus.Infof("Running circuit constraint checking in PARTIAL mode for %s", circuitID)
+		if err := (&invalidity.CircuitInvalidityBatch{}).CheckOnly(batchAssi); err != nil {
+			utils.Panic("gnark-circuit constraint check failed (checkonly): %v", err)
+		}
+		logrus.Info("gnark-circuit constraint check passed (checkonly)")
+
+	default:
+		logrus.Infof("Running invalidity prover in PROD mode with circuit: %s", circuitID)
+		logrus.Info("Loading setup...")
+		if setup, err = circuits.LoadSetup(cfg, circuitID); err != nil {
+			return nil, fmt.Errorf("could not load the setup: %w", err)
+		}
+		// the padding entries of the circuit are copies of the last one
+		txs := make([]*ethtypes.Transaction, maxBatchSize)
+		for i := range txs {
+			txs[i] = entries[min(i, len(entries)-1)].Transaction
+		}
+		batchAssi.KeccakCompiledIOP, batchAssi.KeccakProof = invalidity.MakeKeccakProofsBatch(txs, cfg.Invalidity.MaxRlpByteSize, keccak.WizardCompilationParameters()...)
+		batchAssi.CachedProofPath = proofCachePath(circuitID, &setup, batchInput.Sum(nil))
+		serializedProof = (&invalidity.CircuitInvalidityBatch{}).MakeProof(setup, batchAssi)
+	}
+
+	// Dummy proofs for dev and partial modes
+	if cfg.Invalidity.ProverMode == config.ProverModeDev || isPartial {
+		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+		if err != nil {
+			utils.Panic("error creating SRS store: %v", err)
+		}
+		setup, err = dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDInvalidityBatch, ecc.BLS12_377.ScalarField())
+		if err != nil {
+			utils.Panic("error creating unsafe setup: %v", err)
+		}
+		serializedProof = dummy.MakeProof(&setup, batchInput.SumAsField(), circuits.MockCircuitIDInvalidityBatch)
+	}
+
+	rsp := &BatchResponse{
+		Transactions:                     make([]BatchResponseEntry, len(req.Requests)),
+		FirstTxNumber:                    batchInput.FirstTxNumber,
+		LastTxNumber:                     batchInput.LastTxNumber,
+		PrevFtxRollingHash:               batchInput.PrevFtxRollingHash,
+		FtxRollingHash:                   batchInput.FtxRollingHash,
+		ZkParentStateRootHash:            batchInput.StateRootHash,
+		SimulatedExecutionBlockNumber:    batchInput.SimulatedBlockNumber,
+		SimulatedExecutionBlockTimestamp: batchInput.SimulatedBlockTimestamp,
+		ChainID:                          cfg.Layer2.ChainID,
+		BaseFee:                          cfg.Layer2.BaseFee,
+		CoinBase:                         types.EthAddress(cfg.Layer2.CoinBase),
+		L2BridgeAddress:                  types.EthAddress(cfg.Layer2.MsgSvcContract),
+		PublicInput:                      types.Bls12377Fr(batchInput.Sum(nil)),
+		Proof:                            serializedProof,
+		VerifyingKeyShaSum:               setup.VerifyingKeyDigest(),
+		ProverMode:                       cfg.Invalidity.ProverMode,
+		ProverVersion:                    cfg.Version,
+	}
+
+	for i := range req.Requests {
+		fi := funcInputs[i]
+		rsp.Transactions[i] = BatchResponseEntry{
+			Signer:                  fi.FromAddress,
+			TxHash:                  utils.HexEncodeToString(fi.TxHash[:]),
+			RLPEncodedTx:            req.Requests[i].RlpEncodedTx,
+			ForcedTransactionNumber: req.Requests[i].ForcedTransactionNumber,
+			DeadlineBlockHeight:     req.Requests[i].DeadlineBlockHeight,
+			InvalidityType:          req.Requests[i].InvalidityType,
+			FtxRollingHash:          fi.FtxRollingHash,
+		}
+	}
+
+	return rsp, nil
+}
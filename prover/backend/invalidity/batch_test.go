@@ -0,0 +1,100 @@
+package invalidity_test
+
+import (
+	"math/big"
+	"testing"
+
+	backend "github.com/consensys/linea-monorepo/prover/backend/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// gasLimitBatchRequest returns a batch request of n consecutive forced
+// transactions with a gas limit too low.
+func gasLimitBatchRequest(t *testing.T, n int) *backend.BatchRequest {
+
+	stateRoot := types.KoalaOctuplet(field.RandomOctuplet())
+	req := &backend.BatchRequest{Requests: make([]backend.Request, n)}
+
+	for i := range req.Requests {
+		toAddr := common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc9e7595f2bD50")
+		raw, err := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(59144),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(1000000000),
+			GasFeeCap: big.NewInt(100000000000),
+			Gas:       20000,
+			To:        &toAddr,
+			Value:     big.NewInt(1000),
+		}).MarshalBinary()
+		require.NoError(t, err)
+
+		req.Requests[i] = backend.Request{
+			RlpEncodedTx:                     utils.HexEncodeToString(raw),
+			ForcedTransactionNumber:          uint64(10 + i),
+			DeadlineBlockHeight:              200,
+			InvalidityType:                   invalidity.GasLimitTooLow,
+			ZkParentStateRootHash:            stateRoot,
+			SimulatedExecutionBlockNumber:    100,
+			SimulatedExecutionBlockTimestamp: 1000000000,
+		}
+	}
+
+	return req
+}
+
+// TestBatchRequestValidate checks that only the requests which can be proven
+// by a single batch circuit are accepted.
+func TestBatchRequestValidate(t *testing.T) {
+
+	const maxBatchSize = 4
+
+	testCases := []struct {
+		name   string
+		tamper func(req *backend.BatchRequest)
+		valid  bool
+	}{
+		{name: "Valid", tamper: func(req *backend.BatchRequest) {}, valid: true},
+		{name: "MixedGasLimitTooLowTooHigh", tamper: func(req *backend.BatchRequest) { req.Requests[1].InvalidityType = invalidity.GasLimitTooHigh }, valid: true},
+		{name: "Empty", tamper: func(req *backend.BatchRequest) { req.Requests = nil }},
+		{name: "TooLarge", tamper: func(req *backend.BatchRequest) {
+			for len(req.Requests) <= maxBatchSize {
+				r := req.Requests[len(req.Requests)-1]
+				r.ForcedTransactionNumber++
+				req.Requests = append(req.Requests, r)
+			}
+		}},
+		{name: "NonConsecutive", tamper: func(req *backend.BatchRequest) { req.Requests[2].ForcedTransactionNumber++ }},
+		{name: "DifferentStateRoot", tamper: func(req *backend.BatchRequest) {
+			req.Requests[1].ZkParentStateRootHash = types.KoalaOctuplet(field.RandomOctuplet())
+		}},
+		{name: "DifferentSimulatedBlock", tamper: func(req *backend.BatchRequest) { req.Requests[1].SimulatedExecutionBlockNumber++ }},
+		{name: "DeadlineBeforeSimulatedBlock", tamper: func(req *backend.BatchRequest) { req.Requests[0].DeadlineBlockHeight = 99 }},
+		{name: "DifferentCircuits", tamper: func(req *backend.BatchRequest) { req.Requests[1].InvalidityType = invalidity.BadNonce }},
+		{name: "NotBatchable", tamper: func(req *backend.BatchRequest) {
+			for i := range req.Requests {
+				req.Requests[i].InvalidityType = invalidity.FilteredAddressFrom
+			}
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := gasLimitBatchRequest(t, 3)
+			tc.tamper(req)
+
+			err := req.Validate(config.ProverModeDev, maxBatchSize)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
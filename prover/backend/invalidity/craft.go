@@ -32,11 +32,13 @@ func FuncInput(req *Request, cfg *config.Config) *public_input.Invalidity {
 		fromAddress,
 	)
 
-	// Extract the To address from the transaction
+	// Extract the To address from the transaction. Contract creations are
+	// only supported by the gas limit invalidity proofs, the To address is
+	// then left to zero.
 	var toAddress types.EthAddress
 	if to := tx.To(); to != nil {
 		toAddress = types.EthAddress(*to)
-	} else {
+	} else if req.InvalidityType != circuitInvalidity.GasLimitTooLow && req.InvalidityType != circuitInvalidity.GasLimitTooHigh {
 		panic("to address is nil")
 	}
 
@@ -75,17 +75,16 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 	case invalidity.FilteredAddressFrom, invalidity.FilteredAddressTo:
 		mockCircuitID = circuits.MockCircuitIDInvalidityFilteredAddress
 		circuitID = circuits.InvalidityFilteredAddressCircuitID
+	case invalidity.GasLimitTooLow, invalidity.GasLimitTooHigh:
+		mockCircuitID = circuits.MockCircuitIDInvalidityGasLimit
+		circuitID = circuits.InvalidityGasLimitCircuitID
 	default:
 		return nil, fmt.Errorf("unsupported invalidity type: %s", req.InvalidityType)
 	}
 
-	tx, err := ethereum.RlpDecodeWithSignature(req.RlpEncodedTx)
+	tx, err := decodeForcedTx(cfg, req)
 	if err != nil {
-		return nil, fmt.Errorf("could not decode the RlpEncodedTx: %w", err)
-	}
-
-	if cfg.Invalidity.ProverMode != config.ProverModeDev {
-		SanityCheckInvalidityChainConfig(cfg, tx)
+		return nil, err
 	}
 
 	funcInput := FuncInput(req, cfg)
@@ -101,40 +100,15 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 			logrus.Infof("Running invalidity prover in PROD mode with circuit: %s", circuitID)
 		}
 
-		fromAddress := ethereum.GetFrom(tx)
-
-		assigningInputs := invalidity.AssigningInputs{
-			RlpEncodedTx:   ethereum.EncodeTxForSigning(tx),
-			Transaction:    tx,
-			FromAddress:    common.Address(fromAddress),
-			InvalidityType: req.InvalidityType,
-			FuncInputs:     *funcInput,
-			MaxRlpByteSize: cfg.Invalidity.MaxRlpByteSize,
-			MaxL2Logs:      cfg.TracesLimits.BlockL2L1Logs(),
-		}
+		assigningInputs := newAssigningInputs(cfg, req, tx, funcInput)
 
 		switch req.InvalidityType {
-		case invalidity.BadNonce, invalidity.BadBalance:
-			if isPartial {
-				// is partial mode compile with dummy suite.
-				assigningInputs.KeccakCompiledIOP, assigningInputs.KeccakProof = invalidity.MakeKeccakProofs(tx, cfg.Invalidity.MaxRlpByteSize, keccakDummy.Compile)
-			} else {
-				// in full mode compile with proper wizard compilation suite.
-				assigningInputs.KeccakCompiledIOP, assigningInputs.KeccakProof = invalidity.MakeKeccakProofs(tx, cfg.Invalidity.MaxRlpByteSize, keccak.WizardCompilationParameters()...)
-			}
-			accountTrieInputs, fromAddressAccount, topRoot, err := req.AccountTrieInputs()
-			if err != nil {
-				return nil, fmt.Errorf("could not extract account trie inputs: %w", err)
-			}
-			// sanity checks
-			if fromAddressAccount != fromAddress {
-				utils.Panic("from address mismatch: %v != %v", fromAddressAccount, fromAddress)
-			}
-
-			if topRoot != req.ZkParentStateRootHash {
-				utils.Panic("topRoot is different from the parent state root: %v != %v", topRoot, req.ZkParentStateRootHash)
+		case invalidity.BadNonce, invalidity.BadBalance,
+			invalidity.FilteredAddressFrom, invalidity.FilteredAddressTo,
+			invalidity.GasLimitTooLow, invalidity.GasLimitTooHigh:
+			if err := setKeccakInputs(cfg, req, &assigningInputs, isPartial); err != nil {
+				return nil, err
 			}
-			assigningInputs.AccountTrieInputs = accountTrieInputs
 
 		case invalidity.BadPrecompile, invalidity.TooManyLogs:
 			limits := &cfg.TracesLimits
@@ -214,13 +188,6 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 			}
 			assigningInputs.ZkEvmWizardProof = proof
 
-		case invalidity.FilteredAddressFrom, invalidity.FilteredAddressTo:
-			if isPartial {
-				assigningInputs.KeccakCompiledIOP, assigningInputs.KeccakProof = invalidity.MakeKeccakProofs(tx, cfg.Invalidity.MaxRlpByteSize, keccakDummy.Compile)
-			} else {
-				assigningInputs.KeccakCompiledIOP, assigningInputs.KeccakProof = invalidity.MakeKeccakProofs(tx, cfg.Invalidity.MaxRlpByteSize, keccak.WizardCompilationParameters()...)
-			}
-
 		}
 
 		if isPartial {
@@ -234,16 +201,7 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 				return nil, fmt.Errorf("could not load the setup: %w", err)
 			}
 
-			// Enable proof caching: derive cache path from public input hash
-			// and VK digest. Including the VK digest ensures stale proofs are
-			// not reused after a trusted-setup change.
-			cacheDir := filepath.Join(os.TempDir(), "linea-invalidity-proof-cache")
-			if err := os.MkdirAll(cacheDir, 0755); err == nil {
-				piHash := hex.EncodeToString(funcInput.Sum(nil))
-				vkDigest := setup.VerifyingKeyDigest()
-				assigningInputs.CachedProofPath = filepath.Join(cacheDir, fmt.Sprintf("%s-%s-%s.proof", circuitID, vkDigest, piHash))
-				logrus.Infof("Proof cache path: %s", assigningInputs.CachedProofPath)
-			}
+			assigningInputs.CachedProofPath = proofCachePath(circuitID, &setup, funcInput.Sum(nil))
 
 			serializedProof = c.MakeProof(setup, assigningInputs)
 		}
@@ -289,6 +247,98 @@ func Prove(cfg *config.Config, req *Request, large bool) (*Response, error) {
 	return rsp, nil
 }
 
+// proofCachePath returns the path at which the proof is cached, derived from
+// the public input hash and the VK digest. Including the VK digest ensures
+// stale proofs are not reused after a trusted-setup change. An empty path is
+// returned, disabling the cache, if the cache directory can not be created.
+func proofCachePath(circuitID circuits.CircuitID, setup *circuits.Setup, publicInput []byte) string {
+	cacheDir := filepath.Join(os.TempDir(), "linea-invalidity-proof-cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return ""
+	}
+	piHash := hex.EncodeToString(publicInput)
+	res := filepath.Join(cacheDir, fmt.Sprintf("%s-%s-%s.proof", circuitID, setup.VerifyingKeyDigest(), piHash))
+	logrus.Infof("Proof cache path: %s", res)
+	return res
+}
+
+// decodeForcedTx decodes the forced transaction of the request and checks it
+// against the configuration.
+func decodeForcedTx(cfg *config.Config, req *Request) (*ethtypes.Transaction, error) {
+
+	tx, err := ethereum.RlpDecodeWithSignature(req.RlpEncodedTx)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the RlpEncodedTx: %w", err)
+	}
+
+	if cfg.Invalidity.ProverMode != config.ProverModeDev {
+		SanityCheckInvalidityChainConfig(cfg, tx)
+	}
+
+	if req.InvalidityType == invalidity.GasLimitTooHigh && tx.Gas() <= cfg.Invalidity.BlockGasLimit {
+		return nil, fmt.Errorf("invalid request: gas limit %d does not exceed the block gas limit %d", tx.Gas(), cfg.Invalidity.BlockGasLimit)
+	}
+
+	return tx, nil
+}
+
+// newAssigningInputs returns the assigning inputs of the invalidity circuit
+// common to all the invalidity types. The RLP encoding is the one of the
+// unsigned transaction.
+func newAssigningInputs(cfg *config.Config, req *Request, tx *ethtypes.Transaction, funcInput *public_input.Invalidity) invalidity.AssigningInputs {
+
+	return invalidity.AssigningInputs{
+		RlpEncodedTx:   ethereum.EncodeTxForSigning(tx),
+		Transaction:    tx,
+		FromAddress:    common.Address(funcInput.FromAddress),
+		InvalidityType: req.InvalidityType,
+		FuncInputs:     *funcInput,
+		MaxRlpByteSize: cfg.Invalidity.MaxRlpByteSize,
+		MaxL2Logs:      cfg.TracesLimits.BlockL2L1Logs(),
+		BlockGasLimit:  cfg.Invalidity.BlockGasLimit,
+	}
+}
+
+// setKeccakInputs generates the keccak wizard proof of the forced transaction
+// and, for BadNonce/BadBalance, decodes the account trie inputs. In partial
+// mode the keccak module is compiled with the dummy suite; in full mode with
+// the proper wizard compilation suite.
+func setKeccakInputs(cfg *config.Config, req *Request, assi *invalidity.AssigningInputs, isPartial bool) error {
+
+	suite := keccak.WizardCompilationParameters()
+	if isPartial {
+		suite = keccak.CompilationParams{keccakDummy.Compile}
+	}
+
+	assi.KeccakCompiledIOP, assi.KeccakProof = invalidity.MakeKeccakProofs(assi.Transaction, cfg.Invalidity.MaxRlpByteSize, suite...)
+
+	return setAccountTrieInputs(req, assi)
+}
+
+// setAccountTrieInputs decodes the account trie inputs for BadNonce/BadBalance,
+// it is a no-op for the other invalidity types.
+func setAccountTrieInputs(req *Request, assi *invalidity.AssigningInputs) error {
+
+	if req.InvalidityType != invalidity.BadNonce && req.InvalidityType != invalidity.BadBalance {
+		return nil
+	}
+
+	accountTrieInputs, fromAddressAccount, topRoot, err := req.AccountTrieInputs()
+	if err != nil {
+		return fmt.Errorf("could not extract account trie inputs: %w", err)
+	}
+	// sanity checks
+	if fromAddressAccount != linTypes.EthAddress(assi.FromAddress) {
+		utils.Panic("from address mismatch: %v != %v", fromAddressAccount, assi.FromAddress)
+	}
+
+	if topRoot != req.ZkParentStateRootHash {
+		utils.Panic("topRoot is different from the parent state root: %v != %v", topRoot, req.ZkParentStateRootHash)
+	}
+	assi.AccountTrieInputs = accountTrieInputs
+	return nil
+}
+
 // SanityCheckInvalidityChainConfig checks that the transaction's chainID matches
 // the config. For non-legacy txs the chainID is embedded in the transaction;
 // for legacy txs this check is skipped (chainID derived from V is unreliable).
@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
 	"github.com/consensys/linea-monorepo/prover/backend/execution/statemanager"
 	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
 	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt_koalabear"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/sirupsen/logrus"
 )
 
@@ -29,7 +31,8 @@ type Request struct {
 	DeadlineBlockHeight uint64 `json:"ftxBlockNumberDeadline"`
 
 	// The type of invalidity for the forced transaction.
-	// Valid values: BadNonce, BadBalance, BadPrecompile, TooManyLogs, FilteredAddressFrom, FilteredAddressTo
+	// Valid values: BadNonce, BadBalance, BadPrecompile, TooManyLogs, FilteredAddressFrom, FilteredAddressTo,
+	// GasLimitTooLow, GasLimitTooHigh
 	InvalidityType invalidity.InvalidityType `json:"invalidityType"`
 
 	// ZK parent state root hash
@@ -46,6 +49,7 @@ type Request struct {
 	// Required for BadPrecompile, TooManyLogs cases
 	ZkStateMerkleProof [][]statemanager.DecodedTrace `json:"zkStateMerkleProof,omitempty"`
 	// case of FilteredAddressFrom/FilteredAddressTo: accountMerkleProof=null, zkStateMerkleProof=null
+	// case of GasLimitTooLow/GasLimitTooHigh: accountMerkleProof=null, zkStateMerkleProof=null
 
 	// Simulated execution block number (ParentAggregationLastBlockNumber + 1)
 	SimulatedExecutionBlockNumber uint64 `json:"simulatedExecutionBlockNumber,omitempty"`
@@ -106,6 +110,13 @@ func (req *Request) Validate(proverMode config.ProverMode) error {
 	case invalidity.FilteredAddressFrom, invalidity.FilteredAddressTo:
 		// No additional fields required.
 
+	case invalidity.GasLimitTooLow, invalidity.GasLimitTooHigh:
+		// No additional fields required, the gas limit is read from the
+		// transaction itself.
+		if err := req.validateGasLimit(); err != nil {
+			return err
+		}
+
 	default:
 		return fmt.Errorf("unknown invalidity type: %s", req.InvalidityType)
 	}
@@ -113,6 +124,29 @@ func (req *Request) Validate(proverMode config.ProverMode) error {
 	return nil
 }
 
+// validateGasLimit checks that the forced transaction is supported by the gas
+// limit invalidity circuit and, for GasLimitTooLow, that its gas limit is
+// indeed below its minimum gas. The GasLimitTooHigh case depends on the block
+// gas limit of the configuration and is checked when proving.
+func (req *Request) validateGasLimit() error {
+	tx, err := ethereum.RlpDecodeWithSignature(req.RlpEncodedTx)
+	if err != nil {
+		return fmt.Errorf("could not decode the RlpEncodedTx: %w", err)
+	}
+
+	if tx.Type() != ethtypes.DynamicFeeTxType && tx.Type() != ethtypes.SetCodeTxType {
+		return fmt.Errorf("%s invalidity type only supports dynamic-fee and set-code transactions, got type %d", req.InvalidityType, tx.Type())
+	}
+
+	if req.InvalidityType == invalidity.GasLimitTooLow {
+		if minGas := invalidity.MinimumGas(tx); tx.Gas() >= minGas {
+			return fmt.Errorf("gas limit %d is not below the minimum gas %d of the transaction", tx.Gas(), minGas)
+		}
+	}
+
+	return nil
+}
+
 // validateAccountMerkleProof performs comprehensive sanity checks on the
 // decoded accountMerkleProof, covering both existing and non-existing accounts.
 func (req *Request) validateAccountMerkleProof() error {
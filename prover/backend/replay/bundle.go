@@ -0,0 +1,341 @@
+// Package replay implements the replay bundles of the controller. A bundle is
+// a gzipped tarball collecting everything needed to re-run a failed proving
+// job on another machine: the request, the conflated trace file, the config,
+// the setup checksums and the environment overrides of the prover.
+package replay
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// ManifestFileName is the name of the manifest in the bundle
+	ManifestFileName = "manifest.json"
+	requestDir       = "request"
+	tracesDir        = "traces"
+	configDir        = "config"
+)
+
+// envPrefixes lists the prefixes of the environment variables read by the
+// prover and which are recorded in the bundle.
+var envPrefixes = []string{"LIMITLESS_", "GOMAXPROCS", "GOGC", "GOMEMLIMIT", "GODEBUG"}
+
+// Manifest describes the content of a bundle and how the job was run
+type Manifest struct {
+	// JobName is the name of the job definition, e.g. "execution"
+	JobName string `json:"jobName"`
+	// Request is the name of the request file. The prover infers the type of
+	// the job from it, so it is the original name of the request.
+	Request string `json:"request"`
+	// Config is the name of the config file the prover was run with
+	Config string `json:"config"`
+	// Trace is the name of the conflated trace file. It is empty if the job
+	// does not have one or if it could not be found.
+	Trace string `json:"trace,omitempty"`
+	// Large indicates whether the failing run used the large prover command
+	Large bool `json:"large"`
+	// ExitCode is the exit code of the failing run
+	ExitCode int `json:"exitCode"`
+	// ProverVersion is the version of the prover in the config
+	ProverVersion string `json:"proverVersion"`
+	// ProverRevision is the VCS revision the binary writing the bundle was
+	// built from, see [BuildRevision].
+	ProverRevision string `json:"proverRevision,omitempty"`
+	// SetupChecksums maps the circuit IDs to the checksums of their setup
+	SetupChecksums map[string]SetupChecksums `json:"setupChecksums"`
+	// Env are the environment overrides of the prover process
+	Env map[string]string `json:"env"`
+	// CreatedAt is the time at which the bundle was created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Files are the paths of the files to pack in a bundle
+type Files struct {
+	Request string
+	// RequestContent is the content of the request. It is read from Request
+	// if nil, which allows snapshotting a request that is about to be moved.
+	RequestContent []byte
+	Config         string
+	// Trace is optional
+	Trace string
+}
+
+// RequestPath returns the path of the request in a bundle extracted in dir
+func (m *Manifest) RequestPath(dir string) string {
+	return filepath.Join(dir, requestDir, m.Request)
+}
+
+// ConfigPath returns the path of the config in a bundle extracted in dir
+func (m *Manifest) ConfigPath(dir string) string {
+	return filepath.Join(dir, configDir, m.Config)
+}
+
+// TracesDir returns the directory of the conflated trace in a bundle
+// extracted in dir.
+func (m *Manifest) TracesDir(dir string) string {
+	return filepath.Join(dir, tracesDir)
+}
+
+// EnvList returns the environment overrides in the "KEY=value" format of
+// [os/exec.Cmd.Env], sorted by key.
+func (m *Manifest) EnvList() []string {
+	res := make([]string, 0, len(m.Env))
+	for k, v := range m.Env {
+		res = append(res, k+"="+v)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// EnvOverrides returns the environment variables of the current process that
+// are read by the prover.
+func EnvOverrides() map[string]string {
+	res := map[string]string{}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		for _, prefix := range envPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				res[k] = v
+				break
+			}
+		}
+	}
+	return res
+}
+
+// BuildRevision returns the VCS revision the current binary was built from,
+// with a "-dirty" suffix if the tree had local modifications. It is empty if
+// the binary was built without the VCS information.
+func BuildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+
+	if revision != "" && modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// Write packs the files into a bundle at bundlePath. The names of the files
+// in the manifest default to the base names of files when they are not set.
+// The bundle is first written in a temporary file so that a partial bundle is
+// never left at bundlePath. Writing stops with the error of ctx once it is
+// done.
+func Write(ctx context.Context, bundlePath string, m *Manifest, files Files) (err error) {
+
+	if m.Request == "" {
+		m.Request = filepath.Base(files.Request)
+	}
+	if m.Config == "" {
+		m.Config = filepath.Base(files.Config)
+	}
+	m.Trace = ""
+	if files.Trace != "" {
+		m.Trace = filepath.Base(files.Trace)
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not encode the manifest: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(bundlePath), 0o755); err != nil {
+		return err
+	}
+
+	tmpPath := bundlePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	var (
+		gz = gzip.NewWriter(f)
+		tw = tar.NewWriter(gz)
+	)
+
+	if err := writeBytes(tw, ManifestFileName, manifest); err != nil {
+		return err
+	}
+
+	if files.RequestContent != nil {
+		if err := writeBytes(tw, filepath.Join(requestDir, m.Request), files.RequestContent); err != nil {
+			return err
+		}
+	} else if err := writeFile(ctx, tw, filepath.Join(requestDir, m.Request), files.Request); err != nil {
+		return err
+	}
+
+	if err := writeFile(ctx, tw, filepath.Join(configDir, m.Config), files.Config); err != nil {
+		return err
+	}
+
+	if m.Trace != "" {
+		if err := writeFile(ctx, tw, filepath.Join(tracesDir, m.Trace), files.Trace); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, bundlePath)
+}
+
+func writeBytes(tw *tar.Writer, name string, content []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("could not write %v in the bundle: %w", name, err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		return fmt.Errorf("could not write %v in the bundle: %w", name, err)
+	}
+	return nil
+}
+
+func writeFile(ctx context.Context, tw *tar.Writer, name, srcPath string) error {
+
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("could not open %v: %w", srcPath, err)
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("could not stat %v: %w", srcPath, err)
+	}
+
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("could not write %v in the bundle: %w", name, err)
+	}
+	if _, err := io.Copy(tw, ctxReader{ctx: ctx, r: src}); err != nil {
+		return fmt.Errorf("could not write %v in the bundle: %w", name, err)
+	}
+	return nil
+}
+
+// ctxReader is an [io.Reader] failing with the error of ctx once it is done
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// Extract unpacks the bundle at bundlePath in dir and returns its manifest.
+// Only regular files are extracted and the function returns an error if an
+// entry would be written outside of dir.
+func Extract(bundlePath, dir string) (*Manifest, error) {
+
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the bundle %v: %w", bundlePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read the bundle %v: %w", bundlePath, err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		dst := filepath.Join(dir, hdr.Name)
+		if !strings.HasPrefix(dst, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("invalid entry %q in the bundle", hdr.Name)
+		}
+
+		if err := extractFile(tr, dst); err != nil {
+			return nil, err
+		}
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the manifest of the bundle: %w", err)
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("could not decode the manifest of the bundle: %w", err)
+	}
+
+	return m, nil
+}
+
+func extractFile(r io.Reader, dst string) error {
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G110 -- the bundles are produced by our own controller
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("could not extract %v: %w", dst, err)
+	}
+
+	return f.Close()
+}
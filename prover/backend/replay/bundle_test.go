@@ -0,0 +1,122 @@
+package replay
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+
+	var (
+		srcDir     = t.TempDir()
+		dstDir     = t.TempDir()
+		bundlePath = filepath.Join(t.TempDir(), "bundles", "job.tgz")
+		files      = Files{
+			Request: filepath.Join(srcDir, "1-2-getZkProof.json.inprogress.worker"),
+			Config:  filepath.Join(srcDir, "config.toml"),
+			Trace:   filepath.Join(srcDir, "1-2.conflated.lt"),
+		}
+	)
+
+	require.NoError(t, os.WriteFile(files.Request, []byte(`{"conflatedExecutionTracesFile":"1-2.conflated.lt"}`), 0o600))
+	require.NoError(t, os.WriteFile(files.Config, []byte("version = \"4.0.0\"\n"), 0o600))
+	require.NoError(t, os.WriteFile(files.Trace, []byte("trace"), 0o600))
+
+	m := &Manifest{
+		JobName:        "execution",
+		Request:        "1-2-getZkProof.json",
+		Large:          true,
+		ExitCode:       78,
+		ProverVersion:  "4.0.0",
+		SetupChecksums: map[string]SetupChecksums{"execution-large": {VerifyingKey: "vk", Circuit: "circuit"}},
+		Env:            map[string]string{"LIMITLESS_SUBPROVER_JOBS": "2", "GOGC": "50"},
+	}
+
+	require.NoError(t, Write(context.Background(), bundlePath, m, files))
+	assert.NoFileExists(t, bundlePath+".tmp")
+
+	got, err := Extract(bundlePath, dstDir)
+	require.NoError(t, err)
+	assert.Equal(t, m.SetupChecksums, got.SetupChecksums)
+	assert.Equal(t, []string{"GOGC=50", "LIMITLESS_SUBPROVER_JOBS=2"}, got.EnvList())
+	assert.Equal(t, "1-2-getZkProof.json", got.Request)
+	assert.Equal(t, "config.toml", got.Config)
+	assert.True(t, got.Large)
+	assert.Equal(t, 78, got.ExitCode)
+
+	for _, tc := range []struct{ path, content string }{
+		{got.RequestPath(dstDir), `{"conflatedExecutionTracesFile":"1-2.conflated.lt"}`},
+		{got.ConfigPath(dstDir), "version = \"4.0.0\"\n"},
+		{filepath.Join(got.TracesDir(dstDir), got.Trace), "trace"},
+	} {
+		b, err := os.ReadFile(tc.path)
+		require.NoError(t, err)
+		assert.Equal(t, tc.content, string(b))
+	}
+}
+
+func TestWriteCancelled(t *testing.T) {
+
+	var (
+		srcDir     = t.TempDir()
+		bundlePath = filepath.Join(t.TempDir(), "job.tgz")
+		files      = Files{
+			Request:        filepath.Join(srcDir, "1-2-getZkProof.json.inprogress.worker"),
+			RequestContent: []byte(`{}`),
+			Config:         filepath.Join(srcDir, "config.toml"),
+		}
+	)
+
+	require.NoError(t, os.WriteFile(files.Config, []byte("version = \"4.0.0\"\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.ErrorIs(t, Write(ctx, bundlePath, &Manifest{}, files), context.Canceled)
+	assert.NoFileExists(t, bundlePath)
+	assert.NoFileExists(t, bundlePath+".tmp")
+}
+
+func TestExtractRejectsPathTraversal(t *testing.T) {
+
+	bundlePath := filepath.Join(t.TempDir(), "evil.tgz")
+	f, err := os.Create(bundlePath)
+	require.NoError(t, err)
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, writeBytes(tw, "../escaped", []byte("x")))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+
+	dir := t.TempDir()
+	_, err = Extract(bundlePath, dir)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escaped"))
+}
+
+func TestRewriteConfig(t *testing.T) {
+
+	var (
+		dir = t.TempDir()
+		src = filepath.Join(dir, "config.toml")
+		dst = filepath.Join(dir, "replay.toml")
+	)
+
+	require.NoError(t, os.WriteFile(src, []byte("version = \"4.0.0\"\n\n[execution]\nconflated_traces_dir = \"/shared/traces\"\nprover_mode = \"full\"\n"), 0o600))
+	require.NoError(t, RewriteConfig(src, dst, map[string]any{"execution.conflated_traces_dir": "/tmp/bundle/traces"}))
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "/tmp/bundle/traces")
+	assert.NotContains(t, string(b), "/shared/traces")
+	assert.Contains(t, string(b), "prover_mode")
+}
@@ -0,0 +1,103 @@
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/spf13/viper"
+)
+
+// SetupChecksums are the checksums of the setup of a circuit, as found in its
+// [circuits.SetupManifest].
+type SetupChecksums struct {
+	VerifyingKey string `json:"verifyingKey"`
+	Circuit      string `json:"circuit"`
+}
+
+// ReadSetupChecksums returns the checksums of all the setups of the prover
+// version of cfg, indexed by circuit ID.
+func ReadSetupChecksums(cfg *config.Config) (map[string]SetupChecksums, error) {
+
+	root := filepath.Join(cfg.AssetsDir, cfg.Version)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("could not list the setups in %v: %w", root, err)
+	}
+
+	res := map[string]SetupChecksums{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		manifestPath := filepath.Join(root, e.Name(), config.ManifestFileName)
+		if _, err := os.Stat(manifestPath); err != nil {
+			continue
+		}
+
+		manifest, err := circuits.ReadSetupManifest(manifestPath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the setup manifest %v: %w", manifestPath, err)
+		}
+
+		res[e.Name()] = SetupChecksums{
+			VerifyingKey: manifest.Checksums.VerifyingKey,
+			Circuit:      manifest.Checksums.Circuit,
+		}
+	}
+
+	return res, nil
+}
+
+// CheckSetupChecksums returns an error listing the circuits whose setup for
+// cfg is missing or differs from the one recorded in the bundle.
+func CheckSetupChecksums(cfg *config.Config, expected map[string]SetupChecksums) error {
+
+	actual, err := ReadSetupChecksums(cfg)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []string
+	for circuitID, exp := range expected {
+		act, ok := actual[circuitID]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%v: missing", circuitID))
+		case act != exp:
+			mismatches = append(mismatches, fmt.Sprintf("%v: %+v, expected %+v", circuitID, act, exp))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("the setup does not match the one of the bundle:\n\t%v", strings.Join(mismatches, "\n\t"))
+	}
+
+	return nil
+}
+
+// RewriteConfig writes in dst the config file src with the provided keys
+// overridden. The keys are the ones of the config file, e.g.
+// "execution.conflated_traces_dir". The format of dst is inferred from its
+// extension.
+func RewriteConfig(src, dst string, overrides map[string]any) error {
+
+	v := viper.New()
+	v.SetConfigFile(src)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("could not read the config %v: %w", src, err)
+	}
+
+	for k, val := range overrides {
+		v.Set(k, val)
+	}
+
+	if err := v.WriteConfigAs(dst); err != nil {
+		return fmt.Errorf("could not write the config %v: %w", dst, err)
+	}
+
+	return nil
+}
@@ -50,6 +50,8 @@ type Circuit struct {
 	//   - Bits 2-5: production payload circuits (execution, execution-large, etc.)
 	//   - Bits 6-8: invalidity dummy circuits
 	//   - Bits 9-13: invalidity production circuits
+	//   - Bits 14-17: infrastructure circuits, never allowed (placeholder keys)
+	//   - Bits 18-19: gas limit invalidity circuits (dummy, production)
 	verifyingKeys []emVkey `gnark:"-"`
 
 	publicInputVerifyingKey        emVkey              `gnark:"-"`
@@ -93,7 +95,8 @@ func (c *Circuit) Define(api frontend.API) error {
 	//
 	// We only need len(c.verifyingKeys) bits for the circuit allowlist (payload circuits).
 	// The maskBits from ToBitsCanonical may have more bits than needed, so we truncate.
-	// If maskBits has fewer bits (shouldn't happen), we pad with zeros.
+	// If maskBits has fewer bits (shouldn't happen), we pad with zeros. The IDs
+	// of the infrastructure circuits are never allowed, whatever the mask.
 	//
 	// Example: If mask = 60 = 0b111100, then:
 	//   isCircuitAllowed[0] = 0 (execution-dummy NOT allowed)
@@ -103,7 +106,7 @@ func (c *Circuit) Define(api frontend.API) error {
 	//   ... and so on for all circuit IDs
 	isCircuitAllowed := make([]frontend.Variable, len(c.verifyingKeys))
 	for i := range isCircuitAllowed {
-		if i < len(maskBits) {
+		if i < len(maskBits) && !circuits.IsInfrastructureCircuitID(uint(i)) {
 			isCircuitAllowed[i] = maskBits[i]
 		} else {
 			isCircuitAllowed[i] = 0
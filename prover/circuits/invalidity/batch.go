@@ -0,0 +1,380 @@
+package invalidity
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/std/rangecheck"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/internal"
+	wizardk "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2_bls12377"
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	"github.com/sirupsen/logrus"
+)
+
+// CircuitInvalidityBatch proves the invalidity of a run of consecutive forced
+// transactions in a single proof. It holds MaxNbTx instances of the same sub
+// circuit, of which only the first NbTx are effective; the remaining ones are
+// padded with copies of the last effective one.
+//
+// The circuit checks that:
+//   - all the transactions are proven against the same state, chain
+//     configuration and simulated block;
+//   - the simulated block number does not exceed the deadline of any
+//     transaction of the batch;
+//   - none of the transactions is proven invalid for a filtered address, as
+//     the filtered addresses are not exposed by the batch;
+//   - the FTX rolling hash, chained from PrevFtxRollingHash over the effective
+//     transactions, ends at FtxRollingHash and the FTX numbers run from
+//     FirstTxNumber to LastTxNumber.
+//
+// The keccak hashes of the transactions of all the sub circuits are proven by a
+// single wizard proof, verified once by the batch circuit, see
+// [CheckKeccakConsistencyBatch].
+//
+// Only the batch-level fields are exposed, through [public_input.InvalidityBatch].
+type CircuitInvalidityBatch struct {
+	// The sub circuits, one per forced transaction of the batch
+	SubCircuits []SubCircuit `gnark:",secret"`
+	// Keccak verifier circuit for the transactions of all the sub circuits
+	KeccakH wizardk.VerifierCircuit
+	// The deadline block numbers of the forced transactions
+	DeadLineBlockNumbers []frontend.Variable `gnark:",secret"`
+	// The number of effective forced transactions in the batch
+	NbTx frontend.Variable `gnark:",secret"`
+	// the functional public inputs of the circuit.
+	FuncInputs BatchFunctionalPublicInputsGnark `gnark:",secret"`
+	// the hash of the functional public inputs
+	PublicInput frontend.Variable `gnark:",public"`
+}
+
+// BatchFunctionalPublicInputsGnark represents the gnark version of
+// [public_input.InvalidityBatch]
+type BatchFunctionalPublicInputsGnark struct {
+	FirstTxNumber           frontend.Variable
+	LastTxNumber            frontend.Variable
+	PrevFtxRollingHash      frontend.Variable
+	FtxRollingHash          frontend.Variable
+	StateRootHash           [2]frontend.Variable
+	CoinBase                frontend.Variable
+	BaseFee                 frontend.Variable
+	ChainID                 frontend.Variable
+	L2MessageServiceAddr    frontend.Variable
+	SimulatedBlockTimestamp frontend.Variable
+	SimulatedBlockNumber    frontend.Variable
+}
+
+// BatchAssigningInputs collects the inputs used for the assignment of the
+// batch circuit. Entries are the assigning inputs of the forced transactions
+// of the batch, in FTX number order.
+type BatchAssigningInputs struct {
+	Entries    []AssigningInputs
+	FuncInputs public_input.InvalidityBatch
+	// MaxNbTx is the number of sub circuits of the batch circuit
+	MaxNbTx int
+	// The keccak proof of the transactions of the entries padded to MaxNbTx,
+	// see [MakeKeccakProofsBatch]. The keccak inputs of the entries are not
+	// used.
+	KeccakCompiledIOP *wizardk.CompiledIOP
+	KeccakProof       wizardk.Proof
+
+	// CachedProofPath, see [AssigningInputs.CachedProofPath]
+	CachedProofPath string
+}
+
+// keccakSharing is implemented by the sub circuits which can leave the
+// verification of their keccak hash to the batch circuit.
+type keccakSharing interface {
+	// shareKeccak must be called before the allocation or the assignment of the
+	// sub circuit.
+	shareKeccak()
+	// keccakInputs returns the RLP encoding of the transaction and its hash
+	keccakInputs() (rlpEncodedTx []frontend.Variable, txHash [2]frontend.Variable)
+}
+
+// shareKeccak makes the batch circuit verify the keccak hash of the sub
+// circuit.
+func shareKeccak(sub SubCircuit) keccakSharing {
+	ks, ok := sub.(keccakSharing)
+	if !ok {
+		panic(fmt.Sprintf("the sub circuit %T can not be batched", sub))
+	}
+	ks.shareKeccak()
+	return ks
+}
+
+// IsBatchable returns true if forced transactions of the given invalidity type
+// can be proven in a batch. The filtered address cases are excluded because
+// the batch does not expose the filtered addresses, and the precompile/logs
+// cases because each of them embeds a full zkEVM proof.
+func IsBatchable(invalidityType InvalidityType) bool {
+	switch invalidityType {
+	case BadNonce, BadBalance, GasLimitTooLow, GasLimitTooHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Define the constraints
+func (c *CircuitInvalidityBatch) Define(api frontend.API) error {
+
+	var (
+		fpi     = &c.FuncInputs
+		r       = internal.NewRange(api, c.NbTx, len(c.SubCircuits))
+		rc      = rangecheck.New(api)
+		rolling = fpi.PrevFtxRollingHash
+		rlps    = make([][]frontend.Variable, len(c.SubCircuits))
+		hashes  = make([][2]frontend.Variable, len(c.SubCircuits))
+	)
+
+	api.AssertIsDifferent(c.NbTx, 0)
+
+	for i, sub := range c.SubCircuits {
+
+		if err := sub.Define(api); err != nil {
+			return err
+		}
+		rlps[i], hashes[i] = sub.(keccakSharing).keccakInputs()
+
+		// The padding entries are copies of the last effective one, so the
+		// shared fields are checked for all the entries.
+		subPI := sub.FunctionalPIQGnark()
+		api.AssertIsEqual(subPI.StateRootHash[0], fpi.StateRootHash[0])
+		api.AssertIsEqual(subPI.StateRootHash[1], fpi.StateRootHash[1])
+		api.AssertIsEqual(subPI.CoinBase, fpi.CoinBase)
+		api.AssertIsEqual(subPI.BaseFee, fpi.BaseFee)
+		api.AssertIsEqual(subPI.ChainID, fpi.ChainID)
+		api.AssertIsEqual(subPI.L2MessageServiceAddr, fpi.L2MessageServiceAddr)
+		api.AssertIsEqual(subPI.SimulatedBlockTimestamp, fpi.SimulatedBlockTimestamp)
+		api.AssertIsEqual(subPI.SimulatedBlockNumber, fpi.SimulatedBlockNumber)
+		api.AssertIsEqual(subPI.FromIsFiltered, 0)
+		api.AssertIsEqual(subPI.ToIsFiltered, 0)
+
+		// SimulatedBlockNumber <= DeadLineBlockNumber
+		rc.Check(c.DeadLineBlockNumbers[i], 64)
+		internal.AssertIsLessIf(api, r.InRange[i], fpi.SimulatedBlockNumber, api.Add(c.DeadLineBlockNumbers[i], 1))
+
+		next := UpdateFtxRollingHashGnark(api, FtxRollingHashInputs{
+			PrevFtxRollingHash:  rolling,
+			TxHash0:             subPI.TxHash[0],
+			TxHash1:             subPI.TxHash[1],
+			ExpectedBlockHeight: c.DeadLineBlockNumbers[i],
+			FromAddress:         subPI.FromAddress,
+		})
+		rolling = api.Select(r.InRange[i], next, rolling)
+	}
+
+	// The padding entries are hashed too, it keeps the layout of the keccak
+	// module independent of NbTx.
+	CheckKeccakConsistencyBatch(api, rlps, hashes, &c.KeccakH)
+	c.KeccakH.Verify(api)
+
+	api.AssertIsEqual(fpi.FtxRollingHash, rolling)
+	api.AssertIsEqual(fpi.LastTxNumber, api.Sub(api.Add(fpi.FirstTxNumber, c.NbTx), 1))
+
+	rc.Check(fpi.FirstTxNumber, 64)
+	rc.Check(fpi.SimulatedBlockTimestamp, 64)
+	rc.Check(fpi.SimulatedBlockNumber, 64)
+
+	api.AssertIsEqual(c.PublicInput, fpi.Sum(api))
+
+	return nil
+}
+
+// Allocate the circuit with maxNbTx sub circuits created by newSubCircuit. The
+// keccak IOP of the config must be the one of [MakeKeccakCompiledIOPBatch] for
+// maxNbTx transactions.
+func (c *CircuitInvalidityBatch) Allocate(config Config, maxNbTx int, newSubCircuit func() SubCircuit) {
+	c.SubCircuits = make([]SubCircuit, maxNbTx)
+	c.DeadLineBlockNumbers = make([]frontend.Variable, maxNbTx)
+	for i := range c.SubCircuits {
+		c.SubCircuits[i] = newSubCircuit()
+		shareKeccak(c.SubCircuits[i])
+		allocateSubCircuit(c.SubCircuits[i], config)
+	}
+	c.KeccakH = *wizardk.AllocateWizardCircuit(config.KeccakCompiledIOP, 0)
+}
+
+// Assign the circuit. The sub circuits are created from the invalidity type
+// of the entries.
+func (c *CircuitInvalidityBatch) Assign(assi BatchAssigningInputs) {
+
+	nbTx := len(assi.Entries)
+	if nbTx == 0 || nbTx > assi.MaxNbTx {
+		panic(fmt.Sprintf("the batch has %d forced transactions, expected between 1 and %d", nbTx, assi.MaxNbTx))
+	}
+
+	c.SubCircuits = make([]SubCircuit, assi.MaxNbTx)
+	c.DeadLineBlockNumbers = make([]frontend.Variable, assi.MaxNbTx)
+
+	for i := range c.SubCircuits {
+		entry := assi.Entries[min(i, nbTx-1)]
+		c.SubCircuits[i] = NewSubCircuit(entry.InvalidityType)
+		shareKeccak(c.SubCircuits[i])
+		assignSubCircuit(c.SubCircuits[i], entry)
+		c.DeadLineBlockNumbers[i] = entry.FuncInputs.DeadLineBlockNumber
+	}
+
+	c.KeccakH = *wizardk.AssignVerifierCircuit(assi.KeccakCompiledIOP, assi.KeccakProof, 0)
+
+	c.NbTx = nbTx
+	c.FuncInputs.Assign(assi.FuncInputs)
+	c.PublicInput = assi.FuncInputs.Sum(nil)
+}
+
+// MakeProof and solve the circuit.
+func (c *CircuitInvalidityBatch) MakeProof(setup circuits.Setup, assi BatchAssigningInputs) string {
+	c.Assign(assi)
+	return proveAndSerialize(setup, c, assi.CachedProofPath, assi.FuncInputs.Sum(nil))
+}
+
+// CheckOnly verifies natively that the batch is valid without generating a
+// proof. Each entry is checked with [CircuitInvalidity.CheckOnly] and the
+// batch-level fields are checked against the entries.
+func (c *CircuitInvalidityBatch) CheckOnly(assi BatchAssigningInputs) error {
+	t0 := time.Now()
+
+	if err := CheckBatchConsistency(assi.Entries, assi.FuncInputs, assi.MaxNbTx); err != nil {
+		return err
+	}
+
+	for i := range assi.Entries {
+		if err := (&CircuitInvalidity{}).CheckOnly(assi.Entries[i]); err != nil {
+			return fmt.Errorf("forced transaction %d of the batch: %w", assi.Entries[i].FuncInputs.TxNumber, err)
+		}
+	}
+
+	logrus.Infof("invalidity batch CheckOnly (%d transactions): native check done in %s", len(assi.Entries), time.Since(t0))
+	return nil
+}
+
+// CheckBatchConsistency checks that the batch-level functional public inputs
+// are consistent with those of the forced transactions of the batch, as
+// enforced by [CircuitInvalidityBatch].
+func CheckBatchConsistency(entries []AssigningInputs, batch public_input.InvalidityBatch, maxNbTx int) error {
+
+	if len(entries) == 0 || len(entries) > maxNbTx {
+		return fmt.Errorf("the batch has %d forced transactions, expected between 1 and %d", len(entries), maxNbTx)
+	}
+
+	if batch.LastTxNumber != batch.FirstTxNumber+uint64(len(entries))-1 {
+		return fmt.Errorf("the FTX numbers %d to %d do not match the %d forced transactions of the batch", batch.FirstTxNumber, batch.LastTxNumber, len(entries))
+	}
+
+	rolling := batch.PrevFtxRollingHash
+	for i := range entries {
+		fi := &entries[i].FuncInputs
+
+		if !IsBatchable(entries[i].InvalidityType) {
+			return fmt.Errorf("invalidity type %s can not be batched", entries[i].InvalidityType)
+		}
+		if reflect.TypeOf(NewSubCircuit(entries[i].InvalidityType)) != reflect.TypeOf(NewSubCircuit(entries[0].InvalidityType)) {
+			return fmt.Errorf("the forced transactions of the batch are not handled by the same circuit: %s and %s", entries[0].InvalidityType, entries[i].InvalidityType)
+		}
+		if fi.TxNumber != batch.FirstTxNumber+uint64(i) {
+			return fmt.Errorf("FTX number mismatch at position %d: expected %d, got %d", i, batch.FirstTxNumber+uint64(i), fi.TxNumber)
+		}
+		if fi.StateRootHash != batch.StateRootHash ||
+			fi.CoinBase != batch.CoinBase ||
+			fi.BaseFee != batch.BaseFee ||
+			fi.ChainID != batch.ChainID ||
+			fi.L2MessageServiceAddr != batch.L2MessageServiceAddr ||
+			fi.SimulatedBlockTimestamp != batch.SimulatedBlockTimestamp ||
+			fi.SimulatedBlockNumber != batch.SimulatedBlockNumber {
+			return fmt.Errorf("forced transaction %d is not proven against the state and block of the batch", fi.TxNumber)
+		}
+		if fi.FromIsFiltered || fi.ToIsFiltered {
+			return fmt.Errorf("forced transaction %d is proven invalid for a filtered address", fi.TxNumber)
+		}
+		if fi.SimulatedBlockNumber > fi.DeadLineBlockNumber {
+			return fmt.Errorf("forced transaction %d has a deadline %d before the simulated block %d", fi.TxNumber, fi.DeadLineBlockNumber, fi.SimulatedBlockNumber)
+		}
+
+		rolling = UpdateFtxRollingHash(rolling, fi.TxHash, fi.DeadLineBlockNumber, fi.FromAddress)
+		if rolling != fi.FtxRollingHash {
+			return fmt.Errorf("FTX rolling hash mismatch for forced transaction %d", fi.TxNumber)
+		}
+	}
+
+	if rolling != batch.FtxRollingHash {
+		return fmt.Errorf("FTX rolling hash mismatch at the end of the batch")
+	}
+
+	return nil
+}
+
+// Assign the functional public inputs
+func (gpi *BatchFunctionalPublicInputsGnark) Assign(pi public_input.InvalidityBatch) {
+	gpi.FirstTxNumber = pi.FirstTxNumber
+	gpi.LastTxNumber = pi.LastTxNumber
+	gpi.PrevFtxRollingHash = pi.PrevFtxRollingHash[:]
+	gpi.FtxRollingHash = pi.FtxRollingHash[:]
+
+	stateRootBytes := pi.StateRootHash.ToBytes()
+	gpi.StateRootHash[0] = stateRootBytes[:16]
+	gpi.StateRootHash[1] = stateRootBytes[16:]
+
+	gpi.CoinBase = pi.CoinBase[:]
+	gpi.BaseFee = pi.BaseFee
+	gpi.ChainID = pi.ChainID
+	gpi.L2MessageServiceAddr = pi.L2MessageServiceAddr[:]
+	gpi.SimulatedBlockTimestamp = pi.SimulatedBlockTimestamp
+	gpi.SimulatedBlockNumber = pi.SimulatedBlockNumber
+}
+
+// Sum computes the hash over the functional inputs using Poseidon2, matching
+// [public_input.InvalidityBatch.Sum]
+func (gpi *BatchFunctionalPublicInputsGnark) Sum(api frontend.API) frontend.Variable {
+
+	hsh, err := poseidon2_bls12377.NewGnarkMDHasher(api)
+	if err != nil {
+		panic(err)
+	}
+
+	hsh.Write(
+		gpi.FirstTxNumber,
+		gpi.LastTxNumber,
+		gpi.PrevFtxRollingHash,
+		gpi.FtxRollingHash,
+		gpi.StateRootHash[0],
+		gpi.StateRootHash[1],
+		gpi.CoinBase,
+		gpi.BaseFee,
+		gpi.ChainID,
+		gpi.L2MessageServiceAddr,
+		gpi.SimulatedBlockTimestamp,
+		gpi.SimulatedBlockNumber,
+	)
+
+	return hsh.Sum()
+}
+
+type batchBuilder struct {
+	config        Config
+	maxNbTx       int
+	newSubCircuit func() SubCircuit
+}
+
+// NewBatchBuilder returns a builder for the batch circuit with maxNbTx sub
+// circuits created by newSubCircuit.
+func NewBatchBuilder(config Config, maxNbTx int, newSubCircuit func() SubCircuit) *batchBuilder {
+	return &batchBuilder{config: config, maxNbTx: maxNbTx, newSubCircuit: newSubCircuit}
+}
+
+func (b *batchBuilder) Compile() (constraint.ConstraintSystem, error) {
+	circuit := &CircuitInvalidityBatch{}
+	circuit.Allocate(b.config, b.maxNbTx, b.newSubCircuit)
+
+	ccs, err := frontend.Compile(ecc.BLS12_377.ScalarField(), scs.NewBuilder, circuit, frontend.WithCapacity(1<<24))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile invalidity batch circuit: %w", err)
+	}
+	return ccs, nil
+}
@@ -0,0 +1,228 @@
+package invalidity_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	"github.com/consensys/linea-monorepo/prover/utils/gnarkutil"
+	linTypes "github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const batchMaxNbTx = 3
+
+// compileGasLimitBatchCircuit compiles the batch circuit over batchMaxNbTx gas
+// limit sub circuits.
+func compileGasLimitBatchCircuit(t *testing.T) constraint.ConstraintSystem {
+	t.Helper()
+
+	circuit := invalidity.CircuitInvalidityBatch{}
+	circuit.Allocate(invalidity.Config{
+		KeccakCompiledIOP: invalidity.MakeKeccakCompiledIOPBatch(gasLimitMaxRlpByteSize, batchMaxNbTx, dummy.Compile),
+		MaxRlpByteSize:    gasLimitMaxRlpByteSize,
+		BlockGasLimit:     gasLimitBlockGasLimit,
+	}, batchMaxNbTx, func() invalidity.SubCircuit { return &invalidity.GasLimitCircuit{} })
+
+	cs, err := frontend.Compile(
+		ecc.BLS12_377.ScalarField(),
+		scs.NewBuilder,
+		&circuit,
+	)
+	require.NoError(t, err)
+	return cs
+}
+
+// gasLimitBatchInputs returns the assigning inputs of a batch of consecutive
+// GasLimitTooLow forced transactions, proven against the same state and
+// simulated block.
+func gasLimitBatchInputs(names ...string) invalidity.BatchAssigningInputs {
+
+	var (
+		txs           = gasLimitTestTxs()
+		stateRootHash = linTypes.KoalaOctuplet(field.RandomOctuplet())
+		prev          = linTypes.Bls12377Fr{31: 7}
+		rolling       = prev
+		entries       = make([]invalidity.AssigningInputs, len(names))
+	)
+
+	for i, name := range names {
+		minGas := invalidity.MinimumGas(types.NewTx(txs[name](0)))
+		tx := types.NewTx(txs[name](minGas - 1))
+
+		entries[i] = gasLimitAssigningInputs(tx, invalidity.GasLimitTooLow, gasLimitBlockGasLimit)
+		fi := &entries[i].FuncInputs
+		fi.StateRootHash = stateRootHash
+		fi.TxNumber = uint64(10 + i)
+		fi.DeadLineBlockNumber = fi.SimulatedBlockNumber + uint64(i)
+		rolling = invalidity.UpdateFtxRollingHash(rolling, fi.TxHash, fi.DeadLineBlockNumber, fi.FromAddress)
+		fi.FtxRollingHash = rolling
+	}
+
+	first := &entries[0].FuncInputs
+	res := invalidity.BatchAssigningInputs{
+		Entries: entries,
+		FuncInputs: public_input.InvalidityBatch{
+			FirstTxNumber:           first.TxNumber,
+			LastTxNumber:            first.TxNumber + uint64(len(entries)) - 1,
+			PrevFtxRollingHash:      prev,
+			FtxRollingHash:          rolling,
+			StateRootHash:           stateRootHash,
+			CoinBase:                first.CoinBase,
+			BaseFee:                 first.BaseFee,
+			ChainID:                 first.ChainID,
+			L2MessageServiceAddr:    first.L2MessageServiceAddr,
+			SimulatedBlockTimestamp: first.SimulatedBlockTimestamp,
+			SimulatedBlockNumber:    first.SimulatedBlockNumber,
+		},
+		MaxNbTx: batchMaxNbTx,
+	}
+
+	txs := make([]*types.Transaction, len(entries))
+	for i := range entries {
+		txs[i] = entries[i].Transaction
+	}
+	setBatchKeccakProof(&res, txs)
+	return res
+}
+
+// setBatchKeccakProof proves the keccak hashes of the given transactions,
+// padded with copies of the last one, as expected by the batch circuit.
+func setBatchKeccakProof(assi *invalidity.BatchAssigningInputs, txs []*types.Transaction) {
+	padded := make([]*types.Transaction, assi.MaxNbTx)
+	for i := range padded {
+		padded[i] = txs[min(i, len(txs)-1)]
+	}
+	assi.KeccakCompiledIOP, assi.KeccakProof = invalidity.MakeKeccakProofsBatch(padded, gasLimitMaxRlpByteSize, dummy.Compile)
+}
+
+// TestInvalidityBatch tests a partially filled batch of gas limit proofs, and
+// that tampering with the exposed rolling hash or FTX numbers is rejected both
+// natively and by the circuit.
+func TestInvalidityBatch(t *testing.T) {
+	gnarkutil.RegisterHintsAndGkrGates()
+
+	cs := compileGasLimitBatchCircuit(t)
+
+	testCases := []struct {
+		name     string
+		tamper   func(pi *public_input.InvalidityBatch)
+		isSolved bool
+	}{
+		{name: "Valid", tamper: func(pi *public_input.InvalidityBatch) {}, isSolved: true},
+		{name: "BadFtxRollingHash", tamper: func(pi *public_input.InvalidityBatch) { pi.FtxRollingHash = pi.PrevFtxRollingHash }},
+		{name: "BadPrevFtxRollingHash", tamper: func(pi *public_input.InvalidityBatch) { pi.PrevFtxRollingHash[31]++ }},
+		{name: "BadLastTxNumber", tamper: func(pi *public_input.InvalidityBatch) { pi.LastTxNumber++ }},
+		{name: "BadSimulatedBlockNumber", tamper: func(pi *public_input.InvalidityBatch) { pi.SimulatedBlockNumber++ }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			assi := gasLimitBatchInputs("Transfer", "Calldata")
+			tc.tamper(&assi.FuncInputs)
+
+			err := invalidity.CheckBatchConsistency(assi.Entries, assi.FuncInputs, assi.MaxNbTx)
+			if tc.isSolved {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			assignment := invalidity.CircuitInvalidityBatch{}
+			assignment.Assign(assi)
+
+			witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+			require.NoError(t, err)
+
+			err = cs.IsSolved(witness)
+			if tc.isSolved {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestInvalidityBatchKeccakSlots checks that the keccak proof of the batch
+// must hash the transactions of the sub circuits in their order.
+func TestInvalidityBatchKeccakSlots(t *testing.T) {
+	gnarkutil.RegisterHintsAndGkrGates()
+
+	cs := compileGasLimitBatchCircuit(t)
+
+	assi := gasLimitBatchInputs("Transfer", "Calldata")
+	setBatchKeccakProof(&assi, []*types.Transaction{assi.Entries[1].Transaction, assi.Entries[0].Transaction})
+
+	assignment := invalidity.CircuitInvalidityBatch{}
+	assignment.Assign(assi)
+
+	witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+	require.NoError(t, err)
+	require.Error(t, cs.IsSolved(witness))
+}
+
+// TestCheckBatchConsistency checks that the batches which can not be proven
+// by [invalidity.CircuitInvalidityBatch] are rejected natively.
+func TestCheckBatchConsistency(t *testing.T) {
+
+	testCases := []struct {
+		name   string
+		tamper func(assi *invalidity.BatchAssigningInputs)
+	}{
+		{
+			name:   "TooManyTransactions",
+			tamper: func(assi *invalidity.BatchAssigningInputs) { assi.MaxNbTx = 1 },
+		},
+		{
+			name: "NonConsecutiveTxNumbers",
+			tamper: func(assi *invalidity.BatchAssigningInputs) {
+				assi.Entries[1].FuncInputs.TxNumber++
+			},
+		},
+		{
+			name: "DifferentStateRoot",
+			tamper: func(assi *invalidity.BatchAssigningInputs) {
+				assi.Entries[1].FuncInputs.StateRootHash = linTypes.KoalaOctuplet(field.RandomOctuplet())
+			},
+		},
+		{
+			name: "DifferentCircuits",
+			tamper: func(assi *invalidity.BatchAssigningInputs) {
+				assi.Entries[1].InvalidityType = invalidity.BadNonce
+			},
+		},
+		{
+			name: "FilteredAddress",
+			tamper: func(assi *invalidity.BatchAssigningInputs) {
+				for i := range assi.Entries {
+					assi.Entries[i].InvalidityType = invalidity.FilteredAddressTo
+				}
+			},
+		},
+		{
+			name: "DeadlineBeforeSimulatedBlock",
+			tamper: func(assi *invalidity.BatchAssigningInputs) {
+				assi.Entries[0].FuncInputs.DeadLineBlockNumber = assi.FuncInputs.SimulatedBlockNumber - 1
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assi := gasLimitBatchInputs("Transfer", "Calldata")
+			require.NoError(t, invalidity.CheckBatchConsistency(assi.Entries, assi.FuncInputs, assi.MaxNbTx))
+
+			tc.tamper(&assi)
+			require.Error(t, invalidity.CheckBatchConsistency(assi.Entries, assi.FuncInputs, assi.MaxNbTx))
+		})
+	}
+}
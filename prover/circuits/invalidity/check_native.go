@@ -15,6 +15,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/zkevm/prover/common"
 	publicInput "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput"
 	invalidityPI "github.com/consensys/linea-monorepo/prover/zkevm/prover/publicInput/invalidity_pi"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -211,6 +212,70 @@ func CheckOnlyNativeFilteredAddress(
 	return nil
 }
 
+// CheckOnlyNativeGasLimit performs native verification of the circuit
+// constraints for GasLimitTooLow / GasLimitTooHigh invalidity types.
+//
+// The keccak wizard constraints are already validated by the dummy compiler.
+// This checks:
+//   - keccak256(rlpEncodedTx) == funcInputs.TxHash
+//   - The gas limit from the RLP matches the transaction's gas limit
+//   - ToAddress matches the transaction's To field (zero for a creation)
+//   - gasLimit < max(intrinsicGas, floorDataGas) (type 6) or
+//     gasLimit > blockGasLimit (type 7)
+func CheckOnlyNativeGasLimit(
+	assi AssigningInputs,
+) error {
+	rlp := assi.RlpEncodedTx
+
+	// --- Keccak hash check ---
+	computedHash := crypto.Keccak256(rlp)
+	expectedHash := assi.FuncInputs.TxHash
+	if !bytes.Equal(computedHash, expectedHash[:]) {
+		return fmt.Errorf("keccak hash mismatch: computed=%x, expected=%x", computedHash, expectedHash[:])
+	}
+
+	// --- Gas limit from RLP ---
+	if len(rlp) == 0 || (rlp[0] != ethtypes.DynamicFeeTxType && rlp[0] != ethtypes.SetCodeTxType) {
+		return fmt.Errorf("unsupported transaction type: only types 0x02 and 0x04 are supported")
+	}
+	offset, err := parseRLPList(rlp[1:])
+	if err != nil {
+		return fmt.Errorf("could not parse RLP list: %w", err)
+	}
+	gasBytes, err := extractRLPField(rlp[1:], offset, 4)
+	if err != nil {
+		return fmt.Errorf("could not extract gas limit from RLP: %w", err)
+	}
+	gasLimit := bytesToUint64(gasBytes)
+	if gasLimit != assi.Transaction.Gas() {
+		return fmt.Errorf("gas limit mismatch: rlp=%d, tx=%d", gasLimit, assi.Transaction.Gas())
+	}
+
+	// --- To address ---
+	var toAddr types.EthAddress
+	if txTo := assi.Transaction.To(); txTo != nil {
+		toAddr = types.EthAddress(*txTo)
+	}
+	if toAddr != assi.FuncInputs.ToAddress {
+		return fmt.Errorf("to address mismatch: tx=%x, funcInputs=%x", toAddr[:], assi.FuncInputs.ToAddress[:])
+	}
+
+	switch assi.InvalidityType {
+	case GasLimitTooLow:
+		if minGas := MinimumGas(assi.Transaction); gasLimit >= minGas {
+			return fmt.Errorf("gas limit is not too low: gasLimit=%d >= minGas=%d", gasLimit, minGas)
+		}
+	case GasLimitTooHigh:
+		if gasLimit <= assi.BlockGasLimit {
+			return fmt.Errorf("gas limit is not too high: gasLimit=%d <= blockGasLimit=%d", gasLimit, assi.BlockGasLimit)
+		}
+	default:
+		return fmt.Errorf("unsupported invalidity type for gas-limit native check: %s", assi.InvalidityType)
+	}
+
+	return nil
+}
+
 // verifyAccountTrie verifies the account trie membership / non-membership
 // proof natively using Poseidon2 over KoalaBear.
 func verifyAccountTrie(ati AccountTrieInputs, fromAddress types.EthAddress) error {
@@ -0,0 +1,300 @@
+package invalidity
+
+import (
+	"bytes"
+
+	"github.com/consensys/gnark/frontend"
+	wizardk "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/consensys/linea-monorepo/prover/circuits/internal"
+)
+
+// GasLimitCircuit defines the circuit for gas limit invalidity proofs. It
+// proves that the gas limit of a forced transaction is either below the
+// minimum gas the transaction has to pay upfront (GasLimitTooLow) or above the
+// block gas limit (GasLimitTooHigh).
+//
+// The minimum gas is max(intrinsicGas, floorDataGas) where, following the
+// Prague rules:
+//
+//	intrinsicGas = 21000 (53000 for a contract creation)
+//	             + 16 * nonZeroDataBytes + 4 * zeroDataBytes
+//	             + 2 * ceil(len(data) / 32)      (contract creation only, EIP-3860)
+//	             + 2400 * accessListAddresses + 1900 * accessListStorageKeys
+//	             + 25000 * authorizations         (EIP-7702)
+//	floorDataGas = 21000 + 10 * (zeroDataBytes + 4 * nonZeroDataBytes)   (EIP-7623)
+//
+// All the terms are computed in-circuit from the RLP encoding of the
+// transaction, which is bound to TxHash through the keccak verifier.
+// Dynamic-fee (0x02) and set-code (0x04) transactions are supported.
+type GasLimitCircuit struct {
+	// RLP-encoded payload prefixed with the type byte: txType || rlp(tx.inner)
+	RLPEncodedTx []frontend.Variable
+	// Sender address
+	TxFromAddress frontend.Variable
+	// To address extracted from the RLP, zero for a contract creation
+	TxToAddress frontend.Variable
+	// Hash of the transaction (split into two 16-byte chunks)
+	TxHash [2]frontend.Variable
+	// Keccak verifier circuit
+	KeccakH wizardk.VerifierCircuit
+	// Invalidity type: 6 = GasLimitTooLow, 7 = GasLimitTooHigh
+	InvalidityType frontend.Variable
+
+	// Execution PI fields (unconstrained by subcircuit, flow into public input hash)
+	StateRootHash         [2]frontend.Variable
+	CoinBase              frontend.Variable
+	BaseFee               frontend.Variable
+	ChainID               frontend.Variable
+	L2MessageServiceAddr  frontend.Variable
+	InitialBlockTimestamp frontend.Variable
+	InitialBlockNumber    frontend.Variable
+
+	// BlockGasLimit is the gas limit of a block, it is a constant of the circuit.
+	BlockGasLimit uint64 `gnark:"-"`
+
+	// sharedKeccak is set when the keccak verification is done by the batch
+	// circuit, KeccakH is then left empty.
+	sharedKeccak bool
+
+	api frontend.API
+}
+
+// Define represents the constraints relevant to [GasLimitCircuit]
+func (c *GasLimitCircuit) Define(api frontend.API) error {
+	c.api = api
+
+	rawTx := c.RLPEncodedTx
+
+	// ========== TRANSACTION TYPE ==========
+	// Only dynamic-fee (0x02) and set-code (0x04) transactions are supported
+	txType := rawTx[0]
+	api.AssertIsEqual(api.Mul(api.Sub(txType, 2), api.Sub(txType, 4)), 0)
+	isSetCode := api.IsZero(api.Sub(txType, 4))
+
+	// ========== TOP-LEVEL FIELDS ==========
+	// [chainId(0), nonce(1), maxPriorityFeePerGas(2), maxFeePerGas(3), gasLimit(4),
+	//  to(5), value(6), data(7), accessList(8), authorizationList(9, set-code only)]
+	offset := getRLPListDataOffsetZk(api, rawTx)
+	for fieldIdx := 0; fieldIdx < 4; fieldIdx++ {
+		offset = skipRLPFieldZk(api, rawTx, offset)
+	}
+
+	gasLimit := extractRLPFieldValueZk(api, rawTx, offset)
+	offset = skipRLPFieldZk(api, rawTx, offset)
+
+	// An empty "to" field (0x80) denotes a contract creation
+	isCreate := api.IsZero(api.Sub(getValueAtOffset(api, rawTx, offset), 0x80))
+	api.AssertIsEqual(extractRLPFieldValueZk(api, rawTx, offset), c.TxToAddress)
+	offset = skipRLPFieldZk(api, rawTx, offset)
+
+	// skip the value
+	offset = skipRLPFieldZk(api, rawTx, offset)
+
+	// The data may be longer than 55 bytes, so the generic item decoder is
+	// used from here on.
+	dataStart, dataEnd := decodeRLPItemZk(api, rawTx, offset)
+	accessListStart, accessListEnd := decodeRLPItemZk(api, rawTx, dataEnd)
+	authStart, authEnd := decodeRLPItemZk(api, rawTx, accessListEnd)
+	authStart = api.Select(isSetCode, authStart, accessListEnd)
+	authEnd = api.Select(isSetCode, authEnd, accessListEnd)
+
+	// ========== LINEAR PASS OVER THE ENCODING ==========
+	var (
+		inData     = frontend.Variable(0)
+		nbZero     = frontend.Variable(0)
+		nbNonZero  = frontend.Variable(0)
+		nbWords    = frontend.Variable(0)
+		wordPos    = frontend.Variable(0)
+		accessList = newRLPWalker(accessListStart, accessListEnd)
+		authList   = newRLPWalker(authStart, authEnd)
+	)
+
+	for i := range rawTx {
+		b := rawTx[i]
+		bits := api.ToBinary(b, 8)
+
+		// calldata bytes
+		inData = updateRegionFlag(api, inData, i, dataStart, dataEnd)
+		isZeroByte := api.Mul(inData, api.IsZero(b))
+		nbZero = api.Add(nbZero, isZeroByte)
+		nbNonZero = api.Add(nbNonZero, api.Sub(inData, isZeroByte))
+
+		// 32-byte words of calldata, wordPos is the position in the current word
+		nbWords = api.Add(nbWords, api.Mul(inData, api.IsZero(wordPos)))
+		nextWordPos := api.Select(api.IsZero(api.Sub(wordPos, 31)), 0, api.Add(wordPos, 1))
+		wordPos = api.Mul(inData, nextWordPos)
+
+		// access list and authorization list items
+		accessList.step(api, i, b, bits)
+		authList.step(api, i, b, bits)
+	}
+
+	accessList.finalize(api)
+	authList.finalize(api)
+
+	// ========== MINIMUM GAS ==========
+	intrinsicGas := api.Add(
+		params.TxGas,
+		api.Mul(isCreate, params.TxGasContractCreation-params.TxGas),
+		api.Mul(nbNonZero, params.TxDataNonZeroGasEIP2028),
+		api.Mul(nbZero, params.TxDataZeroGas),
+		api.Mul(isCreate, nbWords, params.InitCodeWordGas),
+		api.Mul(accessList.nbAddresses, params.TxAccessListAddressGas),
+		api.Mul(accessList.nbStorageKeys, params.TxAccessListStorageKeyGas),
+		api.Mul(authList.nbLists, params.CallNewAccountGas),
+	)
+	floorDataGas := api.Add(
+		params.TxGas,
+		api.Mul(params.TxCostFloorPerToken, api.Add(nbZero, api.Mul(nbNonZero, params.TxTokenPerNonZeroByte))),
+	)
+	minGas := api.Select(isLessThan(api, intrinsicGas, floorDataGas), floorDataGas, intrinsicGas)
+
+	// ========== GAS LIMIT CHECK ==========
+	// 0 = GasLimitTooLow, 1 = GasLimitTooHigh
+	binaryType := api.Sub(c.InvalidityType, int(GasLimitTooLow))
+	api.AssertIsBoolean(binaryType)
+
+	tooLow := isLessThan(api, gasLimit, minGas)
+	tooHigh := isGreaterThan(api, gasLimit, c.BlockGasLimit)
+	api.AssertIsEqual(api.Select(binaryType, tooHigh, tooLow), 1)
+
+	// ========== KECCAK VERIFICATION ==========
+	// Verify TxHash matches the keccak hash of the RLP-encoded transaction
+	if !c.sharedKeccak {
+		CheckKeccakConsistency(api, rawTx, c.TxHash, &c.KeccakH)
+		c.KeccakH.Verify(api)
+	}
+
+	return nil
+}
+
+// Allocate the circuit
+func (c *GasLimitCircuit) Allocate(config Config) {
+	if config.BlockGasLimit == 0 {
+		utils.Panic("the block gas limit must be set for the gas limit invalidity circuit")
+	}
+	c.BlockGasLimit = config.BlockGasLimit
+	if !c.sharedKeccak {
+		c.KeccakH = *wizardk.AllocateWizardCircuit(config.KeccakCompiledIOP, 0)
+	}
+	c.RLPEncodedTx = make([]frontend.Variable, config.MaxRlpByteSize)
+}
+
+// Assign the circuit from [AssigningInputs]
+func (c *GasLimitCircuit) Assign(assi AssigningInputs) {
+	txHash := crypto.Keccak256(assi.RlpEncodedTx)
+
+	c.TxFromAddress = assi.FromAddress[:]
+	c.TxHash[0] = txHash[0:LIMB_SIZE]
+	c.TxHash[1] = txHash[LIMB_SIZE:]
+
+	c.TxToAddress = 0
+	if to := assi.Transaction.To(); to != nil {
+		c.TxToAddress = to[:]
+	}
+
+	txType := assi.RlpEncodedTx[0]
+	if txType != types.DynamicFeeTxType && txType != types.SetCodeTxType {
+		utils.Panic("only support typed 2 and 4 transactions, maybe the rlp is not prefixed with the type byte")
+	}
+
+	gasLimit := assi.Transaction.Gas()
+	switch assi.InvalidityType {
+	case GasLimitTooLow:
+		if minGas := MinimumGas(assi.Transaction); gasLimit >= minGas {
+			utils.Panic("tried to generate a gas-limit-too-low invalidity proof but gasLimit=%d >= minGas=%d", gasLimit, minGas)
+		}
+	case GasLimitTooHigh:
+		if gasLimit <= assi.BlockGasLimit {
+			utils.Panic("tried to generate a gas-limit-too-high invalidity proof but gasLimit=%d <= blockGasLimit=%d", gasLimit, assi.BlockGasLimit)
+		}
+	default:
+		utils.Panic("expected invalidity type GasLimitTooLow or GasLimitTooHigh but received %v", assi.InvalidityType)
+	}
+	c.InvalidityType = int(assi.InvalidityType)
+
+	// Assign RLP encoding
+	c.RLPEncodedTx = make([]frontend.Variable, assi.MaxRlpByteSize)
+
+	elements := internal.FromBytesToElements(assi.RlpEncodedTx)
+	rlpLen := len(elements)
+	if rlpLen > assi.MaxRlpByteSize {
+		utils.Panic("rlp encoding is too large: got %d, max %d", rlpLen, assi.MaxRlpByteSize)
+	}
+
+	copy(c.RLPEncodedTx, elements)
+	for i := len(elements); i < assi.MaxRlpByteSize; i++ {
+		c.RLPEncodedTx[i] = 0
+	}
+
+	if !c.sharedKeccak {
+		c.KeccakH = *wizardk.AssignVerifierCircuit(assi.KeccakCompiledIOP, assi.KeccakProof, 0)
+	}
+	rootBytes := assi.FuncInputs.StateRootHash.ToBytes()
+	c.StateRootHash[0] = rootBytes[:16]
+	c.StateRootHash[1] = rootBytes[16:]
+
+	c.CoinBase = assi.FuncInputs.CoinBase[:]
+	c.BaseFee = assi.FuncInputs.BaseFee
+	c.ChainID = assi.FuncInputs.ChainID
+	c.L2MessageServiceAddr = assi.FuncInputs.L2MessageServiceAddr[:]
+	c.InitialBlockTimestamp = assi.FuncInputs.SimulatedBlockTimestamp
+	c.InitialBlockNumber = assi.FuncInputs.SimulatedBlockNumber
+}
+
+// FunctionalPIQGnark returns the subcircuit-derived functional public inputs
+func (c *GasLimitCircuit) FunctionalPIQGnark() FunctionalPIQGnark {
+	return FunctionalPIQGnark{
+		TxHash:                  c.TxHash,
+		FromAddress:             c.TxFromAddress,
+		StateRootHash:           c.StateRootHash,
+		ToAddress:               c.TxToAddress,
+		ToIsFiltered:            0,
+		FromIsFiltered:          0,
+		CoinBase:                c.CoinBase,
+		BaseFee:                 c.BaseFee,
+		ChainID:                 c.ChainID,
+		L2MessageServiceAddr:    c.L2MessageServiceAddr,
+		SimulatedBlockTimestamp: c.InitialBlockTimestamp,
+		SimulatedBlockNumber:    c.InitialBlockNumber,
+	}
+}
+
+func (c *GasLimitCircuit) shareKeccak() { c.sharedKeccak = true }
+
+func (c *GasLimitCircuit) keccakInputs() ([]frontend.Variable, [2]frontend.Variable) {
+	return c.RLPEncodedTx, c.TxHash
+}
+
+// MinimumGas returns the minimum gas limit a transaction must have to be
+// included in a block: the maximum of its intrinsic gas and of its EIP-7623
+// floor data gas. It mirrors the computation done by [GasLimitCircuit].
+func MinimumGas(tx *types.Transaction) uint64 {
+	var (
+		data     = tx.Data()
+		isCreate = tx.To() == nil
+		z        = uint64(bytes.Count(data, []byte{0}))
+		nz       = uint64(len(data)) - z
+	)
+
+	gas := params.TxGas
+	if isCreate {
+		gas = params.TxGasContractCreation
+		gas += (uint64(len(data)) + 31) / 32 * params.InitCodeWordGas
+	}
+	gas += nz*params.TxDataNonZeroGasEIP2028 + z*params.TxDataZeroGas
+
+	accessList := tx.AccessList()
+	gas += uint64(len(accessList)) * params.TxAccessListAddressGas
+	gas += uint64(accessList.StorageKeys()) * params.TxAccessListStorageKeyGas
+	gas += uint64(len(tx.SetCodeAuthorizations())) * params.CallNewAccountGas
+
+	floorDataGas := params.TxGas + (z+nz*params.TxTokenPerNonZeroByte)*params.TxCostFloorPerToken
+
+	return max(gas, floorDataGas)
+}
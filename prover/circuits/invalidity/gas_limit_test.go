@@ -0,0 +1,287 @@
+package invalidity_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/linea-monorepo/prover/backend/ethereum"
+	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	linTypes "github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gasLimitMaxRlpByteSize = 1024
+	gasLimitBlockGasLimit  = 30_000_000
+)
+
+var (
+	gasLimitToAddr   = common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc9e7595f2bD50")
+	gasLimitFromAddr = common.HexToAddress("0x00aed6")
+)
+
+// gasLimitTestTxs returns transactions exercising every term of the minimum
+// gas: calldata (short, long, zero and non-zero bytes), access list, contract
+// creation and authorization list. The gas limit of the transactions is left
+// to zero and set by the caller.
+func gasLimitTestTxs() map[string]func(gas uint64) types.TxData {
+
+	calldata := make([]byte, 100)
+	for i := range calldata {
+		if i%5 != 0 {
+			calldata[i] = byte(i)
+		}
+	}
+
+	largeCalldata := make([]byte, 600)
+	for i := range largeCalldata {
+		largeCalldata[i] = 0xff
+	}
+
+	accessList := types.AccessList{
+		{Address: common.HexToAddress("0x01"), StorageKeys: []common.Hash{{1}, {2}}},
+		{Address: common.HexToAddress("0x02"), StorageKeys: nil},
+		{Address: common.HexToAddress("0x03"), StorageKeys: []common.Hash{{3}}},
+	}
+
+	authList := []types.SetCodeAuthorization{
+		{ChainID: *uint256.NewInt(59144), Address: common.Address{1, 2}, Nonce: 3, V: 1, R: uint256.Int{5}, S: uint256.Int{6}},
+		{ChainID: *uint256.NewInt(0), Address: common.Address{3, 4}, Nonce: 0, V: 0, R: uint256.Int{7, 8}, S: uint256.Int{9, 10}},
+	}
+
+	dynFee := func(to *common.Address, data []byte, al types.AccessList) func(uint64) types.TxData {
+		return func(gas uint64) types.TxData {
+			return &types.DynamicFeeTx{
+				ChainID:    big.NewInt(59144),
+				Nonce:      1,
+				GasTipCap:  big.NewInt(1000000000),
+				GasFeeCap:  big.NewInt(100000000000),
+				Gas:        gas,
+				To:         to,
+				Value:      big.NewInt(1000),
+				Data:       data,
+				AccessList: al,
+			}
+		}
+	}
+
+	return map[string]func(uint64) types.TxData{
+		"Transfer":         dynFee(&gasLimitToAddr, nil, nil),
+		"SingleByte":       dynFee(&gasLimitToAddr, []byte{0x01}, nil),
+		"Calldata":         dynFee(&gasLimitToAddr, calldata, nil),
+		"FloorDataGas":     dynFee(&gasLimitToAddr, largeCalldata, nil),
+		"AccessList":       dynFee(&gasLimitToAddr, calldata[:10], accessList),
+		"ContractCreation": dynFee(nil, calldata[:70], accessList[:1]),
+		"SetCode": func(gas uint64) types.TxData {
+			return &types.SetCodeTx{
+				ChainID:    uint256.NewInt(59144),
+				Nonce:      1,
+				GasTipCap:  uint256.NewInt(1000000000),
+				GasFeeCap:  uint256.NewInt(100000000000),
+				Gas:        gas,
+				To:         gasLimitToAddr,
+				Value:      uint256.NewInt(1000),
+				Data:       calldata[:20],
+				AccessList: accessList[:2],
+				AuthList:   authList,
+			}
+		},
+	}
+}
+
+// compileGasLimitCircuit compiles the gas limit circuit once for all the test
+// cases.
+func compileGasLimitCircuit(t *testing.T) constraint.ConstraintSystem {
+	t.Helper()
+
+	circuit := invalidity.CircuitInvalidity{
+		SubCircuit: &invalidity.GasLimitCircuit{},
+	}
+	circuit.Allocate(invalidity.Config{
+		KeccakCompiledIOP: invalidity.MakeKeccakCompiledIOP(gasLimitMaxRlpByteSize, dummy.Compile),
+		MaxRlpByteSize:    gasLimitMaxRlpByteSize,
+		BlockGasLimit:     gasLimitBlockGasLimit,
+	})
+
+	cs, err := frontend.Compile(
+		ecc.BLS12_377.ScalarField(),
+		scs.NewBuilder,
+		&circuit,
+	)
+	require.NoError(t, err)
+	return cs
+}
+
+// gasLimitAssigningInputs returns the assigning inputs of the gas limit
+// circuit for the given transaction.
+func gasLimitAssigningInputs(tx *types.Transaction, invalidityType invalidity.InvalidityType, blockGasLimit uint64) invalidity.AssigningInputs {
+
+	var toAddress linTypes.EthAddress
+	if to := tx.To(); to != nil {
+		toAddress = linTypes.EthAddress(*to)
+	}
+
+	assi := invalidity.AssigningInputs{
+		Transaction:    tx,
+		FromAddress:    gasLimitFromAddr,
+		MaxRlpByteSize: gasLimitMaxRlpByteSize,
+		InvalidityType: invalidityType,
+		RlpEncodedTx:   ethereum.EncodeTxForSigning(tx),
+		BlockGasLimit:  blockGasLimit,
+		FuncInputs: public_input.Invalidity{
+			TxHash:                  ethereum.GetTxHash(tx),
+			FromAddress:             linTypes.EthAddress(gasLimitFromAddr),
+			StateRootHash:           linTypes.KoalaOctuplet(field.RandomOctuplet()),
+			ToAddress:               toAddress,
+			CoinBase:                linTypes.DummyAddress(32),
+			BaseFee:                 1000000000,
+			ChainID:                 59144,
+			L2MessageServiceAddr:    linTypes.DummyAddress(32),
+			SimulatedBlockTimestamp: 1000000000,
+			SimulatedBlockNumber:    1000000000,
+		},
+	}
+
+	assi.KeccakCompiledIOP, assi.KeccakProof = invalidity.MakeKeccakProofs(tx, gasLimitMaxRlpByteSize, dummy.Compile)
+	return assi
+}
+
+// TestGasLimitCircuit tests the GasLimitCircuit through the full
+// CircuitInvalidity wrapper. For each transaction, a gas limit one unit below
+// the minimum gas must be accepted as GasLimitTooLow while a gas limit equal to
+// the minimum gas must be rejected, which checks that the minimum gas computed
+// in-circuit matches [invalidity.MinimumGas] exactly.
+func TestGasLimitCircuit(t *testing.T) {
+
+	cs := compileGasLimitCircuit(t)
+
+	for name, txData := range gasLimitTestTxs() {
+		t.Run(name, func(t *testing.T) {
+
+			minGas := invalidity.MinimumGas(types.NewTx(txData(0)))
+
+			// gas limit too low
+			tx := types.NewTx(txData(minGas - 1))
+			assi := gasLimitAssigningInputs(tx, invalidity.GasLimitTooLow, gasLimitBlockGasLimit)
+			require.NoError(t, invalidity.CheckOnlyNativeGasLimit(assi))
+
+			assignment := invalidity.CircuitInvalidity{
+				SubCircuit: &invalidity.GasLimitCircuit{},
+			}
+			assignment.Assign(assi)
+
+			witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+			require.NoError(t, err)
+			require.NoError(t, cs.IsSolved(witness))
+
+			// gas limit equal to the minimum gas. The assignment is made as a
+			// GasLimitTooHigh proof with a low block gas limit to pass the
+			// native checks, then the type is switched to GasLimitTooLow.
+			tx = types.NewTx(txData(minGas))
+			assi = gasLimitAssigningInputs(tx, invalidity.GasLimitTooHigh, 0)
+			assi.InvalidityType = invalidity.GasLimitTooLow
+			require.Error(t, invalidity.CheckOnlyNativeGasLimit(assi))
+			assi.InvalidityType = invalidity.GasLimitTooHigh
+
+			sub := &invalidity.GasLimitCircuit{}
+			assignment = invalidity.CircuitInvalidity{SubCircuit: sub}
+			assignment.Assign(assi)
+			sub.InvalidityType = int(invalidity.GasLimitTooLow)
+
+			witness, err = frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+			require.NoError(t, err)
+			require.Error(t, cs.IsSolved(witness))
+		})
+	}
+}
+
+// TestGasLimitCircuitTooHigh tests the GasLimitTooHigh case of the
+// GasLimitCircuit against the block gas limit of the circuit.
+func TestGasLimitCircuitTooHigh(t *testing.T) {
+
+	cs := compileGasLimitCircuit(t)
+	txData := gasLimitTestTxs()["Calldata"]
+
+	testCases := []struct {
+		name     string
+		gas      uint64
+		isSolved bool
+	}{
+		{name: "AboveBlockGasLimit", gas: gasLimitBlockGasLimit + 1, isSolved: true},
+		{name: "EqualToBlockGasLimit", gas: gasLimitBlockGasLimit, isSolved: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			tx := types.NewTx(txData(tc.gas))
+
+			// The block gas limit of the assigning inputs is only used by the
+			// native checks, the circuit uses the one given at compile time.
+			assi := gasLimitAssigningInputs(tx, invalidity.GasLimitTooHigh, gasLimitBlockGasLimit)
+			if !tc.isSolved {
+				require.Error(t, invalidity.CheckOnlyNativeGasLimit(assi))
+				assi.BlockGasLimit = 0
+			}
+			require.NoError(t, invalidity.CheckOnlyNativeGasLimit(assi))
+
+			assignment := invalidity.CircuitInvalidity{
+				SubCircuit: &invalidity.GasLimitCircuit{},
+			}
+			assignment.Assign(assi)
+
+			witness, err := frontend.NewWitness(&assignment, ecc.BLS12_377.ScalarField())
+			require.NoError(t, err)
+
+			err = cs.IsSolved(witness)
+			if tc.isSolved {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestMinimumGas checks [invalidity.MinimumGas] against hand-computed values.
+func TestMinimumGas(t *testing.T) {
+
+	txs := gasLimitTestTxs()
+
+	testCases := []struct {
+		name     string
+		expected uint64
+	}{
+		// 21000
+		{name: "Transfer", expected: 21000},
+		// 21000 + 16 = 21016, floor = 21000 + 10*4 = 21040
+		{name: "SingleByte", expected: 21040},
+		// 21000 + 80*16 + 20*4 = 22360, floor = 21000 + 10*(20 + 80*4) = 24400
+		{name: "Calldata", expected: 24400},
+		// 21000 + 600*16 = 30600, floor = 21000 + 10*600*4 = 45000
+		{name: "FloorDataGas", expected: 45000},
+		// 21000 + 8*16 + 2*4 + 3*2400 + 3*1900 = 34036
+		{name: "AccessList", expected: 34036},
+		// 53000 + 56*16 + 14*4 + 3*2 + 2400 + 2*1900 = 60158
+		{name: "ContractCreation", expected: 60158},
+		// 21000 + 16*16 + 4*4 + 2*2400 + 2*1900 + 2*25000 = 79872
+		{name: "SetCode", expected: 79872},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx := types.NewTx(txs[tc.name](0))
+			require.Equal(t, tc.expected, invalidity.MinimumGas(tx))
+		})
+	}
+}
@@ -58,6 +58,9 @@ type AssigningInputs struct {
 	ZkEvmWizardProof wizard.Proof
 	MaxL2Logs        int
 
+	// BlockGasLimit is the gas limit of a block, used for GasLimitTooHigh
+	BlockGasLimit uint64
+
 	// CachedProofPath, if set, enables proof caching: on first run the proof
 	// is written to this path; on subsequent runs with the same witness the
 	// cached proof is loaded and verified instead of re-proving.
@@ -85,30 +88,12 @@ func (c *CircuitInvalidity) Define(api frontend.API) error {
 
 // Allocate the circuit
 func (c *CircuitInvalidity) Allocate(config Config) {
-	switch sub := c.SubCircuit.(type) {
-	case *BadNonceBalanceCircuit:
-		sub.Allocate(config)
-	case *FilteredAddressCircuit:
-		sub.Allocate(config)
-	case *BadPrecompileCircuit:
-		sub.Allocate(config)
-	default:
-		panic(fmt.Sprintf("unsupported subcircuit type: %T", c.SubCircuit))
-	}
+	allocateSubCircuit(c.SubCircuit, config)
 }
 
 // Assign the circuit
 func (c *CircuitInvalidity) Assign(assi AssigningInputs) {
-	switch sub := c.SubCircuit.(type) {
-	case *BadNonceBalanceCircuit:
-		sub.Assign(assi)
-	case *FilteredAddressCircuit:
-		sub.Assign(assi)
-	case *BadPrecompileCircuit:
-		sub.Assign(assi)
-	default:
-		panic(fmt.Sprintf("unsupported subcircuit type: %T", c.SubCircuit))
-	}
+	assignSubCircuit(c.SubCircuit, assi)
 	// assign the Functional Public Inputs
 	c.FuncInputs.Assign(assi.FuncInputs)
 	// assign the public input
@@ -121,30 +106,32 @@ func (c *CircuitInvalidity) MakeProof(
 	assi AssigningInputs,
 ) string {
 
-	switch assi.InvalidityType {
-	case BadNonce, BadBalance:
-		c.SubCircuit = &BadNonceBalanceCircuit{}
-	case BadPrecompile, TooManyLogs:
-		c.SubCircuit = &BadPrecompileCircuit{}
-	case FilteredAddressFrom, FilteredAddressTo:
-		c.SubCircuit = &FilteredAddressCircuit{}
-	default:
-		panic("unsupported invalidity type")
-	}
-
+	c.SubCircuit = NewSubCircuit(assi.InvalidityType)
 	c.Assign(assi)
 
+	return proveAndSerialize(setup, c, assi.CachedProofPath, assi.FuncInputs.Sum(nil))
+}
+
+// proveAndSerialize generates and checks the proof of the assigned circuit
+// and returns it serialized. publicInput is only used for logging.
+func proveAndSerialize(
+	setup circuits.Setup,
+	assignment frontend.Circuit,
+	cachedProofPath string,
+	publicInput []byte,
+) string {
+
 	opts := []any{
 		emPlonk.GetNativeProverOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
 		emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), ecc.BLS12_377.ScalarField()),
 	}
-	if assi.CachedProofPath != "" {
-		opts = append(opts, circuits.WithCachedProof(assi.CachedProofPath))
+	if cachedProofPath != "" {
+		opts = append(opts, circuits.WithCachedProof(cachedProofPath))
 	}
 
 	proof, err := circuits.ProveCheck(
 		&setup,
-		c,
+		assignment,
 		opts...,
 	)
 
@@ -152,17 +139,66 @@ func (c *CircuitInvalidity) MakeProof(
 		panic(err)
 	}
 
-	// Do not read c.PublicInput after ProveCheck: gnark may replace it with []uint8 in the assignment.
+	// Do not read the public input of the assignment after ProveCheck: gnark may replace it with []uint8.
 	serialized := circuits.SerializeProofRaw(proof)
 	proofByteLen := (len(serialized) - 2) / 2 // hexutil.Encode: "0x" + 2 hex chars per byte
 	if proofByteLen < 0 {
 		proofByteLen = 0
 	}
-	logrus.Infof("generated invalidity circuit proof (%d bytes) for public input 0x%x", proofByteLen, assi.FuncInputs.Sum(nil))
+	logrus.Infof("generated invalidity circuit proof (%d bytes) for public input 0x%x", proofByteLen, publicInput)
 
 	return serialized
 }
 
+// NewSubCircuit returns an empty sub circuit handling the given invalidity
+// type.
+func NewSubCircuit(invalidityType InvalidityType) SubCircuit {
+	switch invalidityType {
+	case BadNonce, BadBalance:
+		return &BadNonceBalanceCircuit{}
+	case BadPrecompile, TooManyLogs:
+		return &BadPrecompileCircuit{}
+	case FilteredAddressFrom, FilteredAddressTo:
+		return &FilteredAddressCircuit{}
+	case GasLimitTooLow, GasLimitTooHigh:
+		return &GasLimitCircuit{}
+	default:
+		panic("unsupported invalidity type")
+	}
+}
+
+// allocateSubCircuit allocates the sub circuit according to its concrete type
+func allocateSubCircuit(sub SubCircuit, config Config) {
+	switch sub := sub.(type) {
+	case *BadNonceBalanceCircuit:
+		sub.Allocate(config)
+	case *FilteredAddressCircuit:
+		sub.Allocate(config)
+	case *BadPrecompileCircuit:
+		sub.Allocate(config)
+	case *GasLimitCircuit:
+		sub.Allocate(config)
+	default:
+		panic(fmt.Sprintf("unsupported subcircuit type: %T", sub))
+	}
+}
+
+// assignSubCircuit assigns the sub circuit according to its concrete type
+func assignSubCircuit(sub SubCircuit, assi AssigningInputs) {
+	switch sub := sub.(type) {
+	case *BadNonceBalanceCircuit:
+		sub.Assign(assi)
+	case *FilteredAddressCircuit:
+		sub.Assign(assi)
+	case *BadPrecompileCircuit:
+		sub.Assign(assi)
+	case *GasLimitCircuit:
+		sub.Assign(assi)
+	default:
+		panic(fmt.Sprintf("unsupported subcircuit type: %T", sub))
+	}
+}
+
 // CheckOnly verifies the constraint system is satisfied without generating a
 // real proof. Used in partial mode.
 //
@@ -176,6 +212,9 @@ func (c *CircuitInvalidity) MakeProof(
 //   - FilteredAddressFrom/FilteredAddressTo: keccak wizard is validated by
 //     dummy compiler; RLP to-address extraction and filtered flag assertions
 //     are checked natively.
+//   - GasLimitTooLow/GasLimitTooHigh: keccak wizard is validated by dummy
+//     compiler; the gas limit is compared natively against the minimum gas of
+//     the transaction or the block gas limit.
 func (c *CircuitInvalidity) CheckOnly(assi AssigningInputs) error {
 	t0 := time.Now()
 	var err error
@@ -193,6 +232,9 @@ func (c *CircuitInvalidity) CheckOnly(assi AssigningInputs) error {
 	case FilteredAddressFrom, FilteredAddressTo:
 		err = CheckOnlyNativeFilteredAddress(assi)
 
+	case GasLimitTooLow, GasLimitTooHigh:
+		err = CheckOnlyNativeGasLimit(assi)
+
 	default:
 		return fmt.Errorf("unsupported invalidity type: %d", assi.InvalidityType)
 	}
@@ -211,6 +253,9 @@ type Config struct {
 	// MaxL2Logs is the maximum number of L2->L1 logs allowed.
 	// Used by the BadPrecompile/TooManyLogs circuit.
 	MaxL2Logs int
+	// BlockGasLimit is the gas limit of a block. Used by the
+	// GasLimitTooHigh circuit.
+	BlockGasLimit uint64
 }
 
 type builder struct {
@@ -284,6 +329,8 @@ const (
 	TooManyLogs         InvalidityType = 3
 	FilteredAddressFrom InvalidityType = 4
 	FilteredAddressTo   InvalidityType = 5
+	GasLimitTooLow      InvalidityType = 6
+	GasLimitTooHigh     InvalidityType = 7
 )
 
 // String returns the string representation of the InvalidityType
@@ -301,6 +348,10 @@ func (t InvalidityType) String() string {
 		return "FilteredAddressFrom"
 	case FilteredAddressTo:
 		return "FilteredAddressTo"
+	case GasLimitTooLow:
+		return "GasLimitTooLow"
+	case GasLimitTooHigh:
+		return "GasLimitTooHigh"
 	default:
 		return "Unknown"
 	}
@@ -330,6 +381,10 @@ func (t *InvalidityType) UnmarshalJSON(data []byte) error {
 		*t = FilteredAddressFrom
 	case "FilteredAddressTo":
 		*t = FilteredAddressTo
+	case "GasLimitTooLow":
+		*t = GasLimitTooLow
+	case "GasLimitTooHigh":
+		*t = GasLimitTooHigh
 	default:
 		return fmt.Errorf("unknown InvalidityType: %s", s)
 	}
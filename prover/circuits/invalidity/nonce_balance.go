@@ -49,6 +49,10 @@ type BadNonceBalanceCircuit struct {
 	InitialBlockTimestamp frontend.Variable
 	InitialBlockNumber    frontend.Variable
 
+	// sharedKeccak is set when the keccak verification is done by the batch
+	// circuit, KeccakH is then left empty.
+	sharedKeccak bool
+
 	api frontend.API
 }
 
@@ -122,8 +126,10 @@ func (circuit *BadNonceBalanceCircuit) Define(api frontend.API) error {
 	api.AssertIsEqual(expectedCost, circuit.TxCost)
 
 	// ========== KECCAK VERIFICATION  ==========
-	CheckKeccakConsistency(api, circuit.RLPEncodedTx, circuit.TxHash, &circuit.KeccakH)
-	circuit.KeccakH.Verify(api)
+	if !circuit.sharedKeccak {
+		CheckKeccakConsistency(api, circuit.RLPEncodedTx, circuit.TxHash, &circuit.KeccakH)
+		circuit.KeccakH.Verify(api)
+	}
 
 	return nil
 }
@@ -133,7 +139,9 @@ func (cir *BadNonceBalanceCircuit) Allocate(config Config) {
 	// Allocate the account trie
 	cir.AccountTrie.Allocate(config)
 	// Allocate the keccak verifier
-	cir.KeccakH = *wizardk.AllocateWizardCircuit(config.KeccakCompiledIOP, 0)
+	if !cir.sharedKeccak {
+		cir.KeccakH = *wizardk.AllocateWizardCircuit(config.KeccakCompiledIOP, 0)
+	}
 	// Allocate the RLPEncodedTx to have a fixed size
 	cir.RLPEncodedTx = make([]frontend.Variable, config.MaxRlpByteSize)
 }
@@ -146,7 +154,6 @@ func (cir *BadNonceBalanceCircuit) Assign(assi AssigningInputs) {
 		txNonce = assi.Transaction.Nonce()
 		acNonce = assi.AccountTrieInputs.Account.Nonce
 		txHash  = crypto.Keccak256(assi.RlpEncodedTx)
-		keccak  = wizardk.AssignVerifierCircuit(assi.KeccakCompiledIOP, assi.KeccakProof, 0)
 	)
 
 	cir.TxNonce = txNonce
@@ -194,7 +201,9 @@ func (cir *BadNonceBalanceCircuit) Assign(assi AssigningInputs) {
 	}
 	// Assign the account trie
 	cir.AccountTrie.Assign(assi.AccountTrieInputs)
-	cir.KeccakH = *keccak
+	if !cir.sharedKeccak {
+		cir.KeccakH = *wizardk.AssignVerifierCircuit(assi.KeccakCompiledIOP, assi.KeccakProof, 0)
+	}
 	cir.ToAddress = assi.Transaction.To()[:]
 
 	cir.ToIsFiltered = 0
@@ -225,6 +234,12 @@ func (c *BadNonceBalanceCircuit) FunctionalPIQGnark() FunctionalPIQGnark {
 	}
 }
 
+func (c *BadNonceBalanceCircuit) shareKeccak() { c.sharedKeccak = true }
+
+func (c *BadNonceBalanceCircuit) keccakInputs() ([]frontend.Variable, [2]frontend.Variable) {
+	return c.RLPEncodedTx, c.TxHash
+}
+
 // reconstructRootHash converts a Root octuplet to 2 BLS12-377 field elements
 // Combining 4 elements (16 bytes) into one BLS field element using base 2^32
 func reconstructRootHash(api frontend.API, root koalagnark.Octuplet) [2]frontend.Variable {
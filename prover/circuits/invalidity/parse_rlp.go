@@ -360,6 +360,130 @@ func skipRLPFieldZk(api frontend.API, rawTx []frontend.Variable, offset frontend
 	return api.Add(offset, fieldLen)
 }
 
+// decodeRLPItemZk decodes the header of the RLP item (string or list) starting
+// at the given offset in a ZK circuit context and returns the offsets of the
+// first byte of its payload and of the first byte following it.
+//
+// All the RLP encodings are supported:
+// - Single byte (< 0x80): the byte is its own payload, start = offset
+// - Short string (0x80-0xb7) / short list (0xc0-0xf7): start = offset + 1
+// - Long string (0xb8-0xbf) / long list (0xf8-0xff): start = offset + 1 + lengthOfLength
+//
+// The length of a long item is limited to 3 bytes, which is more than enough
+// for any transaction fitting in the circuit.
+func decodeRLPItemZk(api frontend.API, rawTx []frontend.Variable, offset frontend.Variable) (start, end frontend.Variable) {
+	firstByte := getValueAtOffset(api, rawTx, offset)
+	bits := api.ToBinary(firstByte, 8)
+
+	isSingleByte := api.Sub(1, bits[7])
+	isList := api.Mul(bits[7], bits[6])
+	// 0xb8-0xbf and 0xf8-0xff both have their bits 3 to 5 set
+	isLong := api.Mul(bits[7], bits[5], bits[4], bits[3])
+
+	// Lists are encoded with prefixes shifted by 0x40 compared to strings
+	shortLen := api.Sub(firstByte, 0x80, api.Mul(isList, 0x40))
+	lengthOfLength := api.Sub(firstByte, 0xb7, api.Mul(isList, 0x40))
+
+	// lengthOfLength must be at most 3 for long items
+	api.AssertIsEqual(
+		api.Mul(isLong, api.Sub(lengthOfLength, 1), api.Sub(lengthOfLength, 2), api.Sub(lengthOfLength, 3)),
+		0,
+	)
+
+	longLen := frontend.Variable(0)
+	for i := 1; i <= 3; i++ {
+		isWithinLen := isLessThan(api, frontend.Variable(i-1), lengthOfLength)
+		byteVal := getValueAtOffset(api, rawTx, api.Add(offset, frontend.Variable(i)))
+		newLen := api.Add(api.Mul(longLen, 256), byteVal)
+		longLen = api.Select(isWithinLen, newLen, longLen)
+	}
+
+	headerLen := api.Add(api.Sub(1, isSingleByte), api.Mul(isLong, lengthOfLength))
+	payloadLen := api.Select(isSingleByte, 1, api.Select(isLong, longLen, shortLen))
+
+	start = api.Add(offset, headerLen)
+	end = api.Add(start, payloadLen)
+	return start, end
+}
+
+// updateRegionFlag returns the flag indicating whether position i lies in
+// [start, end), given the flag of position i-1. Since start <= end, the flag
+// is raised at start and lowered at end, or left untouched if both are equal.
+func updateRegionFlag(api frontend.API, flag frontend.Variable, i int, start, end frontend.Variable) frontend.Variable {
+	return api.Sub(
+		api.Add(flag, api.IsZero(api.Sub(start, i))),
+		api.IsZero(api.Sub(end, i)),
+	)
+}
+
+// rlpWalker walks the RLP items nested in the payload [start, end) of an RLP
+// list, one byte at a time, descending into the nested lists. It is used to
+// count the items of the access list and of the authorization list of a
+// transaction:
+// - an access list is a list of [address, [storageKey, ...]] and its
+// addresses (0x94 prefix) and storage keys (0xa0 prefix) are counted,
+// - an authorization list is a list of [chainId, address, nonce, yParity, r, s]
+// tuples and the tuples are counted as lists.
+//
+// Long strings (0xb8-0xbf) never appear in these lists and are rejected.
+type rlpWalker struct {
+	start, end frontend.Variable
+	// inRegion is 1 if the current position is in [start, end)
+	inRegion frontend.Variable
+	// next is the position of the next item header
+	next frontend.Variable
+
+	nbLists       frontend.Variable
+	nbAddresses   frontend.Variable
+	nbStorageKeys frontend.Variable
+}
+
+func newRLPWalker(start, end frontend.Variable) *rlpWalker {
+	return &rlpWalker{
+		start:         start,
+		end:           end,
+		inRegion:      0,
+		next:          start,
+		nbLists:       0,
+		nbAddresses:   0,
+		nbStorageKeys: 0,
+	}
+}
+
+// step processes the byte b at position i. bits is the 8-bit decomposition of
+// b.
+func (w *rlpWalker) step(api frontend.API, i int, b frontend.Variable, bits []frontend.Variable) {
+	w.inRegion = updateRegionFlag(api, w.inRegion, i, w.start, w.end)
+	isHeader := api.Mul(w.inRegion, api.IsZero(api.Sub(w.next, i)))
+
+	isList := api.Mul(bits[7], bits[6])
+	isString := api.Sub(bits[7], isList)
+	isLong := api.Mul(bits[5], bits[4], bits[3])
+	isLongList := api.Mul(isList, isLong)
+	isLongString := api.Mul(isString, isLong)
+	isShortString := api.Sub(isString, isLongString)
+
+	api.AssertIsEqual(api.Mul(isHeader, isLongString), 0)
+
+	// Strings are skipped while lists are entered: the next header is right
+	// after the header of the list.
+	itemLen := api.Add(
+		1,
+		api.Mul(isShortString, api.Sub(b, 0x80)),
+		api.Mul(isLongList, api.Sub(b, 0xf7)),
+	)
+	w.next = api.Select(isHeader, api.Add(i, itemLen), w.next)
+
+	w.nbLists = api.Add(w.nbLists, api.Mul(isHeader, isList))
+	w.nbAddresses = api.Add(w.nbAddresses, api.Mul(isHeader, api.IsZero(api.Sub(b, 0x94))))
+	w.nbStorageKeys = api.Add(w.nbStorageKeys, api.Mul(isHeader, api.IsZero(api.Sub(b, 0xa0))))
+}
+
+// finalize asserts that the walk ended exactly at the end of the region.
+func (w *rlpWalker) finalize(api frontend.API) {
+	api.AssertIsEqual(w.next, w.end)
+}
+
 // isGreaterThan returns 1 if a > b, 0 otherwise
 // Uses api.Cmp which returns -1 if a < b, 0 if a == b, 1 if a > b
 func isGreaterThan(api frontend.API, a, b frontend.Variable) frontend.Variable {
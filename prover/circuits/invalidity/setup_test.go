@@ -87,6 +87,28 @@ func TestSetupRoundTripFilteredAddress(t *testing.T) {
 	verifySetupRoundTrip(t, builder, circuits.InvalidityFilteredAddressCircuitID)
 }
 
+// TestSetupRoundTripGasLimit verifies the same round-trip for the
+// invalidity-gas-limit circuit.
+func TestSetupRoundTripGasLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping setup round-trip test in short mode")
+	}
+
+	const maxRlpByteSize = 1023
+
+	keccakComp := invalidity.MakeKeccakCompiledIOP(maxRlpByteSize, keccakDummy.Compile)
+	builder := invalidity.NewBuilder(
+		invalidity.Config{
+			KeccakCompiledIOP: keccakComp,
+			MaxRlpByteSize:    maxRlpByteSize,
+			BlockGasLimit:     30_000_000,
+		},
+		&invalidity.GasLimitCircuit{},
+	)
+
+	verifySetupRoundTrip(t, builder, circuits.InvalidityGasLimitCircuitID)
+}
+
 // verifySetupRoundTrip compiles a circuit via the builder, generates a setup
 // with an unsafe SRS, writes the assets to a temp directory, then reads
 // back the manifest and verifying key to check that checksums are consistent.
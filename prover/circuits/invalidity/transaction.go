@@ -298,3 +298,229 @@ func MakeKeccakProofs(tx *types.Transaction, maxRlpByteSize int, compilationSuit
 	}
 	return
 }
+
+// keccakSlotSize returns the number of rows of the keccak data module reserved
+// for each transaction of a batch.
+func keccakSlotSize(maxRlpByteSize int) int {
+	return maxRlpByteSize/LIMB_SIZE + 1
+}
+
+// keccakBatchModule is the keccak module hashing the transactions of a batch.
+// The transaction j occupies the slot of rows [j*slotSize, (j+1)*slotSize) of
+// the data module, its limbs have HashNum j+1 and its hash is on the row j of
+// the info module.
+type keccakBatchModule struct {
+	maxRlpByteSize int
+	maxNbTx        int
+
+	gdm generic.GenDataModule
+	gim generic.GenInfoModule
+	mod *keccak.KeccakSingleProvider
+}
+
+func (m *keccakBatchModule) define(builder *wizard.Builder) {
+	var (
+		comp          = builder.CompiledIOP
+		maxNumKeccakF = m.maxNbTx * (m.maxRlpByteSize/136 + 1) //  136 bytes is the number of bytes absorbed per permutation keccakF.
+		size          = utils.NextPowerOfTwo(m.maxNbTx * keccakSlotSize(m.maxRlpByteSize))
+	)
+
+	m.gdm = CreateGenDataModule(comp, size)
+	m.gim = CreateGenInfoModule(comp, size)
+
+	inp := keccak.KeccakSingleProviderInput{
+		MaxNumKeccakF: maxNumKeccakF,
+		Provider: generic.GenericByteModule{
+			Data: m.gdm,
+			Info: m.gim},
+	}
+	m.mod = keccak.NewKeccakSingleProvider(comp, inp)
+}
+
+func (m *keccakBatchModule) assign(run *wizard.ProverRuntime, txs []*types.Transaction) {
+
+	var (
+		slotSize    = keccakSlotSize(m.maxRlpByteSize)
+		nByteCol    = common.NewVectorBuilder(m.gdm.NBytes)
+		limbCol     = common.NewVectorBuilder(m.gdm.Limb)
+		hashNumCol  = common.NewVectorBuilder(m.gdm.HashNum)
+		toHashCol   = common.NewVectorBuilder(m.gdm.ToHash)
+		indexCol    = common.NewVectorBuilder(m.gdm.Index)
+		hashHi      = common.NewVectorBuilder(m.gim.HashHi)
+		hashLo      = common.NewVectorBuilder(m.gim.HashLo)
+		isHashHiCol = common.NewVectorBuilder(m.gim.IsHashHi)
+		isHashLoCol = common.NewVectorBuilder(m.gim.IsHashLo)
+		expected    = make([][]byte, len(txs))
+	)
+
+	for j, tx := range txs {
+
+		prefixedRlp := ethereum.EncodeTxForSigning(tx)
+		txHash := types.NewLondonSigner(tx.ChainId()).Hash(tx)
+		// sanity check
+		if txHash != crypto.Keccak256Hash(prefixedRlp) {
+			panic("preimage mismatch")
+		}
+		if len(prefixedRlp) > m.maxRlpByteSize {
+			utils.Panic("rlp encoding of transaction %d is too large: got %d, max %d", j, len(prefixedRlp), m.maxRlpByteSize)
+		}
+		expected[j] = prefixedRlp
+
+		// split the prefixedRlp into limbs of left-aligned LIMB_SIZE (16) bytes.
+		for k := 0; k < len(prefixedRlp); k += LIMB_SIZE {
+			b := make([]byte, LIMB_SIZE)
+			nBytes := copy(b, prefixedRlp[k:])
+
+			var f field.Element
+			f.SetBytes(b) // left-aligned
+			limbCol.PushField(f)
+			nByteCol.PushInt(nBytes)
+			hashNumCol.PushInt(j + 1)
+			toHashCol.PushInt(1)
+			indexCol.PushInt(k / LIMB_SIZE)
+		}
+
+		// the rest of the slot is left empty
+		for limbCol.Height() < (j+1)*slotSize {
+			limbCol.PushZero()
+			nByteCol.PushZero()
+			hashNumCol.PushZero()
+			toHashCol.PushZero()
+			indexCol.PushZero()
+		}
+
+		var fHi, fLo field.Element
+		fHi.SetBytes(txHash[:LIMB_SIZE])
+		fLo.SetBytes(txHash[LIMB_SIZE:])
+		hashHi.PushField(fHi)
+		hashLo.PushField(fLo)
+		isHashHiCol.PushInt(1)
+		isHashLoCol.PushInt(1)
+	}
+
+	limbCol.PadAndAssign(run)
+	nByteCol.PadAndAssign(run)
+	hashNumCol.PadAndAssign(run)
+	indexCol.PadAndAssign(run)
+	toHashCol.PadAndAssign(run)
+
+	hashHi.PadAndAssign(run)
+	hashLo.PadAndAssign(run)
+	isHashHiCol.PadAndAssign(run)
+	isHashLoCol.PadAndAssign(run)
+
+	// sanity check
+	streams := m.gdm.ScanStreams(run)
+	for j := range expected {
+		if !bytes.Equal(streams[j], expected[j]) {
+			utils.Panic("gdm stream %d does not match input", j)
+		}
+	}
+
+	m.mod.Run(run)
+}
+
+// MakeKeccakCompiledIOPBatch creates the compiled wizard IOP hashing the
+// maxNbTx transactions of a batch, see [CheckKeccakConsistencyBatch]. This is
+// used for circuit setup where we only need the compiled IOP, not the proof.
+func MakeKeccakCompiledIOPBatch(maxRlpByteSize, maxNbTx int, compilationSuite ...func(*wizard.CompiledIOP)) *wizard.CompiledIOP {
+	m := &keccakBatchModule{maxRlpByteSize: maxRlpByteSize, maxNbTx: maxNbTx}
+	return wizard.Compile(m.define, compilationSuite...)
+}
+
+// MakeKeccakProofsBatch proves the keccak hashes of the transactions of a
+// batch in a single wizard proof. There must be one transaction per sub
+// circuit of the batch circuit, padding included.
+func MakeKeccakProofsBatch(txs []*types.Transaction, maxRlpByteSize int, compilationSuite ...func(*wizard.CompiledIOP)) (
+	comp *wizard.CompiledIOP,
+	proof wizard.Proof,
+) {
+	m := &keccakBatchModule{maxRlpByteSize: maxRlpByteSize, maxNbTx: len(txs)}
+
+	comp = wizard.Compile(m.define, compilationSuite...)
+	proof = wizard.Prove(comp, func(run *wizard.ProverRuntime) { m.assign(run, txs) })
+
+	if err := wizard.Verify(comp, proof); err != nil {
+		utils.Panic("verifier failed: %v", err)
+	}
+	return
+}
+
+// CheckKeccakConsistencyBatch checks the consistency of the batch keccak module
+// against the given inputs and outputs: the transaction j must fill the slot j
+// of the data module, with HashNum j+1, and its hash must be on the row j of
+// the info module. Binding each transaction to its own slot ensures that the
+// transactions can not be swapped or merged.
+func CheckKeccakConsistencyBatch(api frontend.API, hashInputs [][]frontend.Variable, hashOutputs [][2]frontend.Variable, keccak *wizard.VerifierCircuit) {
+
+	var (
+		radix       = big.NewInt(256)
+		limbCol     = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_LIMBS"))
+		hashNumCol  = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_HASH_NUM"))
+		indexCol    = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_INDEX"))
+		toHashCol   = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_TO_HASH"))
+		hashHiCol   = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_HASH_HI"))
+		hashLoCol   = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_HASH_LO"))
+		isHashHiCol = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_IS_HASH_HI"))
+		isHashLoCol = keccak.GetColumn(ifaces.ColIDf("TxHash_INVALIDITY_IS_HASH_LO"))
+		nbTx        = len(hashInputs)
+	)
+
+	if nbTx == 0 || len(hashOutputs) != nbTx {
+		utils.Panic("expected as many hash outputs as hash inputs, got %d and %d", len(hashOutputs), nbTx)
+	}
+
+	slotSize := keccakSlotSize(len(hashInputs[0]))
+	if len(limbCol) < nbTx*slotSize || len(isHashHiCol) < nbTx {
+		utils.Panic("keccak columns are not large enough to hold %d transactions", nbTx)
+	}
+
+	for j, hashInput := range hashInputs {
+
+		if keccakSlotSize(len(hashInput)) != slotSize {
+			utils.Panic("the hash inputs of the batch have different sizes")
+		}
+
+		// each slot starts a new stream
+		start := j * slotSize
+		api.AssertIsEqual(toHashCol[start], 1)
+		api.AssertIsEqual(indexCol[start], 0)
+
+		for k := 0; k < slotSize; k++ {
+			row := start + k
+
+			// the limb k of the input, zero past the end of the input
+			curLimb := frontend.Variable(0)
+			if k*LIMB_SIZE < len(hashInput) {
+				v := make([]frontend.Variable, LIMB_SIZE)
+				for i := range v {
+					v[i] = 0
+					if k*LIMB_SIZE+i < len(hashInput) {
+						v[i] = hashInput[k*LIMB_SIZE+i]
+					}
+				}
+				curLimb = compress.ReadNum(api, v, radix)
+			}
+			api.AssertIsEqual(limbCol[row], curLimb)
+
+			// the limbs of the slot belong to the hash j
+			api.AssertIsEqual(api.Mul(toHashCol[row], api.Sub(hashNumCol[row], j+1)), 0)
+		}
+
+		// check that the keccak hash columns match the hashOutput
+		api.AssertIsEqual(hashHiCol[j], hashOutputs[j][0])
+		api.AssertIsEqual(hashLoCol[j], hashOutputs[j][1])
+		api.AssertIsEqual(isHashHiCol[j], 1)
+		api.AssertIsEqual(isHashLoCol[j], 1)
+	}
+
+	// nothing is hashed past the last slot
+	for row := nbTx * slotSize; row < len(limbCol); row++ {
+		api.AssertIsEqual(limbCol[row], 0)
+		api.AssertIsEqual(toHashCol[row], 0)
+	}
+	for row := nbTx; row < len(isHashHiCol); row++ {
+		api.AssertIsEqual(isHashHiCol[row], 0)
+		api.AssertIsEqual(isHashLoCol[row], 0)
+	}
+}
@@ -29,6 +29,9 @@ type Request struct {
 	Executions         []public_input.Execution
 	Aggregation        public_input.Aggregation
 	Invalidity         []public_input.Invalidity
+	// InvalidityBatches are the batch invalidity proofs, following the ones of
+	// Invalidity in FTX order
+	InvalidityBatches []public_input.InvalidityBatch
 }
 
 func (c *Compiled) Assign(r Request, dictStore dictionary.Store) (a Circuit, err error) {
@@ -58,7 +61,11 @@ func (c *Compiled) Assign(r Request, dictStore dictionary.Store) (a Circuit, err
 		err = fmt.Errorf("failing CHECK_INVAL_LIMIT:\n\t%d invalidity proofs exceeds maximum of %d", len(r.Invalidity), cfg.MaxNbInvalidity)
 		return
 	}
-	if nbC := len(r.DataAvailabilities) + len(r.Executions) + len(r.Invalidity); nbC > cfg.MaxNbCircuits && cfg.MaxNbCircuits > 0 {
+	if len(r.InvalidityBatches) > cfg.MaxNbInvalidityBatch {
+		err = fmt.Errorf("failing CHECK_INVAL_BATCH_LIMIT:\n\t%d batch invalidity proofs exceeds maximum of %d", len(r.InvalidityBatches), cfg.MaxNbInvalidityBatch)
+		return
+	}
+	if nbC := len(r.DataAvailabilities) + len(r.Executions) + len(r.Invalidity) + len(r.InvalidityBatches); nbC > cfg.MaxNbCircuits && cfg.MaxNbCircuits > 0 {
 		err = fmt.Errorf("failing CHECK_CIRCUIT_LIMIT:\n\t%d circuits exceeds maximum of %d", nbC, cfg.MaxNbCircuits)
 		return
 	}
@@ -419,6 +426,8 @@ func (c *Compiled) Assign(r Request, dictStore dictionary.Store) (a Circuit, err
 	}
 
 	a.InvalidityFPI, a.InvalidityPublicInput = assignInvalidity(r, len(a.InvalidityFPI))
+	a.InvalidityBatchFPI, a.InvalidityBatchPublicInput = assignInvalidityBatches(r, len(a.InvalidityBatchFPI))
+	a.NbInvalidityBatch = len(r.InvalidityBatches)
 
 	// get the filtered addresses
 	a.FilteredAddressesFPISnark.Addresses = getFilteredAddresses(r, len(a.InvalidityFPI))
@@ -494,6 +503,27 @@ func assignInvalidity(r Request, n int) (invalidityFPI []invalidity.FunctionalPu
 	return invalidityFPI, invalidityPI
 }
 
+// assignInvalidityBatches assigns the public inputs of the batch invalidity
+// proofs.
+func assignInvalidityBatches(r Request, n int) (batchFPI []invalidity.BatchFunctionalPublicInputsGnark, batchPI []frontend.Variable) {
+
+	batchFPI = make([]invalidity.BatchFunctionalPublicInputsGnark, n)
+	batchPI = make([]frontend.Variable, n)
+
+	for i := 0; i < n; i++ {
+		if i < len(r.InvalidityBatches) {
+			batch := r.InvalidityBatches[i]
+			batchFPI[i].Assign(batch)
+			batchPI[i] = batch.Sum(nil)
+
+		} else { // padding
+			batchFPI[i].Assign(public_input.InvalidityBatch{})
+			batchPI[i] = 0
+		}
+	}
+	return batchFPI, batchPI
+}
+
 func getFilteredAddresses(r Request, nbInvalidity int) []frontend.Variable {
 	filteredAddresses := make([]frontend.Variable, nbInvalidity)
 	idx := 0
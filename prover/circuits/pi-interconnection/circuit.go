@@ -34,10 +34,15 @@ type Circuit struct {
 	ExecutionPublicInput        []frontend.Variable  `gnark:",public"`
 	DataAvailabilityPublicInput []frontend.Variable  `gnark:",public"`
 	InvalidityPublicInput       []frontend.Variable  `gnark:",public"`
+	InvalidityBatchPublicInput  []frontend.Variable  `gnark:",public"`
 
 	DataAvailabilityFPIQ []blobdecompression.FunctionalPublicInputQSnark
 	ExecutionFPIQ        []execution.FunctionalPublicInputQSnark
-	InvalidityFPI        []invalidity.FunctionalPublicInputsGnark // to connected invalidityFPI to the aggregationFPI
+	InvalidityFPI        []invalidity.FunctionalPublicInputsGnark      // to connected invalidityFPI to the aggregationFPI
+	InvalidityBatchFPI   []invalidity.BatchFunctionalPublicInputsGnark // the batch invalidity proofs, following the ones of InvalidityFPI
+
+	// NbInvalidityBatch is the number of effective batch invalidity proofs
+	NbInvalidityBatch frontend.Variable
 
 	public_input.AggregationFPIQSnark
 
@@ -101,7 +106,7 @@ func (c *Circuit) Define(api frontend.API) error {
 	api.AssertIsDifferent(nbExecution, 0)
 
 	if c.MaxNbCircuits > 0 { // CHECK_CIRCUIT_LIMIT
-		api.AssertIsLessOrEqual(api.Add(nbExecution, c.NbDataAvailability, c.NbInvalidity), c.MaxNbCircuits)
+		api.AssertIsLessOrEqual(api.Add(nbExecution, c.NbDataAvailability, c.NbInvalidity, c.NbInvalidityBatch), c.MaxNbCircuits)
 	}
 
 	batchHashes := make([]frontend.Variable, len(c.ExecutionPublicInput))
@@ -312,6 +317,12 @@ func (c *Circuit) Define(api frontend.API) error {
 //
 // publicInput is set to zero for padding entries.
 // it also checks that state root hash and blocknumber are from the parent aggregation.
+//
+// The batch invalidity proofs (i < NbInvalidityBatch) come after the single
+// ones in the FTX order: the first one starts right after the last single
+// invalidity proof and each batch covers the FTX numbers FirstTxNumber to
+// LastTxNumber. They are proven against the same state and simulated block as
+// the single ones.
 func (c *Circuit) checkInvalidityProofs(
 	api frontend.API,
 	parentFinalState [2]frontend.Variable,
@@ -387,6 +398,46 @@ func (c *Circuit) checkInvalidityProofs(
 	// ensure all filtered addresses were consumed
 	api.AssertIsEqual(ctr, c.FilteredAddressesFPISnark.NbAddresses)
 
+	rBatch := internal.NewRange(api, c.NbInvalidityBatch, len(c.InvalidityBatchFPI))
+	if len(c.InvalidityBatchFPI) == 0 {
+		return finalFtxNumber, finalFtxRollingHash
+	}
+
+	// the simulated block is shared by all the invalidity proofs, single or batch
+	simulatedBlockTimestamp := api.Select(rInvalidity.InRange[0], c.InvalidityFPI[0].SimulatedBlockTimestamp, c.InvalidityBatchFPI[0].SimulatedBlockTimestamp)
+	onlyBatches := api.Mul(api.Sub(1, rInvalidity.InRange[0]), rBatch.InRange[0])
+	internal.AssertIsLessIf(api, onlyBatches, parentBlockTimestamp, simulatedBlockTimestamp)
+
+	for i, batchFPI := range c.InvalidityBatchFPI {
+
+		inRange := rBatch.InRange[i]
+
+		internal.AssertEqualIf(api, inRange, batchFPI.StateRootHash[0], parentFinalState[0])
+		internal.AssertEqualIf(api, inRange, batchFPI.StateRootHash[1], parentFinalState[1])
+
+		// CHECK_CHAIN_ID, CHECK_BASE_FEE, CHECK_COINBASE, CHECK_SVC_ADDR
+		internal.AssertEqualIf(api, inRange, batchFPI.ChainID, c.ChainConfigurationFPISnark.ChainID)
+		internal.AssertEqualIf(api, inRange, batchFPI.BaseFee, c.ChainConfigurationFPISnark.BaseFee)
+		internal.AssertEqualIf(api, inRange, batchFPI.CoinBase, c.ChainConfigurationFPISnark.CoinBase)
+		internal.AssertEqualIf(api, inRange, batchFPI.L2MessageServiceAddr, c.ChainConfigurationFPISnark.L2MessageServiceAddress)
+
+		//  CHECK_SIMULATED_BLOCK_TIME
+		internal.AssertEqualIf(api, inRange, batchFPI.SimulatedBlockTimestamp, simulatedBlockTimestamp)
+
+		// CHECK_SIMULATED_BLOCK_NUMBER
+		internal.AssertEqualIf(api, inRange, batchFPI.SimulatedBlockNumber, api.Add(parentFinalBlockNumber, 1))
+
+		api.AssertIsEqual(c.InvalidityBatchPublicInput[i], api.Mul(inRange, batchFPI.Sum(api)))
+
+		// the batch continues the FTX chain, the rolling hash is chained by the
+		// batch circuit from PrevFtxRollingHash to FtxRollingHash
+		internal.AssertEqualIf(api, inRange, batchFPI.FirstTxNumber, api.Add(finalFtxNumber, 1))
+		internal.AssertEqualIf(api, inRange, batchFPI.PrevFtxRollingHash, finalFtxRollingHash)
+
+		finalFtxRollingHash = api.Select(inRange, batchFPI.FtxRollingHash, finalFtxRollingHash)
+		finalFtxNumber = api.Select(inRange, batchFPI.LastTxNumber, finalFtxNumber)
+	}
+
 	return finalFtxNumber, finalFtxRollingHash
 }
 
@@ -413,6 +464,12 @@ type Compiled struct {
 
 func Compile(c config.PublicInput, wizardCompilationOpts keccak.CompilationParams) (*Compiled, error) {
 
+	// the batch invalidity proofs share the simulated block of the single ones,
+	// and checkInvalidityProofs reads it from the first single proof slot.
+	if c.MaxNbInvalidityBatch > 0 && c.MaxNbInvalidity == 0 {
+		return nil, errors.New("max_nb_invalidity_batch > 0 requires max_nb_invalidity > 0")
+	}
+
 	if c.L2MsgMaxNbMerkle <= 0 {
 		merkleNbLeaves := 1 << c.L2MsgMerkleDepth
 		c.L2MsgMaxNbMerkle = (c.MaxNbExecution*c.ExecutionMaxNbMsg + merkleNbLeaves - 1) / merkleNbLeaves
@@ -452,6 +509,7 @@ func (c *Compiled) getConfig() (config.PublicInput, error) {
 		MaxNbDataAvailability: len(c.Circuit.DataAvailabilityFPIQ),
 		MaxNbExecution:        len(c.Circuit.ExecutionFPIQ),
 		MaxNbInvalidity:       len(c.Circuit.InvalidityFPI),
+		MaxNbInvalidityBatch:  len(c.Circuit.InvalidityBatchFPI),
 		ExecutionMaxNbMsg:     executionNbMsg,
 		L2MsgMerkleDepth:      c.Circuit.L2MessageMerkleDepth,
 		L2MsgMaxNbMerkle:      c.Circuit.L2MessageMaxNbMerkle,
@@ -465,9 +523,11 @@ func allocateCircuit(cfg config.PublicInput) Circuit {
 		DataAvailabilityPublicInput: make([]frontend.Variable, cfg.MaxNbDataAvailability),
 		ExecutionPublicInput:        make([]frontend.Variable, cfg.MaxNbExecution),
 		InvalidityPublicInput:       make([]frontend.Variable, cfg.MaxNbInvalidity),
+		InvalidityBatchPublicInput:  make([]frontend.Variable, cfg.MaxNbInvalidityBatch),
 		DataAvailabilityFPIQ:        make([]blobdecompression.FunctionalPublicInputQSnark, cfg.MaxNbDataAvailability),
 		ExecutionFPIQ:               make([]execution.FunctionalPublicInputQSnark, cfg.MaxNbExecution),
 		InvalidityFPI:               make([]invalidity.FunctionalPublicInputsGnark, cfg.MaxNbInvalidity),
+		InvalidityBatchFPI:          make([]invalidity.BatchFunctionalPublicInputsGnark, cfg.MaxNbInvalidityBatch),
 		L2MessageMerkleDepth:        cfg.L2MsgMerkleDepth,
 		L2MessageMaxNbMerkle:        cfg.L2MsgMaxNbMerkle,
 		MaxNbCircuits:               cfg.MaxNbCircuits,
@@ -524,7 +584,7 @@ func (b builder) Compile() (constraint.ConstraintSystem, error) {
 	return cs, nil
 }
 
-// GetMaxNbCircuitsSum computes MaxNbDA + MaxNbExecution + MaxNbInvalidity from the compiled constraint system.
+// GetMaxNbCircuitsSum computes MaxNbDA + MaxNbExecution + MaxNbInvalidity + MaxNbInvalidityBatch from the compiled constraint system.
 // Subtracts 3 to exclude AggregationPublicInput (2) + IsAllowedCircuitID (1).
 // TODO replace with something cleaner, using the config
 func GetMaxNbCircuitsSum(cs constraint.ConstraintSystem) int {
@@ -534,18 +594,20 @@ func GetMaxNbCircuitsSum(cs constraint.ConstraintSystem) int {
 type InnerCircuitType uint8
 
 const (
-	Execution     InnerCircuitType = 0
-	Decompression InnerCircuitType = 1
-	Invalidity    InnerCircuitType = 2 //  all the invalidity subcircuits have the same set of functional public inputs, so we can use the same index for all of them
+	Execution       InnerCircuitType = 0
+	Decompression   InnerCircuitType = 1
+	Invalidity      InnerCircuitType = 2 //  all the invalidity subcircuits have the same set of functional public inputs, so we can use the same index for all of them
+	InvalidityBatch InnerCircuitType = 3 // the batch invalidity circuits share the functional public inputs of [public_input.InvalidityBatch]
 )
 
 func InnerCircuitTypesToIndexes(cfg *config.PublicInput, types []InnerCircuitType) []int {
-	indexes := utils.RightPad(utils.Partition(utils.RangeSlice[int](len(types)), types), 3)
-	return append(append(
+	indexes := utils.RightPad(utils.Partition(utils.RangeSlice[int](len(types)), types), 4)
+	return append(append(append(
 		utils.RightPad(indexes[Execution], cfg.MaxNbExecution),               // Pad Execution indexes
 		utils.RightPad(indexes[Decompression], cfg.MaxNbDataAvailability)..., // Pad Data Availability indexes
 	),
-		utils.RightPad(indexes[Invalidity], cfg.MaxNbInvalidity)...) // Pad Invalidity indexes
+		utils.RightPad(indexes[Invalidity], cfg.MaxNbInvalidity)...), // Pad Invalidity indexes
+		utils.RightPad(indexes[InvalidityBatch], cfg.MaxNbInvalidityBatch)...) // Pad Invalidity Batch indexes
 
 }
 
@@ -134,3 +134,21 @@ func TestMaxNbCircuitsSum(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// TestCompileRejectsBatchesOnly checks that a configuration with batch
+// invalidity proofs but no single ones is rejected instead of panicking while
+// the circuit is defined.
+func TestCompileRejectsBatchesOnly(t *testing.T) {
+	cfg := config.PublicInput{
+		MaxNbDataAvailability: 1,
+		MaxNbExecution:        1,
+		MaxNbInvalidityBatch:  1,
+		ExecutionMaxNbMsg:     2,
+		L2MsgMerkleDepth:      5,
+		L2MsgMaxNbMerkle:      2,
+		MockKeccakWizard:      true,
+	}
+
+	_, err := pi_interconnection.Compile(cfg, nil)
+	assert.Error(t, err)
+}
@@ -30,6 +30,14 @@ const (
 	InvalidityNonceBalanceDummyCircuitID       CircuitID = "invalidity-nonce-balance-dummy"
 	InvalidityPrecompileLogsDummyCircuitID     CircuitID = "invalidity-precompile-logs-dummy"
 	InvalidityFilteredAddressDummyCircuitID    CircuitID = "invalidity-filtered-address-dummy"
+	InvalidityGasLimitCircuitID                CircuitID = "invalidity-gas-limit"
+	InvalidityGasLimitDummyCircuitID           CircuitID = "invalidity-gas-limit-dummy"
+
+	// The batch invalidity circuits prove several consecutive forced
+	// transactions at once, their functional public inputs are the ones of
+	// public_input.InvalidityBatch.
+	InvalidityNonceBalanceBatchCircuitID CircuitID = "invalidity-nonce-balance-batch"
+	InvalidityGasLimitBatchCircuitID     CircuitID = "invalidity-gas-limit-batch"
 )
 
 // MockCircuitID is a type to represent the different mock circuits.
@@ -42,8 +50,25 @@ const (
 	MockCircuitIDInvalidityNonceBalance    MockCircuitID = 2
 	MockCircuitIDInvalidityPrecompileLogs  MockCircuitID = 3
 	MockCircuitIDInvalidityFilteredAddress MockCircuitID = 4
+	MockCircuitIDInvalidityGasLimit        MockCircuitID = 5
+	MockCircuitIDInvalidityBatch           MockCircuitID = 6
 )
 
+// The infrastructure circuits (emulation, aggregation, PI-interconnection,
+// emulation-dummy) have IDs 14 to 17. The payload circuits added after them
+// take the next IDs so that the existing IDs remain stable.
+const (
+	firstInfrastructureCircuitID uint = 14
+	lastInfrastructureCircuitID  uint = 17
+)
+
+// IsInfrastructureCircuitID returns true if the circuit ID is the one of an
+// infrastructure circuit. These circuits are not aggregated and must not be
+// included in the is_allowed_circuit_id bitmask.
+func IsInfrastructureCircuitID(id uint) bool {
+	return id >= firstInfrastructureCircuitID && id <= lastInfrastructureCircuitID
+}
+
 // GlobalCircuitIDMapping defines the fixed mapping of circuit names to circuit IDs.
 // This order is canonical and must remain stable across versions.
 //
@@ -54,7 +79,7 @@ const (
 // given environment.
 //
 //   - Bit i (LSb to MSb) indicates whether circuit ID i is allowed
-//   - Only circuits 0-13 are used in the bitmask (inner payload circuits)
+//   - Circuits 0-13 and 18-21 are used in the bitmask (inner payload circuits)
 //   - Circuits 14-17 (emulation, aggregation, PI-interconnection, emulation-dummy) are
 //     infrastructure circuits and should NOT be included in the bitmask
 //
@@ -70,21 +95,24 @@ const (
 //
 //	execution-dummy (ID 0, bit 0)                              = 0 → DISALLOWED
 //	data-availability-dummy (ID 1, bit 1)                      = 0 → DISALLOWED
-//	emulation-dummy (ID 2, bit 2)                              = 0 → DISALLOWED
-//	execution (ID 3, bit 3)                                    = 1 → ALLOWED
-//	execution-large (ID 4, bit 4)                              = 1 → ALLOWED
-//	execution-limitless (ID 5, bit 5)                          = 1 → ALLOWED
-//	data-availability-v2 (ID 6, bit 6)                         = 1 → ALLOWED
-//	invalidity-nonce-balance-dummy (ID 7, bit 7)               = 0 → DISALLOWED
-//	invalidity-precompile-logs-dummy (ID 8, bit 8)             = 0 → DISALLOWED
-//	invalidity-filtered-address-dummy (ID 9, bit 9)            = 0 → DISALLOWED
-//	invalidity-nonce-balance (ID 10, bit 10)                   = 1 → ALLOWED
-//	invalidity-precompile-logs (ID 11, bit 11)                 = 1 → ALLOWED
-//	invalidity-filtered-address (ID 12, bit 12)                = 1 → ALLOWED
-//	invalidity-precompile-logs-limitless (ID 13, bit 13)       = 1 → ALLOWED
-//	invalidity-precompile-logs-large (ID 14, bit 14)           = 1 → ALLOWED
-//	Binary: 0b111110001111000 = 31864 (decimal)
-//	is_allowed_circuit_id = 31864
+//	execution (ID 2, bit 2)                                    = 1 → ALLOWED
+//	execution-large (ID 3, bit 3)                              = 1 → ALLOWED
+//	execution-limitless (ID 4, bit 4)                          = 1 → ALLOWED
+//	data-availability-v2 (ID 5, bit 5)                         = 1 → ALLOWED
+//	invalidity-nonce-balance-dummy (ID 6, bit 6)               = 0 → DISALLOWED
+//	invalidity-precompile-logs-dummy (ID 7, bit 7)             = 0 → DISALLOWED
+//	invalidity-filtered-address-dummy (ID 8, bit 8)            = 0 → DISALLOWED
+//	invalidity-nonce-balance (ID 9, bit 9)                     = 1 → ALLOWED
+//	invalidity-precompile-logs (ID 10, bit 10)                 = 1 → ALLOWED
+//	invalidity-filtered-address (ID 11, bit 11)                = 1 → ALLOWED
+//	invalidity-precompile-logs-limitless (ID 12, bit 12)       = 1 → ALLOWED
+//	invalidity-precompile-logs-large (ID 13, bit 13)           = 1 → ALLOWED
+//	invalidity-gas-limit-dummy (ID 18, bit 18)                 = 0 → DISALLOWED
+//	invalidity-gas-limit (ID 19, bit 19)                       = 1 → ALLOWED
+//	invalidity-nonce-balance-batch (ID 20, bit 20)             = 0 → DISALLOWED
+//	invalidity-gas-limit-batch (ID 21, bit 21)                 = 0 → DISALLOWED
+//	Binary: 0b10000011111000111100 = 540220 (decimal)
+//	is_allowed_circuit_id = 540220
 //
 // Use ComputeIsAllowedCircuitID() to calculate the bitmask from circuit names.
 var GlobalCircuitIDMapping = map[string]uint{
@@ -115,6 +143,15 @@ var GlobalCircuitIDMapping = map[string]uint{
 	"aggregation":                  15,
 	"public-input-interconnection": 16,
 	"emulation-dummy":              17,
+
+	// Gas limit invalidity circuits (bits 18-19), added after the infrastructure
+	// circuits to keep the existing IDs stable
+	"invalidity-gas-limit-dummy": 18,
+	"invalidity-gas-limit":       19,
+
+	// Batch invalidity circuits (bits 20-21)
+	"invalidity-nonce-balance-batch": 20,
+	"invalidity-gas-limit-batch":     21,
 }
 
 // ComputeIsAllowedCircuitID computes the is_allowed_circuit_id bitmask from a list of
@@ -139,8 +176,8 @@ func ComputeIsAllowedCircuitID(allowedCircuits []string) (uint64, error) {
 			return 0, fmt.Errorf("unknown circuit name: %s", name)
 		}
 
-		// Infrastructure circuits (14-17) should not be in the bitmask
-		if id >= 14 {
+		// Infrastructure circuits should not be in the bitmask
+		if IsInfrastructureCircuitID(id) {
 			return 0, fmt.Errorf("circuit '%s' (ID %d) is an infrastructure circuit and should not be included in is_allowed_circuit_id", name, id)
 		}
 
@@ -175,8 +212,8 @@ func GetAllowedCircuitNames(bitmask uint64) []string {
 	var allowed []string
 
 	for name, id := range GlobalCircuitIDMapping {
-		// Only check payload circuits (0-13)
-		if id < 14 && IsCircuitAllowed(bitmask, id) {
+		// Only check payload circuits
+		if !IsInfrastructureCircuitID(id) && IsCircuitAllowed(bitmask, id) {
 			allowed = append(allowed, name)
 		}
 	}
@@ -196,6 +196,24 @@ func TestComputeIsAllowedCircuitID(t *testing.T) {
 			expectedBitmask: 3584, // 2^9 + 2^10 + 2^11 = 512+1024+2048
 			expectError:     false,
 		},
+		{
+			name: "gas limit invalidity circuits (18-19)",
+			allowedCircuits: []string{
+				"invalidity-gas-limit-dummy",
+				"invalidity-gas-limit",
+			},
+			expectedBitmask: 786432, // 2^18 + 2^19
+			expectError:     false,
+		},
+		{
+			name: "batch invalidity circuits (20-21)",
+			allowedCircuits: []string{
+				"invalidity-nonce-balance-batch",
+				"invalidity-gas-limit-batch",
+			},
+			expectedBitmask: 3145728, // 2^20 + 2^21
+			expectError:     false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -329,6 +347,22 @@ func TestGetAllowedCircuitNames(t *testing.T) {
 				"data-availability-dummy",
 			},
 		},
+		{
+			name:    "infrastructure bits are ignored",
+			bitmask: 15932 | 0b1111<<14 | 1<<19, // mainnet + bits 14-17 + gas limit
+			expectedCircuits: []string{
+				"execution",
+				"execution-large",
+				"execution-limitless",
+				"data-availability-v2",
+				"invalidity-nonce-balance",
+				"invalidity-precompile-logs",
+				"invalidity-filtered-address",
+				"invalidity-precompile-logs-limitless",
+				"invalidity-precompile-logs-large",
+				"invalidity-gas-limit",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -389,6 +423,10 @@ func TestGlobalCircuitIDMapping(t *testing.T) {
 	assert.Equal(t, uint(15), GlobalCircuitIDMapping["aggregation"])
 	assert.Equal(t, uint(16), GlobalCircuitIDMapping["public-input-interconnection"])
 	assert.Equal(t, uint(17), GlobalCircuitIDMapping["emulation-dummy"])
+	assert.Equal(t, uint(18), GlobalCircuitIDMapping["invalidity-gas-limit-dummy"])
+	assert.Equal(t, uint(19), GlobalCircuitIDMapping["invalidity-gas-limit"])
+	assert.Equal(t, uint(20), GlobalCircuitIDMapping["invalidity-nonce-balance-batch"])
+	assert.Equal(t, uint(21), GlobalCircuitIDMapping["invalidity-gas-limit-batch"])
 
 	// Verify no duplicate IDs
 	seen := make(map[uint]string)
@@ -399,8 +437,8 @@ func TestGlobalCircuitIDMapping(t *testing.T) {
 		seen[id] = name
 	}
 
-	// Verify we have exactly 18 circuits
-	assert.Equal(t, 18, len(GlobalCircuitIDMapping))
+	// Verify we have exactly 22 circuits
+	assert.Equal(t, 22, len(GlobalCircuitIDMapping))
 }
 
 // Example test showing how to use these functions for config validation
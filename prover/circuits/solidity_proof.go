@@ -0,0 +1,264 @@
+package circuits
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/hash"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Sizes of the items of a proof encoded by [SerializeProofSolidityBn254]. The
+// layout is the one read by the Verifier.sol contract: the commitments to L,
+// R, O and to the three chunks of H, the openings of L, R, O, S1, S2 at ζ, the
+// commitment to Z and its opening at ωζ, the two KZG quotients and then, for
+// each custom gate, the opening of Qcp at ζ followed by all the BSB22
+// commitments.
+const (
+	solidityWordSize  = 32
+	solidityPointSize = 2 * solidityWordSize
+	solidityFixedSize = 0x300
+)
+
+// bsb22HashDst is the domain separation tag used by gnark (and Verifier.sol)
+// to hash the BSB22 commitments into the public inputs.
+const bsb22HashDst = "BSB22-Plonk"
+
+// DeserializeProofSolidityBn254 decodes a proof serialized with
+// [SerializeProofSolidityBn254] for the verifying key vk so that it can be
+// checked with the native PLONK verifier.
+//
+// The Solidity encoding omits the opening of the linearized polynomial at ζ as
+// the contract recomputes it from the other openings, the Fiat-Shamir
+// challenges and the public inputs. This function does the same, following
+// compute_opening_linearised_polynomial of the contract. The native verifier
+// checks this opening against its own recomputation, so an error here can only
+// make a valid proof fail and never makes an invalid proof pass.
+func DeserializeProofSolidityBn254(vk *plonk_bn254.VerifyingKey, proofHex string, publicInputs []fr.Element) (*plonk_bn254.Proof, error) {
+
+	buf, err := hexutil.Decode(proofHex)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the proof: %w", err)
+	}
+
+	nbCustomGates := len(vk.Qcp)
+	if want := solidityFixedSize + nbCustomGates*(solidityWordSize+solidityPointSize); len(buf) != want {
+		return nil, fmt.Errorf("the proof has %v bytes, expected %v", len(buf), want)
+	}
+
+	if len(publicInputs) != int(vk.NbPublicVariables) {
+		return nil, fmt.Errorf("got %v public inputs, the verifying key has %v", len(publicInputs), vk.NbPublicVariables)
+	}
+
+	r := solidityReader{buf: buf}
+	proof := &plonk_bn254.Proof{
+		Bsb22Commitments: make([]kzg.Digest, nbCustomGates),
+		BatchedProof: kzg.BatchOpeningProof{
+			// The linearized polynomial, L, R, O, S1, S2 and the Qcp.
+			ClaimedValues: make([]fr.Element, 6+nbCustomGates),
+		},
+	}
+
+	for i := range proof.LRO {
+		r.readPoint(&proof.LRO[i])
+	}
+	for i := range proof.H {
+		r.readPoint(&proof.H[i])
+	}
+	for i := 1; i < 6; i++ {
+		r.readFr(&proof.BatchedProof.ClaimedValues[i])
+	}
+	r.readPoint(&proof.Z)
+	r.readFr(&proof.ZShiftedOpening.ClaimedValue)
+	r.readPoint(&proof.BatchedProof.H)
+	r.readPoint(&proof.ZShiftedOpening.H)
+	for i := 0; i < nbCustomGates; i++ {
+		r.readFr(&proof.BatchedProof.ClaimedValues[6+i])
+	}
+	for i := range proof.Bsb22Commitments {
+		r.readPoint(&proof.Bsb22Commitments[i])
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	opening, err := linearizedPolynomialOpening(vk, proof, publicInputs)
+	if err != nil {
+		return nil, err
+	}
+	proof.BatchedProof.ClaimedValues[0] = opening
+
+	return proof, nil
+}
+
+// linearizedPolynomialOpening returns the opening of the linearized polynomial
+// at ζ, that is
+//
+//	-[PI(ζ) - α²L₀(ζ) + α(l(ζ)+βs1(ζ)+γ)(r(ζ)+βs2(ζ)+γ)(o(ζ)+γ)z(ωζ)]
+func linearizedPolynomialOpening(vk *plonk_bn254.VerifyingKey, proof *plonk_bn254.Proof, publicInputs []fr.Element) (fr.Element, error) {
+
+	fs := fiatshamir.NewTranscript(sha256.New(), "gamma", "beta", "alpha", "zeta")
+
+	var bindings [][]byte
+	for _, p := range vk.S {
+		bindings = append(bindings, p.Marshal())
+	}
+	for _, p := range []kzg.Digest{vk.Ql, vk.Qr, vk.Qm, vk.Qo, vk.Qk} {
+		bindings = append(bindings, p.Marshal())
+	}
+	for _, p := range vk.Qcp {
+		bindings = append(bindings, p.Marshal())
+	}
+	for _, x := range publicInputs {
+		bindings = append(bindings, x.Marshal())
+	}
+	for _, p := range proof.LRO {
+		bindings = append(bindings, p.Marshal())
+	}
+	gamma, err := deriveChallenge(fs, "gamma", bindings...)
+	if err != nil {
+		return fr.Element{}, err
+	}
+
+	beta, err := deriveChallenge(fs, "beta")
+	if err != nil {
+		return fr.Element{}, err
+	}
+
+	bindings = bindings[:0]
+	for _, p := range proof.Bsb22Commitments {
+		bindings = append(bindings, p.Marshal())
+	}
+	bindings = append(bindings, proof.Z.Marshal())
+	alpha, err := deriveChallenge(fs, "alpha", bindings...)
+	if err != nil {
+		return fr.Element{}, err
+	}
+
+	bindings = bindings[:0]
+	for _, p := range proof.H {
+		bindings = append(bindings, p.Marshal())
+	}
+	zeta, err := deriveChallenge(fs, "zeta", bindings...)
+	if err != nil {
+		return fr.Element{}, err
+	}
+
+	// ζⁿ-1
+	var zhZeta fr.Element
+	zhZeta.Exp(zeta, new(big.Int).SetUint64(vk.Size))
+	zhZeta.Sub(&zhZeta, new(fr.Element).SetOne())
+
+	// Lᵢ(ζ) = ωⁱ/n * (ζⁿ-1)/(ζ-ωⁱ)
+	lagrange := func(i uint64) fr.Element {
+		var wi, den, res fr.Element
+		wi.Exp(vk.Generator, new(big.Int).SetUint64(i))
+		den.Sub(&zeta, &wi).Inverse(&den)
+		res.Mul(&wi, &vk.SizeInv).Mul(&res, &zhZeta).Mul(&res, &den)
+		return res
+	}
+
+	var pi, tmp fr.Element
+	for i := range publicInputs {
+		li := lagrange(uint64(i))
+		tmp.Mul(&li, &publicInputs[i])
+		pi.Add(&pi, &tmp)
+	}
+
+	for i, p := range proof.Bsb22Commitments {
+		hashed, err := hash.ExpandMsgXmd(p.Marshal(), []byte(bsb22HashDst), 48)
+		if err != nil {
+			return fr.Element{}, fmt.Errorf("could not hash the commitment %v: %w", i, err)
+		}
+		tmp.SetBytes(hashed)
+		li := lagrange(vk.NbPublicVariables + vk.CommitmentConstraintIndexes[i])
+		tmp.Mul(&tmp, &li)
+		pi.Add(&pi, &tmp)
+	}
+
+	claimed := proof.BatchedProof.ClaimedValues
+	l, rr, o, s1, s2 := claimed[1], claimed[2], claimed[3], claimed[4], claimed[5]
+
+	var a, b, c, res fr.Element
+	a.Mul(&s1, &beta).Add(&a, &gamma).Add(&a, &l)
+	b.Mul(&s2, &beta).Add(&b, &gamma).Add(&b, &rr)
+	c.Add(&o, &gamma)
+	res.Mul(&a, &b).Mul(&res, &c).Mul(&res, &alpha).Mul(&res, &proof.ZShiftedOpening.ClaimedValue)
+	res.Add(&res, &pi)
+
+	l0 := lagrange(0)
+	tmp.Square(&alpha).Mul(&tmp, &l0)
+	res.Sub(&res, &tmp)
+	res.Neg(&res)
+
+	return res, nil
+}
+
+// deriveChallenge binds the values to the challenge and returns it reduced
+// modulo r.
+func deriveChallenge(fs *fiatshamir.Transcript, name string, values ...[]byte) (fr.Element, error) {
+	for _, v := range values {
+		if err := fs.Bind(name, v); err != nil {
+			return fr.Element{}, fmt.Errorf("could not bind to challenge %v: %w", name, err)
+		}
+	}
+
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, fmt.Errorf("could not compute challenge %v: %w", name, err)
+	}
+
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+// solidityReader reads the big-endian words of a Solidity-encoded proof. The
+// first error is kept and the following reads are no-ops.
+type solidityReader struct {
+	buf []byte
+	err error
+}
+
+func (r *solidityReader) next(n int) []byte {
+	res := r.buf[:n]
+	r.buf = r.buf[n:]
+	return res
+}
+
+// readFr reads a scalar, which must be reduced as the contract requires.
+func (r *solidityReader) readFr(x *fr.Element) {
+	if r.err != nil {
+		return
+	}
+	if err := x.SetBytesCanonical(r.next(solidityWordSize)); err != nil {
+		r.err = fmt.Errorf("invalid opening in the proof: %w", err)
+	}
+}
+
+// readPoint reads the affine coordinates of a G1 point. The point at infinity
+// is encoded as (0, 0), which is also its representation in gnark-crypto.
+func (r *solidityReader) readPoint(p *bn254.G1Affine) {
+	if r.err != nil {
+		return
+	}
+	if err := p.X.SetBytesCanonical(r.next(solidityWordSize)); err != nil {
+		r.err = fmt.Errorf("invalid point in the proof: %w", err)
+		return
+	}
+	if err := p.Y.SetBytesCanonical(r.next(solidityWordSize)); err != nil {
+		r.err = fmt.Errorf("invalid point in the proof: %w", err)
+		return
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		r.err = errors.New("invalid point in the proof: not in G1")
+	}
+}
@@ -0,0 +1,66 @@
+package circuits
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/scs"
+	"github.com/consensys/gnark/test/unsafekzg"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeserializeProofSolidityBn254(t *testing.T) {
+
+	cs, err := frontend.Compile(ecc.BN254.ScalarField(), scs.NewBuilder, &circuit{make([]frontend.Variable, 2)})
+	require.NoError(t, err)
+
+	canonical, lagrange, err := unsafekzg.NewSRS(cs)
+	require.NoError(t, err)
+
+	pk, vk, err := plonk.Setup(cs, canonical, lagrange)
+	require.NoError(t, err)
+
+	publicInputs := []fr.Element{fr.NewElement(3), fr.NewElement(5)}
+	assignment := &circuit{Input: []frontend.Variable{publicInputs[0], publicInputs[1]}}
+
+	fullWitness, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	publicWitness, err := fullWitness.Public()
+	require.NoError(t, err)
+
+	proof, err := plonk.Prove(cs, pk, fullWitness)
+	require.NoError(t, err)
+
+	encoded := SerializeProofSolidityBn254(proof)
+
+	decoded, err := DeserializeProofSolidityBn254(vk.(*plonk_bn254.VerifyingKey), encoded, publicInputs)
+	require.NoError(t, err)
+	assert.Equal(t, proof, decoded)
+	require.NoError(t, plonk.Verify(decoded, vk, publicWitness))
+
+	// A proof decoded for other public inputs must not pass.
+	otherInputs := []fr.Element{fr.NewElement(4), fr.NewElement(5)}
+	decoded, err = DeserializeProofSolidityBn254(vk.(*plonk_bn254.VerifyingKey), encoded, otherInputs)
+	require.NoError(t, err)
+	otherAssignment := &circuit{Input: []frontend.Variable{otherInputs[0], otherInputs[1]}}
+	otherWitness, err := frontend.NewWitness(otherAssignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	require.NoError(t, err)
+	require.Error(t, plonk.Verify(decoded, vk, otherWitness))
+
+	// Truncated or tampered encodings are rejected.
+	raw, err := hexutil.Decode(encoded)
+	require.NoError(t, err)
+
+	_, err = DeserializeProofSolidityBn254(vk.(*plonk_bn254.VerifyingKey), hexutil.Encode(raw[:len(raw)-1]), publicInputs)
+	require.Error(t, err)
+
+	raw[0] ^= 1
+	_, err = DeserializeProofSolidityBn254(vk.(*plonk_bn254.VerifyingKey), hexutil.Encode(raw), publicInputs)
+	require.Error(t, err)
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// adminHistorySize is the number of finished jobs reported by the admin API
+const adminHistorySize = 20
+
+// ActiveJobInfo describes the job the controller is processing
+type ActiveJobInfo struct {
+	File      string    `json:"file"`
+	Type      string    `json:"type"`
+	StartedAt time.Time `json:"startedAt"`
+	// Retry is true once the job is retried locally in large mode
+	Retry bool `json:"retry"`
+	// PID is the PID of the child process of the current attempt. It is zero
+	// until the process is started.
+	PID int `json:"pid"`
+}
+
+// FinishedJobInfo describes a job the controller has finished processing
+type FinishedJobInfo struct {
+	File       string    `json:"file"`
+	Type       string    `json:"type"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	ExitCode   int       `json:"exitCode"`
+	What       string    `json:"what"`
+	Large      bool      `json:"large"`
+}
+
+// AdminStatus is the response of the status endpoint of the admin API
+type AdminStatus struct {
+	Paused   bool           `json:"paused"`
+	Draining bool           `json:"draining"`
+	Active   *ActiveJobInfo `json:"activeJob"`
+	// QueueDepth is the number of jobs waiting in the queue, by job type
+	QueueDepth map[string]int `json:"queueDepth"`
+	// History lists the last finished jobs, the most recent first
+	History []FinishedJobInfo `json:"history"`
+}
+
+// Admin keeps track of the state of the controller and serves it over HTTP.
+// It also allows pausing the controller and requesting a graceful drain.
+type Admin struct {
+	fsWatcher *FsWatcher
+	// drain requests a graceful shutdown of the controller. It returns false
+	// if the request could not be delivered.
+	drain func() bool
+	// draining returns true once a graceful shutdown has been requested
+	draining func() bool
+
+	paused atomic.Bool
+
+	mu      sync.Mutex
+	active  *ActiveJobInfo
+	history []FinishedJobInfo
+}
+
+// NewAdmin returns an [Admin] reporting the queue of fsWatcher. The drain and
+// draining functions request and report the graceful shutdown of the
+// controller.
+func NewAdmin(fsWatcher *FsWatcher, drain, draining func() bool) *Admin {
+	return &Admin{
+		fsWatcher: fsWatcher,
+		drain:     drain,
+		draining:  draining,
+	}
+}
+
+// Paused returns true if the controller should not pick new jobs
+func (a *Admin) Paused() bool {
+	return a.paused.Load()
+}
+
+// jobStarted records that the controller started processing job
+func (a *Admin) jobStarted(job *Job) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active = &ActiveJobInfo{
+		File:      job.OriginalFile,
+		Type:      job.Def.Name,
+		StartedAt: time.Now().UTC(),
+	}
+}
+
+// processStarted records the PID of the child process running the active
+// job. It is meant to be used as [Executor.OnStart].
+func (a *Admin) processStarted(_ *Job, pid int, retry bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.active == nil {
+		return
+	}
+	a.active.PID = pid
+	a.active.Retry = a.active.Retry || retry
+}
+
+// jobFinished records that the active job finished with status
+func (a *Admin) jobFinished(job *Job, status Status) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	startedAt := time.Time{}
+	if a.active != nil {
+		startedAt = a.active.StartedAt
+	}
+
+	finished := FinishedJobInfo{
+		File:       job.OriginalFile,
+		Type:       job.Def.Name,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now().UTC(),
+		ExitCode:   status.ExitCode,
+		What:       status.What,
+		Large:      status.Large,
+	}
+
+	a.history = append([]FinishedJobInfo{finished}, a.history...)
+	if len(a.history) > adminHistorySize {
+		a.history = a.history[:adminHistorySize]
+	}
+	a.active = nil
+}
+
+// Status returns the current state of the controller
+func (a *Admin) Status() AdminStatus {
+
+	// Listing the queue reads the filesystem so it is done without the lock
+	queueDepth := a.fsWatcher.QueueDepth()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	res := AdminStatus{
+		Paused:     a.Paused(),
+		Draining:   a.draining(),
+		QueueDepth: queueDepth,
+		History:    append([]FinishedJobInfo{}, a.history...),
+	}
+
+	if a.active != nil {
+		active := *a.active
+		res.Active = &active
+	}
+
+	return res
+}
+
+// Handler returns the handler of the admin API. The routes are:
+//
+//   - GET /admin/status: the [AdminStatus] of the controller
+//   - POST /admin/drain: finishes the active job and exits, as on SIGTERM
+//   - POST /admin/pause: stops picking new jobs
+//   - POST /admin/resume: resumes picking new jobs
+//
+// The POST routes require the header "Authorization: Bearer <token>" and are
+// rejected with 401 otherwise. If token is empty, they are disabled and always
+// rejected with 403.
+func (a *Admin) Handler(token string) http.Handler {
+
+	mux := http.NewServeMux()
+	post := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(pattern, requireAdminToken(token, handler))
+	}
+
+	mux.HandleFunc("GET /admin/status", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, a.Status())
+	})
+
+	post("POST /admin/drain", func(w http.ResponseWriter, r *http.Request) {
+		logrus.Info("Admin API: drain requested")
+		if !a.drain() {
+			http.Error(w, "could not request the drain, retry later", http.StatusServiceUnavailable)
+			return
+		}
+		writeAdminJSON(w, http.StatusAccepted, a.Status())
+	})
+
+	post("POST /admin/pause", func(w http.ResponseWriter, r *http.Request) {
+		logrus.Info("Admin API: pausing the controller")
+		a.paused.Store(true)
+		writeAdminJSON(w, http.StatusOK, a.Status())
+	})
+
+	post("POST /admin/resume", func(w http.ResponseWriter, r *http.Request) {
+		logrus.Info("Admin API: resuming the controller")
+		a.paused.Store(false)
+		writeAdminJSON(w, http.StatusOK, a.Status())
+	})
+
+	return mux
+}
+
+// requireAdminToken wraps next so that it is only served to the requests
+// bearing token.
+func requireAdminToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			http.Error(w, "no admin API token is configured", http.StatusForbidden)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			logrus.Warnf("Admin API: rejected unauthenticated request %v %v from %v", r.Method, r.URL.Path, r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAdminJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("Admin API: could not encode the response: %v", err)
+	}
+}
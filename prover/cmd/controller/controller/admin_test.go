@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"text/template"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminAPI(t *testing.T) {
+
+	cfg := setupFsTestSpotInstance(t)
+	createTestInputFile(cfg.Execution.DirFrom(), 0, 1, execJob, 0)
+	createTestInputFile(cfg.Execution.DirFrom(), 1, 2, execJob, 0)
+
+	var (
+		draining  atomic.Bool
+		fsWatcher = NewFsWatcher(cfg)
+		admin     = NewAdmin(fsWatcher, func() bool { draining.Store(true); return true }, draining.Load)
+		srv       = httptest.NewServer(admin.Handler(testAdminToken))
+	)
+	defer srv.Close()
+
+	getStatus := func() AdminStatus {
+		resp, err := http.Get(srv.URL + "/admin/status")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var status AdminStatus
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		return status
+	}
+
+	post := func(route string, expCode int) {
+		require.Equal(t, expCode, postAdmin(t, srv.URL+route, testAdminToken), route)
+	}
+
+	status := getStatus()
+	assert.Nil(t, status.Active)
+	assert.Equal(t, 2, status.QueueDepth[jobNameExecution])
+	assert.Empty(t, status.History)
+
+	// The locked job is no longer counted in the queue
+	job := fsWatcher.GetBest()
+	require.NotNil(t, job)
+	admin.jobStarted(job)
+	admin.processStarted(job, 1234, false)
+	admin.processStarted(job, 1235, true)
+
+	status = getStatus()
+	require.NotNil(t, status.Active)
+	assert.Equal(t, job.OriginalFile, status.Active.File)
+	assert.Equal(t, jobNameExecution, status.Active.Type)
+	assert.Equal(t, 1235, status.Active.PID)
+	assert.True(t, status.Active.Retry)
+	assert.Equal(t, 1, status.QueueDepth[jobNameExecution])
+
+	admin.jobFinished(job, Status{ExitCode: CodeUnsatisfied, What: "exit code 78", Large: true})
+
+	status = getStatus()
+	assert.Nil(t, status.Active)
+	require.Len(t, status.History, 1)
+	assert.Equal(t, job.OriginalFile, status.History[0].File)
+	assert.Equal(t, CodeUnsatisfied, status.History[0].ExitCode)
+	assert.True(t, status.History[0].Large)
+
+	post("/admin/pause", http.StatusOK)
+	assert.True(t, admin.Paused())
+	assert.True(t, getStatus().Paused)
+
+	post("/admin/resume", http.StatusOK)
+	assert.False(t, admin.Paused())
+
+	post("/admin/drain", http.StatusAccepted)
+	assert.True(t, getStatus().Draining)
+
+	// The mutating routes are POST only
+	resp, err := http.Get(srv.URL + "/admin/pause")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.False(t, admin.Paused())
+}
+
+func TestAdminAPIAuth(t *testing.T) {
+
+	admin := NewAdmin(NewFsWatcher(setupFsTestSpotInstance(t)), func() bool { return true }, func() bool { return false })
+
+	srv := httptest.NewServer(admin.Handler(testAdminToken))
+	defer srv.Close()
+
+	for _, route := range []string{"/admin/pause", "/admin/resume", "/admin/drain"} {
+		assert.Equal(t, http.StatusUnauthorized, postAdmin(t, srv.URL+route, ""), route)
+		assert.Equal(t, http.StatusUnauthorized, postAdmin(t, srv.URL+route, "wrong-token"), route)
+	}
+	assert.False(t, admin.Paused())
+
+	// The status stays readable without the token
+	resp, err := http.Get(srv.URL + "/admin/status")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Without a configured token, the POST routes are disabled
+	noToken := httptest.NewServer(admin.Handler(""))
+	defer noToken.Close()
+
+	assert.Equal(t, http.StatusForbidden, postAdmin(t, noToken.URL+"/admin/pause", ""))
+	assert.Equal(t, http.StatusForbidden, postAdmin(t, noToken.URL+"/admin/pause", testAdminToken))
+	assert.False(t, admin.Paused())
+}
+
+func TestAdminHistorySize(t *testing.T) {
+
+	admin := NewAdmin(NewFsWatcher(setupFsTestSpotInstance(t)), nil, func() bool { return false })
+	jdef := &JobDefinition{Name: jobNameExecution}
+
+	for i := 0; i < adminHistorySize+5; i++ {
+		job := &Job{Def: jdef, OriginalFile: string(rune('a' + i))}
+		admin.jobStarted(job)
+		admin.jobFinished(job, Status{ExitCode: i})
+	}
+
+	history := admin.Status().History
+	require.Len(t, history, adminHistorySize)
+	assert.Equal(t, adminHistorySize+4, history[0].ExitCode)
+}
+
+func TestExecutorOnStart(t *testing.T) {
+
+	var (
+		pids    []int
+		retries []bool
+		jdef    = JobDefinition{
+			Name:            jobNameExecution,
+			OutputFileTmpl:  template.Must(template.New("output-file").Parse("output-fill-constant")),
+			RequestsRootDir: "./testdata",
+		}
+		job = &Job{Def: &jdef, LockedFile: "exit-77.sh"}
+	)
+
+	e := NewExecutor(&config.Config{
+		Controller: config.Controller{
+			WorkerCmdTmpl:              template.Must(template.New("test-cmd").Parse("/bin/sh {{.InFile}}")),
+			WorkerCmdLargeTmpl:         template.Must(template.New("test-cmd-large").Parse("/bin/sh {{.InFile}}")),
+			RetryLocallyWithLargeCodes: config.DefaultRetryLocallyWithLargeCodes,
+		},
+	})
+
+	e.OnStart = func(_ *Job, pid int, retry bool) {
+		pids = append(pids, pid)
+		retries = append(retries, retry)
+	}
+
+	status := e.Run(context.Background(), job)
+	assert.Equal(t, CodeTraceLimit, status.ExitCode)
+	assert.True(t, status.Large)
+	require.Len(t, pids, 2)
+	assert.NotZero(t, pids[0])
+	assert.Equal(t, []bool{false, true}, retries)
+}
+
+const testAdminToken = "test-admin-token"
+
+// postAdmin sends a POST request to url with the bearer token, if any, and
+// returns the status code of the response.
+func postAdmin(t *testing.T, url, token string) int {
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
@@ -43,6 +43,7 @@ func cobraControllerRunCmd(c *cobra.Command, args []string) {
 		logrus.Fatalf("could not get the config : %v", err)
 	}
 	cfg.Controller.LocalID = fLocalID
+	cfg.Controller.AdminAPIToken = os.Getenv("PROVER_ADMIN_API_TOKEN")
 
 	// TODO @gbotrel @AlexandreBelling check who is responsible for creating the directories
 	// create the sub directories if they do not exist
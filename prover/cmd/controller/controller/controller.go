@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -24,6 +25,7 @@ func runController(ctx context.Context, cfg *config.Config) {
 		cLog      = cfg.Logger().WithField("component", "main-loop")
 		fsWatcher = NewFsWatcher(cfg)
 		executor  = NewExecutor(cfg)
+		bundles   = newReplayBundleWriter(cLog, cfg, fConfig)
 
 		// Track currently active job for safe requeue
 		activeJob      *Job
@@ -63,12 +65,36 @@ func runController(ctx context.Context, cfg *config.Config) {
 		cLog.Infof("REQUEUED job: %v", job.OriginalFile)
 	}
 
+	// The admin API requests a drain by emulating a SIGTERM so that both go
+	// through the same handling.
+	admin := NewAdmin(
+		fsWatcher,
+		func() bool {
+			select {
+			case signalChan <- syscall.SIGTERM:
+				return true
+			default:
+				return false
+			}
+		},
+		gracefulShutdownRequested.Load,
+	)
+	executor.OnStart = admin.processStarted
+
 	// Start the metric server
-	if cfg.Controller.Prometheus.Enabled {
+	if cfg.Controller.Prometheus.Enabled || cfg.Controller.EnableAdminAPI {
+		var adminHandler http.Handler
+		if cfg.Controller.EnableAdminAPI {
+			if cfg.Controller.AdminAPIToken == "" {
+				cLog.Warn("PROVER_ADMIN_API_TOKEN is not set, the POST routes of the admin API are disabled")
+			}
+			adminHandler = admin.Handler(cfg.Controller.AdminAPIToken)
+		}
 		metrics.StartServer(
 			cfg.Controller.LocalID,
 			cfg.Controller.Prometheus.Route,
 			cfg.Controller.Prometheus.Port,
+			adminHandler,
 		)
 	}
 
@@ -224,6 +250,19 @@ func runController(ctx context.Context, cfg *config.Config) {
 				continue
 			}
 
+			// Skip fetching new jobs if the controller has been paused through
+			// the admin API
+			if admin.Paused() {
+				numRetrySoFar++
+				pausedMsg := "Controller paused, skipping new jobs"
+				if numRetrySoFar > 5 {
+					cLog.Debug(pausedMsg)
+				} else {
+					cLog.Info(pausedMsg)
+				}
+				continue
+			}
+
 			// Fetch the best block we can fetch
 			job := fsWatcher.GetBest()
 
@@ -247,7 +286,9 @@ func runController(ctx context.Context, cfg *config.Config) {
 			activeJobMutex.Unlock()
 
 			// Run the command (potentially retrying in large mode)
+			admin.jobStarted(job)
 			status := executor.Run(cmdCtx, job)
+			admin.jobFinished(job, status)
 
 			// CreateColumns the job according to the status we got
 			switch {
@@ -327,6 +368,22 @@ func runController(ctx context.Context, cfg *config.Config) {
 				activeJobMutex.Unlock()
 				notifyJobDone()
 
+			// The watchdog killed a stuck prover: the job is put back in the
+			// queue once, possibly for another worker. A second kill by the
+			// watchdog is handled as a failure.
+			case status.ExitCode == CodeWatchdog && !job.HasFailedWith(CodeWatchdog):
+				requeuePath := job.RequeueFile(status)
+				cLog.Infof("Requeuing %v as %v: %v", job.OriginalFile, requeuePath, status.What)
+				os.Remove(job.TmpResponseFile(cfg))
+				if err := os.Rename(job.InProgressPath(), requeuePath); err != nil {
+					cLog.Errorf("Error renaming %v to %v: %v", job.InProgressPath(), requeuePath, err)
+				}
+
+				activeJobMutex.Lock()
+				activeJob = nil
+				activeJobMutex.Unlock()
+				notifyJobDone()
+
 			// Controller killed the job via external signal handlers
 			// Important not to set active job to nil so that it can re-queued again if necessary
 			case status.ExitCode == CodeKilledByUs:
@@ -344,6 +401,10 @@ func runController(ctx context.Context, cfg *config.Config) {
 
 			// Failure case
 			default:
+				// Collect what is needed to reproduce the failure while the
+				// request is still in progress.
+				bundles.tryWrite(ctx, job, status)
+
 				// Move the inprogress to the done directory
 				cLog.Infof("Moving %v with failure suffix (code %v)", job.OriginalFile, status.ExitCode)
 				jobFailed := job.DoneFile(status)
@@ -28,6 +28,7 @@ const (
 	CodeFatal            int = 14   // When the process could not start
 	CodeCantRunCommand   int = 15   // When the controller could not run the command
 	CodeKilledByUs       int = 1137 // When the process is killed by the controller. We purposefully use a non-Unix code.
+	CodeWatchdog         int = 1124 // When the process is killed by the watchdog on timeout or missing heartbeats
 )
 
 // Status of a finished job
@@ -38,6 +39,8 @@ type Status struct {
 	What string
 	// Additional errors for context
 	Err error
+	// Whether the last run of the job used the large command
+	Large bool
 }
 
 // Resource collects all the informations about the job that can be used to
@@ -54,6 +57,9 @@ type Executor struct {
 	Config *config.Config
 	// Logger specific to the executor
 	Logger *logrus.Entry
+	// OnStart is optional and called with the PID of every process started
+	// by the executor. Retry is true for the local retries in large mode.
+	OnStart func(job *Job, pid int, retry bool)
 }
 
 func NewExecutor(cfg *config.Config) *Executor {
@@ -92,10 +98,11 @@ func (e *Executor) Run(ctx context.Context, job *Job) (status Status) {
 	}
 
 	// Run the initial command
-	status = runCmd(ctx, cmd, job, false)
+	status = e.runCmd(ctx, cmd, job, false)
+	status.Large = largeRun
 
 	// Do not retry for blob decompression, aggregation or invalidity jobs
-	if job.Def.Name == jobNameDataAvailability || job.Def.Name == jobNameAggregation || job.Def.Name == jobNameInvalidity {
+	if job.Def.Name == jobNameDataAvailability || job.Def.Name == jobNameAggregation || job.Def.Name == jobNameInvalidity || job.Def.Name == jobNameInvalidityBatch {
 		return status
 	}
 
@@ -115,7 +122,7 @@ func (e *Executor) Run(ctx context.Context, job *Job) (status Status) {
 	if isIn(status.ExitCode, retryableCodes) {
 		if largeRun {
 			// For large jobs, retry with the same large command
-			status = runCmd(ctx, cmd, job, true)
+			status = e.runCmd(ctx, cmd, job, true)
 		} else {
 			// For regular jobs, retry with the large command
 			largeCmd, err := e.buildCmd(job, true)
@@ -126,8 +133,9 @@ func (e *Executor) Run(ctx context.Context, job *Job) (status Status) {
 					What:     "can't format the command",
 				}
 			}
-			status = runCmd(ctx, largeCmd, job, true)
+			status = e.runCmd(ctx, largeCmd, job, true)
 		}
+		status.Large = true
 	}
 
 	return status
@@ -178,7 +186,7 @@ func (e *Executor) buildCmd(job *Job, large bool) (cmd string, err error) {
 
 // Run a command and returns the status. Retry gives an indication on whether
 // this is a local retry or not.
-func runCmd(ctx context.Context, cmdStr string, job *Job, retry bool) Status {
+func (e *Executor) runCmd(ctx context.Context, cmdStr string, job *Job, retry bool) Status {
 	logrus.Infof("The executor is about to run the command: %s", cmdStr)
 
 	// Build exec.Command so we can set process group
@@ -192,6 +200,15 @@ func runCmd(ctx context.Context, cmdStr string, job *Job, retry bool) Status {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
+	// A heartbeat file left by a previous run must not be mistaken for the
+	// heartbeats of this one.
+	hbFile := e.heartbeatFile()
+	if len(hbFile) > 0 {
+		os.Remove(hbFile)
+		defer os.Remove(hbFile)
+		cmd.Env = append(os.Environ(), config.HeartbeatFileEnv+"="+hbFile)
+	}
+
 	pname := processName(job, cmdStr)
 	metrics.CollectPreProcess(job.Def.Name, job.Start, job.End, false)
 
@@ -210,8 +227,16 @@ func runCmd(ctx context.Context, cmdStr string, job *Job, retry bool) Status {
 		}
 	}
 
+	if e.OnStart != nil {
+		e.OnStart(job, cmd.Process.Pid, retry)
+	}
+
 	done := make(chan Status)
 
+	stopWatchdog := make(chan struct{})
+	defer close(stopWatchdog)
+	watchdog := e.watchdog(job, hbFile, startTime, stopWatchdog)
+
 	go func() {
 
 		// Since the channel is used for sending only once and only in this
@@ -273,6 +298,20 @@ func runCmd(ctx context.Context, cmdStr string, job *Job, retry bool) Status {
 			What:     "the process was requested to be killed by the controller (process may not have started)",
 		}
 
+	case reason := <-watchdog:
+		// The process is likely stuck so it is not given the chance to
+		// terminate gracefully.
+		logrus.Errorf("The watchdog is killing the process %v: %v", pname, reason)
+		if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			logrus.Warnf("failed to send SIGKILL to process group %v: %v. Killing the process only", cmd.Process.Pid, err)
+			_ = cmd.Process.Kill()
+		}
+		<-done
+		return Status{
+			ExitCode: CodeWatchdog,
+			What:     "killed by the watchdog: " + reason,
+		}
+
 	case status := <-done:
 		return status
 	}
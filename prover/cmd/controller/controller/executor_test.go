@@ -144,3 +144,64 @@ func TestEarlyExitOnSpotInstanceMode(t *testing.T) {
 	// We wait 3 more second to ensure all sub-process have exited
 	time.Sleep(3 * time.Second)
 }
+
+func TestExecutorWatchdog(t *testing.T) {
+
+	testDefinition := JobDefinition{
+		Name: jobNameExecution,
+		OutputFileTmpl: template.Must(
+			template.New("output-file").
+				Parse("output-fill-constant"),
+		),
+		RequestsRootDir: "./testdata",
+	}
+
+	testcases := []struct {
+		Explainer  string
+		LockedFile string
+		Controller config.Controller
+	}{
+		{
+			Explainer:  "the job timeout is exceeded",
+			LockedFile: "sleep-4.sh",
+			Controller: config.Controller{JobTimeouts: map[string]int{jobNameExecution: 1}},
+		},
+		{
+			Explainer:  "the heartbeats stop",
+			LockedFile: "heartbeat-stall.sh",
+			Controller: config.Controller{LocalID: "test-watchdog", HeartbeatTimeout: 1},
+		},
+	}
+
+	for _, c := range testcases {
+		t.Run(c.Explainer, func(t *testing.T) {
+
+			c.Controller.WorkerCmdTmpl = template.Must(
+				template.New("test-cmd").
+					Parse("/bin/sh {{.InFile}}"),
+			)
+
+			e := NewExecutor(&config.Config{Controller: c.Controller})
+			job := &Job{Def: &testDefinition, LockedFile: c.LockedFile}
+
+			start := time.Now()
+			status := e.Run(context.Background(), job)
+
+			assert.Equalf(t, CodeWatchdog, status.ExitCode, "got status %++v", status)
+			assert.Less(t, time.Since(start), 4*time.Second, "the process was not killed")
+		})
+	}
+
+	// Without a timeout nor a heartbeat timeout, the prover is not interrupted
+	e := NewExecutor(&config.Config{
+		Controller: config.Controller{
+			WorkerCmdTmpl: template.Must(
+				template.New("test-cmd").
+					Parse("/bin/sh {{.InFile}}"),
+			),
+		},
+	})
+
+	status := e.Run(context.Background(), &Job{Def: &testDefinition, LockedFile: "exit-0.sh"})
+	assert.Equal(t, CodeSuccess, status.ExitCode)
+}
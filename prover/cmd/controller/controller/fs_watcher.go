@@ -49,6 +49,7 @@ func NewFsWatcher(conf *config.Config) *FsWatcher {
 
 	if conf.Controller.EnableInvalidity {
 		fs.JobToWatch = append(fs.JobToWatch, InvalidityDefinition(conf))
+		fs.JobToWatch = append(fs.JobToWatch, InvalidityBatchDefinition(conf))
 	}
 
 	return fs
@@ -107,6 +108,27 @@ func (fs *FsWatcher) GetBest() (job *Job) {
 	return jobs[best]
 }
 
+// QueueDepth returns the number of jobs waiting in the queue of each job
+// definition. The jobs are not locked.
+func (fs *FsWatcher) QueueDepth() map[string]int {
+
+	res := make(map[string]int, len(fs.JobToWatch))
+	for i := range fs.JobToWatch {
+		jdef := &fs.JobToWatch[i]
+		jobs := []*Job{}
+		if err := fs.appendJobFromDef(jdef, &jobs); err != nil {
+			fs.Logger.Errorf(
+				"Got an error trying to count jobs `%v` in dir %v: %v",
+				jdef.Name, jdef.dirFrom(), err,
+			)
+			continue
+		}
+		res[jdef.Name] += len(jobs)
+	}
+
+	return res
+}
+
 // Returns the best file that we could lock and its position in the slice. If
 // everything failed returns 0, false.
 func (f *FsWatcher) lockBest(jobs []*Job) (pos int, success bool) {
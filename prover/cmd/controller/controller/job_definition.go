@@ -15,6 +15,7 @@ const (
 	jobNameDataAvailability = "compression"
 	jobNameAggregation      = "aggregation"
 	jobNameInvalidity       = "invalidity"
+	jobNameInvalidityBatch  = "invalidity-batch"
 )
 
 // JobDefinition represents a collection of static parameters allowing to define
@@ -269,6 +270,48 @@ func InvalidityDefinition(conf *config.Config) JobDefinition {
 	}
 }
 
+// Definition of a batch invalidity prover job. The requests share the
+// directories of the invalidity jobs, the file names of the two kinds of job
+// do not overlap.
+func InvalidityBatchDefinition(conf *config.Config) JobDefinition {
+
+	return JobDefinition{
+		RequestsRootDir: conf.Invalidity.RequestsRootDir,
+
+		Name: jobNameInvalidityBatch,
+
+		InputFileRegexp: regexp2.MustCompile(
+			fmt.Sprintf(
+				`^[0-9]+-[0-9]+-getZkInvalidityBatchProof\.json(\.failure\.%v_[0-9]+)*$`,
+				config.FailSuffix,
+			),
+			regexp2.None,
+		),
+
+		OutputFileTmpl: tmplMustCompile(
+			"invalidity-batch-output-file",
+			"{{.Start}}-{{.End}}-getZkInvalidityBatchProof.json",
+		),
+
+		// Batch invalidity proofs have the same priority as the single ones
+		Priority: 0,
+
+		ParamsRegexp: struct {
+			Start       *regexp2.Regexp
+			End         *regexp2.Regexp
+			Stv         *regexp2.Regexp
+			Etv         *regexp2.Regexp
+			Cv          *regexp2.Regexp
+			ContentHash *regexp2.Regexp
+		}{
+			Start: regexp2.MustCompile(`^[0-9]+`, regexp2.None),
+			End:   regexp2.MustCompile(`(?<=^[0-9]+-)[0-9]+`, regexp2.None),
+		},
+
+		FailureSuffix: matchFailureSuffix(config.FailSuffix),
+	}
+}
+
 // Version prefix template
 func matchVersionWithPrefix(pre string) *regexp2.Regexp {
 	return regexp2.MustCompile(
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"path"
 	"testing"
 
 	"github.com/consensys/linea-monorepo/prover/config"
@@ -294,6 +295,56 @@ func TestInvalidityInFileRegexp(t *testing.T) {
 	}
 }
 
+func TestInvalidityBatchInFileRegexp(t *testing.T) {
+
+	var (
+		correctM           = "1000-1010-getZkInvalidityBatchProof.json"
+		correctWithFailM   = "1000-1010-getZkInvalidityBatchProof.json.failure.code_77"
+		correctWith2FailsM = "1000-1010-getZkInvalidityBatchProof.json.failure.code_77.failure.code_77"
+		notAPoint          = "1000-1010-getZkInvalidityBatchProofAjson"
+		singleInvalidity   = "1000-10-getZkInvalidityProof.json"
+		wrongFormat        = "1000-getZkInvalidityBatchProof.json"
+	)
+
+	var (
+		respM = "responses/1000-1010-getZkInvalidityBatchProof.json"
+		// #nosec G101 -- Not a credential
+		respWithFailM = "responses/1000-1010-getZkInvalidityBatchProof.json"
+		// #nosec G101 -- Not a credential
+		respWith2FailsM = "responses/1000-1010-getZkInvalidityBatchProof.json"
+	)
+
+	testcase := []inpFileNamesCases{
+		{
+			Ext: "", Fail: "code", ShouldMatch: true,
+			Fnames:         []string{correctM, correctWithFailM, correctWith2FailsM},
+			Explainer:      "happy path batch invalidity",
+			ExpectedOutput: []string{respM, respWithFailM, respWith2FailsM},
+		},
+		{
+			Ext: "", Fail: "code", ShouldMatch: false,
+			Fnames:    []string{notAPoint, singleInvalidity, wrongFormat},
+			Explainer: "does not pick obviously invalid files",
+		},
+	}
+
+	for _, c := range testcase {
+		conf := config.Config{}
+		conf.Version = "0.1.2"
+
+		def := InvalidityBatchDefinition(&conf)
+
+		t.Run(c.Explainer, func(t *testing.T) {
+			runInpFileTestCase(t, &def, c)
+		})
+	}
+
+	// the batch requests must not be picked by the single invalidity jobs
+	def := InvalidityDefinition(&config.Config{})
+	_, err := NewJob(&def, correctM)
+	assert.Error(t, err)
+}
+
 func runInpFileTestCase(t *testing.T, def *JobDefinition, c inpFileNamesCases) {
 
 	for i, fname := range c.Fnames {
@@ -375,3 +426,39 @@ func TestFailSuffixMatching(t *testing.T) {
 		assert.Equalf(t, c.ncodes, found, "failed to parse %v", c.s)
 	}
 }
+
+func TestRequeueFile(t *testing.T) {
+
+	for _, large := range []bool{false, true} {
+
+		conf := config.Config{}
+		conf.Version = "0.1.2"
+		conf.Execution.CanRunFullLarge = large
+
+		def := ExecutionDefinition(&conf)
+
+		fname := "102-103-etv0.2.3-stv1.2.3-getZkProof.json"
+		if large {
+			fname += ".large"
+		}
+		fname += ".failure.code_137"
+
+		job, err := NewJob(&def, fname)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.False(t, job.HasFailedWith(CodeWatchdog))
+		assert.True(t, job.HasFailedWith(137))
+		assert.False(t, job.HasFailedWith(13))
+
+		requeued := job.RequeueFile(Status{ExitCode: CodeWatchdog})
+		assert.Equal(t, def.dirFrom()+"/"+fname+".failure.code_1124", requeued)
+
+		// The requeued file must be picked up again by the same kind of prover
+		requeuedJob, err := NewJob(&def, path.Base(requeued))
+		if assert.NoError(t, err) {
+			assert.True(t, requeuedJob.HasFailedWith(CodeWatchdog))
+			assert.True(t, requeuedJob.HasFailedWith(137))
+		}
+	}
+}
@@ -161,6 +161,24 @@ func (j *Job) DeferToLargeFile(status Status) (s string, err error) {
 	), nil
 }
 
+// Returns the name of the input file modified so that the job is picked again
+// from the queue. Unlike [Job.DeferToLargeFile], the previous failure suffixes
+// are kept so that the history of the job can be checked with
+// [Job.HasFailedWith].
+func (j *Job) RequeueFile(status Status) string {
+	return fmt.Sprintf(
+		"%v/%v.failure.%v_%v",
+		j.Def.dirFrom(), j.OriginalFile,
+		config.FailSuffix, status.ExitCode,
+	)
+}
+
+// Returns true if the input file has a failure suffix with the given code
+func (j *Job) HasFailedWith(code int) bool {
+	suffix := fmt.Sprintf(".failure.%v_%v.", config.FailSuffix, code)
+	return strings.Contains(j.OriginalFile+".", suffix)
+}
+
 // Returns the done file following the jobs status
 func (j *Job) DoneFile(status Status) string {
 
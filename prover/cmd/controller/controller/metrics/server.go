@@ -22,8 +22,9 @@ const (
 // Empty prometheus server
 var server *http.Server = nil
 
-// The server will
-func StartServer(worker_id string, route string, port int) {
+// The server will serve the metrics on the provided route. If admin is not
+// nil, it is also served under /admin/.
+func StartServer(worker_id string, route string, port int, admin http.Handler) {
 
 	// If the endpoint is left empty, uses the standard /metrics endpoint
 	if len(route) == 0 {
@@ -64,6 +65,9 @@ func StartServer(worker_id string, route string, port int) {
 		Handler: func() http.Handler {
 			mux := http.NewServeMux()
 			mux.Handle(route, promhttp.Handler())
+			if admin != nil {
+				mux.Handle("/admin/", admin)
+			}
 			return mux
 		}(),
 	}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/replay"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// replayBundleWriter writes the replay bundles of the failed jobs in the
+// background so that a large bundle does not delay picking the next job. It
+// writes one bundle at a time and skips the failures happening meanwhile. A
+// bundle in progress when the controller exits is abandoned.
+type replayBundleWriter struct {
+	cLog     *logrus.Entry
+	cfg      *config.Config
+	confFile string
+	busy     atomic.Bool
+}
+
+func newReplayBundleWriter(cLog *logrus.Entry, cfg *config.Config, confFile string) *replayBundleWriter {
+	return &replayBundleWriter{cLog: cLog, cfg: cfg, confFile: confFile}
+}
+
+// tryWrite writes a replay bundle for a failed job if the controller is
+// configured to do so for its exit code. It must be called before the
+// inprogress file is moved: the request is read before returning and the
+// rest of the bundle is written in the background. Failing to write the
+// bundle is logged and does not alter the flow of the controller.
+func (w *replayBundleWriter) tryWrite(ctx context.Context, job *Job, status Status) {
+
+	if len(w.cfg.Controller.ReplayBundleDir) == 0 || !isIn(status.ExitCode, w.cfg.Controller.ReplayBundleCodes) {
+		return
+	}
+
+	if !w.busy.CompareAndSwap(false, true) {
+		w.cLog.Warnf("A replay bundle is still being written, skipping the one of %v (code %v)", job.OriginalFile, status.ExitCode)
+		return
+	}
+
+	b, err := newReplayBundle(w.cfg, w.confFile, job, status)
+	if err != nil {
+		w.busy.Store(false)
+		w.cLog.Errorf("could not write the replay bundle for %v: %v", job.OriginalFile, err)
+		return
+	}
+
+	go func() {
+		defer w.busy.Store(false)
+
+		if timeout := w.cfg.Controller.ReplayBundleTimeout; timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+			defer cancel()
+		}
+
+		if err := b.write(ctx); err != nil {
+			w.cLog.Errorf("could not write the replay bundle for %v: %v", job.OriginalFile, err)
+			return
+		}
+
+		w.cLog.Infof("Wrote the replay bundle of %v (code %v) to %v", job.OriginalFile, status.ExitCode, b.path)
+	}()
+}
+
+// replayBundle is a replay bundle ready to be written. It holds a copy of the
+// request so that it can be written after the request is moved.
+type replayBundle struct {
+	path     string
+	manifest *replay.Manifest
+	files    replay.Files
+}
+
+// newReplayBundle reads the request of the job and collects what goes in its
+// replay bundle. The conflated trace file is left out if it is larger than
+// the configured limit.
+func newReplayBundle(cfg *config.Config, confFile string, job *Job, status Status) (*replayBundle, error) {
+
+	request, err := os.ReadFile(job.InProgressPath())
+	if err != nil {
+		return nil, fmt.Errorf("could not read the request: %w", err)
+	}
+
+	files := replay.Files{
+		Request:        job.InProgressPath(),
+		RequestContent: request,
+		Config:         confFile,
+	}
+
+	if job.Def.Name == jobNameExecution {
+		traceFile, err := executionTraceFile(cfg, request)
+		if err != nil {
+			return nil, err
+		}
+
+		// The trace file may have been deleted already, in which case the
+		// bundle is still useful to investigate the request.
+		info, err := os.Stat(traceFile)
+		maxSize := cfg.Controller.ReplayBundleMaxTraceSizeMB << 20
+		switch {
+		case err != nil:
+			logrus.Warnf("the trace file %v of %v is not included in the replay bundle: %v", traceFile, job.OriginalFile, err)
+		case maxSize > 0 && info.Size() > maxSize:
+			logrus.Warnf("the trace file %v of %v is not included in the replay bundle: %v bytes exceed the limit of %v MiB", traceFile, job.OriginalFile, info.Size(), cfg.Controller.ReplayBundleMaxTraceSizeMB)
+		default:
+			files.Trace = traceFile
+		}
+	}
+
+	setupChecksums, err := replay.ReadSetupChecksums(cfg)
+	if err != nil {
+		// The setup may not be available for the dev or proofless modes
+		logrus.Warnf("the setup checksums are not included in the replay bundle: %v", err)
+	}
+
+	// The bundle keeps the original name of the request as the prover infers
+	// the type of the job from it.
+	m := &replay.Manifest{
+		JobName:        job.Def.Name,
+		Request:        job.OriginalFile,
+		Large:          status.Large,
+		ExitCode:       status.ExitCode,
+		ProverVersion:  cfg.Version,
+		ProverRevision: replay.BuildRevision(),
+		SetupChecksums: setupChecksums,
+		Env:            replay.EnvOverrides(),
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	bundleName := fmt.Sprintf("%v.code-%v.tgz", job.OriginalFile, status.ExitCode)
+
+	return &replayBundle{
+		path:     filepath.Join(cfg.Controller.ReplayBundleDir, bundleName),
+		manifest: m,
+		files:    files,
+	}, nil
+}
+
+func (b *replayBundle) write(ctx context.Context) error {
+	return replay.Write(ctx, b.path, b.manifest, b.files)
+}
+
+// executionTraceFile returns the path of the conflated trace file of an
+// execution request.
+func executionTraceFile(cfg *config.Config, request []byte) (string, error) {
+
+	req := &execution.Request{}
+	if err := json.Unmarshal(request, req); err != nil {
+		return "", fmt.Errorf("could not decode the request: %w", err)
+	}
+
+	return req.ConflatedExecTraceFilepath(cfg.Execution.ConflatedTracesDir), nil
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/backend/replay"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeReplayBundle writes the replay bundle of the job in the foreground and
+// returns its path.
+func writeReplayBundle(ctx context.Context, cfg *config.Config, confFile string, job *Job, status Status) (string, error) {
+
+	b, err := newReplayBundle(cfg, confFile, job, status)
+	if err != nil {
+		return "", err
+	}
+
+	if err := b.write(ctx); err != nil {
+		return "", err
+	}
+
+	return b.path, nil
+}
+
+func TestWriteReplayBundle(t *testing.T) {
+
+	var (
+		testDir  = t.TempDir()
+		confFile = path.Join(testDir, "config.toml")
+		cfg      = &config.Config{
+			Version:   "0.2.4",
+			AssetsDir: path.Join(testDir, "assets"),
+			Controller: config.Controller{
+				LocalID:           "test-prover-id",
+				ReplayBundleDir:   path.Join(testDir, "replay"),
+				ReplayBundleCodes: config.DefaultReplayBundleCodes,
+			},
+			Execution: config.Execution{
+				WithRequestDir:     config.WithRequestDir{RequestsRootDir: path.Join(testDir, "execution")},
+				ConflatedTracesDir: path.Join(testDir, "traces"),
+			},
+		}
+		jdef     = ExecutionDefinition(cfg)
+		original = "10-12-getZkProof.json"
+	)
+
+	require.NoError(t, os.MkdirAll(cfg.Execution.DirFrom(), 0o755))
+	require.NoError(t, os.MkdirAll(cfg.Execution.ConflatedTracesDir, 0o755))
+	require.NoError(t, os.MkdirAll(path.Join(cfg.AssetsDir, cfg.Version, "execution"), 0o755))
+	require.NoError(t, os.WriteFile(confFile, []byte("version = \"0.2.4\"\n"), 0o600))
+	require.NoError(t, os.WriteFile(path.Join(cfg.Execution.ConflatedTracesDir, "10-12.conflated.lt"), []byte("trace"), 0o600))
+	require.NoError(t, os.WriteFile(
+		path.Join(cfg.AssetsDir, cfg.Version, "execution", config.ManifestFileName),
+		[]byte(`{"circuitName":"execution","checksums":{"verifyingKey":"vk","circuit":"circuit"}}`), 0o600))
+
+	job, err := NewJob(&jdef, original)
+	require.NoError(t, err)
+	job.LockedFile = original + "." + config.InProgressSuffix + "." + cfg.Controller.LocalID
+	require.NoError(t, os.WriteFile(job.InProgressPath(), []byte(`{"conflatedExecutionTracesFile":"10-12.conflated.lt"}`), 0o600))
+
+	t.Setenv("LIMITLESS_SUBPROVER_JOBS", "2")
+
+	bundlePath, err := writeReplayBundle(context.Background(), cfg, confFile, job, Status{ExitCode: CodeUnsatisfied, Large: true})
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(cfg.Controller.ReplayBundleDir, original+".code-78.tgz"), bundlePath)
+
+	dir := t.TempDir()
+	m, err := replay.Extract(bundlePath, dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, original, m.Request)
+	assert.Equal(t, "10-12.conflated.lt", m.Trace)
+	assert.True(t, m.Large)
+	assert.Equal(t, CodeUnsatisfied, m.ExitCode)
+	assert.Equal(t, replay.BuildRevision(), m.ProverRevision)
+	assert.Equal(t, "2", m.Env["LIMITLESS_SUBPROVER_JOBS"])
+	assert.Equal(t, replay.SetupChecksums{VerifyingKey: "vk", Circuit: "circuit"}, m.SetupChecksums["execution"])
+	assert.FileExists(t, m.RequestPath(dir))
+	assert.FileExists(t, m.ConfigPath(dir))
+}
+
+func TestReplayBundleWriter(t *testing.T) {
+
+	var (
+		testDir  = t.TempDir()
+		confFile = path.Join(testDir, "config.toml")
+		cfg      = &config.Config{
+			Version:   "0.2.4",
+			AssetsDir: path.Join(testDir, "assets"),
+			Controller: config.Controller{
+				LocalID:                    "test-prover-id",
+				ReplayBundleDir:            path.Join(testDir, "replay"),
+				ReplayBundleCodes:          config.DefaultReplayBundleCodes,
+				ReplayBundleTimeout:        60,
+				ReplayBundleMaxTraceSizeMB: 1,
+			},
+			Execution: config.Execution{
+				WithRequestDir:     config.WithRequestDir{RequestsRootDir: path.Join(testDir, "execution")},
+				ConflatedTracesDir: path.Join(testDir, "traces"),
+			},
+		}
+		jdef     = ExecutionDefinition(cfg)
+		original = "10-12-getZkProof.json"
+		request  = `{"conflatedExecutionTracesFile":"10-12.conflated.lt"}`
+	)
+
+	require.NoError(t, os.MkdirAll(cfg.Execution.DirFrom(), 0o755))
+	require.NoError(t, os.MkdirAll(cfg.Execution.ConflatedTracesDir, 0o755))
+	require.NoError(t, os.WriteFile(confFile, []byte("version = \"0.2.4\"\n"), 0o600))
+	// The trace file exceeds the size limit of the bundles
+	require.NoError(t, os.WriteFile(path.Join(cfg.Execution.ConflatedTracesDir, "10-12.conflated.lt"), make([]byte, 2<<20), 0o600))
+
+	job, err := NewJob(&jdef, original)
+	require.NoError(t, err)
+	job.LockedFile = original + "." + config.InProgressSuffix + "." + cfg.Controller.LocalID
+	require.NoError(t, os.WriteFile(job.InProgressPath(), []byte(request), 0o600))
+
+	w := newReplayBundleWriter(cfg.Logger().WithField("component", "test"), cfg, confFile)
+
+	// The codes not listed in the config do not produce a bundle
+	w.tryWrite(context.Background(), job, Status{ExitCode: CodeTraceLimit})
+	assert.False(t, w.busy.Load())
+	assert.NoDirExists(t, cfg.Controller.ReplayBundleDir)
+
+	// The request is read before tryWrite returns, so that the controller can
+	// move it right away.
+	w.tryWrite(context.Background(), job, Status{ExitCode: CodeUnsatisfied})
+	require.NoError(t, os.Remove(job.InProgressPath()))
+	require.Eventually(t, func() bool { return !w.busy.Load() }, 10*time.Second, 10*time.Millisecond)
+
+	dir := t.TempDir()
+	m, err := replay.Extract(path.Join(cfg.Controller.ReplayBundleDir, original+".code-78.tgz"), dir)
+	require.NoError(t, err)
+	assert.Empty(t, m.Trace)
+
+	b, err := os.ReadFile(m.RequestPath(dir))
+	require.NoError(t, err)
+	assert.Equal(t, request, string(b))
+}
@@ -0,0 +1,3 @@
+#!/bin/sh
+echo "round-0" > "$PROVER_HEARTBEAT_FILE"
+sleep 4
\ No newline at end of file
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watchdogTick is the period at which the watchdog checks the heartbeat file
+const watchdogTick = time.Second
+
+// Returns the path of the heartbeat file passed to the prover or an empty
+// string if the heartbeats are not monitored.
+func (e *Executor) heartbeatFile() string {
+	if e.Config.Controller.HeartbeatTimeout <= 0 {
+		return ""
+	}
+	return filepath.Join(os.TempDir(), "prover-heartbeat."+e.Config.Controller.LocalID)
+}
+
+// Starts the watchdog of a run of job started at startTime. The returned
+// channel receives the reason for which the run must be killed: either the
+// job timeout is exceeded or the prover stopped updating hbFile. The heartbeat
+// file is only checked once the prover has created it. The watchdog exits when
+// stop is closed.
+func (e *Executor) watchdog(job *Job, hbFile string, startTime time.Time, stop <-chan struct{}) <-chan string {
+
+	var (
+		res       = make(chan string, 1)
+		timeout   = time.Duration(e.Config.Controller.JobTimeouts[job.Def.Name]) * time.Second
+		hbTimeout = time.Duration(e.Config.Controller.HeartbeatTimeout) * time.Second
+	)
+
+	if timeout <= 0 && len(hbFile) == 0 {
+		return res
+	}
+
+	go func() {
+
+		// A nil channel never fires, which disables the timeout
+		var timeoutChan <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(time.Until(startTime.Add(timeout)))
+			defer timer.Stop()
+			timeoutChan = timer.C
+		}
+
+		ticker := time.NewTicker(watchdogTick)
+		defer ticker.Stop()
+
+		for {
+			select {
+
+			case <-stop:
+				return
+
+			case <-timeoutChan:
+				res <- fmt.Sprintf("the run exceeded the %v timeout of %v jobs", timeout, job.Def.Name)
+				return
+
+			case now := <-ticker.C:
+				if len(hbFile) == 0 {
+					continue
+				}
+
+				info, err := os.Stat(hbFile)
+				if err != nil {
+					// No heartbeat yet
+					continue
+				}
+
+				if since := now.Sub(info.ModTime()); since > hbTimeout {
+					stage, _ := os.ReadFile(hbFile)
+					res <- fmt.Sprintf(
+						"no heartbeat for %v, the last one was at stage `%v`",
+						since.Round(time.Second), strings.TrimSpace(string(stage)),
+					)
+					return
+				}
+			}
+		}
+	}()
+
+	return res
+}
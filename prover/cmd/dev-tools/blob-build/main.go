@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/consensys/linea-monorepo/prover/backend/blobsubmission"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/blobbuilder"
+	v2 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v2"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const zeroHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "blob-build [files...]",
+	Short: "fills blobs with RLP blocks read from files or stdin and writes the blobs and their submission responses",
+	RunE:  buildBlobs,
+}
+
+// global variables holding the programs arguments
+var (
+	dictPath            string
+	dataLimit           int
+	blobVersion         uint16
+	blocksPerBatch      int
+	odir                string
+	eip4844Enabled      bool
+	parentStateRootHash string
+	finalStateRootHash  string
+	prevShnarf          string
+	dataParentHash      string
+)
+
+// initializes the programs flags
+func init() {
+	rootCmd.Flags().StringVar(&dictPath, "dict", "lib/compressor/compressor_dict.bin", "path to the compression dictionary")
+	rootCmd.Flags().IntVar(&dataLimit, "data-limit", v2.MaxUsableBytes, "maximum size of a blob in bytes")
+	rootCmd.Flags().Uint16Var(&blobVersion, "blob-version", blobbuilder.DefaultVersion, "version of the blobs to build")
+	rootCmd.Flags().IntVar(&blocksPerBatch, "blocks-per-batch", 0, "number of blocks per batch, 0 means one batch per input file")
+	rootCmd.Flags().StringVar(&odir, "odir", ".", "output directory where to write the blobs and the responses")
+	rootCmd.Flags().BoolVar(&eip4844Enabled, "eip4844", true, "craft EIP-4844 responses instead of calldata responses")
+	rootCmd.Flags().StringVar(&parentStateRootHash, "parent-state-root-hash", zeroHash, "state root hash before the first blob")
+	rootCmd.Flags().StringVar(&finalStateRootHash, "final-state-root-hash", zeroHash, "state root hash after each blob")
+	rootCmd.Flags().StringVar(&prevShnarf, "prev-shnarf", zeroHash, "shnarf preceding the first blob")
+	rootCmd.Flags().StringVar(&dataParentHash, "data-parent-hash", zeroHash, "data hash of the blob preceding the first blob")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		logrus.Fatalf("exiting with error: %v", err)
+	}
+}
+
+// blobWriter accumulates the sealed blobs and chains their submission
+// responses.
+type blobWriter struct {
+	prevShnarf          string
+	dataParentHash      string
+	parentStateRootHash string
+	nbBlobs             int
+}
+
+func buildBlobs(cmd *cobra.Command, args []string) error {
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := os.MkdirAll(odir, 0755); err != nil {
+		return fmt.Errorf("could not create output directory %v: %w", odir, err)
+	}
+
+	builder, err := blobbuilder.NewVersioned(blobVersion, dataLimit, dictPath)
+	if err != nil {
+		return err
+	}
+
+	w := &blobWriter{
+		prevShnarf:          prevShnarf,
+		dataParentHash:      dataParentHash,
+		parentStateRootHash: parentStateRootHash,
+	}
+
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	for _, name := range args {
+		if err := w.processInput(ctx, builder, name); err != nil {
+			return err
+		}
+		builder.StartNewBatch()
+	}
+
+	if err := w.flush(builder); err != nil {
+		return err
+	}
+
+	logrus.Infof("wrote %d blob(s) in %v", w.nbBlobs, odir)
+	return nil
+}
+
+// processInput reads the RLP blocks of a single input and writes them in the
+// builder, flushing the blob each time it is full.
+func (w *blobWriter) processInput(ctx context.Context, builder *blobbuilder.Builder, name string) error {
+
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("could not open input %v: %w", name, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	stream := rlp.NewStream(bufio.NewReader(r), 0)
+	nbInBatch := 0
+
+	for {
+		block, err := stream.Raw()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read RLP block from %v: %w", name, err)
+		}
+
+		if blocksPerBatch > 0 && nbInBatch == blocksPerBatch {
+			builder.StartNewBatch()
+			nbInBatch = 0
+		}
+
+		ok, err := builder.Write(ctx, block)
+		if err != nil {
+			return fmt.Errorf("input %v: %w", name, err)
+		}
+
+		if !ok {
+			// The blob is full, the block goes in a new one
+			if err := w.flush(builder); err != nil {
+				return err
+			}
+			if _, err := builder.Write(ctx, block); err != nil {
+				return fmt.Errorf("input %v: %w", name, err)
+			}
+			nbInBatch = 0
+		}
+
+		nbInBatch++
+	}
+}
+
+// flush seals the blob being built, crafts its submission response and writes
+// both in the output directory. It does nothing if the blob is empty.
+func (w *blobWriter) flush(builder *blobbuilder.Builder) error {
+
+	blob := builder.Seal()
+	if blob == nil {
+		return nil
+	}
+
+	upperBoundaries := blob.UpperBoundaries()
+	conflationOrder := blobsubmission.ConflationOrder{
+		StartingBlockNumber: int(blob.StartingBlockNumber()),
+		UpperBoundaries:     make([]int, len(upperBoundaries)),
+	}
+	for i := range upperBoundaries {
+		conflationOrder.UpperBoundaries[i] = int(upperBoundaries[i])
+	}
+
+	req := &blobsubmission.Request{
+		Eip4844Enabled:      eip4844Enabled,
+		CompressedData:      base64.StdEncoding.EncodeToString(blob.Data),
+		DataParentHash:      w.dataParentHash,
+		ConflationOrder:     conflationOrder,
+		ParentStateRootHash: w.parentStateRootHash,
+		FinalStateRootHash:  finalStateRootHash,
+		PrevShnarf:          w.prevShnarf,
+	}
+
+	resp, err := blobsubmission.CraftResponse(req)
+	if err != nil {
+		return fmt.Errorf("could not craft the blob submission response: %w", err)
+	}
+
+	start, end := conflationOrder.Range()
+	blobPath := filepath.Join(odir, fmt.Sprintf("blob-%v-%v.bin", start, end))
+	if err := os.WriteFile(blobPath, blob.Data, 0600); err != nil {
+		return fmt.Errorf("could not write blob: %w", err)
+	}
+
+	serialized, err := json.MarshalIndent(resp, "", "\t")
+	if err != nil {
+		return fmt.Errorf("could not serialize submission response: %w", err)
+	}
+
+	respPath := filepath.Join(odir, fmt.Sprintf("blocks-%v-%v.json", start, end))
+	if err := os.WriteFile(respPath, serialized, 0600); err != nil {
+		return fmt.Errorf("could not write submission response: %w", err)
+	}
+
+	logrus.Infof("blob %v-%v: %d batch(es), %d bytes", start, end, len(blob.Batches), len(blob.Data))
+
+	w.prevShnarf = resp.ExpectedShnarf
+	w.dataParentHash = resp.DataHash
+	w.parentStateRootHash = resp.FinalStateRootHash
+	w.nbBlobs++
+
+	return nil
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/invalidity"
+	circuitInvalidity "github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/config"
+	public_input "github.com/consensys/linea-monorepo/prover/public-input"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ftxRecord is a forced transaction as found in one of the audited files.
+type ftxRecord struct {
+	File   string
+	Number uint64
+
+	// PrevRollingHash is the rolling hash before the forced transaction. It
+	// is not known for the requests of a batch other than the first one, when
+	// they leave it to be derived.
+	PrevRollingHash    types.Bls12377Fr
+	HasPrevRollingHash bool
+
+	TxHash               common.Hash
+	From                 types.EthAddress
+	Deadline             uint64
+	SimulatedBlockNumber uint64
+
+	// ClaimedRollingHash is the rolling hash after the forced transaction as
+	// given by a response; nil for the requests.
+	ClaimedRollingHash *types.Bls12377Fr
+}
+
+// aggregationRecord holds the FTX-related fields of an aggregation request.
+type aggregationRecord struct {
+	File              string
+	ParentNumber      uint64
+	ParentRollingHash types.Bls12377Fr
+	InvalidityProofs  []string
+}
+
+// Finding is an inconsistency found by the auditor. FtxNumber is zero for the
+// findings that do not relate to a single forced transaction.
+type Finding struct {
+	File      string `json:"file"`
+	FtxNumber uint64 `json:"ftxNumber,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.FtxNumber == 0 {
+		return fmt.Sprintf("%s: %s", f.File, f.Message)
+	}
+	return fmt.Sprintf("%s: FTX %d: %s", f.File, f.FtxNumber, f.Message)
+}
+
+// auditor collects the forced transactions and the aggregations found in the
+// audited files and checks their consistency.
+type auditor struct {
+	records      map[uint64][]*ftxRecord
+	aggregations []aggregationRecord
+	// responses indexes the records of the invalidity responses by file name,
+	// as they are referenced by the aggregation requests.
+	responses map[string][]*ftxRecord
+	findings  []Finding
+}
+
+func newAuditor() *auditor {
+	return &auditor{
+		records:   make(map[uint64][]*ftxRecord),
+		responses: make(map[string][]*ftxRecord),
+	}
+}
+
+func (a *auditor) addFinding(file string, ftxNumber uint64, msg string, args ...any) {
+	a.findings = append(a.findings, Finding{File: file, FtxNumber: ftxNumber, Message: fmt.Sprintf(msg, args...)})
+}
+
+func (a *auditor) addRecord(rec *ftxRecord, isResponse bool) {
+	a.records[rec.Number] = append(a.records[rec.Number], rec)
+	if isResponse {
+		name := filepath.Base(rec.File)
+		a.responses[name] = append(a.responses[name], rec)
+	}
+}
+
+// auditedFileRegexp matches the names of the invalidity and aggregation
+// requests and responses as written by the controller, including the
+// suffixes it appends to the requests it processed or recovered.
+var auditedFileRegexp = regexp.MustCompile(fmt.Sprintf(
+	`^[0-9]+-[0-9]+(-[a-fA-F0-9]+)?-getZk(InvalidityProof|InvalidityBatchProof|AggregatedProof)\.json(\.%v|\.%v|\.failure\.%v_[0-9]+)*$`,
+	config.LargeSuffix, config.SuccessSuffix, config.FailSuffix,
+))
+
+// loadDir loads all the request and response files of the directory tree.
+func (a *auditor) loadDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !auditedFileRegexp.MatchString(d.Name()) {
+			return nil
+		}
+		return a.loadFile(path)
+	})
+}
+
+// loadFile loads a request or a response file. The kind of file is inferred
+// from its JSON fields; the files of any other kind are ignored.
+func (a *auditor) loadFile(path string) error {
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		a.addFinding(path, 0, "not a JSON object: %v", err)
+		return nil
+	}
+
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := fields[k]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case has("ftxRLP"):
+		var req invalidity.Request
+		return a.decode(path, b, &req, func() error { return a.loadRequest(path, &req, true) })
+	case has("ftxRequests"):
+		var req invalidity.BatchRequest
+		return a.decode(path, b, &req, func() error {
+			for i := range req.Requests {
+				if err := a.loadRequest(path, &req.Requests[i], i == 0); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case has("rlpEncodedTx", "ftxRollingHash", "proof"):
+		var resp invalidity.Response
+		return a.decode(path, b, &resp, func() error { return a.loadResponse(path, &resp) })
+	case has("ftxs", "ftxRollingHash", "proof"):
+		var resp invalidity.BatchResponse
+		return a.decode(path, b, &resp, func() error { return a.loadBatchResponse(path, &resp) })
+	case has("invalidityProofs", "parentAggregationLastFtxNumber"):
+		var req aggregation.Request
+		return a.decode(path, b, &req, func() error { return a.loadAggregation(path, &req) })
+	}
+
+	return nil
+}
+
+// decode decodes the file into v and calls load. Decoding errors are reported
+// as findings as they would make the prover fail on the file.
+func (a *auditor) decode(path string, b []byte, v any, load func() error) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		a.addFinding(path, 0, "could not decode the file: %v", err)
+		return nil
+	}
+	return load()
+}
+
+func (a *auditor) loadRequest(path string, req *invalidity.Request, hasPrev bool) error {
+
+	fi, err := funcInput(func() *public_input.Invalidity { return invalidity.FuncInput(req, &config.Config{}) })
+	if err != nil {
+		a.addFinding(path, req.ForcedTransactionNumber, "could not recompute the functional inputs: %v", err)
+		return nil
+	}
+
+	a.addRecord(&ftxRecord{
+		File:                 path,
+		Number:               req.ForcedTransactionNumber,
+		PrevRollingHash:      req.PrevFtxRollingHash,
+		HasPrevRollingHash:   hasPrev || req.PrevFtxRollingHash != (types.Bls12377Fr{}),
+		TxHash:               fi.TxHash,
+		From:                 fi.FromAddress,
+		Deadline:             req.DeadlineBlockHeight,
+		SimulatedBlockNumber: req.SimulatedExecutionBlockNumber,
+	}, false)
+	return nil
+}
+
+func (a *auditor) loadResponse(path string, resp *invalidity.Response) error {
+
+	fi, err := funcInput(resp.FuncInput)
+	if err != nil {
+		a.addFinding(path, resp.ForcedTransactionNumber, "could not recompute the functional inputs: %v", err)
+		return nil
+	}
+
+	if sum := types.AsBls12377Fr(fi.Sum(nil)); sum != resp.PublicInput {
+		a.addFinding(path, resp.ForcedTransactionNumber, "public input mismatch: given %s, computed %s", resp.PublicInput.Hex(), sum.Hex())
+	}
+
+	claimed := resp.FtxRollingHash
+	a.addRecord(&ftxRecord{
+		File:                 path,
+		Number:               resp.ForcedTransactionNumber,
+		PrevRollingHash:      resp.PrevFtxRollingHash,
+		HasPrevRollingHash:   true,
+		TxHash:               fi.TxHash,
+		From:                 fi.FromAddress,
+		Deadline:             resp.DeadlineBlockHeight,
+		SimulatedBlockNumber: resp.SimulatedExecutionBlockNumber,
+		ClaimedRollingHash:   &claimed,
+	}, true)
+	return nil
+}
+
+func (a *auditor) loadBatchResponse(path string, resp *invalidity.BatchResponse) error {
+
+	if sum := types.AsBls12377Fr(resp.FuncInput().Sum(nil)); sum != resp.PublicInput {
+		a.addFinding(path, resp.FirstTxNumber, "public input mismatch: given %s, computed %s", resp.PublicInput.Hex(), sum.Hex())
+	}
+
+	for i := range resp.Transactions {
+		e := &resp.Transactions[i]
+		claimed := e.FtxRollingHash
+		a.addRecord(&ftxRecord{
+			File:                 path,
+			Number:               e.ForcedTransactionNumber,
+			PrevRollingHash:      resp.PrevFtxRollingHash,
+			HasPrevRollingHash:   i == 0,
+			TxHash:               common.HexToHash(e.TxHash),
+			From:                 e.Signer,
+			Deadline:             e.DeadlineBlockHeight,
+			SimulatedBlockNumber: resp.SimulatedExecutionBlockNumber,
+			ClaimedRollingHash:   &claimed,
+		}, true)
+	}
+	return nil
+}
+
+func (a *auditor) loadAggregation(path string, req *aggregation.Request) error {
+
+	parent, err := parseRollingHash(req.ParentAggregationLastFtxRollingHash)
+	if err != nil {
+		a.addFinding(path, 0, "invalid parentAggregationLastFtxRollingHash: %v", err)
+		return nil
+	}
+
+	a.aggregations = append(a.aggregations, aggregationRecord{
+		File:              path,
+		ParentNumber:      uint64(req.ParentAggregationLastFtxNumber),
+		ParentRollingHash: parent,
+		InvalidityProofs:  req.InvalidityProofs,
+	})
+	return nil
+}
+
+// audit checks the loaded records and returns the findings, sorted by FTX
+// number.
+func (a *auditor) audit() []Finding {
+
+	numbers := make([]uint64, 0, len(a.records))
+	for n := range a.records {
+		numbers = append(numbers, n)
+	}
+	slices.Sort(numbers)
+
+	// The rolling hashes given by the parents of the aggregations anchor the
+	// chain.
+	anchors := make(map[uint64]types.Bls12377Fr)
+	for _, agg := range a.aggregations {
+		if h, ok := anchors[agg.ParentNumber]; ok && h != agg.ParentRollingHash {
+			a.addFinding(agg.File, agg.ParentNumber, "parent rolling hash %s conflicts with %s given by another aggregation", agg.ParentRollingHash.Hex(), h.Hex())
+			continue
+		}
+		anchors[agg.ParentNumber] = agg.ParentRollingHash
+	}
+
+	// known holds the FTX numbers of the records and of the anchors, to
+	// detect the gaps.
+	known := slices.Clone(numbers)
+	for n := range anchors {
+		known = append(known, n)
+	}
+	slices.Sort(known)
+	known = slices.Compact(known)
+
+	rolling := make(map[uint64]types.Bls12377Fr)
+
+	for _, n := range numbers {
+		recs := a.records[n]
+		a.checkDuplicates(recs)
+
+		if i, _ := slices.BinarySearch(known, n); i > 0 && known[i-1]+1 < n {
+			a.addFinding(recs[0].File, n, "FTX %d to %d are missing", known[i-1]+1, n-1)
+		}
+
+		prev, ok := rolling[n-1]
+		if !ok {
+			prev, ok = anchors[n-1]
+		}
+
+		for _, rec := range recs {
+			if ok && rec.HasPrevRollingHash && rec.PrevRollingHash != prev {
+				a.addFinding(rec.File, n, "prevFtxRollingHash %s does not match the rolling hash %s after FTX %d", rec.PrevRollingHash.Hex(), prev.Hex(), n-1)
+			}
+			if rec.SimulatedBlockNumber > rec.Deadline {
+				a.addFinding(rec.File, n, "deadline %d is missed: the simulated execution block is %d", rec.Deadline, rec.SimulatedBlockNumber)
+			}
+		}
+
+		// Without a previous forced transaction nor an anchor, the chain
+		// starts from the rolling hash given by the records.
+		for _, rec := range recs {
+			if !ok && rec.HasPrevRollingHash {
+				prev, ok = rec.PrevRollingHash, true
+			}
+		}
+
+		if ok {
+			r := circuitInvalidity.UpdateFtxRollingHash(prev, recs[0].TxHash, recs[0].Deadline, recs[0].From)
+			rolling[n] = r
+			for _, rec := range recs {
+				if rec.ClaimedRollingHash != nil && *rec.ClaimedRollingHash != r {
+					a.addFinding(rec.File, n, "ftxRollingHash %s does not match the recomputed %s", rec.ClaimedRollingHash.Hex(), r.Hex())
+				}
+			}
+		} else {
+			a.addFinding(recs[0].File, n, "the rolling hash can not be recomputed: the rolling hash after FTX %d is unknown", n-1)
+		}
+	}
+
+	for _, agg := range a.aggregations {
+		a.checkAggregation(agg, rolling)
+	}
+
+	a.blameAggregations()
+
+	sort.SliceStable(a.findings, func(i, j int) bool { return a.findings[i].FtxNumber < a.findings[j].FtxNumber })
+	return a.findings
+}
+
+// checkDuplicates reports the records of the same FTX number that describe
+// different forced transactions.
+func (a *auditor) checkDuplicates(recs []*ftxRecord) {
+	for _, rec := range recs[1:] {
+		if rec.TxHash != recs[0].TxHash || rec.From != recs[0].From || rec.Deadline != recs[0].Deadline {
+			a.addFinding(rec.File, rec.Number, "conflicts with %s: the transaction hash, sender or deadline differ", recs[0].File)
+		}
+		if rec.HasPrevRollingHash && recs[0].HasPrevRollingHash && rec.PrevRollingHash != recs[0].PrevRollingHash {
+			a.addFinding(rec.File, rec.Number, "conflicts with %s: the prevFtxRollingHash differ", recs[0].File)
+		}
+	}
+}
+
+// checkAggregation performs the FTX checks made by the aggregation prover when
+// collecting the invalidity responses.
+func (a *auditor) checkAggregation(agg aggregationRecord, rolling map[uint64]types.Bls12377Fr) {
+
+	if h, ok := rolling[agg.ParentNumber]; ok && h != agg.ParentRollingHash {
+		a.addFinding(agg.File, agg.ParentNumber, "parentAggregationLastFtxRollingHash %s does not match the rolling hash %s after FTX %d", agg.ParentRollingHash.Hex(), h.Hex(), agg.ParentNumber)
+	}
+
+	var (
+		expected = agg.ParentNumber + 1
+		simBlock uint64
+	)
+
+	for i, name := range agg.InvalidityProofs {
+		recs := a.responses[filepath.Base(name)]
+		if len(recs) == 0 {
+			a.addFinding(agg.File, expected, "invalidity response %s is missing", name)
+			expected++
+			continue
+		}
+		if len(recs) > 1 {
+			a.addFinding(agg.File, recs[0].Number, "invalidity response %s holds %d forced transactions, the aggregation expects one per response", name, len(recs))
+		}
+
+		rec := recs[0]
+		if rec.Number != expected {
+			a.addFinding(agg.File, rec.Number, "forced transaction numbers should be consecutive: %s holds FTX %d, expected %d", name, rec.Number, expected)
+		}
+		if i == 0 {
+			simBlock = rec.SimulatedBlockNumber
+		} else if rec.SimulatedBlockNumber != simBlock {
+			a.addFinding(agg.File, rec.Number, "%s has simulated block %d, expected %d as the other invalidity proofs of the aggregation", name, rec.SimulatedBlockNumber, simBlock)
+		}
+		expected = rec.Number + 1
+	}
+}
+
+// blameAggregations reports, for each aggregation, the forced transactions
+// in its range having a finding as these make the aggregation fail.
+func (a *auditor) blameAggregations() {
+	var blames []Finding
+	for _, agg := range a.aggregations {
+		first, last := agg.ParentNumber+1, agg.ParentNumber+uint64(len(agg.InvalidityProofs))
+		for _, f := range a.findings {
+			if f.File == agg.File || f.FtxNumber < first || f.FtxNumber > last {
+				continue
+			}
+			blames = append(blames, Finding{
+				File:      agg.File,
+				FtxNumber: f.FtxNumber,
+				Message:   fmt.Sprintf("the aggregation would fail because of %s", f.File),
+			})
+		}
+	}
+	a.findings = append(a.findings, blames...)
+}
+
+// funcInput calls f, turning the panics raised on malformed inputs into an
+// error.
+func funcInput(f func() *public_input.Invalidity) (fi *public_input.Invalidity, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return f(), nil
+}
+
+// parseRollingHash parses a rolling hash, the empty string standing for zero.
+func parseRollingHash(s string) (h types.Bls12377Fr, err error) {
+	if s == "" {
+		return h, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return types.Bls12377FrFromHex(s), nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	backendInvalidity "github.com/consensys/linea-monorepo/prover/backend/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testAuditor returns an auditor loaded with an aggregation over the
+// consistent responses of the forced transactions 1 to nbFtx.
+func testAuditor(nbFtx int) *auditor {
+
+	a := newAuditor()
+	agg := aggregationRecord{File: "agg.json", ParentRollingHash: types.Bls12377Fr{31: 1}}
+	prev := agg.ParentRollingHash
+
+	for i := 1; i <= nbFtx; i++ {
+		rec := &ftxRecord{
+			File:                 fmt.Sprintf("resp-%d.json", i),
+			Number:               uint64(i),
+			PrevRollingHash:      prev,
+			HasPrevRollingHash:   true,
+			TxHash:               common.Hash{byte(i)},
+			From:                 types.EthAddress{byte(i)},
+			Deadline:             100,
+			SimulatedBlockNumber: 50,
+		}
+		r := invalidity.UpdateFtxRollingHash(prev, rec.TxHash, rec.Deadline, rec.From)
+		rec.ClaimedRollingHash = &r
+		prev = r
+
+		a.addRecord(rec, true)
+		agg.InvalidityProofs = append(agg.InvalidityProofs, rec.File)
+	}
+
+	a.aggregations = append(a.aggregations, agg)
+	return a
+}
+
+func TestAudit(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		tamper   func(a *auditor)
+		expected string
+	}{
+		{
+			name:   "Consistent",
+			tamper: func(a *auditor) {},
+		},
+		{
+			name:     "Gap",
+			tamper:   func(a *auditor) { delete(a.records, 2) },
+			expected: "FTX 2 to 2 are missing",
+		},
+		{
+			name:     "BadPrevRollingHash",
+			tamper:   func(a *auditor) { a.records[2][0].PrevRollingHash = types.Bls12377Fr{} },
+			expected: "prevFtxRollingHash",
+		},
+		{
+			name:     "BadRollingHash",
+			tamper:   func(a *auditor) { a.records[3][0].ClaimedRollingHash = &types.Bls12377Fr{} },
+			expected: "does not match the recomputed",
+		},
+		{
+			name:     "MissedDeadline",
+			tamper:   func(a *auditor) { a.records[1][0].Deadline = 49 },
+			expected: "deadline 49 is missed",
+		},
+		{
+			name: "ConflictingDuplicate",
+			tamper: func(a *auditor) {
+				dup := *a.records[2][0]
+				dup.File = "req-2.json"
+				dup.TxHash = common.Hash{0xff}
+				a.addRecord(&dup, false)
+			},
+			expected: "conflicts with",
+		},
+		{
+			name:     "BadParentRollingHash",
+			tamper:   func(a *auditor) { a.aggregations[0].ParentRollingHash = types.Bls12377Fr{31: 2} },
+			expected: "prevFtxRollingHash",
+		},
+		{
+			name: "UnorderedResponses",
+			tamper: func(a *auditor) {
+				proofs := a.aggregations[0].InvalidityProofs
+				proofs[0], proofs[1] = proofs[1], proofs[0]
+			},
+			expected: "should be consecutive",
+		},
+		{
+			name:     "MissingResponse",
+			tamper:   func(a *auditor) { a.aggregations[0].InvalidityProofs[1] = "resp-unknown.json" },
+			expected: "is missing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := testAuditor(3)
+			tc.tamper(a)
+			findings := a.audit()
+
+			if tc.expected == "" {
+				require.Empty(t, findings)
+				return
+			}
+
+			var found, blamed bool
+			for _, f := range findings {
+				found = found || strings.Contains(f.Message, tc.expected)
+				blamed = blamed || f.File == "agg.json"
+			}
+			require.True(t, found, "expected a finding containing %q, got %v", tc.expected, findings)
+			require.True(t, blamed, "expected the aggregation to be reported, got %v", findings)
+		})
+	}
+}
+
+// TestLoadDir checks that the request and response files are selected by the
+// controller names, including the suffixes of the processed requests, and
+// that a consistent set of files has no finding.
+func TestLoadDir(t *testing.T) {
+
+	dir := t.TempDir()
+	write := func(name string, v any) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, b, 0o600))
+	}
+
+	parent := types.Bls12377Fr{31: 1}
+	resp := backendInvalidity.BatchResponse{
+		FirstTxNumber:      1,
+		LastTxNumber:       2,
+		PrevFtxRollingHash: parent,
+		Proof:              "0x",
+	}
+	prev := parent
+	for i := 1; i <= 2; i++ {
+		e := backendInvalidity.BatchResponseEntry{
+			Signer:                  types.EthAddress{byte(i)},
+			TxHash:                  common.Hash{byte(i)}.Hex(),
+			ForcedTransactionNumber: uint64(i),
+			DeadlineBlockHeight:     100,
+		}
+		e.FtxRollingHash = invalidity.UpdateFtxRollingHash(prev, common.HexToHash(e.TxHash), e.DeadlineBlockHeight, e.Signer)
+		prev = e.FtxRollingHash
+		resp.Transactions = append(resp.Transactions, e)
+	}
+	resp.FtxRollingHash = prev
+	resp.SimulatedExecutionBlockNumber = 50
+	resp.PublicInput = types.AsBls12377Fr(resp.FuncInput().Sum(nil))
+
+	write("responses/1-2-getZkInvalidityBatchProof.json", resp)
+	write("requests-done/0-10-getZkAggregatedProof.json.success", aggregation.Request{
+		ParentAggregationLastFtxRollingHash: parent.Hex(),
+	})
+	write("requests-done/11-20-getZkAggregatedProof.json.failure.code_2", aggregation.Request{
+		ParentAggregationLastFtxNumber:      2,
+		ParentAggregationLastFtxRollingHash: prev.Hex(),
+	})
+	write("requests/11-20-getZkAggregatedProof.json.large.failure.code_137", aggregation.Request{
+		ParentAggregationLastFtxNumber:      2,
+		ParentAggregationLastFtxRollingHash: prev.Hex(),
+	})
+
+	// These are not controller files or are being processed: loading them
+	// would report a conflicting parent rolling hash.
+	conflicting := aggregation.Request{ParentAggregationLastFtxRollingHash: types.Bls12377Fr{31: 2}.Hex()}
+	write("requests/notes.json", conflicting)
+	write("requests/0-10-getZkAggregatedProof.json.inprogress.local", conflicting)
+
+	a := newAuditor()
+	require.NoError(t, a.loadDir(dir))
+
+	var loaded []string
+	for _, agg := range a.aggregations {
+		rel, err := filepath.Rel(dir, agg.File)
+		require.NoError(t, err)
+		loaded = append(loaded, filepath.ToSlash(rel))
+	}
+	require.ElementsMatch(t, []string{
+		"requests-done/0-10-getZkAggregatedProof.json.success",
+		"requests-done/11-20-getZkAggregatedProof.json.failure.code_2",
+		"requests/11-20-getZkAggregatedProof.json.large.failure.code_137",
+	}, loaded)
+	require.Len(t, a.responses["1-2-getZkInvalidityBatchProof.json"], 2)
+
+	require.Empty(t, a.audit())
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "ftx-audit [dirs...]",
+	Short: "checks the consistency of the forced transactions of the invalidity and aggregation requests and responses",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runAudit,
+}
+
+// global variables holding the programs arguments
+var (
+	jsonOutput bool
+)
+
+// initializes the programs flags
+func init() {
+	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "print the findings as JSON")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		logrus.Fatalf("exiting with error: %v", err)
+	}
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+
+	a := newAuditor()
+	for _, dir := range args {
+		if err := a.loadDir(dir); err != nil {
+			return fmt.Errorf("could not load %s: %w", dir, err)
+		}
+	}
+
+	logrus.Infof("loaded %d forced transactions and %d aggregations", len(a.records), len(a.aggregations))
+
+	findings := a.audit()
+
+	if jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(findings); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+	}
+
+	if len(findings) > 0 {
+		return fmt.Errorf("found %d inconsistencies", len(findings))
+	}
+
+	logrus.Info("no inconsistency found")
+	return nil
+}
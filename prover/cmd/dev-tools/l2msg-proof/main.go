@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "l2msg-proof [execution responses...]",
+	Short: "generates the Merkle proofs to claim on L1 the L2 messages finalized by an aggregation",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runL2MsgProof,
+}
+
+// global variables holding the programs arguments
+var (
+	aggregationFile string
+	messageHashes   []string
+	outFile         string
+)
+
+// initializes the programs flags
+func init() {
+	rootCmd.Flags().StringVar(&aggregationFile, "aggregation", "", "aggregation response file")
+	rootCmd.Flags().StringSliceVar(&messageHashes, "msg", nil, "hash of the L2 message to prove, all the messages are proven if omitted")
+	rootCmd.Flags().StringVar(&outFile, "out", "", "output file, the proofs are printed on stdout if omitted")
+	rootCmd.MarkFlagRequired("aggregation")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		logrus.Fatalf("exiting with error: %v", err)
+	}
+}
+
+func runL2MsgProof(cmd *cobra.Command, args []string) error {
+
+	agg := &aggregation.Response{}
+	if err := readJSON(aggregationFile, agg); err != nil {
+		return err
+	}
+
+	var execs []*execution.Response
+	for _, arg := range args {
+		loaded, err := loadExecutions(arg, agg)
+		if err != nil {
+			return fmt.Errorf("could not load %s: %w", arg, err)
+		}
+		execs = append(execs, loaded...)
+	}
+
+	logrus.Infof("loaded %d execution responses for blocks %d to %d", len(execs), agg.LastFinalizedBlockNumber+1, agg.FinalBlockNumber)
+
+	trees, err := aggregation.BuildL2MessageTrees(agg, execs)
+	if err != nil {
+		return err
+	}
+
+	var proofs []aggregation.L2MessageClaimProof
+	if len(messageHashes) == 0 {
+		if proofs, err = trees.ProveAll(); err != nil {
+			return err
+		}
+	}
+
+	for _, h := range messageHashes {
+		p, err := trees.Prove(h)
+		if err != nil {
+			return err
+		}
+		proofs = append(proofs, p)
+	}
+
+	var w io.Writer = os.Stdout
+	if outFile != "" {
+		f, err := os.Create(outFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(proofs)
+}
+
+// loadExecutions loads the execution responses found at path, which is either
+// a file or a directory tree. The JSON files that are not execution responses
+// or whose blocks are not finalized by agg are ignored.
+func loadExecutions(path string, agg *aggregation.Response) ([]*execution.Response, error) {
+
+	var res []*execution.Response
+
+	err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".json" {
+			return nil
+		}
+
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(b, &fields); err != nil {
+			logrus.Debugf("skipping %s: %v", p, err)
+			return nil
+		}
+		if _, ok := fields["blocksData"]; !ok {
+			return nil
+		}
+
+		resp := &execution.Response{}
+		if err := json.Unmarshal(b, resp); err != nil {
+			return fmt.Errorf("could not decode %s: %w", p, err)
+		}
+
+		first, last := uint(resp.FirstBlockNumber), uint(resp.FirstBlockNumber+len(resp.BlocksData)-1)
+		if last <= agg.LastFinalizedBlockNumber || first > agg.FinalBlockNumber {
+			return nil
+		}
+
+		if first <= agg.LastFinalizedBlockNumber || last > agg.FinalBlockNumber {
+			return fmt.Errorf("%s covers blocks %d to %d, across the bounds of the aggregation", p, first, last)
+		}
+
+		res = append(res, resp)
+		return nil
+	})
+
+	return res, err
+}
+
+func readJSON(path string, into any) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, into); err != nil {
+		return fmt.Errorf("could not decode %s: %w", path, err)
+	}
+	return nil
+}
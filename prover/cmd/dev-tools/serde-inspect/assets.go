@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/protocol/coin"
+	"github.com/consensys/linea-monorepo/prover/protocol/distributed"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+)
+
+// assetTypes lists the Go types of the assets written by the setup, by the
+// name used for the --type flag.
+var assetTypes = map[string]func() any{
+	"zkevm":          func() any { return &zkevm.ZkEvm{} },
+	"compiled-iop":   func() any { return &wizard.CompiledIOP{} },
+	"disc":           func() any { return &distributed.StandardModuleDiscoverer{} },
+	"segment":        func() any { return &distributed.RecursedSegmentCompilation{} },
+	"blueprint":      func() any { return &distributed.ModuleSegmentationBlueprint{} },
+	"module-gl":      func() any { return &distributed.ModuleGL{} },
+	"module-lpp":     func() any { return &distributed.ModuleLPP{} },
+	"vk-merkle-tree": func() any { return &distributed.VerificationKeyMerkleTree{} },
+}
+
+// assetNamePrefixes maps the prefixes of the asset file names, as written by
+// the setup, to their type.
+var assetNamePrefixes = []struct {
+	prefix, assetType string
+}{
+	{config.ExecutionCircuitBinFileName, "zkevm"},
+	{"zkevm-wiop", "zkevm"},
+	{"disc", "disc"},
+	{"dw-bootstrapper", "compiled-iop"},
+	{"dw-compiled-", "segment"},
+	{"dw-blueprint-", "blueprint"},
+	{"dw-debug-gl-", "module-gl"},
+	{"dw-debug-lpp-", "module-lpp"},
+	{"verification-key-merkle-tree", "vk-merkle-tree"},
+}
+
+func assetTypeNames() []string {
+	res := make([]string, 0, len(assetTypes))
+	for name := range assetTypes {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// resolveAssetType returns the type of the asset at path: the one of the
+// --type flag if set, or else the one inferred from the file name.
+func resolveAssetType(path string) (string, func() any, error) {
+
+	name := assetType
+	if name == "" {
+		base := filepath.Base(filepath.Clean(path))
+		for _, p := range assetNamePrefixes {
+			if strings.HasPrefix(base, p.prefix) {
+				name = p.assetType
+				break
+			}
+		}
+	}
+
+	if name == "" {
+		return "", nil, fmt.Errorf("cannot infer the type of %s from its name, use --type", path)
+	}
+
+	newAsset, ok := assetTypes[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown asset type %q, expected one of %v", name, assetTypeNames())
+	}
+
+	return name, newAsset, nil
+}
+
+// namedCompiledIOP is a compiled IOP found in an asset along with its path
+// from the root of the asset.
+type namedCompiledIOP struct {
+	path string
+	comp *wizard.CompiledIOP
+}
+
+var compiledIOPPtrType = reflect.TypeFor[*wizard.CompiledIOP]()
+
+// findCompiledIOPs returns the compiled IOPs reachable from obj, in the order
+// of the fields. A compiled IOP reachable through several paths is listed
+// once. The IOPs held in interfaces or nested in another compiled IOP are not
+// searched for.
+func findCompiledIOPs(obj any) []namedCompiledIOP {
+
+	var (
+		res      []namedCompiledIOP
+		seen     = map[uintptr]struct{}{}
+		holdsIOP = map[reflect.Type]bool{}
+		walk     func(v reflect.Value, path string)
+	)
+
+	walk = func(v reflect.Value, path string) {
+
+		if !mayHoldCompiledIOP(v.Type(), holdsIOP) {
+			return
+		}
+
+		switch v.Kind() {
+		case reflect.Ptr:
+			if v.IsNil() {
+				return
+			}
+			if _, ok := seen[v.Pointer()]; ok {
+				return
+			}
+			seen[v.Pointer()] = struct{}{}
+			if v.Type() == compiledIOPPtrType {
+				res = append(res, namedCompiledIOP{path: path, comp: v.Interface().(*wizard.CompiledIOP)})
+				return
+			}
+			walk(v.Elem(), path)
+		case reflect.Struct:
+			for i := 0; i < v.NumField(); i++ {
+				if f := v.Type().Field(i); f.IsExported() {
+					walk(v.Field(i), joinPath(path, f.Name))
+				}
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < v.Len(); i++ {
+				walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+			}
+		case reflect.Map:
+			keys := v.MapKeys()
+			sort.Slice(keys, func(i, j int) bool {
+				return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+			})
+			for _, k := range keys {
+				walk(v.MapIndex(k), fmt.Sprintf("%s[%v]", path, k))
+			}
+		}
+	}
+
+	walk(reflect.ValueOf(obj), "")
+	return res
+}
+
+// mayHoldCompiledIOP tells whether a value of type t can reach a compiled
+// IOP without going through an interface. It spares walking through the
+// large slices of field elements of the assets.
+func mayHoldCompiledIOP(t reflect.Type, memo map[reflect.Type]bool) bool {
+
+	if res, ok := memo[t]; ok {
+		return res
+	}
+
+	// Breaks the cycles of recursive types; a type reaching a compiled IOP
+	// only through itself never does.
+	memo[t] = false
+
+	var res bool
+	switch t.Kind() {
+	case reflect.Ptr:
+		res = t == compiledIOPPtrType || mayHoldCompiledIOP(t.Elem(), memo)
+	case reflect.Slice, reflect.Array:
+		res = mayHoldCompiledIOP(t.Elem(), memo)
+	case reflect.Map:
+		res = mayHoldCompiledIOP(t.Elem(), memo)
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !res; i++ {
+			if f := t.Field(i); f.IsExported() {
+				res = mayHoldCompiledIOP(f.Type, memo)
+			}
+		}
+	}
+
+	memo[t] = res
+	return res
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// writeCompiledIOP prints the content of comp round by round.
+func writeCompiledIOP(w io.Writer, path string, comp *wizard.CompiledIOP) {
+
+	if path == "" {
+		path = "(root)"
+	}
+
+	numRounds := max(
+		comp.NumRounds(),
+		comp.Columns.NumRounds(),
+		comp.QueriesParams.NumRounds(),
+		comp.QueriesNoParams.NumRounds(),
+		comp.SubProvers.Len(),
+		comp.SubVerifiers.Len(),
+	)
+
+	fmt.Fprintf(w, "== %s: %d rounds, %d columns, %d queries with params, %d queries without params, %d coins\n",
+		path, numRounds, len(comp.Columns.AllKeys()), len(comp.QueriesParams.AllKeys()),
+		len(comp.QueriesNoParams.AllKeys()), len(comp.Coins.AllKeys()))
+
+	for round := 0; round < numRounds; round++ {
+
+		fmt.Fprintf(w, "-- round %d\n", round)
+
+		for _, name := range comp.Columns.AllKeysAt(round) {
+			fmt.Fprintf(w, "  column    %v size=%d status=%v%s\n",
+				name, comp.Columns.GetHandle(name).Size(), comp.Columns.Status(name), ignoredTag(comp.Columns.IsIgnored(name)))
+		}
+
+		for _, name := range comp.QueriesParams.AllKeysAt(round) {
+			fmt.Fprintf(w, "  query     %v %T%s\n", name, comp.QueriesParams.Data(name), ignoredTag(comp.QueriesParams.IsIgnored(name)))
+		}
+
+		for _, name := range comp.QueriesNoParams.AllKeysAt(round) {
+			fmt.Fprintf(w, "  query     %v %T (no params)%s\n", name, comp.QueriesNoParams.Data(name), ignoredTag(comp.QueriesNoParams.IsIgnored(name)))
+		}
+
+		for _, name := range comp.Coins.AllKeysAt(round) {
+			fmt.Fprintf(w, "  coin      %v %s\n", name, coinTypeName(comp.Coins.Data(name).Type))
+		}
+
+		for _, action := range comp.SubProvers.GetOrEmpty(round) {
+			fmt.Fprintf(w, "  prover    %T\n", action)
+		}
+
+		for _, action := range comp.SubVerifiers.GetOrEmpty(round) {
+			fmt.Fprintf(w, "  verifier  %T\n", action)
+		}
+	}
+}
+
+func ignoredTag(ignored bool) string {
+	if ignored {
+		return " (ignored)"
+	}
+	return ""
+}
+
+func coinTypeName(t coin.Type) string {
+	switch t {
+	case coin.IntegerVec:
+		return "integer-vec"
+	case coin.FieldExt:
+		return "field-ext"
+	case coin.FieldFromSeed:
+		return "field-from-seed"
+	default:
+		return fmt.Sprintf("type-%d", int(t))
+	}
+}
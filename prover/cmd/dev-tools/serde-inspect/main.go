@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/serde"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// rootCmd represents the base command when called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "serde-inspect",
+	Short: "inspects the serialized prover assets (flat, zstd-compressed or chunked)",
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check [assets...]",
+	Short: "validates the header of the assets and, when their type is known, that all their references resolve",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runCheck,
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile [asset]",
+	Short: "prints the serialized size of each part of the asset",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfile,
+}
+
+var iopCmd = &cobra.Command{
+	Use:   "iop [asset]",
+	Short: "lists the columns, queries, coins and prover/verifier actions per round of the compiled IOPs of the asset",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIOP,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [asset-a] [asset-b]",
+	Short: "compares two assets and prints the paths where they differ",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDiff,
+}
+
+// global variables holding the programs arguments
+var (
+	assetType     string
+	minSize       int64
+	maxMismatches int
+	failFast      bool
+)
+
+// initializes the programs flags
+func init() {
+	for _, cmd := range []*cobra.Command{checkCmd, profileCmd, iopCmd, diffCmd} {
+		cmd.Flags().StringVar(&assetType, "type", "", fmt.Sprintf("type of the asset, one of %v; inferred from the file name if omitted", assetTypeNames()))
+		rootCmd.AddCommand(cmd)
+	}
+	profileCmd.Flags().Int64Var(&minSize, "min-size", 1<<20, "hides the parts of the asset smaller than this number of bytes")
+	diffCmd.Flags().BoolVar(&failFast, "fail-fast", false, "stops at the first difference")
+	diffCmd.Flags().IntVar(&maxMismatches, "max", 100, "maximal number of differences to print, 0 to print them all")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		logrus.Fatalf("exiting with error: %v", err)
+	}
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+
+	var nbFailed int
+	for _, path := range args {
+		if err := checkAsset(path); err != nil {
+			fmt.Printf("%s: FAILED: %v\n", path, err)
+			nbFailed++
+			continue
+		}
+		fmt.Printf("%s: OK\n", path)
+	}
+
+	if nbFailed > 0 {
+		return fmt.Errorf("%d of the %d assets are invalid", nbFailed, len(args))
+	}
+	return nil
+}
+
+func checkAsset(path string) error {
+
+	asset, err := serde.OpenRawAsset(path)
+	if err != nil {
+		return err
+	}
+	defer asset.Close()
+
+	header, err := asset.Header()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: format=%v size=%d version=%d payload-type=%d payload-offset=%d",
+		path, asset.Format, header.DataSize, header.Version, header.PayloadType, header.PayloadOff)
+	if asset.Format == serde.AssetChunked {
+		fmt.Printf(" chunks=%d", asset.NumChunks)
+	}
+	fmt.Println()
+
+	name, newAsset, err := resolveAssetType(path)
+	if err != nil {
+		logrus.Warnf("%s: only the header was checked: %v", path, err)
+		return nil
+	}
+
+	if err := asset.Decode(newAsset()); err != nil {
+		return fmt.Errorf("decoding as %s: %w", name, err)
+	}
+	return nil
+}
+
+func runProfile(cmd *cobra.Command, args []string) error {
+
+	obj, asset, err := decodeAsset(args[0])
+	if err != nil {
+		return err
+	}
+	defer asset.Close()
+
+	node, err := serde.Profile(obj)
+	if err != nil {
+		return err
+	}
+
+	return serde.WriteProfileTo(node.PruneTree(minSize), os.Stdout)
+}
+
+func runIOP(cmd *cobra.Command, args []string) error {
+
+	obj, asset, err := decodeAsset(args[0])
+	if err != nil {
+		return err
+	}
+	defer asset.Close()
+
+	iops := findCompiledIOPs(obj)
+	if len(iops) == 0 {
+		return fmt.Errorf("%s does not hold any compiled IOP", args[0])
+	}
+
+	for _, iop := range iops {
+		writeCompiledIOP(os.Stdout, iop.path, iop.comp)
+	}
+	return nil
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+
+	a, assetA, err := decodeAsset(args[0])
+	if err != nil {
+		return err
+	}
+	defer assetA.Close()
+
+	b, assetB, err := decodeAsset(args[1])
+	if err != nil {
+		return err
+	}
+	defer assetB.Close()
+
+	mismatches := serde.DeepDiff(a, b, failFast)
+	for i, m := range mismatches {
+		if maxMismatches > 0 && i >= maxMismatches {
+			fmt.Printf("... and %d more\n", len(mismatches)-i)
+			break
+		}
+		fmt.Println(m)
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("the assets differ in %d place(s)", len(mismatches))
+	}
+
+	fmt.Println("the assets are identical")
+	return nil
+}
+
+// decodeAsset opens the asset at path and deserializes it with the type given
+// by the --type flag or inferred from its name. The returned asset must be
+// closed once the object is no longer used.
+func decodeAsset(path string) (any, *serde.RawAsset, error) {
+
+	name, newAsset, err := resolveAssetType(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	asset, err := serde.OpenRawAsset(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj := newAsset()
+	if err := asset.Decode(obj); err != nil {
+		asset.Close()
+		return nil, nil, fmt.Errorf("could not decode %s as %s: %w", path, name, err)
+	}
+
+	return obj, asset, nil
+}
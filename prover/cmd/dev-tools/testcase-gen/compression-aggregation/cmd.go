@@ -498,6 +498,8 @@ func ProcessInvaliditySpec(rng *rand.Rand, spec *InvalidityProofSpec, prevResp *
 		invalidityReq = RandFilteredAddressProofRequest(rng, spec, *spec.InvalidityType, specFile)
 	case circInvalidity.BadPrecompile, circInvalidity.TooManyLogs:
 		invalidityReq = RandBadPrecompileProofRequest(rng, spec, *spec.InvalidityType)
+	case circInvalidity.GasLimitTooLow, circInvalidity.GasLimitTooHigh:
+		invalidityReq = RandGasLimitProofRequest(rng, spec, *spec.InvalidityType)
 	default:
 		printlnAndExit("unsupported invalidity type: %v", *spec.InvalidityType)
 	}
@@ -84,3 +84,30 @@ func TestInvalidityBadPrecompile(t *testing.T) {
 		})
 	}
 }
+
+// test the gas limit cases only for Dev mode; the transactions are built so
+// that their gas limit is below the minimum gas or above the block gas limit.
+func TestInvalidityGasLimit(t *testing.T) {
+	// #nosec G404 --we don't need a cryptographic RNG for testing purpose
+	rng := rand.New(rand.NewSource(seed))
+
+	configFile = "../../../../config/config-integration-development.toml"
+	viper.Set("assets_dir", "../../../../prover-assets")
+
+	for _, invalidityType := range []circuitInvalidity.InvalidityType{
+		circuitInvalidity.GasLimitTooLow,
+		circuitInvalidity.GasLimitTooHigh,
+	} {
+		invType := invalidityType
+		t.Run(invType.String(), func(t *testing.T) {
+			spec := &InvalidityProofSpec{
+				ChainID:             big.NewInt(59139),
+				ExpectedBlockHeight: 1_000_000_000,
+				FtxNumber:           1678,
+				InvalidityType:      &invType,
+			}
+
+			ProcessInvaliditySpec(rng, spec, nil, "spec-invalidity-gas-limit.json")
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/consensys/linea-monorepo/prover/backend/blobsubmission"
 	"github.com/consensys/linea-monorepo/prover/backend/invalidity"
 	circInvalidity "github.com/consensys/linea-monorepo/prover/circuits/invalidity"
+	"github.com/consensys/linea-monorepo/prover/config"
 	"github.com/consensys/linea-monorepo/prover/maths/field"
 	"github.com/consensys/linea-monorepo/prover/utils"
 	linTypes "github.com/consensys/linea-monorepo/prover/utils/types"
@@ -345,6 +346,66 @@ func RandFilteredAddressProofRequest(rng *rand.Rand, spec *InvalidityProofSpec,
 	}
 }
 
+// RandGasLimitProofRequest generates a random invalidity request for the
+// GasLimitTooLow or GasLimitTooHigh case. The gas limit of the transaction is
+// set one unit below its minimum gas or one unit above the default block gas
+// limit.
+func RandGasLimitProofRequest(rng *rand.Rand, spec *InvalidityProofSpec, invalidityType circInvalidity.InvalidityType) *invalidity.Request {
+
+	if invalidityType != circInvalidity.GasLimitTooLow && invalidityType != circInvalidity.GasLimitTooHigh {
+		panic(fmt.Sprintf("RandGasLimitProofRequest: expected GasLimitTooLow or GasLimitTooHigh, got %v", invalidityType))
+	}
+
+	var (
+		signer  = types.NewLondonSigner(spec.ChainID)
+		address = common.HexToAddress("0xfeeddeadbeeffeeddeadbeeffeeddead01245678")
+	)
+
+	deterministicSeed := fmt.Sprintf("fixed_test_seed_for_invalidity_proof_123456_%v", rng.Int63())
+	hash := crypto.Keccak256([]byte(deterministicSeed))
+	privKey, err := crypto.ToECDSA(hash)
+	if err != nil {
+		panic(err)
+	}
+
+	txData := &types.DynamicFeeTx{
+		ChainID:   spec.ChainID,
+		Nonce:     rng.Uint64() % 100,
+		GasTipCap: big.NewInt(int64(112121212)),
+		GasFeeCap: big.NewInt(int64(123543135)),
+		To:        &address,
+		Value:     big.NewInt(int64(845315452)),
+		Data:      []byte{0x00, 0x01, 0x02, 0x03},
+	}
+
+	if invalidityType == circInvalidity.GasLimitTooLow {
+		txData.Gas = circInvalidity.MinimumGas(types.NewTx(txData)) - 1
+	} else {
+		txData.Gas = config.DefaultBlockGasLimit + 1
+	}
+
+	signedTx, err := types.SignTx(types.NewTx(txData), signer, privKey)
+	if err != nil {
+		panic(fmt.Sprintf("failed to sign transaction: %v", err))
+	}
+
+	rlpEncodedTxBytes, err := signedTx.MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal signed transaction: %v", err))
+	}
+
+	return &invalidity.Request{
+		RlpEncodedTx:                     "0x" + common.Bytes2Hex(rlpEncodedTxBytes),
+		ForcedTransactionNumber:          uint64(spec.FtxNumber),
+		InvalidityType:                   invalidityType,
+		DeadlineBlockHeight:              uint64(spec.ExpectedBlockHeight),
+		PrevFtxRollingHash:               linTypes.Bls12377Fr{},
+		SimulatedExecutionBlockNumber:    uint64(spec.LastFinalizedBlockNumber) + 1,
+		SimulatedExecutionBlockTimestamp: 1700000000,
+		ZkParentStateRootHash:            linTypes.KoalaOctuplet(field.RandomOctuplet()),
+	}
+}
+
 // RandBadPrecompileProofRequest generates a random invalidity request for the
 // BadPrecompile or TooManyLogs case. Trace fields are left empty; prove.go
 // will use MockZkevmArithCols to generate the wizard proof internally.
@@ -49,6 +49,7 @@ func Prove(args ProverArgs) error {
 		jobExecution        = strings.Contains(args.Input, "getZkProof")
 		jobDataAvailability = strings.Contains(args.Input, "getZkBlobCompressionProof")
 		jobInvalidity       = strings.Contains(args.Input, "getZkInvalidityProof")
+		jobInvalidityBatch  = strings.Contains(args.Input, "getZkInvalidityBatchProof")
 		jobAggregation      = strings.Contains(args.Input, "getZkAggregatedProof")
 	)
 
@@ -58,6 +59,8 @@ func Prove(args ProverArgs) error {
 		return handleExecutionJob(cfg, args)
 	case jobInvalidity:
 		return handleInvalidityJob(cfg, args)
+	case jobInvalidityBatch:
+		return handleInvalidityBatchJob(cfg, args)
 	case jobDataAvailability:
 		return handleDataAvailabilityJob(cfg, args)
 	case jobAggregation:
@@ -83,6 +86,14 @@ func handleExecutionJob(cfg *config.Config, args ProverArgs) error {
 		if err != nil {
 			return fmt.Errorf("could not prove the execution in limitless mode: %w", err)
 		}
+	} else if cfg.Execution.ProverMode == config.ProverModeWitnessExport {
+		// Witness-export mode: the output is the directory of the witness,
+		// the response is written by the prove-from-witness command.
+		large := args.Large || (strings.Contains(args.Input, "large") && cfg.Execution.CanRunFullLarge)
+		if err := execution.ExportWitness(cfg, req, large, args.Output); err != nil {
+			return fmt.Errorf("could not export the execution witness: %w", err)
+		}
+		return nil
 	} else {
 		// Standard execution mode
 		large := args.Large || (strings.Contains(args.Input, "large") && cfg.Execution.CanRunFullLarge)
@@ -149,6 +160,20 @@ func handleInvalidityJob(cfg *config.Config, args ProverArgs) error {
 	return writeResponse(args.Output, resp)
 }
 
+// handleInvalidityBatchJob processes a batched invalidity job
+func handleInvalidityBatchJob(cfg *config.Config, args ProverArgs) error {
+	req := &invalidity.BatchRequest{}
+	if err := readRequest(args.Input, req); err != nil {
+		return fmt.Errorf("could not read the input file (%v): %w", args.Input, err)
+	}
+
+	resp, err := invalidity.ProveBatch(cfg, req)
+	if err != nil {
+		return fmt.Errorf("could not prove the invalidity batch: %w", err)
+	}
+	return writeResponse(args.Output, resp)
+}
+
 // readRequest reads and decodes a request from a file
 func readRequest(path string, into any) error {
 	f, err := os.Open(path)
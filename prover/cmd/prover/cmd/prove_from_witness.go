@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils/signal"
+)
+
+type ProveFromWitnessArgs struct {
+	Input      string
+	Output     string
+	ConfigFile string
+}
+
+// ProveFromWitness completes an execution proof from a witness exported by
+// the prove command in witness-export mode and writes the response.
+func ProveFromWitness(args ProveFromWitnessArgs) error {
+
+	signal.RegisterStackTraceDumpHandler()
+
+	const cmdName = "prove-from-witness"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file at %v: %w", cmdName, args.ConfigFile, err)
+	}
+
+	resp, err := execution.ProveFromWitness(cfg, args.Input)
+	if err != nil {
+		return fmt.Errorf("could not prove the execution from the witness: %w", err)
+	}
+
+	return writeResponse(args.Output, resp)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/consensys/linea-monorepo/prover/backend/replay"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+type ReplayArgs struct {
+	Bundle string
+	// WorkDir is where the bundle is extracted. A temporary directory is
+	// used if empty. It is kept after the replay for investigation.
+	WorkDir string
+	// Output is the response file, defaults to a file in WorkDir
+	Output string
+	// AssetsDir overrides the assets directory of the config of the bundle
+	AssetsDir string
+}
+
+// Replay re-runs the prover on a replay bundle written by the controller. The
+// prove command is run in a child process as the environment overrides of the
+// bundle are read by the prover at startup. The returned error wraps the
+// [exec.ExitError] of the child so that the replay exits with the same code.
+func Replay(args ReplayArgs) error {
+
+	dir := args.WorkDir
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "prover-replay-")
+		if err != nil {
+			return err
+		}
+		dir = tmp
+	}
+
+	m, err := replay.Extract(args.Bundle, dir)
+	if err != nil {
+		return fmt.Errorf("could not extract the replay bundle: %w", err)
+	}
+
+	logrus.Infof("Replaying %v job %v (exit code %v, large=%v, prover version %v, revision %v) in %v",
+		m.JobName, m.Request, m.ExitCode, m.Large, m.ProverVersion, m.ProverRevision, dir)
+
+	// The prover may behave differently when built from another revision.
+	if rev := replay.BuildRevision(); rev != m.ProverRevision {
+		logrus.Warnf("the bundle was written by a binary built from revision %q but the replay runs revision %q: the outcome may differ from the original run",
+			m.ProverRevision, rev)
+	}
+
+	// The trace and the assets are looked for at the paths of the machine
+	// where the job failed.
+	overrides := map[string]any{}
+	if m.Trace != "" {
+		overrides["execution.conflated_traces_dir"] = m.TracesDir(dir)
+	}
+	if args.AssetsDir != "" {
+		overrides["assets_dir"] = args.AssetsDir
+	}
+
+	cfgPath := filepath.Join(dir, "replay"+filepath.Ext(m.Config))
+	if err := replay.RewriteConfig(m.ConfigPath(dir), cfgPath, overrides); err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfigFromFile(cfgPath)
+	if err != nil {
+		return fmt.Errorf("replay failed to read the config of the bundle: %w", err)
+	}
+
+	if err := replay.CheckSetupChecksums(cfg, m.SetupChecksums); err != nil {
+		return err
+	}
+
+	output := args.Output
+	if output == "" {
+		output = filepath.Join(dir, "response.json")
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	cmdArgs := []string{"prove", "--config", cfgPath, "--in", m.RequestPath(dir), "--out", output}
+	if m.Large {
+		cmdArgs = append(cmdArgs, "--large")
+	}
+
+	// The overrides of the bundle come last so that they take precedence
+	child := exec.Command(exe, cmdArgs...)
+	child.Env = append(os.Environ(), m.EnvList()...)
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	logrus.Infof("Running %v with %v", child.String(), m.EnvList())
+
+	if err := child.Run(); err != nil {
+		return fmt.Errorf("the replayed job failed (the original run exited with code %v): %w", m.ExitCode, err)
+	}
+
+	logrus.Infof("The replayed job succeeded (the original run exited with code %v), response written in %v", m.ExitCode, output)
+	return nil
+}
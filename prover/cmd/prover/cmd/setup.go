@@ -8,20 +8,27 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/consensys/linea-monorepo/prover/circuits/invalidity"
 	pi_interconnection "github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection"
 	"github.com/consensys/linea-monorepo/prover/circuits/pi-interconnection/keccak"
+	"github.com/consensys/linea-monorepo/prover/crypto/soundness"
 	"github.com/consensys/linea-monorepo/prover/crypto/state-management/smt_koalabear"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
 	"github.com/consensys/linea-monorepo/prover/protocol/serde"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
 	"github.com/consensys/linea-monorepo/prover/utils/signal"
 
 	blob_v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
 	"github.com/sirupsen/logrus"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	kzg377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
 	gnarkio "github.com/consensys/gnark/io"
 	"github.com/consensys/linea-monorepo/prover/circuits"
 	"github.com/consensys/linea-monorepo/prover/circuits/aggregation"
@@ -52,6 +59,9 @@ var AllCircuits = []circuits.CircuitID{
 	circuits.InvalidityPrecompileLogsLargeCircuitID,
 	circuits.InvalidityPrecompileLogsLimitlessCircuitID,
 	circuits.InvalidityFilteredAddressCircuitID,
+	circuits.InvalidityGasLimitCircuitID,
+	circuits.InvalidityNonceBalanceBatchCircuitID,
+	circuits.InvalidityGasLimitBatchCircuitID,
 	circuits.PublicInputInterconnectionCircuitID,
 	circuits.AggregationCircuitID,
 	circuits.EmulationCircuitID,
@@ -78,6 +88,27 @@ var PayloadCircuits = []string{
 	"invalidity-precompile-logs-large",     // ID 13
 }
 
+// AppendedPayloadCircuits defines the ordered list of the payload circuits added
+// after the infrastructure circuits. This order corresponds to circuit IDs 18
+// onwards in GlobalCircuitIDMapping.
+var AppendedPayloadCircuits = []string{
+	"invalidity-gas-limit-dummy",     // ID 18
+	"invalidity-gas-limit",           // ID 19
+	"invalidity-nonce-balance-batch", // ID 20
+	"invalidity-gas-limit-batch",     // ID 21
+}
+
+// payloadCircuitsByID returns the names of the circuits verified by the
+// aggregation circuit, indexed by circuit ID. The IDs of the infrastructure
+// circuits (14-17) are left empty.
+func payloadCircuitsByID() []string {
+	res := slices.Clone(PayloadCircuits)
+	for id := uint(len(res)); circuits.IsInfrastructureCircuitID(id); id++ {
+		res = append(res, "")
+	}
+	return append(res, AppendedPayloadCircuits...)
+}
+
 // Setup orchestrates the setup process for specified circuits, ensuring assets are generated or updated as needed.
 func Setup(ctx context.Context, args SetupArgs) error {
 	const cmdName = "setup"
@@ -154,7 +185,7 @@ func Setup(ctx context.Context, args SetupArgs) error {
 		return fmt.Errorf("%s failed to load public input interconnection setup: %w", cmdName, err)
 	}
 
-	// Collect verifying keys for payload circuits only (IDs 0-13)
+	// Collect verifying keys for payload circuits only (IDs 0-13 and 18+)
 	// The IsAllowedCircuitID bitmask in the config determines which ones are actually allowed at runtime
 	payloadVks, err := collectPayloadVerifyingKeys(ctx, cfg, srsProvider)
 	if err != nil {
@@ -260,6 +291,36 @@ func serializeInnerCircuit(cfg *config.Config, c circuits.CircuitID) error {
 	return nil
 }
 
+// checkSoundness logs the conjectured soundness of the Vortex layers of the
+// compiled zkEVM and returns an error if one of them is under the
+// MinSecurityBits of the execution config.
+func checkSoundness(cfg *config.Config, zkEvm *zkevm.ZkEvm) error {
+
+	var layers []soundness.Layer
+	for _, iop := range []struct {
+		name string
+		comp *wizard.CompiledIOP
+	}{
+		{"pre-recursion", zkEvm.InitialCompiledIOP},
+		{"post-recursion", zkEvm.RecursionCompiledIOP},
+	} {
+		if iop.comp == nil {
+			continue
+		}
+		for _, l := range vortex.Soundness(iop.comp) {
+			l.Name = iop.name + "/" + l.Name
+			logrus.Infof("[soundness] %v", l)
+			layers = append(layers, l)
+		}
+	}
+
+	if cfg.Execution.MinSecurityBits == 0 {
+		return nil
+	}
+
+	return soundness.Check(layers, float64(cfg.Execution.MinSecurityBits))
+}
+
 // parseCircuitInputs: Converts the comma-separated circuit string into a map of enabled circuits.
 func parseCircuitInputs(circuitsStr string) (map[circuits.CircuitID]bool, error) {
 	inCircuits := make(map[circuits.CircuitID]bool)
@@ -287,6 +348,9 @@ func createCircuitBuilder(c circuits.CircuitID, cfg *config.Config, args SetupAr
 		limits := cfg.TracesLimits
 		extraFlags["cfg_checksum"] = limits.Checksum()
 		zkEvm := zkevm.FullZkEvm(&limits, cfg)
+		if err := checkSoundness(cfg, zkEvm); err != nil {
+			return nil, nil, fmt.Errorf("circuit %s: %w", c, err)
+		}
 		return execution.NewBuilder(zkEvm), extraFlags, nil
 
 	case circuits.ExecutionLargeCircuitID:
@@ -294,6 +358,9 @@ func createCircuitBuilder(c circuits.CircuitID, cfg *config.Config, args SetupAr
 		limits.SetLargeMode()
 		extraFlags["cfg_checksum"] = limits.Checksum()
 		zkEvm := zkevm.FullZkEvmLarge(&limits, cfg)
+		if err := checkSoundness(cfg, zkEvm); err != nil {
+			return nil, nil, fmt.Errorf("circuit %s: %w", c, err)
+		}
 		return execution.NewBuilder(zkEvm), extraFlags, nil
 
 	case circuits.ExecutionLimitlessCircuitID:
@@ -405,6 +472,40 @@ func createCircuitBuilder(c circuits.CircuitID, cfg *config.Config, args SetupAr
 			},
 			&invalidity.FilteredAddressCircuit{}), extraFlags, nil
 
+	case circuits.InvalidityGasLimitCircuitID:
+		// the block gas limit is a constant of the circuit
+		extraFlags["blockGasLimit"] = cfg.Invalidity.BlockGasLimit
+		keccakComp := invalidity.MakeKeccakCompiledIOP(cfg.Invalidity.MaxRlpByteSize, keccak.WizardCompilationParameters()...)
+		return invalidity.NewBuilder(
+			invalidity.Config{
+				Depth:             smt_koalabear.DefaultDepth,
+				KeccakCompiledIOP: keccakComp,
+				MaxRlpByteSize:    cfg.Invalidity.MaxRlpByteSize,
+				BlockGasLimit:     cfg.Invalidity.BlockGasLimit,
+			},
+			&invalidity.GasLimitCircuit{}), extraFlags, nil
+
+	case circuits.InvalidityNonceBalanceBatchCircuitID,
+		circuits.InvalidityGasLimitBatchCircuitID:
+		// the batch size and block gas limit are constants of the circuit
+		extraFlags["maxBatchSize"] = cfg.Invalidity.MaxBatchSize
+		newSubCircuit := func() invalidity.SubCircuit { return &invalidity.BadNonceBalanceCircuit{} }
+		if c == circuits.InvalidityGasLimitBatchCircuitID {
+			extraFlags["blockGasLimit"] = cfg.Invalidity.BlockGasLimit
+			newSubCircuit = func() invalidity.SubCircuit { return &invalidity.GasLimitCircuit{} }
+		}
+		// a single keccak proof covers the transactions of the whole batch
+		keccakComp := invalidity.MakeKeccakCompiledIOPBatch(cfg.Invalidity.MaxRlpByteSize, cfg.Invalidity.MaxBatchSize, keccak.WizardCompilationParameters()...)
+		return invalidity.NewBatchBuilder(
+			invalidity.Config{
+				Depth:             smt_koalabear.DefaultDepth,
+				KeccakCompiledIOP: keccakComp,
+				MaxRlpByteSize:    cfg.Invalidity.MaxRlpByteSize,
+				BlockGasLimit:     cfg.Invalidity.BlockGasLimit,
+			},
+			cfg.Invalidity.MaxBatchSize,
+			newSubCircuit), extraFlags, nil
+
 	case circuits.EmulationDummyCircuitID:
 		// we can get the Verifier.sol from there.
 		return dummy.NewBuilder(circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField()), extraFlags, nil
@@ -414,14 +515,21 @@ func createCircuitBuilder(c circuits.CircuitID, cfg *config.Config, args SetupAr
 	}
 }
 
-// collectPayloadVerifyingKeys gathers verifying keys for payload circuits only (IDs 0-13).
+// collectPayloadVerifyingKeys gathers verifying keys for payload circuits only (IDs 0-13 and 18+).
 // These are the circuits that can be aggregated. Infrastructure circuits (emulation,
 // aggregation, pi-interconnection, emulation-dummy) are excluded.
-// The returned slice is indexed by payload circuit ID (0-13).
+// The returned slice is indexed by circuit ID. The slots of the infrastructure
+// circuits (14-17) hold a placeholder key that no proof can verify against,
+// see [placeholderVerifyingKey].
 func collectPayloadVerifyingKeys(ctx context.Context, cfg *config.Config, srsProvider circuits.SRSProvider) ([]plonk.VerifyingKey, error) {
-	payloadVks := make([]plonk.VerifyingKey, len(PayloadCircuits))
+	payloadCircuits := payloadCircuitsByID()
+	payloadVks := make([]plonk.VerifyingKey, len(payloadCircuits))
+
+	for i, circuitName := range payloadCircuits {
+		if circuits.IsInfrastructureCircuitID(uint(i)) {
+			continue
+		}
 
-	for i, circuitName := range PayloadCircuits {
 		logrus.Infof("Collecting verifying key for payload circuit %s (ID %d)", circuitName, i)
 
 		if isPayloadDummyCircuit(circuitName) {
@@ -448,9 +556,51 @@ func collectPayloadVerifyingKeys(ctx context.Context, cfg *config.Config, srsPro
 		payloadVks[i] = vk
 	}
 
+	for i := range payloadVks {
+		if !circuits.IsInfrastructureCircuitID(uint(i)) {
+			continue
+		}
+		vk, err := placeholderVerifyingKey(payloadVks[0], uint(i))
+		if err != nil {
+			return nil, err
+		}
+		payloadVks[i] = vk
+	}
+
 	return payloadVks, nil
 }
 
+// placeholderVerifyingKey returns the verifying key used for the slot of the
+// infrastructure circuit id. It shares the base verifying key (domain, SRS,
+// coset shift, public inputs) of vk, as required by the aggregation circuit,
+// but its selector and permutation commitments are hashed to the curve so that
+// their discrete logs are unknown and no proof can verify against it.
+func placeholderVerifyingKey(vk plonk.VerifyingKey, id uint) (plonk.VerifyingKey, error) {
+	vk0, ok := vk.(*plonk_bls12377.VerifyingKey)
+	if !ok {
+		return nil, fmt.Errorf("unexpected verifying key type %T", vk)
+	}
+
+	res := *vk0
+	res.Qcp = slices.Clone(vk0.Qcp)
+	res.CommitmentConstraintIndexes = slices.Clone(vk0.CommitmentConstraintIndexes)
+
+	dst := []byte("LINEA-PLACEHOLDER-VK-" + circuits.CircuitNameByID(id))
+	digests := []*kzg377.Digest{&res.S[0], &res.S[1], &res.S[2], &res.Ql, &res.Qr, &res.Qm, &res.Qo, &res.Qk}
+	for i := range res.Qcp {
+		digests = append(digests, &res.Qcp[i])
+	}
+	for i, d := range digests {
+		p, err := bls12377.HashToG1([]byte{byte(i)}, dst)
+		if err != nil {
+			return nil, fmt.Errorf("could not hash the placeholder commitment %d to the curve: %w", i, err)
+		}
+		*d = p
+	}
+
+	return &res, nil
+}
+
 // isPayloadDummyCircuit returns true if the circuit is a dummy circuit for payload testing.
 // Note: emulation-dummy is NOT a payload dummy - it's an infrastructure circuit dummy.
 func isPayloadDummyCircuit(cID string) bool {
@@ -458,7 +608,8 @@ func isPayloadDummyCircuit(cID string) bool {
 	case circuits.ExecutionDummyCircuitID, circuits.DataAvailabilityDummyCircuitID,
 		circuits.InvalidityNonceBalanceDummyCircuitID,
 		circuits.InvalidityPrecompileLogsDummyCircuitID,
-		circuits.InvalidityFilteredAddressDummyCircuitID:
+		circuits.InvalidityFilteredAddressDummyCircuitID,
+		circuits.InvalidityGasLimitDummyCircuitID:
 		return true
 	}
 	return false
@@ -479,6 +630,8 @@ func getDummyCircuitParams(cID string) (ecc.ID, circuits.MockCircuitID, error) {
 		return ecc.BLS12_377, circuits.MockCircuitIDInvalidityPrecompileLogs, nil
 	case circuits.InvalidityFilteredAddressDummyCircuitID:
 		return ecc.BLS12_377, circuits.MockCircuitIDInvalidityFilteredAddress, nil
+	case circuits.InvalidityGasLimitDummyCircuitID:
+		return ecc.BLS12_377, circuits.MockCircuitIDInvalidityGasLimit, nil
 	default:
 		return 0, 0, fmt.Errorf("unknown dummy circuit: %s", cID)
 	}
@@ -517,7 +670,7 @@ func setupAggregationCircuits(ctx context.Context, cfg *config.Config, force boo
 	// for the aggregation circuits to be able to check compatibility at run time with the proofs
 	extraFlags := map[string]any{
 		"allowedVkForAggregationDigests":      listOfChecksums(payloadVks),
-		"allowedVkForAggregationCircuitNames": PayloadCircuits,
+		"allowedVkForAggregationCircuitNames": aggregationCircuitNames(),
 	}
 
 	allowedVkForEmulation := make([]plonk.VerifyingKey, 0, len(cfg.Aggregation.NumProofs))
@@ -547,6 +700,18 @@ func setupAggregationCircuits(ctx context.Context, cfg *config.Config, force boo
 	return allowedVkForEmulation, nil
 }
 
+// aggregationCircuitNames returns the names of the circuits of the verifying
+// keys passed to the aggregation circuit, see [collectPayloadVerifyingKeys].
+func aggregationCircuitNames() []string {
+	names := payloadCircuitsByID()
+	for i := range names {
+		if circuits.IsInfrastructureCircuitID(uint(i)) {
+			names[i] = "unused-" + circuits.CircuitNameByID(uint(i))
+		}
+	}
+	return names
+}
+
 // getDummyCircuitVK compiles a dummy circuit and returns its verifying key.
 // This is used by collectVerifyingKeys to get VKs for dummy circuits.
 func getDummyCircuitVK(ctx context.Context, srsProvider circuits.SRSProvider, circuit circuits.CircuitID, builder circuits.Builder) (plonk.VerifyingKey, error) {
@@ -8,6 +8,7 @@ import (
 	frBls "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	frBw6 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 	"github.com/consensys/gnark/backend/plonk"
+	plonk_bls12377 "github.com/consensys/gnark/backend/plonk/bls12-377"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/scs"
 	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
@@ -362,6 +363,74 @@ func TestPayloadCircuitsMatchGlobalMapping(t *testing.T) {
 	}
 }
 
+// TestPayloadCircuitsByID checks that the verifying keys passed to the
+// aggregation circuit are indexed by GlobalCircuitIDMapping ID: every payload
+// circuit, including the ones appended after the infrastructure circuits, is
+// at the index of its ID, and the infrastructure IDs are left empty.
+func TestPayloadCircuitsByID(t *testing.T) {
+	byID := payloadCircuitsByID()
+
+	for name, id := range circuits.GlobalCircuitIDMapping {
+		if circuits.IsInfrastructureCircuitID(id) {
+			require.Less(t, int(id), len(byID))
+			assert.Empty(t, byID[id], "infrastructure circuit %q must not be aggregated", name)
+			continue
+		}
+		require.Less(t, int(id), len(byID), "circuit %q (ID %d) is missing", name, id)
+		assert.Equal(t, name, byID[id])
+	}
+
+	assert.Len(t, byID, len(circuits.GlobalCircuitIDMapping))
+
+	names := aggregationCircuitNames()
+	require.Len(t, names, len(byID))
+	assert.Equal(t, "unused-emulation", names[circuits.GlobalCircuitIDMapping["emulation"]])
+}
+
+// TestPlaceholderVerifyingKey checks that the placeholder keys of the
+// infrastructure slots share the base verifying key of circuit 0, are distinct
+// from each other, and reject an honest proof of circuit 0.
+func TestPlaceholderVerifyingKey(t *testing.T) {
+	curveID, mockID, err := getDummyCircuitParams(string(circuits.ExecutionDummyCircuitID))
+	require.NoError(t, err)
+
+	ccs, err := dummy.NewBuilder(mockID, curveID.ScalarField()).Compile()
+	require.NoError(t, err)
+	setup, err := circuits.MakeSetup(context.Background(), circuits.ExecutionDummyCircuitID, ccs, circuits.NewUnsafeSRSProvider(), nil)
+	require.NoError(t, err)
+
+	assignment := dummy.Assign(mockID, frBls.NewElement(42))
+	witness, err := frontend.NewWitness(assignment, curveID.ScalarField())
+	require.NoError(t, err)
+	publicWitness, err := witness.Public()
+	require.NoError(t, err)
+	proof, err := plonk.Prove(setup.Circuit, setup.ProvingKey, witness)
+	require.NoError(t, err)
+	require.NoError(t, plonk.Verify(proof, setup.VerifyingKey, publicWitness))
+
+	vk0 := setup.VerifyingKey.(*plonk_bls12377.VerifyingKey)
+	seen := map[string]bool{listOfChecksums([]plonk.VerifyingKey{vk0})[0]: true}
+	for name, id := range circuits.GlobalCircuitIDMapping {
+		if !circuits.IsInfrastructureCircuitID(id) {
+			continue
+		}
+		vk, err := placeholderVerifyingKey(vk0, id)
+		require.NoError(t, err)
+
+		placeholder := vk.(*plonk_bls12377.VerifyingKey)
+		assert.Equal(t, vk0.Size, placeholder.Size)
+		assert.Equal(t, vk0.NbPublicVariables, placeholder.NbPublicVariables)
+		assert.Equal(t, vk0.CosetShift, placeholder.CosetShift)
+		assert.Equal(t, vk0.Kzg, placeholder.Kzg)
+
+		digest := listOfChecksums([]plonk.VerifyingKey{vk})[0]
+		assert.False(t, seen[digest], "placeholder key of %q collides with another key", name)
+		seen[digest] = true
+
+		assert.Error(t, plonk.Verify(proof, vk, publicWitness), "placeholder key of %q accepts a proof", name)
+	}
+}
+
 // circuitNameToIdx returns the index of a circuit name in the allowedInputs slice
 func circuitNameToIdx(allowedInputs []string, name string) int {
 	for i, n := range allowedInputs {
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/linea-monorepo/prover/backend/files"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/crypto/poseidon2_koalabear"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/logdata"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/recursion"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/vortex"
+	"github.com/consensys/linea-monorepo/prover/protocol/plonkinternal"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/consensys/linea-monorepo/prover/zkevm"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the compilation suites accepted by the suite-report command.
+const (
+	SuiteFull  = "full"
+	SuiteLarge = "large"
+)
+
+type SuiteReportArgs struct {
+	ConfigFile string
+	// Suite is either [SuiteFull] or [SuiteLarge].
+	Suite string
+	// Output is the path of the CSV report. The report is written to the
+	// standard output when empty.
+	Output string
+	// Gnark enables the compilation of the gnark recursion circuit after each
+	// Vortex step to count its constraints and hash calls. This is slow.
+	Gnark bool
+}
+
+// SuiteReport compiles the zkEVM with the pre-recursion and post-recursion
+// suites of the execution prover and writes, for each compilation step, the
+// size of the compiled-IOP and of its proof and the cost of its verifier.
+func SuiteReport(args SuiteReportArgs) error {
+
+	const cmdName = "suite-report"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file: %w", cmdName, err)
+	}
+
+	limits := cfg.TracesLimits
+	switch args.Suite {
+	case SuiteFull:
+	case SuiteLarge:
+		limits.SetLargeMode()
+	default:
+		return fmt.Errorf("%s: unknown suite %q, expected %q or %q", cmdName, args.Suite, SuiteFull, SuiteLarge)
+	}
+
+	report := &logdata.SuiteReport{}
+	if args.Gnark {
+		report.VerifierCost = recursionCircuitCost
+	}
+
+	var (
+		pre, post = zkevm.FullCompilationSuites(args.Suite == SuiteLarge)
+		preSuite  = report.Instrument("pre-recursion", pre)
+		postSuite = report.Instrument("post-recursion", post)
+	)
+
+	zkevm.FullZKEVMWithSuite(&limits, cfg, preSuite, &postSuite)
+
+	var w io.Writer = os.Stdout
+	if args.Output != "" {
+		f := files.MustOverwrite(args.Output)
+		defer f.Close()
+		w = f
+	}
+
+	if err := report.WriteCSV(w); err != nil {
+		return fmt.Errorf("%s could not write the report: %w", cmdName, err)
+	}
+
+	return nil
+}
+
+// recursionCircuitCost compiles the gnark circuit verifying comp, the way the
+// post-recursion suite does, and returns its number of constraints and of
+// Poseidon2 compressions. It only applies right after a Vortex compilation
+// over Koalabear; the BLS12-377 outer circuit is not covered.
+func recursionCircuitCost(comp *wizard.CompiledIOP) (hashCalls, constraints int, ok bool, err error) {
+
+	pcs, isVortex := comp.PcsCtxs.(*vortex.Ctx)
+	if !isVortex || pcs.IsBLS {
+		return 0, 0, false, nil
+	}
+
+	circ := recursion.AllocRecursionCircuit(comp, true)
+	ccs, getClaims, err := plonkinternal.CompileCircuitWithExternalHasher(circ, false)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("could not compile the recursion circuit: %w", err)
+	}
+
+	// The external hasher records one claim per lane of every compression.
+	hashCalls = len(getClaims()) / poseidon2_koalabear.BlockSize
+
+	logrus.Infof("[suite-report] recursion circuit: %v constraints, %v hash calls", ccs.GetNbConstraints(), hashCalls)
+	return hashCalls, ccs.GetNbConstraints(), true, nil
+}
@@ -0,0 +1,511 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	frBn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/plonk"
+	plonk_bn254 "github.com/consensys/gnark/backend/plonk/bn254"
+	"github.com/consensys/gnark/backend/witness"
+	emPlonk "github.com/consensys/gnark/std/recursion/plonk"
+	"github.com/consensys/linea-monorepo/prover/backend/aggregation"
+	"github.com/consensys/linea-monorepo/prover/backend/dataavailability"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/backend/invalidity"
+	"github.com/consensys/linea-monorepo/prover/circuits"
+	"github.com/consensys/linea-monorepo/prover/circuits/dummy"
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/consensys/linea-monorepo/prover/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type VerifyArgs struct {
+	Input      string
+	ConfigFile string
+}
+
+// Exit codes returned by the prover binary when the verify command rejects a
+// response. Any other failure exits with code 1. Code 2 is avoided as the Go
+// runtime uses it for panics.
+const (
+	ExitCodePublicInputMismatch = 4
+	ExitCodeInvalidProof        = 3
+)
+
+var (
+	// ErrPublicInputMismatch is returned when the public input recomputed from
+	// the fields of a response differs from the one it claims.
+	ErrPublicInputMismatch = errors.New("public input mismatch")
+	// ErrInvalidProof is returned when the proof of a response does not pass
+	// the PLONK verifier.
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// ExitCode returns the process exit code to use for an error returned by one
+// of the commands. The errors wrapping the exit of a child process, as in
+// [Replay], return its exit code.
+func ExitCode(err error) int {
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return exitErr.ExitCode()
+	case errors.Is(err, ErrPublicInputMismatch):
+		return ExitCodePublicInputMismatch
+	case errors.Is(err, ErrInvalidProof):
+		return ExitCodeInvalidProof
+	default:
+		return 1
+	}
+}
+
+// responseKind identifies the type of a prover response.
+type responseKind string
+
+const (
+	kindExecution        responseKind = "execution"
+	kindDataAvailability responseKind = "data-availability"
+	kindInvalidity       responseKind = "invalidity"
+	kindInvalidityBatch  responseKind = "invalidity-batch"
+	kindAggregation      responseKind = "aggregation"
+)
+
+// responseClaim is what a BLS12-377 response claims to have proven: a proof,
+// its public input (already checked against the response fields) and the
+// digest of the verifying key it was produced for.
+type responseClaim struct {
+	kind               responseKind
+	publicInput        fr.Element
+	proof              string
+	verifyingKeyShaSum string
+	// circuitIDs lists the setups on disk that may have produced the proof
+	// and mockCircuitIDs the dummy circuits used in dev mode.
+	circuitIDs     []circuits.CircuitID
+	mockCircuitIDs []circuits.MockCircuitID
+}
+
+// Verify checks a response file produced by the prove command. The public
+// input is recomputed from the fields of the response and compared with the
+// claimed one, then the proof is verified against the setup whose verifying
+// key matches the one referenced by the response.
+func Verify(args VerifyArgs) error {
+
+	const cmdName = "verify"
+
+	cfg, err := config.NewConfigFromFile(args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("%s failed to read config file at %v: %w", cmdName, args.ConfigFile, err)
+	}
+
+	raw, err := os.ReadFile(args.Input)
+	if err != nil {
+		return fmt.Errorf("%s failed to read the response file at %v: %w", cmdName, args.Input, err)
+	}
+
+	kind, err := detectResponseKind(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmdName, err)
+	}
+
+	logrus.Infof("verifying %v response %v", kind, args.Input)
+
+	if kind == kindAggregation {
+		if err := verifyAggregationResponse(cfg, raw); err != nil {
+			return fmt.Errorf("%s: %w", cmdName, err)
+		}
+		logrus.Infof("%v proof is valid", kind)
+		return nil
+	}
+
+	claim, err := parseResponse(cfg, kind, raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmdName, err)
+	}
+
+	logrus.Infof("public input matches the response fields: %v", claim.publicInput.String())
+
+	setup, err := findSetup(cfg, claim)
+	if err != nil {
+		return fmt.Errorf("%s: %w", cmdName, err)
+	}
+
+	if err := verifyClaim(&setup, claim); err != nil {
+		return fmt.Errorf("%s: %w", cmdName, err)
+	}
+
+	logrus.Infof("%v proof is valid", kind)
+	return nil
+}
+
+// detectResponseKind tells the type of a response from the JSON keys that
+// only this type of response carries.
+func detectResponseKind(raw []byte) (responseKind, error) {
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("could not parse the response: %w", err)
+	}
+
+	has := func(key string) bool {
+		_, ok := fields[key]
+		return ok
+	}
+
+	// The order matters: the invalidity batch entries carry an
+	// invalidityType but only the batch response has "ftxs".
+	switch {
+	case has("aggregatedProof"):
+		return kindAggregation, nil
+	case has("decompressionProof"):
+		return kindDataAvailability, nil
+	case has("ftxs"):
+		return kindInvalidityBatch, nil
+	case has("invalidityType"):
+		return kindInvalidity, nil
+	case has("blocksData"):
+		return kindExecution, nil
+	default:
+		return "", errors.New("unknown response type")
+	}
+}
+
+// parseResponse decodes a BLS12-377 response, recomputes its public input
+// from its fields and returns an error wrapping [ErrPublicInputMismatch] if it
+// differs from the claimed one. cfg is only used for the data availability
+// responses, to access the decompression dictionaries.
+func parseResponse(cfg *config.Config, kind responseKind, raw []byte) (*responseClaim, error) {
+
+	switch kind {
+
+	case kindExecution:
+		resp := &execution.Response{}
+		if err := json.Unmarshal(raw, resp); err != nil {
+			return nil, fmt.Errorf("could not parse the execution response: %w", err)
+		}
+
+		if len(resp.BlocksData) == 0 {
+			return nil, errors.New("the execution response has no blocks")
+		}
+
+		claim := &responseClaim{
+			kind:               kind,
+			proof:              resp.Proof,
+			verifyingKeyShaSum: resp.VerifyingKeyShaSum,
+			circuitIDs: []circuits.CircuitID{
+				circuits.ExecutionCircuitID,
+				circuits.ExecutionLargeCircuitID,
+				circuits.ExecutionLimitlessCircuitID,
+				circuits.ExecutionDummyCircuitID,
+			},
+			mockCircuitIDs: []circuits.MockCircuitID{circuits.MockCircuitIDExecution},
+		}
+		return claim, claim.setPublicInput(resp.PublicInput[:], resp.FuncInput().SumAsField())
+
+	case kindInvalidity:
+		resp := &invalidity.Response{}
+		if err := json.Unmarshal(raw, resp); err != nil {
+			return nil, fmt.Errorf("could not parse the invalidity response: %w", err)
+		}
+
+		claim := &responseClaim{
+			kind:               kind,
+			proof:              resp.Proof,
+			verifyingKeyShaSum: resp.VerifyingKeyShaSum,
+			circuitIDs: []circuits.CircuitID{
+				circuits.InvalidityNonceBalanceCircuitID,
+				circuits.InvalidityPrecompileLogsCircuitID,
+				circuits.InvalidityPrecompileLogsLargeCircuitID,
+				circuits.InvalidityPrecompileLogsLimitlessCircuitID,
+				circuits.InvalidityFilteredAddressCircuitID,
+				circuits.InvalidityGasLimitCircuitID,
+				circuits.InvalidityNonceBalanceDummyCircuitID,
+				circuits.InvalidityPrecompileLogsDummyCircuitID,
+				circuits.InvalidityFilteredAddressDummyCircuitID,
+				circuits.InvalidityGasLimitDummyCircuitID,
+			},
+			mockCircuitIDs: []circuits.MockCircuitID{
+				circuits.MockCircuitIDInvalidityNonceBalance,
+				circuits.MockCircuitIDInvalidityPrecompileLogs,
+				circuits.MockCircuitIDInvalidityFilteredAddress,
+				circuits.MockCircuitIDInvalidityGasLimit,
+			},
+		}
+		return claim, claim.setPublicInput(resp.PublicInput[:], resp.FuncInput().SumAsField())
+
+	case kindInvalidityBatch:
+		resp := &invalidity.BatchResponse{}
+		if err := json.Unmarshal(raw, resp); err != nil {
+			return nil, fmt.Errorf("could not parse the invalidity batch response: %w", err)
+		}
+
+		claim := &responseClaim{
+			kind:               kind,
+			proof:              resp.Proof,
+			verifyingKeyShaSum: resp.VerifyingKeyShaSum,
+			circuitIDs: []circuits.CircuitID{
+				circuits.InvalidityNonceBalanceBatchCircuitID,
+				circuits.InvalidityGasLimitBatchCircuitID,
+			},
+			mockCircuitIDs: []circuits.MockCircuitID{circuits.MockCircuitIDInvalidityBatch},
+		}
+		return claim, claim.setPublicInput(resp.PublicInput[:], resp.FuncInput().SumAsField())
+
+	case kindDataAvailability:
+		resp := &dataavailability.Response{}
+		if err := json.Unmarshal(raw, resp); err != nil {
+			return nil, fmt.Errorf("could not parse the data availability response: %w", err)
+		}
+
+		fpi, err := resp.FuncInput(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("could not recompute the functional public input: %w", err)
+		}
+
+		snarkHash, err := utils.HexDecodeString(resp.SnarkHash)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse the snark hash: %w", err)
+		}
+
+		if !bytes.Equal(snarkHash, fpi.SnarkHash) {
+			return nil, fmt.Errorf("%w: snark hash is %v, recomputed %v", ErrPublicInputMismatch, resp.SnarkHash, utils.HexEncodeToString(fpi.SnarkHash))
+		}
+
+		sum, err := fpi.Sum()
+		if err != nil {
+			return nil, fmt.Errorf("could not hash the functional public input: %w", err)
+		}
+
+		var computed, given fr.Element
+		if err := computed.SetBytesCanonical(sum); err != nil {
+			return nil, fmt.Errorf("the recomputed public input is not a field element: %w", err)
+		}
+
+		if _, err := given.SetString(resp.Debug.PublicInput); err != nil {
+			return nil, fmt.Errorf("could not parse the public input %q: %w", resp.Debug.PublicInput, err)
+		}
+
+		claim := &responseClaim{
+			kind:               kind,
+			proof:              resp.DecompressionProof,
+			verifyingKeyShaSum: resp.VerifyingKeyShaSum,
+			circuitIDs: []circuits.CircuitID{
+				circuits.DataAvailabilityV2CircuitID,
+				circuits.DataAvailabilityDummyCircuitID,
+			},
+			mockCircuitIDs: []circuits.MockCircuitID{circuits.MockCircuitIDDecompression},
+		}
+		return claim, claim.checkPublicInput(given, computed)
+
+	default:
+		return nil, fmt.Errorf("unsupported response type: %v", kind)
+	}
+}
+
+// setPublicInput parses the claimed public input and checks it against the
+// one recomputed from the response fields. A claimed value that is not the
+// canonical encoding of a field element is a mismatch.
+func (c *responseClaim) setPublicInput(given []byte, computed fr.Element) error {
+	var g fr.Element
+	if err := g.SetBytesCanonical(given); err != nil {
+		return fmt.Errorf("%w: %v response claims a non-canonical public input 0x%x: %v", ErrPublicInputMismatch, c.kind, given, err)
+	}
+	return c.checkPublicInput(g, computed)
+}
+
+func (c *responseClaim) checkPublicInput(given, computed fr.Element) error {
+	if !given.Equal(&computed) {
+		return fmt.Errorf("%w: %v response claims %v, recomputed %v", ErrPublicInputMismatch, c.kind, given.String(), computed.String())
+	}
+	c.publicInput = computed
+	return nil
+}
+
+// findSetup returns the setup whose verifying key digest is the one referenced
+// by the claim. The setups on disk are looked up through their manifest so
+// that only the matching one is loaded. The dev-mode dummy setups are not
+// stored and are rebuilt from the SRS instead.
+func findSetup(cfg *config.Config, claim *responseClaim) (circuits.Setup, error) {
+
+	if claim.verifyingKeyShaSum == "" {
+		return circuits.Setup{}, errors.New("the response does not reference a verifying key")
+	}
+
+	for _, id := range claim.circuitIDs {
+
+		manifestPath := filepath.Join(cfg.PathForSetup(string(id)), config.ManifestFileName)
+		manifest, err := circuits.ReadSetupManifest(manifestPath)
+		if err != nil {
+			logrus.Debugf("skipping circuit %v: %v", id, err)
+			continue
+		}
+
+		if manifest.Checksums.VerifyingKey != claim.verifyingKeyShaSum {
+			continue
+		}
+
+		logrus.Infof("loading the setup of circuit %v", id)
+
+		setup, err := circuits.LoadSetup(cfg, id)
+		if err != nil {
+			return circuits.Setup{}, fmt.Errorf("could not load the setup of circuit %v: %w", id, err)
+		}
+
+		return setup, nil
+	}
+
+	srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+	if err != nil {
+		return circuits.Setup{}, fmt.Errorf("could not create the SRS store: %w", err)
+	}
+
+	for _, id := range claim.mockCircuitIDs {
+
+		setup, err := dummy.MakeUnsafeSetup(srsProvider, id, ecc.BLS12_377.ScalarField())
+		if err != nil {
+			return circuits.Setup{}, fmt.Errorf("could not make the dummy setup %v: %w", id, err)
+		}
+
+		if setup.VerifyingKeyDigest() == claim.verifyingKeyShaSum {
+			logrus.Infof("using the dummy setup of mock circuit %v", id)
+			return setup, nil
+		}
+	}
+
+	return circuits.Setup{}, fmt.Errorf("no %v setup has the verifying key %v", claim.kind, claim.verifyingKeyShaSum)
+}
+
+// verifyClaim runs the native PLONK verifier on the proof of the claim. The
+// BLS12-377 proofs are meant to be recursively verified in BW6-761 and are
+// verified with the matching options.
+func verifyClaim(setup *circuits.Setup, claim *responseClaim) error {
+
+	if got := setup.VerifyingKeyDigest(); got != claim.verifyingKeyShaSum {
+		return fmt.Errorf("verifying key digest mismatch: response has %v, setup has %v", claim.verifyingKeyShaSum, got)
+	}
+
+	proofBytes, err := utils.HexDecodeString(claim.proof)
+	if err != nil {
+		return fmt.Errorf("%w: could not decode the proof: %v", ErrInvalidProof, err)
+	}
+
+	proof := plonk.NewProof(setup.CurveID())
+	if _, err := proof.ReadFrom(bytes.NewReader(proofBytes)); err != nil {
+		return fmt.Errorf("%w: could not parse the proof: %v", ErrInvalidProof, err)
+	}
+
+	// All the circuits verified here have a single public input.
+	publicWitness, err := witness.New(setup.Circuit.Field())
+	if err != nil {
+		return fmt.Errorf("could not create the public witness: %w", err)
+	}
+
+	values := make(chan any, 1)
+	values <- claim.publicInput
+	close(values)
+
+	if err := publicWitness.Fill(1, 0, values); err != nil {
+		return fmt.Errorf("could not fill the public witness: %w", err)
+	}
+
+	verifierOpts := emPlonk.GetNativeVerifierOptions(ecc.BW6_761.ScalarField(), setup.Circuit.Field())
+	if err := plonk.Verify(proof, setup.VerifyingKey, publicWitness, verifierOpts); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	return nil
+}
+
+// verifyAggregationResponse checks the public input of an aggregation
+// response and verifies its proof against the verifying key of the emulation
+// setup, or of the dummy emulation circuit in dev mode. The proof is encoded
+// for the Solidity verifier and is decoded back with
+// [circuits.DeserializeProofSolidityBn254].
+func verifyAggregationResponse(cfg *config.Config, raw []byte) error {
+
+	resp := &aggregation.Response{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return fmt.Errorf("could not parse the aggregation response: %w", err)
+	}
+
+	computed := resp.FuncInput().GetPublicInputHex()
+	if !strings.EqualFold(computed, resp.AggregatedProofPublicInput) {
+		return fmt.Errorf("%w: aggregation response claims %v, recomputed %v", ErrPublicInputMismatch, resp.AggregatedProofPublicInput, computed)
+	}
+
+	logrus.Infof("public input matches the response fields: %v", computed)
+
+	var publicInput frBn254.Element
+	if _, err := publicInput.SetString(computed); err != nil {
+		return fmt.Errorf("could not parse the public input %v: %w", computed, err)
+	}
+
+	vk, err := emulationVerifyingKey(cfg)
+	if err != nil {
+		return err
+	}
+
+	proof, err := circuits.DeserializeProofSolidityBn254(vk, resp.AggregatedProof, []frBn254.Element{publicInput})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	publicWitness, err := witness.New(ecc.BN254.ScalarField())
+	if err != nil {
+		return fmt.Errorf("could not create the public witness: %w", err)
+	}
+
+	values := make(chan any, 1)
+	values <- publicInput
+	close(values)
+
+	if err := publicWitness.Fill(1, 0, values); err != nil {
+		return fmt.Errorf("could not fill the public witness: %w", err)
+	}
+
+	if err := plonk.Verify(proof, vk, publicWitness); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	return nil
+}
+
+// emulationVerifyingKey returns the verifying key the aggregation proofs are
+// produced for. As for the prover, the dev mode uses the dummy emulation
+// circuit rebuilt from the SRS. Otherwise, only the verifying key of the
+// emulation setup is read from disk.
+func emulationVerifyingKey(cfg *config.Config) (*plonk_bn254.VerifyingKey, error) {
+
+	if cfg.Aggregation.ProverMode == config.ProverModeDev {
+		srsProvider, err := circuits.NewSRSStore(cfg.PathForSRS())
+		if err != nil {
+			return nil, fmt.Errorf("could not create the SRS store: %w", err)
+		}
+
+		setup, err := dummy.MakeUnsafeSetup(srsProvider, circuits.MockCircuitIDEmulation, ecc.BN254.ScalarField())
+		if err != nil {
+			return nil, fmt.Errorf("could not make the dummy emulation setup: %w", err)
+		}
+
+		logrus.Infof("using the dummy setup of mock circuit %v", circuits.MockCircuitIDEmulation)
+		return setup.VerifyingKey.(*plonk_bn254.VerifyingKey), nil
+	}
+
+	vkPath := filepath.Join(cfg.PathForSetup(string(circuits.EmulationCircuitID)), config.VerifyingKeyFileName)
+	vk := &plonk_bn254.VerifyingKey{}
+	if err := circuits.ReadVerifyingKey(vkPath, vk); err != nil {
+		return nil, fmt.Errorf("could not read the verifying key of circuit %v: %w", circuits.EmulationCircuitID, err)
+	}
+
+	logrus.Infof("loaded the verifying key of circuit %v", circuits.EmulationCircuitID)
+	return vk, nil
+}
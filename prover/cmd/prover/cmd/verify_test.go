@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/linea-monorepo/prover/backend/execution"
+	"github.com/consensys/linea-monorepo/prover/utils/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectResponseKind(t *testing.T) {
+	cases := map[string]responseKind{
+		`{"aggregatedProof": "0x", "finalShnarf": "0x"}`:                   kindAggregation,
+		`{"decompressionProof": "0x", "compressedData": ""}`:               kindDataAvailability,
+		`{"ftxs": [{"invalidityType": 0}], "proof": "0x"}`:                 kindInvalidityBatch,
+		`{"invalidityType": 0, "proof": "0x"}`:                             kindInvalidity,
+		`{"blocksData": [], "proof": "0x", "verifyingKeyShaSum": "0xabc"}`: kindExecution,
+	}
+
+	for raw, want := range cases {
+		got, err := detectResponseKind([]byte(raw))
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	_, err := detectResponseKind([]byte(`{"proof": "0x"}`))
+	require.Error(t, err)
+}
+
+func TestParseExecutionResponse(t *testing.T) {
+
+	resp := &execution.Response{
+		FirstBlockNumber: 10,
+		ChainID:          59144,
+		BaseFee:          7,
+		BlocksData: []execution.BlockData{
+			{TimeStamp: 1000},
+			{TimeStamp: 1012},
+		},
+		AllL2L1MessageHashes: []types.FullBytes32{{1, 2, 3}},
+	}
+	resp.PublicInput = types.Bls12377Fr(resp.FuncInput().Sum())
+
+	raw, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	kind, err := detectResponseKind(raw)
+	require.NoError(t, err)
+	require.Equal(t, kindExecution, kind)
+
+	claim, err := parseResponse(nil, kind, raw)
+	require.NoError(t, err)
+	assert.Equal(t, resp.FuncInput().SumAsField(), claim.publicInput)
+
+	// Tampering with a field the public input commits to must be detected.
+	resp.BlocksData[1].TimeStamp++
+	raw, err = json.Marshal(resp)
+	require.NoError(t, err)
+
+	_, err = parseResponse(nil, kind, raw)
+	require.ErrorIs(t, err, ErrPublicInputMismatch)
+	assert.Equal(t, ExitCodePublicInputMismatch, ExitCode(err))
+}
+
+func TestSetPublicInputNonCanonical(t *testing.T) {
+
+	var computed fr.Element
+	computed.SetUint64(42)
+
+	claim := &responseClaim{kind: kindExecution}
+	b := computed.Bytes()
+	require.NoError(t, claim.setPublicInput(b[:], computed))
+
+	// The same value shifted by the modulus reduces to the recomputed one but
+	// is not its canonical encoding.
+	var shifted big.Int
+	shifted.Add(computed.BigInt(new(big.Int)), fr.Modulus())
+	err := claim.setPublicInput(shifted.FillBytes(make([]byte, fr.Bytes)), computed)
+	require.ErrorIs(t, err, ErrPublicInputMismatch)
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 1, ExitCode(fmt.Errorf("some failure")))
+	assert.Equal(t, ExitCodePublicInputMismatch, ExitCode(fmt.Errorf("verify: %w", ErrPublicInputMismatch)))
+	assert.Equal(t, ExitCodeInvalidProof, ExitCode(fmt.Errorf("verify: %w", ErrInvalidProof)))
+
+	// Go exits with code 2 on a panic, the verify codes must not collide.
+	for _, code := range []int{ExitCodePublicInputMismatch, ExitCodeInvalidProof} {
+		assert.NotContains(t, []int{0, 1, 2}, code)
+	}
+}
@@ -44,12 +44,49 @@ var (
 	}
 
 	logStatsArgs cmd.LogStatsArgs
+
+	// verifyCmd represents the verify command
+	verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "verify a response, recomputes its public input from its fields and checks its proof",
+		RunE:  cmdVerify,
+	}
+
+	verifyArgs cmd.VerifyArgs
+
+	// suiteReportCmd represents the suite-report command
+	suiteReportCmd = &cobra.Command{
+		Use:   "suite-report",
+		Short: "compile the zkEVM and write a CSV table of the proof size and verifier cost after each compilation step",
+		RunE:  cmdSuiteReport,
+	}
+
+	suiteReportArgs cmd.SuiteReportArgs
+
+	// proveFromWitnessCmd represents the prove-from-witness command
+	proveFromWitnessCmd = &cobra.Command{
+		Use:   "prove-from-witness",
+		Short: "complete an execution proof from a witness exported in witness-export mode",
+		RunE:  cmdProveFromWitness,
+	}
+
+	proveFromWitnessArgs cmd.ProveFromWitnessArgs
+
+	// replayCmd represents the replay command
+	replayCmd = &cobra.Command{
+		Use:   "replay <bundle.tgz>",
+		Short: "re-run a failed job from a replay bundle written by the controller",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdReplay,
+	}
+
+	replayArgs cmd.ReplayArgs
 )
 
 func main() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
 
@@ -78,6 +115,23 @@ func init() {
 	rootCmd.AddCommand(logStatsCmd)
 	logStatsCmd.Flags().StringVar(&logStatsArgs.Input, "in", "", "input file")
 	logStatsCmd.Flags().StringVar(&logStatsArgs.StatsFile, "stats-file", "", "stats file where to log the result")
+
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVar(&verifyArgs.Input, "in", "", "response file")
+
+	rootCmd.AddCommand(suiteReportCmd)
+	suiteReportCmd.Flags().StringVar(&suiteReportArgs.Suite, "suite", cmd.SuiteFull, "compilation suite to report on: full or large")
+	suiteReportCmd.Flags().StringVar(&suiteReportArgs.Output, "out", "", "output CSV file (stdout if empty)")
+	suiteReportCmd.Flags().BoolVar(&suiteReportArgs.Gnark, "gnark", false, "compile the gnark recursion circuit after each vortex step to count its constraints and hash calls")
+
+	rootCmd.AddCommand(proveFromWitnessCmd)
+	proveFromWitnessCmd.Flags().StringVar(&proveFromWitnessArgs.Input, "in", "", "witness directory")
+	proveFromWitnessCmd.Flags().StringVar(&proveFromWitnessArgs.Output, "out", "", "output file")
+
+	rootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().StringVar(&replayArgs.WorkDir, "work-dir", "", "directory where the bundle is extracted (a temporary directory if empty)")
+	replayCmd.Flags().StringVar(&replayArgs.Output, "out", "", "output file (in the work directory if empty)")
+	replayCmd.Flags().StringVar(&replayArgs.AssetsDir, "assets-dir", "", "path to the directory where the assets are stored (override the config of the bundle)")
 }
 
 func cmdSetup(_cmd *cobra.Command, _ []string) error {
@@ -95,6 +149,26 @@ func cmdLogStats(_cmd *cobra.Command, _ []string) error {
 	return cmd.LogStats(_cmd.Context(), logStatsArgs)
 }
 
+func cmdVerify(*cobra.Command, []string) error {
+	verifyArgs.ConfigFile = fConfigFile
+	return cmd.Verify(verifyArgs)
+}
+
+func cmdSuiteReport(*cobra.Command, []string) error {
+	suiteReportArgs.ConfigFile = fConfigFile
+	return cmd.SuiteReport(suiteReportArgs)
+}
+
+func cmdProveFromWitness(*cobra.Command, []string) error {
+	proveFromWitnessArgs.ConfigFile = fConfigFile
+	return cmd.ProveFromWitness(proveFromWitnessArgs)
+}
+
+func cmdReplay(_ *cobra.Command, args []string) error {
+	replayArgs.Bundle = args[0]
+	return cmd.Replay(replayArgs)
+}
+
 // allCircuitList returns the list [cmd.AllCircuits] where the circuit id
 // are converted into strings.
 func allCircuitList() []string {
@@ -213,6 +213,17 @@ type Controller struct {
 	// Prometheus stores the configuration for the Prometheus metrics server.
 	Prometheus Prometheus
 
+	// EnableAdminAPI serves the admin API of the controller under /admin/ on
+	// the metrics server. The server is started even if Prometheus is not
+	// enabled. Defaults to false.
+	EnableAdminAPI bool `mapstructure:"enable_admin_api"`
+
+	// AdminAPIToken is the bearer token required by the POST routes of the
+	// admin API, which are disabled if it is empty. It is not read from the
+	// toml configuration file but from the PROVER_ADMIN_API_TOKEN environment
+	// variable.
+	AdminAPIToken string `mapstructure:"-"`
+
 	// The delays at which we retry when we find no files in the queue. If this
 	// is set to [0, 1, 2, 3, 4, 5]. It will retry after 0 sec the first time it
 	// cannot find a file in the queue, 1 sec the second time and so on. Once it
@@ -225,6 +236,39 @@ type Controller struct {
 	// List of exit codes for which the job will retry in large mode
 	RetryLocallyWithLargeCodes []int `mapstructure:"retry_locally_with_large_codes"`
 
+	// ReplayBundleDir is the directory where the controller writes a replay
+	// bundle for the jobs failing with one of the ReplayBundleCodes. No bundle
+	// is written if empty.
+	ReplayBundleDir string `mapstructure:"replay_bundle_dir"`
+
+	// List of exit codes for which the controller writes a replay bundle
+	ReplayBundleCodes []int `mapstructure:"replay_bundle_codes"`
+
+	// ReplayBundleTimeout is the number of seconds after which the controller
+	// gives up writing a replay bundle. The bundles are written in the
+	// background, one at a time. No timeout is applied if zero. Defaults to
+	// 600.
+	ReplayBundleTimeout int `mapstructure:"replay_bundle_timeout_seconds"`
+
+	// ReplayBundleMaxTraceSizeMB is the size, in MiB, above which the
+	// conflated trace file is left out of the replay bundle. No limit is
+	// applied if zero. Defaults to 4096.
+	ReplayBundleMaxTraceSizeMB int64 `mapstructure:"replay_bundle_max_trace_size_mb"`
+
+	// JobTimeouts is the maximal number of seconds a run of the prover may
+	// take, by job type (execution, compression, aggregation, invalidity or
+	// invalidity-batch). The local retry in large mode gets a new budget. The
+	// watchdog kills the runs exceeding it. No timeout is applied if the job
+	// type is not listed.
+	JobTimeouts map[string]int `mapstructure:"job_timeout_seconds"`
+
+	// HeartbeatTimeout is the number of seconds after which the watchdog kills
+	// a prover that stopped writing heartbeats. The prover writes one at each
+	// wizard round and limitless phase, so the value must exceed the longest
+	// step without any, such as the outer gnark proof. The check starts after
+	// the first heartbeat. Disabled if zero.
+	HeartbeatTimeout int `mapstructure:"heartbeat_timeout_seconds"`
+
 	// The number of seconds infra (AWS) waits before reclaiming a spot instance
 	SpotInstanceReclaimTime int `mapstructure:"spot_instance_reclaim_time_seconds"`
 
@@ -259,7 +303,7 @@ type Execution struct {
 	WithRequestDir `mapstructure:",squash"`
 
 	// ProverMode stores the kind of prover to use.
-	ProverMode ProverMode `mapstructure:"prover_mode" validate:"required,oneof=dev partial full proofless bench check-only limitless"`
+	ProverMode ProverMode `mapstructure:"prover_mode" validate:"required,oneof=dev partial full proofless bench check-only limitless witness-export"`
 
 	// CanRunFullLarge indicates whether the prover is running on a large machine (and can run full large traces).
 	CanRunFullLarge bool `mapstructure:"can_run_full_large"`
@@ -290,6 +334,12 @@ type Execution struct {
 	// serialized file on disk via memory-mapping, instead of compiling it
 	// at proving time. The file is produced during setup by protocol/serde.
 	Serialization bool `mapstructure:"serialization"`
+
+	// MinSecurityBits is an optional lower bound on the conjectured bits of
+	// security of every Vortex layer of the execution circuits. When set, the
+	// setup fails if a layer falls under it. The field defaults to 0, which
+	// disables the check.
+	MinSecurityBits int `mapstructure:"min_security_bits" validate:"gte=0"`
 }
 
 type DataAvailability struct {
@@ -324,6 +374,15 @@ type Invalidity struct {
 	// in bytes (this is the payload size without signature)
 	MaxRlpByteSize int `mapstructure:"max_rlp_byte_size" validate:"gte=0"`
 
+	// BlockGasLimit is the gas limit of an L2 block. A forced transaction with
+	// a larger gas limit can never be included and is proven invalid. It is a
+	// constant of the gas limit invalidity circuit.
+	BlockGasLimit uint64 `mapstructure:"block_gas_limit" validate:"gt=0"`
+
+	// MaxBatchSize is the maximum number of forced transactions proven by a
+	// single batched invalidity proof. It is a constant of the batch circuits.
+	MaxBatchSize int `mapstructure:"max_batch_size" validate:"gt=0"`
+
 	// LimitlessWithDebug is only looked at when the limitless invalidity prover is
 	// activated. When set to true, the limitless invalidity prover will only run in
 	// debug mode and not produce any proof. This is useful to investigate
@@ -375,7 +434,8 @@ type PublicInput struct {
 	MaxNbDataAvailability int `mapstructure:"max_nb_data_availability" validate:"gte=0"`
 	MaxNbExecution        int `mapstructure:"max_nb_execution" validate:"gte=0"`
 	MaxNbInvalidity       int `mapstructure:"max_nb_invalidity" validate:"gte=0"`
-	MaxNbCircuits         int `mapstructure:"max_nb_circuits" validate:"gte=0"` // if not set, will be set to MaxNbDA + MaxNbExecution +maxNbInvalidity
+	MaxNbInvalidityBatch  int `mapstructure:"max_nb_invalidity_batch" validate:"gte=0"`
+	MaxNbCircuits         int `mapstructure:"max_nb_circuits" validate:"gte=0"` // if not set, will be set to MaxNbDA + MaxNbExecution + MaxNbInvalidity + MaxNbInvalidityBatch
 	ExecutionMaxNbMsg     int `mapstructure:"execution_max_nb_msg" validate:"gte=0"`
 	L2MsgMerkleDepth      int `mapstructure:"l2_msg_merkle_depth" validate:"gte=0"`
 	L2MsgMaxNbMerkle      int `mapstructure:"l2_msg_max_nb_merkle" validate:"gte=0"` // if not explicitly provided (i.e. non-positive) it will be set to maximum
@@ -10,8 +10,12 @@ import (
 var (
 	DefaultDeferToOtherLargeCodes     = []int{137}        // List of exit codes for which the job will put back the job to be reexecuted in large mode.
 	DefaultRetryLocallyWithLargeCodes = []int{77, 333, 2} // List of exit codes for which the job will retry in large mode
+	DefaultReplayBundleCodes          = []int{78, 2}      // List of exit codes for which a replay bundle is written: unsatisfied constraints and panics.
 )
 
+// DefaultBlockGasLimit is the gas limit of a Linea block.
+const DefaultBlockGasLimit uint64 = 2_000_000_000
+
 func setDefaultValues() {
 
 	setDefaultPaths()
@@ -31,6 +35,9 @@ func setDefaultValues() {
 	viper.SetDefault("controller.retry_delays", []int{0, 1, 2, 3, 5, 8, 13, 21, 44, 85})
 	viper.SetDefault("controller.defer_to_other_large_codes", DefaultDeferToOtherLargeCodes)
 	viper.SetDefault("controller.retry_locally_with_large_codes", DefaultRetryLocallyWithLargeCodes)
+	viper.SetDefault("controller.replay_bundle_codes", DefaultReplayBundleCodes)
+	viper.SetDefault("controller.replay_bundle_timeout_seconds", 600)
+	viper.SetDefault("controller.replay_bundle_max_trace_size_mb", 4096)
 
 	viper.SetDefault("controller.spot_instance_reclaim_time_seconds", 120)
 	viper.SetDefault("controller.termination_grace_period_seconds", 2700)
@@ -46,6 +53,9 @@ func setDefaultValues() {
 	viper.SetDefault("data_availability.max_nb_batches", 100)
 	viper.SetDefault("data_availability.max_uncompressed_nb_bytes", v1.MaxUncompressedBytes)
 	viper.SetDefault("data_availability.dict_nb_bytes", 65536)
+
+	viper.SetDefault("invalidity.block_gas_limit", DefaultBlockGasLimit)
+	viper.SetDefault("invalidity.max_batch_size", 8)
 }
 
 func setDefaultPaths() {
@@ -26,4 +26,9 @@ const (
 
 	// LargeSuffix is the extension to add in order to defer the job to the large prover.
 	LargeSuffix = "large"
+
+	// HeartbeatFileEnv is the environment variable through which the
+	// controller passes to the prover the path of the file where it writes its
+	// heartbeats.
+	HeartbeatFileEnv = "PROVER_HEARTBEAT_FILE"
 )
@@ -29,4 +29,8 @@ const (
 	// ProverModeCheckOnly is used to test the constraints of the whole system
 	ProverModeCheckOnly  ProverMode = "check-only"
 	ProverModeEncodeOnly ProverMode = "encode-only"
+	// ProverModeWitnessExport is used to only generate the witness of the
+	// inner-proof and store it on disk. The proof is then completed by the
+	// prove-from-witness command, possibly on another machine.
+	ProverModeWitnessExport ProverMode = "witness-export"
 )
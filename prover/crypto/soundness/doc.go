@@ -0,0 +1,13 @@
+// Package soundness estimates the bits of security of the proof systems used
+// by the prover from their actual parameters: the number of queries and rate
+// of the Reed-Solomon codes of Vortex and FRI, the proximity gap of their
+// batching step, the size of the field the Fiat-Shamir challenges are sampled
+// from, the grinding and the hardness of the SIS instances.
+//
+// The SIS estimates port the models of the sis_estimation notebook: direct
+// SVP in the L2 and L-infinity norms, BKZ and the combinatorial attack of
+// Camion-Patarin and Wagner (CPW). The Reed-Solomon estimates are conjectured:
+// they assume list decoding up to the Johnson bound for Vortex, up to
+// capacity for FRI, and proximity gaps with an error of (m-1)n/|F| when
+// batching m codewords of length n.
+package soundness
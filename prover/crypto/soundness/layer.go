@@ -0,0 +1,69 @@
+package soundness
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Layer holds the bits of security of each part of one step of a proof
+// system, e.g. one Vortex compilation of a wizard suite. A part that does not
+// apply to the step is +Inf.
+type Layer struct {
+	// Name identifies the layer in the reports and errors.
+	Name string
+	// QueryBits is the soundness of the query phase, including the grinding.
+	QueryBits float64
+	// ProximityGapBits is the soundness of the batching of the codewords.
+	ProximityGapBits float64
+	// EvaluationBits is the soundness of the evaluation check at a random
+	// point.
+	EvaluationBits float64
+	// SISBits is the hardness of the SIS instance used to hash the columns.
+	SISBits float64
+	// FieldBits is the log of the size of the field the Fiat-Shamir
+	// challenges are sampled from. No part can be sounder than that.
+	FieldBits float64
+}
+
+// Bits returns the bits of security of the layer: the one of its weakest
+// part.
+func (l Layer) Bits() float64 {
+	return min(l.QueryBits, l.ProximityGapBits, l.EvaluationBits, l.SISBits, l.FieldBits)
+}
+
+// String returns a one-line description of the layer, listing the bits of
+// each of its parts.
+func (l Layer) String() string {
+	return fmt.Sprintf(
+		"%v: %.1f bits (query=%v proximity-gap=%v evaluation=%v sis=%v field=%v)",
+		l.Name, l.Bits(), fmtBits(l.QueryBits), fmtBits(l.ProximityGapBits),
+		fmtBits(l.EvaluationBits), fmtBits(l.SISBits), fmtBits(l.FieldBits),
+	)
+}
+
+func fmtBits(b float64) string {
+	if math.IsInf(b, 1) {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.1f", b)
+}
+
+// Check returns an error listing the layers whose security is less than
+// minBits, or nil if there are none.
+func Check(layers []Layer, minBits float64) error {
+
+	var failing []string
+	for _, l := range layers {
+		if l.Bits() < minBits {
+			failing = append(failing, l.String())
+		}
+	}
+
+	if len(failing) > 0 {
+		return fmt.Errorf("%d layer(s) are below the %v bits of security target:\n\t%v",
+			len(failing), minBits, strings.Join(failing, "\n\t"))
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package soundness
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// koalabearModulus is the modulus of the base field of the prover
+const koalabearModulus = 1<<31 - 1<<24 + 1
+
+func TestJohnsonQueryBits(t *testing.T) {
+	// The (blow-up, opened columns) pairs of the Vortex steps of the zkEVM
+	// compilation suites are chosen to reach 128 bits in that regime.
+	assert.Equal(t, 128.0, JohnsonQueryBits(2, 256))
+	assert.Equal(t, 64.0*2, JohnsonQueryBits(16, 64))
+	assert.Equal(t, 129.0, JohnsonQueryBits(8, 86))
+	assert.Equal(t, 256.0, CapacityQueryBits(2, 256))
+}
+
+func TestProximityGapBits(t *testing.T) {
+	fieldBits := ExtensionFieldBits(koalabearModulus, 4)
+	assert.InDelta(t, 123.9, fieldBits, 0.1)
+	assert.InDelta(t, fieldBits-10-20, ProximityGapBits(1<<10+1, 1<<20, fieldBits), 1e-9)
+	assert.True(t, math.IsInf(ProximityGapBits(1, 1<<20, fieldBits), 1))
+}
+
+func TestFRI(t *testing.T) {
+	l := FRI("fri", FRIParams{LogDomainSize: 21, LogDegree: 20, NumQueries: 100, Grinding: 16}, 124)
+	assert.Equal(t, 116.0, l.QueryBits)
+	assert.InDelta(t, 124-21-math.Log2(20), l.ProximityGapBits, 1e-9)
+	assert.Equal(t, l.ProximityGapBits, l.Bits())
+}
+
+func TestCheck(t *testing.T) {
+
+	layers := []Layer{
+		{Name: "strong", QueryBits: 130, ProximityGapBits: 110, EvaluationBits: 100, SISBits: math.Inf(1), FieldBits: 124},
+		{Name: "weak-sis", QueryBits: 130, ProximityGapBits: 110, EvaluationBits: 100, SISBits: 35, FieldBits: 124},
+	}
+
+	assert.Equal(t, 100.0, layers[0].Bits())
+	require.NoError(t, Check(layers, 35))
+
+	err := Check(layers, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "weak-sis: 35.0 bits")
+	assert.Contains(t, err.Error(), "sis=35.0")
+	assert.NotContains(t, err.Error(), "strong")
+}
@@ -0,0 +1,152 @@
+package soundness
+
+import (
+	"fmt"
+	"math"
+)
+
+// minimizeBounded returns the minimum of f over [a, b] using Brent's method.
+// It follows scipy's minimize_scalar(method="bounded") with its default
+// tolerance, so that the estimates match the ones of the sis_estimation
+// notebook.
+func minimizeBounded(f func(float64) float64, a, b float64) float64 {
+
+	const (
+		xatol   = 1e-5
+		maxIter = 500
+	)
+
+	var (
+		sqrtEps    = math.Sqrt(2.2e-16)
+		goldenMean = 0.5 * (3.0 - math.Sqrt(5.0))
+		fulc       = a + goldenMean*(b-a)
+		nfc, xf    = fulc, fulc
+		rat, e     = 0.0, 0.0
+		x          = xf
+		fx         = f(x)
+		ffulc      = fx
+		fnfc       = fx
+		xm         = 0.5 * (a + b)
+		tol1       = sqrtEps*math.Abs(xf) + xatol/3.0
+		tol2       = 2.0 * tol1
+	)
+
+	for num := 1; math.Abs(xf-xm) > tol2-0.5*(b-a) && num < maxIter; num++ {
+
+		golden := true
+
+		// Attempts a parabolic fit
+		if math.Abs(e) > tol1 {
+			golden = false
+			r := (xf - nfc) * (fx - ffulc)
+			q := (xf - fulc) * (fx - fnfc)
+			p := (xf-fulc)*q - (xf-nfc)*r
+			q = 2.0 * (q - r)
+			if q > 0.0 {
+				p = -p
+			}
+			q = math.Abs(q)
+			r = e
+			e = rat
+
+			if math.Abs(p) < math.Abs(0.5*q*r) && p > q*(a-xf) && p < q*(b-xf) {
+				rat = p / q
+				x = xf + rat
+				if x-a < tol2 || b-x < tol2 {
+					rat = tol1 * signOrOne(xm-xf)
+				}
+			} else {
+				golden = true
+			}
+		}
+
+		if golden {
+			if xf >= xm {
+				e = a - xf
+			} else {
+				e = b - xf
+			}
+			rat = goldenMean * e
+		}
+
+		x = xf + signOrOne(rat)*math.Max(math.Abs(rat), tol1)
+		fu := f(x)
+
+		if fu <= fx {
+			if x >= xf {
+				a = xf
+			} else {
+				b = xf
+			}
+			fulc, ffulc = nfc, fnfc
+			nfc, fnfc = xf, fx
+			xf, fx = x, fu
+		} else {
+			if x < xf {
+				a = x
+			} else {
+				b = x
+			}
+			switch {
+			case fu <= fnfc || nfc == xf:
+				fulc, ffulc = nfc, fnfc
+				nfc, fnfc = x, fu
+			case fu <= ffulc || fulc == xf || fulc == nfc:
+				fulc, ffulc = x, fu
+			}
+		}
+
+		xm = 0.5 * (a + b)
+		tol1 = sqrtEps*math.Abs(xf) + xatol/3.0
+		tol2 = 2.0 * tol1
+	}
+
+	return fx
+}
+
+// signOrOne returns the sign of x, or 1 if x is zero.
+func signOrOne(x float64) float64 {
+	if x < 0 {
+		return -1
+	}
+	return 1
+}
+
+// bisect returns a zero of f in [a, b] where f(a) and f(b) have opposite
+// signs, as scipy's bisect does.
+func bisect(f func(float64) float64, a, b float64) (float64, error) {
+
+	const (
+		xtol    = 2e-12
+		rtol    = 8.881784197001252e-16
+		maxIter = 100
+	)
+
+	fa, fb := f(a), f(b)
+	if fa*fb > 0 {
+		return 0, fmt.Errorf("f(a) and f(b) must have different signs, got f(%v)=%v and f(%v)=%v", a, fa, b, fb)
+	}
+
+	if fa == 0 {
+		return a, nil
+	}
+
+	if fb == 0 {
+		return b, nil
+	}
+
+	dm := b - a
+	for i := 0; i < maxIter; i++ {
+		dm *= 0.5
+		xm := a + dm
+		fm := f(xm)
+		if fm*fa >= 0 {
+			a = xm
+		}
+		if fm == 0 || math.Abs(dm) < xtol+rtol*math.Abs(xm) {
+			return xm, nil
+		}
+	}
+
+	return a + dm, nil
+}
@@ -0,0 +1,86 @@
+package soundness
+
+import (
+	"math"
+)
+
+// ExtensionFieldBits returns the log of the size of the degree-th extension
+// of the prime field of the given modulus.
+func ExtensionFieldBits(modulus uint64, degree int) float64 {
+	return float64(degree) * math.Log2(float64(modulus))
+}
+
+// JohnsonQueryBits returns the soundness, in bits, of numQueries queries to
+// a Reed-Solomon codeword of rate 1/blowUp when the verifier only relies on
+// list decoding up to the Johnson bound (the Guruswami-Sudan radius): each
+// query passes with probability sqrt(1/blowUp) for a word that is far from the
+// code.
+func JohnsonQueryBits(blowUp, numQueries int) float64 {
+	return float64(numQueries) * math.Log2(float64(blowUp)) / 2
+}
+
+// CapacityQueryBits returns the soundness, in bits, of numQueries queries to a
+// Reed-Solomon codeword of rate 1/blowUp under the conjecture that the code
+// is list decodable up to capacity: each query passes with probability
+// 1/blowUp for a word that is far from the code.
+func CapacityQueryBits(blowUp, numQueries int) float64 {
+	return float64(numQueries) * math.Log2(float64(blowUp))
+}
+
+// ProximityGapBits returns the soundness, in bits, of batching numCodewords
+// Reed-Solomon codewords of length codewordSize with the powers of a random
+// challenge sampled from a field of fieldBits bits. It uses the conjectured
+// error of (numCodewords-1)*codewordSize/|F|, which is proven in the unique
+// decoding regime. Batching a single codeword is free and returns +Inf.
+func ProximityGapBits(numCodewords, codewordSize int, fieldBits float64) float64 {
+	if numCodewords <= 1 {
+		return math.Inf(1)
+	}
+	return fieldBits - math.Log2(float64(numCodewords-1)) - math.Log2(float64(codewordSize))
+}
+
+// EvaluationBits returns the soundness, in bits, of checking an identity
+// between polynomials of degree less than degree at a random point sampled
+// from a field of fieldBits bits (Schwartz-Zippel).
+func EvaluationBits(degree int, fieldBits float64) float64 {
+	return fieldBits - math.Log2(float64(degree))
+}
+
+// FRIParams are the parameters of a FRI instance. They mirror the arguments
+// of the FRI implementation of prover-ray.
+type FRIParams struct {
+	// LogDomainSize is the log of the size of the evaluation domain.
+	LogDomainSize int
+	// LogDegree is the log of the degree bound of the polynomial.
+	LogDegree int
+	// NumQueries is the number of queries of the query phase.
+	NumQueries int
+	// Grinding is the number of bits of proof of work required from the
+	// prover before sampling the queries.
+	Grinding int
+}
+
+// FRI returns the soundness of a FRI instance whose folding challenges are
+// sampled from a field of fieldBits bits. As in prover-ray, the query phase
+// is analysed up to capacity: it yields log(blowUp) bits per query plus the
+// grinding bits. Each of the LogDegree folding rounds batches two codewords.
+func FRI(name string, p FRIParams, fieldBits float64) Layer {
+
+	var (
+		blowUp    = 1 << (p.LogDomainSize - p.LogDegree)
+		numRounds = max(p.LogDegree, 1)
+		// The error of a folding round is the one of batching two codewords
+		// of the size of the first layer, the largest one, and accumulates
+		// over the rounds.
+		foldingBits = ProximityGapBits(2, 1<<p.LogDomainSize, fieldBits) - math.Log2(float64(numRounds))
+	)
+
+	return Layer{
+		Name:             name,
+		QueryBits:        CapacityQueryBits(blowUp, p.NumQueries) + float64(p.Grinding),
+		ProximityGapBits: foldingBits,
+		EvaluationBits:   math.Inf(1),
+		SISBits:          math.Inf(1),
+		FieldBits:        fieldBits,
+	}
+}
@@ -0,0 +1,47 @@
+package soundness
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateSIS(t *testing.T) {
+
+	// The SVP and BKZ figures are the ones of the "current parameters" table of
+	// sis_estimation/Readme.md.
+	testCases := []struct {
+		logQ, logBound, n int
+		svpL2, svpLinf    float64
+		bkz               float64
+	}{
+		{64, 2, 32, 315.41, 634.88, 371.53},
+		{64, 6, 128, 415.27, 872.96, 415.0},
+		{64, 16, 512, 614.42, 1269.76, 344.85},
+		{64, 22, 1024, 886.27, 1904.64, 401.92},
+		{254, 1, 3, 238.9, 472.44, 281.37},
+		{254, 16, 128, 609.74, 1269.76, 341.83},
+	}
+
+	for _, tc := range testCases {
+		e := EstimateSIS(float64(tc.logQ), tc.logBound, tc.n)
+		assert.InDeltaf(t, tc.svpL2, e.SVPL2, 0.01, "svp-l2 for %+v", tc)
+		assert.InDeltaf(t, tc.svpLinf, e.SVPLinf, 0.01, "svp-linf for %+v", tc)
+		assert.InDeltaf(t, tc.bkz, e.BKZ, 0.01, "bkz for %+v", tc)
+	}
+}
+
+func TestCPWAttack(t *testing.T) {
+	// With 2^30 inputs of 2 bits and 32 outputs of 64 bits, the density of the
+	// solutions is 2^20, the best depth is k=24 and the number of inputs can
+	// be reduced to 1024*2^24/25.
+	cost := cpwAttackPessimistic(64, 2, 32, 1<<30)
+	assert.InDelta(t, 24+2*1024.0/25, cost, 1e-6)
+}
+
+func TestEstimateSIS_StdParams(t *testing.T) {
+	// The standard ring-SIS parameters over Koalabear
+	e := EstimateSIS(31, 16, 512)
+	assert.Greater(t, e.Bits(), 128.0)
+	assert.Equal(t, e.BKZ, e.Bits())
+}
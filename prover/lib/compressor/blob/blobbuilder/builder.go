@@ -0,0 +1,255 @@
+// Package blobbuilder exposes the blob filling logic of the sequencer (see
+// libcompressor) as a pure-Go API. A [Builder] wraps a [v1.BlobMaker] and
+// makes it safe to share between goroutines, keeps track of the block numbers
+// of each batch so that the conflation order of a blob can be recovered, and
+// reports errors with their context instead of storing them in a handle table.
+package blobbuilder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	v1 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v1"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DefaultVersion is the blob version used by [New]. It matches the version
+// used by libcompressor.
+const DefaultVersion uint16 = 2
+
+// ErrBlockTooLarge is returned by [Builder.Write] and [Builder.CanWrite] when
+// a block does not fit in the blob even though the blob is empty. Retrying
+// with a fresh blob would not help.
+var ErrBlockTooLarge = errors.New("block does not fit in an empty blob")
+
+// Builder fills a blob with RLP-encoded blocks. It is the Go counterpart of a
+// libcompressor handle. Several builders can be used concurrently and each
+// builder can be shared between goroutines; calls on a given builder are
+// serialized.
+type Builder struct {
+	mu sync.Mutex
+	bm *v1.BlobMaker
+	// batches lists the block numbers written in each batch of the current
+	// blob. The last entry is the batch being built; it may be empty.
+	batches [][]uint64
+}
+
+// Blob is a snapshot of a sealed blob along with the block numbers of each of
+// its batches.
+type Blob struct {
+	// Data is the blob payload as returned by [v1.BlobMaker.Bytes].
+	Data []byte
+	// Batches lists the block numbers of each batch, in order. Empty batches
+	// are omitted.
+	Batches [][]uint64
+}
+
+// New returns a [Builder] for blobs of version [DefaultVersion]. dataLimit is
+// the maximum size of the blob in bytes and dictPath points to the
+// compression dictionary.
+func New(dataLimit int, dictPath string) (*Builder, error) {
+	return NewVersioned(DefaultVersion, dataLimit, dictPath)
+}
+
+// NewVersioned returns a [Builder] for blobs of the given version.
+func NewVersioned(version uint16, dataLimit int, dictPath string) (*Builder, error) {
+	bm, err := v1.NewVersionedBlobMaker(version, dataLimit, dictPath)
+	if err != nil {
+		return nil, fmt.Errorf("blobbuilder: creating the blob maker: %w", err)
+	}
+	return &Builder{bm: bm, batches: [][]uint64{nil}}, nil
+}
+
+// Write attempts to append the RLP-encoded block to the current batch. It
+// returns false and no error if the blob is full; in that case the caller
+// is expected to seal the blob and retry with an empty one. The builder does
+// not keep a reference to rlpBlock.
+func (b *Builder) Write(ctx context.Context, rlpBlock []byte) (bool, error) {
+	return b.write(ctx, rlpBlock, false)
+}
+
+// CanWrite behaves as [Builder.Write] but leaves the blob unchanged. It
+// returns true if the block would have been appended.
+func (b *Builder) CanWrite(ctx context.Context, rlpBlock []byte) (bool, error) {
+	return b.write(ctx, rlpBlock, true)
+}
+
+func (b *Builder) write(ctx context.Context, rlpBlock []byte, dryRun bool) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	number, err := BlockNumber(rlpBlock)
+	if err != nil {
+		return false, fmt.Errorf("blobbuilder: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// The lock may have been held for a while by a concurrent call
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	ok, err := b.bm.Write(rlpBlock, dryRun)
+	if err != nil {
+		return false, fmt.Errorf("blobbuilder: writing block %d: %w", number, err)
+	}
+
+	if !ok {
+		if b.nbBlocks() == 0 {
+			return false, fmt.Errorf("blobbuilder: writing block %d: %w", number, ErrBlockTooLarge)
+		}
+		return false, nil
+	}
+
+	if !dryRun {
+		last := len(b.batches) - 1
+		b.batches[last] = append(b.batches[last], number)
+	}
+
+	return true, nil
+}
+
+// StartNewBatch closes the batch being built and opens a new one. It is a
+// no-op if the current batch is empty.
+func (b *Builder) StartNewBatch() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bm.StartNewBatch()
+	if len(b.batches[len(b.batches)-1]) > 0 {
+		b.batches = append(b.batches, nil)
+	}
+}
+
+// Reset discards the content of the blob.
+func (b *Builder) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.reset()
+}
+
+func (b *Builder) reset() {
+	b.bm.Reset()
+	b.batches = [][]uint64{nil}
+}
+
+// Len returns the size of the blob in bytes, header included.
+func (b *Builder) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.bm.Len()
+}
+
+// NbBlocks returns the number of blocks written in the blob.
+func (b *Builder) NbBlocks() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nbBlocks()
+}
+
+func (b *Builder) nbBlocks() int {
+	n := 0
+	for _, batch := range b.batches {
+		n += len(batch)
+	}
+	return n
+}
+
+// Bytes returns a copy of the blob.
+func (b *Builder) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.bm.Bytes())
+}
+
+// Seal returns the current blob and resets the builder. It returns nil if no
+// block was written.
+func (b *Builder) Seal() *Blob {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.nbBlocks() == 0 {
+		return nil
+	}
+
+	blob := &Blob{Data: bytes.Clone(b.bm.Bytes())}
+	for _, batch := range b.batches {
+		if len(batch) > 0 {
+			blob.Batches = append(blob.Batches, batch)
+		}
+	}
+
+	b.reset()
+	return blob
+}
+
+// WorstCompressedBlockSize returns the size of the RLP-encoded block once
+// compressed in an empty blob. See [v1.BlobMaker.WorstCompressedBlockSize].
+func (b *Builder) WorstCompressedBlockSize(rlpBlock []byte) (int, error) {
+	_, n, err := b.bm.WorstCompressedBlockSize(rlpBlock)
+	if err != nil {
+		return -1, fmt.Errorf("blobbuilder: %w", err)
+	}
+	return n, nil
+}
+
+// WorstCompressedTxSize returns the size of the RLP-encoded transaction once
+// compressed in an empty blob. See [v1.BlobMaker.WorstCompressedTxSize].
+func (b *Builder) WorstCompressedTxSize(rlpTx []byte) (int, error) {
+	n, err := b.bm.WorstCompressedTxSize(rlpTx)
+	if err != nil {
+		return -1, fmt.Errorf("blobbuilder: %w", err)
+	}
+	return n, nil
+}
+
+// RawCompressedSize returns the compressed size of the raw input, padding
+// included. See [v1.BlobMaker.RawCompressedSize].
+func (b *Builder) RawCompressedSize(data []byte) (int, error) {
+	n, err := b.bm.RawCompressedSize(data)
+	if err != nil {
+		return -1, fmt.Errorf("blobbuilder: %w", err)
+	}
+	return n, nil
+}
+
+// StartingBlockNumber returns the number of the first block of the blob.
+func (blob *Blob) StartingBlockNumber() uint64 {
+	return blob.Batches[0][0]
+}
+
+// UpperBoundaries returns the number of the last block of each batch.
+func (blob *Blob) UpperBoundaries() []uint64 {
+	res := make([]uint64, len(blob.Batches))
+	for i, batch := range blob.Batches {
+		res[i] = batch[len(batch)-1]
+	}
+	return res
+}
+
+// BlockNumber returns the number of an RLP-encoded block. Only the header is
+// decoded.
+func BlockNumber(rlpBlock []byte) (uint64, error) {
+	s := rlp.NewStream(bytes.NewReader(rlpBlock), uint64(len(rlpBlock)))
+	if _, err := s.List(); err != nil {
+		return 0, fmt.Errorf("decoding RLP block: %w", err)
+	}
+
+	var header types.Header
+	if err := s.Decode(&header); err != nil {
+		return 0, fmt.Errorf("decoding RLP block header: %w", err)
+	}
+
+	if header.Number == nil || !header.Number.IsUint64() {
+		return 0, errors.New("decoding RLP block header: invalid block number")
+	}
+
+	return header.Number.Uint64(), nil
+}
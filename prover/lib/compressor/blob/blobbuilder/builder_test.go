@@ -0,0 +1,133 @@
+package blobbuilder_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/blobbuilder"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/dictionary"
+	"github.com/consensys/linea-monorepo/prover/lib/compressor/blob/internal/rlpblocks"
+	v2 "github.com/consensys/linea-monorepo/prover/lib/compressor/blob/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDictPath = "../../compressor_dict.bin"
+
+func TestBuilderSeal(t *testing.T) {
+	blocks := rlpblocks.Get()[:4]
+
+	b, err := blobbuilder.New(v2.MaxUsableBytes, testDictPath)
+	require.NoError(t, err)
+
+	for i, block := range blocks {
+		if i == 2 {
+			b.StartNewBatch()
+		}
+		ok, err := b.Write(context.Background(), block)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	require.Equal(t, len(blocks), b.NbBlocks())
+
+	blob := b.Seal()
+	require.NotNil(t, blob)
+	assert.Zero(t, b.NbBlocks(), "the builder should be reset after sealing")
+	assert.Nil(t, b.Seal(), "sealing an empty builder should return nil")
+
+	expectedNumbers := make([]uint64, len(blocks))
+	for i := range blocks {
+		expectedNumbers[i], err = blobbuilder.BlockNumber(blocks[i])
+		require.NoError(t, err)
+	}
+	assert.Equal(t, [][]uint64{expectedNumbers[:2], expectedNumbers[2:]}, blob.Batches)
+	assert.Equal(t, expectedNumbers[0], blob.StartingBlockNumber())
+	assert.Equal(t, []uint64{expectedNumbers[1], expectedNumbers[3]}, blob.UpperBoundaries())
+
+	dict, err := os.ReadFile(testDictPath)
+	require.NoError(t, err)
+	dictStore, err := dictionary.SingletonStore(dict, blobbuilder.DefaultVersion)
+	require.NoError(t, err)
+
+	resp, err := v2.DecompressBlob(blob.Data, dictStore)
+	require.NoError(t, err)
+	assert.Len(t, resp.Blocks, len(blocks))
+	assert.Equal(t, 2, resp.Header.NbBatches())
+}
+
+func TestBuilderCanWrite(t *testing.T) {
+	block := rlpblocks.Get()[0]
+
+	b, err := blobbuilder.New(v2.MaxUsableBytes, testDictPath)
+	require.NoError(t, err)
+
+	ok, err := b.CanWrite(context.Background(), block)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, b.Len(), "CanWrite should not append the block")
+	assert.Zero(t, b.NbBlocks())
+}
+
+func TestBuilderBlockTooLarge(t *testing.T) {
+	block := rlpblocks.Get()[0]
+
+	b, err := blobbuilder.New(64, testDictPath)
+	require.NoError(t, err)
+
+	ok, err := b.Write(context.Background(), block)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, blobbuilder.ErrBlockTooLarge)
+}
+
+func TestBuilderCancelledContext(t *testing.T) {
+	block := rlpblocks.Get()[0]
+
+	b, err := blobbuilder.New(v2.MaxUsableBytes, testDictPath)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ok, err := b.Write(ctx, block)
+	assert.False(t, ok)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, b.NbBlocks())
+}
+
+func TestBuildersConcurrent(t *testing.T) {
+	const nbBuilders = 4
+	blocks := rlpblocks.Get()[:8]
+
+	var (
+		wg    sync.WaitGroup
+		blobs = make([]*blobbuilder.Blob, nbBuilders)
+		errs  = make([]error, nbBuilders)
+	)
+
+	for i := range nbBuilders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err := blobbuilder.New(v2.MaxUsableBytes, testDictPath)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, block := range blocks {
+				if _, err := b.Write(context.Background(), block); err != nil {
+					errs[i] = err
+					return
+				}
+			}
+			blobs[i] = b.Seal()
+		}()
+	}
+	wg.Wait()
+
+	for i := range nbBuilders {
+		require.NoError(t, errs[i])
+		assert.Equal(t, blobs[0], blobs[i], "independent builders should produce the same blob")
+	}
+}
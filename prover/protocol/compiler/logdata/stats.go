@@ -215,6 +215,18 @@ func (c *WizardStats) TotalCells() int {
 		c.NumCellsProof
 }
 
+// ProofSize returns the number of field elements sent to the verifier: the
+// cells of the proof columns and the query results.
+func (c *WizardStats) ProofSize() int {
+	return c.NumCellsProof +
+		c.NumResultsInnerProduct +
+		c.NumResultUnivariate +
+		c.NumResultLocalOpening +
+		c.NumResultLogDerivativeSum +
+		c.NumResultGrandProduct +
+		c.NumResultHorner
+}
+
 // TotalFSStats returns the fiat-shamir stats for every rounds
 func (c *WizardStats) TotalFSStats() (numWritten, numSampled int) {
 
@@ -0,0 +1,154 @@
+package logdata
+
+import (
+	"encoding/csv"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/sirupsen/logrus"
+)
+
+// SuiteStep collects the metrics of a compiled-IOP right after a step of a
+// compilation suite.
+type SuiteStep struct {
+	// Layer names the suite the step belongs to, e.g. "pre-recursion".
+	Layer string
+	// Index is the position of the step in its suite.
+	Index int
+	// Compiler is the name of the compilation step, e.g. "vortex.Compile".
+	Compiler  string
+	NumRounds int
+	// CommittedCells counts the cells of the committed columns, in base field
+	// elements.
+	CommittedCells int
+	// ProofSize is the number of field elements sent to the verifier: the
+	// cells of the proof columns and the results of the queries.
+	ProofSize int
+	// OpenedColumns is the number of columns opened by the Vortex compilation
+	// context of the compiled-IOP, or 0 if there is none.
+	OpenedColumns int
+	// VerifierHashCalls and GnarkConstraints are the cost of verifying the
+	// compiled-IOP in a gnark circuit, or -1 when it was not computed.
+	VerifierHashCalls int
+	GnarkConstraints  int
+}
+
+// VerifierCostFunc computes the number of hash calls and of constraints of
+// the gnark verifier of comp. It returns ok=false when there is no such
+// verifier at the current step of the compilation.
+type VerifierCostFunc func(comp *wizard.CompiledIOP) (hashCalls, constraints int, ok bool, err error)
+
+// SuiteReport records a [SuiteStep] after each step of the compilation suites
+// it instruments.
+type SuiteReport struct {
+	Steps []SuiteStep
+	// VerifierCost is optional and is called after every step when set.
+	VerifierCost VerifierCostFunc
+}
+
+// Instrument returns a copy of the suite where every step is followed by a
+// step recording its metrics in the report under the provided layer name.
+func (r *SuiteReport) Instrument(layer string, suite []func(*wizard.CompiledIOP)) []func(*wizard.CompiledIOP) {
+
+	res := make([]func(*wizard.CompiledIOP), 0, 2*len(suite))
+	for i, step := range suite {
+		var (
+			index = i
+			name  = compilerName(step)
+		)
+		res = append(res, step, func(comp *wizard.CompiledIOP) {
+			r.record(comp, layer, index, name)
+		})
+	}
+	return res
+}
+
+func (r *SuiteReport) record(comp *wizard.CompiledIOP, layer string, index int, name string) {
+
+	var (
+		stats = GetWizardStats(comp)
+		step  = SuiteStep{
+			Layer:             layer,
+			Index:             index,
+			Compiler:          name,
+			NumRounds:         comp.NumRounds(),
+			CommittedCells:    stats.NumCellsCommitted,
+			ProofSize:         stats.ProofSize(),
+			VerifierHashCalls: -1,
+			GnarkConstraints:  -1,
+		}
+	)
+
+	if pcs, ok := comp.PcsCtxs.(interface{ NbColsToOpen() int }); ok {
+		step.OpenedColumns = pcs.NbColsToOpen()
+	}
+
+	if r.VerifierCost != nil {
+		hashCalls, constraints, ok, err := r.VerifierCost(comp)
+		switch {
+		case err != nil:
+			logrus.Warnf("[suite-report] could not compute the verifier cost after %v/%v %v: %v", layer, index, name, err)
+		case ok:
+			step.VerifierHashCalls = hashCalls
+			step.GnarkConstraints = constraints
+		}
+	}
+
+	logrus.Infof("[suite-report] %+v", step)
+	r.Steps = append(r.Steps, step)
+}
+
+// suiteReportHeader is the header of the CSV written by [SuiteReport.WriteCSV].
+var suiteReportHeader = []string{
+	"layer", "index", "compiler", "rounds", "committed_cells", "proof_size",
+	"opened_columns", "verifier_hash_calls", "gnark_constraints",
+}
+
+// WriteCSV writes the report as a CSV table with one row per step. The table
+// only holds deterministic values so that two reports can be diffed.
+func (r *SuiteReport) WriteCSV(w io.Writer) error {
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(suiteReportHeader); err != nil {
+		return err
+	}
+
+	for _, s := range r.Steps {
+		row := []string{
+			s.Layer,
+			strconv.Itoa(s.Index),
+			s.Compiler,
+			strconv.Itoa(s.NumRounds),
+			strconv.Itoa(s.CommittedCells),
+			strconv.Itoa(s.ProofSize),
+			strconv.Itoa(s.OpenedColumns),
+			strconv.Itoa(s.VerifierHashCalls),
+			strconv.Itoa(s.GnarkConstraints),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// closureSuffix matches the suffix given by the Go runtime to the closures,
+// as returned by the compiler constructors such as [compiler.Arcane].
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$`)
+
+// compilerName returns the name of the function implementing a compilation
+// step, without its import path, e.g. "vortex.Compile".
+func compilerName(step func(*wizard.CompiledIOP)) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(step).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	return closureSuffix.ReplaceAllString(path.Base(fn.Name()), "")
+}
@@ -0,0 +1,77 @@
+package logdata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/innerproduct"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuiteReport(t *testing.T) {
+
+	define := func(b *wizard.Builder) {
+		a := b.RegisterCommit("A", 8)
+		b.InnerProduct("IP", a, a)
+	}
+
+	// The verifier cost is reported for the steps where it is available only,
+	// here the second one.
+	calls := 0
+	report := &SuiteReport{
+		VerifierCost: func(comp *wizard.CompiledIOP) (int, int, bool, error) {
+			calls++
+			return calls, 42, calls == 2, nil
+		},
+	}
+
+	// innerproduct.Compile returns a closure while dummy.Compile is a plain
+	// function: both must be named after the function of the package.
+	suite := []func(*wizard.CompiledIOP){innerproduct.Compile(), dummy.Compile}
+	instrumented := report.Instrument("test", suite)
+	require.Len(t, instrumented, 2*len(suite))
+
+	wizard.Compile(define, instrumented...)
+
+	require.Len(t, report.Steps, len(suite))
+	for i, name := range []string{"innerproduct.Compile", "dummy.Compile"} {
+		s := report.Steps[i]
+		assert.Equal(t, "test", s.Layer)
+		assert.Equal(t, i, s.Index)
+		assert.Equal(t, name, s.Compiler)
+		assert.Positive(t, s.NumRounds)
+	}
+
+	assert.Equal(t, -1, report.Steps[0].VerifierHashCalls)
+	assert.Equal(t, -1, report.Steps[0].GnarkConstraints)
+	assert.Equal(t, 2, report.Steps[1].VerifierHashCalls)
+	assert.Equal(t, 42, report.Steps[1].GnarkConstraints)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 1+len(suite))
+	assert.Equal(t, suiteReportHeader, rows[0])
+
+	for i, row := range rows[1:] {
+		s := report.Steps[i]
+		assert.Equal(t, []string{
+			s.Layer,
+			strconv.Itoa(s.Index),
+			s.Compiler,
+			strconv.Itoa(s.NumRounds),
+			strconv.Itoa(s.CommittedCells),
+			strconv.Itoa(s.ProofSize),
+			strconv.Itoa(s.OpenedColumns),
+			strconv.Itoa(s.VerifierHashCalls),
+			strconv.Itoa(s.GnarkConstraints),
+		}, row)
+	}
+}
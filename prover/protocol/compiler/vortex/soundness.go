@@ -0,0 +1,61 @@
+package vortex
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/consensys/linea-monorepo/prover/crypto/soundness"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/maths/field/fext"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+)
+
+// Soundness returns the conjectured soundness of each Vortex compilation
+// applied to comp, in the order of the compilation. The verifier actions of
+// Vortex remain registered in comp after the self-recursion, so this can be
+// called on the output of a complete compilation suite.
+func Soundness(comp *wizard.CompiledIOP) []soundness.Layer {
+
+	var res []soundness.Layer
+
+	for round := 0; round < comp.SubVerifiers.Len(); round++ {
+		for _, action := range comp.SubVerifiers.GetOrEmpty(round) {
+			if va, ok := action.(*VortexVerifierAction); ok && va.Ctx.NumCols > 0 {
+				name := fmt.Sprintf("vortex-%d", len(res))
+				res = append(res, va.Ctx.Soundness(name))
+			}
+		}
+	}
+
+	return res
+}
+
+// Soundness returns the conjectured soundness of the Vortex commitment of the
+// context. The opened columns are analysed in the Guruswami-Sudan list
+// decoding regime, as in [Ctx.NbColsToOpen], and the coins are sampled in the
+// extension field.
+func (ctx *Ctx) Soundness(name string) soundness.Layer {
+
+	var (
+		fieldBits    = soundness.ExtensionFieldBits(field.Modulus().Uint64(), fext.ExtensionDegree)
+		codewordSize = ctx.NumCols * ctx.BlowUpFactor
+		sisBits      = math.Inf(1)
+	)
+
+	if ctx.CommittedRowsCountSIS > 0 && !ctx.IsBLS {
+		sisBits = soundness.EstimateSIS(
+			float64(field.Bits),
+			ctx.SisParams.LogTwoBound,
+			1<<ctx.SisParams.LogTwoDegree,
+		).Bits()
+	}
+
+	return soundness.Layer{
+		Name:             name,
+		QueryBits:        soundness.JohnsonQueryBits(ctx.BlowUpFactor, ctx.NbColsToOpen()),
+		ProximityGapBits: soundness.ProximityGapBits(ctx.CommittedRowsCount, codewordSize, fieldBits),
+		EvaluationBits:   soundness.EvaluationBits(ctx.NumCols, fieldBits),
+		SISBits:          sisBits,
+		FieldBits:        fieldBits,
+	}
+}
@@ -182,13 +182,49 @@ func StoreChunkedWithSize(basePath string, asset any, chunkSize int) error {
 // Returns an MmapBackedBuffer that must be released by the caller.
 func LoadChunkedMmapBacked(basePath string, assetPtr any) (*MmapBackedBuffer, error) {
 
+	logrus.Infof("Loading chunked asset %s...", filepath.Join(basePath, "manifest"))
+
+	var (
+		mmapData  []byte
+		numChunks int
+		loadErr   error
+	)
+	tLoad := profiling.TimeIt(func() {
+		mmapData, numChunks, loadErr = readChunkedToMmap(basePath)
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	decompSize := len(mmapData)
+
+	// Deserialize
+	var deserErr error
+	tDeser := profiling.TimeIt(func() {
+		deserErr = Deserialize(mmapData, assetPtr)
+	})
+	if deserErr != nil {
+		_ = syscall.Munmap(mmapData)
+		return nil, fmt.Errorf("deserialization failed: %w", deserErr)
+	}
+
+	logrus.Infof("Loaded chunked %s [Size: %s, Chunks: %d] | Load+Decomp: %s | Deser: %s",
+		basePath, formatSize(decompSize), numChunks, tLoad, tDeser)
+
+	return &MmapBackedBuffer{data: mmapData}, nil
+}
+
+// readChunkedToMmap reads the manifest of the chunked asset stored at basePath
+// and decompresses its chunks in parallel into an anonymous mmap buffer. It
+// returns the buffer, which must be unmapped by the caller, along with the
+// number of chunks.
+func readChunkedToMmap(basePath string) ([]byte, int, error) {
+
 	manifestPath := filepath.Join(basePath, "manifest")
-	logrus.Infof("Loading chunked asset %s...", manifestPath)
 
 	// 1. Read manifest
 	manifest, entries, err := readManifest(manifestPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read manifest: %w", err)
+		return nil, 0, fmt.Errorf("failed to read manifest: %w", err)
 	}
 
 	decompSize := int(manifest.DecompressedSz)
@@ -199,7 +235,7 @@ func LoadChunkedMmapBacked(basePath string, assetPtr any) (*MmapBackedBuffer, er
 		syscall.PROT_READ|syscall.PROT_WRITE,
 		syscall.MAP_ANON|syscall.MAP_PRIVATE)
 	if err != nil {
-		return nil, fmt.Errorf("mmap allocation failed (%s): %w", formatSize(decompSize), err)
+		return nil, 0, fmt.Errorf("mmap allocation failed (%s): %w", formatSize(decompSize), err)
 	}
 
 	// 3. Parallel read + decompress into mmap
@@ -214,59 +250,43 @@ func LoadChunkedMmapBacked(basePath string, assetPtr any) (*MmapBackedBuffer, er
 	eg := &errgroup.Group{}
 	eg.SetLimit(maxWorkers)
 
-	var loadErr error
-	var tLoad time.Duration
-	tLoad = profiling.TimeIt(func() {
-		for i := range numChunks {
-			i := i
-			eg.Go(func() error {
-				entry := entries[i]
-				chunkPath := filepath.Join(basePath, fmt.Sprintf("chunk-%04d.lz4", i))
-
-				compressed, err := os.ReadFile(chunkPath)
+	for i := range numChunks {
+		i := i
+		eg.Go(func() error {
+			entry := entries[i]
+			chunkPath := filepath.Join(basePath, fmt.Sprintf("chunk-%04d.lz4", i))
+
+			compressed, err := os.ReadFile(chunkPath)
+			if err != nil {
+				return fmt.Errorf("read chunk %d: %w", i, err)
+			}
+
+			if entry.Offset+uint64(entry.DecompSz) > uint64(decompSize) {
+				return fmt.Errorf("chunk %d: range [%d, %d) exceeds the decompressed size %d", i, entry.Offset, entry.Offset+uint64(entry.DecompSz), decompSize)
+			}
+			dst := mmapData[entry.Offset : entry.Offset+uint64(entry.DecompSz)]
+
+			if uint32(len(compressed)) == entry.DecompSz {
+				// Was stored raw (incompressible)
+				copy(dst, compressed)
+			} else {
+				n, err := lz4.UncompressBlock(compressed, dst)
 				if err != nil {
-					return fmt.Errorf("read chunk %d: %w", i, err)
+					return fmt.Errorf("lz4 decompress chunk %d: %w", i, err)
 				}
-
-				dst := mmapData[entry.Offset : entry.Offset+uint64(entry.DecompSz)]
-
-				if uint32(len(compressed)) == entry.DecompSz {
-					// Was stored raw (incompressible)
-					copy(dst, compressed)
-				} else {
-					n, err := lz4.UncompressBlock(compressed, dst)
-					if err != nil {
-						return fmt.Errorf("lz4 decompress chunk %d: %w", i, err)
-					}
-					if n != int(entry.DecompSz) {
-						return fmt.Errorf("chunk %d: expected %d decompressed bytes, got %d", i, entry.DecompSz, n)
-					}
+				if n != int(entry.DecompSz) {
+					return fmt.Errorf("chunk %d: expected %d decompressed bytes, got %d", i, entry.DecompSz, n)
 				}
-				return nil
-			})
-		}
-		loadErr = eg.Wait()
-	})
-
-	if loadErr != nil {
-		_ = syscall.Munmap(mmapData)
-		return nil, loadErr
+			}
+			return nil
+		})
 	}
-
-	// 4. Deserialize
-	var deserErr error
-	tDeser := profiling.TimeIt(func() {
-		deserErr = Deserialize(mmapData, assetPtr)
-	})
-	if deserErr != nil {
+	if err := eg.Wait(); err != nil {
 		_ = syscall.Munmap(mmapData)
-		return nil, fmt.Errorf("deserialization failed: %w", deserErr)
+		return nil, 0, err
 	}
 
-	logrus.Infof("Loaded chunked %s [Size: %s, Chunks: %d] | Load+Decomp: %s | Deser: %s",
-		basePath, formatSize(decompSize), numChunks, tLoad, tDeser)
-
-	return &MmapBackedBuffer{data: mmapData}, nil
+	return mmapData, numChunks, nil
 }
 
 // HasChunkedAsset returns true if a chunked manifest exists for the given base path.
@@ -31,11 +31,62 @@ import (
 // give a warning when that happens. Finally, the function fully disregards
 // values that are tagged with the `serde:"omit` tag.
 func DeepCmp(a, b interface{}, failFast bool) bool {
-	cachedPtrs := make(map[uintptr]struct{})
-	return compareExportedFieldsWithPath(cachedPtrs, reflect.ValueOf(a), reflect.ValueOf(b), "", failFast)
+	c := &comparison{
+		cachedPtrs: make(map[uintptr]struct{}),
+		failFast:   failFast,
+		log:        true,
+	}
+	return c.compare(reflect.ValueOf(a), reflect.ValueOf(b), "")
+}
+
+// Mismatch is a difference between two values found by [DeepDiff].
+type Mismatch struct {
+	// Path locates the differing value from the root, e.g.
+	// "QueriesNoParams.Mapping[ID].Round".
+	Path   string
+	Reason string
+}
+
+func (m Mismatch) String() string {
+	if m.Path == "" {
+		return "(root): " + m.Reason
+	}
+	return m.Path + ": " + m.Reason
+}
+
+// DeepDiff compares a and b with the same rules as [DeepCmp] but returns the
+// mismatches instead of logging them. An empty result means that the values
+// are deeply-equal.
+func DeepDiff(a, b interface{}, failFast bool) []Mismatch {
+	c := &comparison{
+		cachedPtrs: make(map[uintptr]struct{}),
+		failFast:   failFast,
+	}
+	c.compare(reflect.ValueOf(a), reflect.ValueOf(b), "")
+	return c.mismatches
+}
+
+// comparison holds the state of a [DeepCmp] or [DeepDiff] traversal.
+type comparison struct {
+	cachedPtrs map[uintptr]struct{}
+	failFast   bool
+	// log tells whether mismatches are logged as they are found; they are
+	// collected in mismatches in any case.
+	log        bool
+	mismatches []Mismatch
+}
+
+// report records a mismatch at path and logs it at the given level if the
+// comparison logs its findings.
+func (c *comparison) report(level logrus.Level, path, format string, args ...any) {
+	m := Mismatch{Path: path, Reason: fmt.Sprintf(format, args...)}
+	c.mismatches = append(c.mismatches, m)
+	if c.log {
+		logrus.StandardLogger().Logf(level, "Mismatch at %s", m)
+	}
 }
 
-func compareExportedFieldsWithPath(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path string, failFast bool) bool {
+func (c *comparison) compare(a, b reflect.Value, path string) bool {
 	// Handle invalid values
 	if !a.IsValid() || !b.IsValid() {
 		// Treat nil and zero values as equivalent
@@ -52,14 +103,14 @@ func compareExportedFieldsWithPath(cachedPtrs map[uintptr]struct{}, a, b reflect
 
 	// Type check after normalization of invalid values
 	if a.Type() != b.Type() {
-		logrus.Printf("Mismatch at %s: types differ (v1: %v, v2: %v, types: %v, %v)\n", path, a.Interface(), b.Interface(), a.Type(), b.Type())
+		c.report(logrus.InfoLevel, path, "types differ (v1: %v, v2: %v, types: %v, %v)", a.Interface(), b.Interface(), a.Type(), b.Type())
 		return false
 	}
 
 	// Specialized handlers
 	switch a.Type() {
 	case reflect.TypeFor[*symbolic.Expression]():
-		return compareSymbolicExpressions(a, b, path)
+		return c.compareSymbolicExpressions(a, b, path)
 	case reflect.TypeFor[frontend.Variable]():
 		return true
 	}
@@ -69,25 +120,25 @@ func compareExportedFieldsWithPath(cachedPtrs map[uintptr]struct{}, a, b reflect
 		// Ignore Func
 		return true
 	case reflect.Interface:
-		return compareExportedFieldsWithPath(cachedPtrs, a.Elem(), b.Elem(), path+".(interface)", failFast)
+		return c.compare(a.Elem(), b.Elem(), path+".(interface)")
 	case reflect.Map:
-		return compareMaps(cachedPtrs, a, b, path, failFast)
+		return c.compareMaps(a, b, path)
 	case reflect.Ptr:
-		return comparePointers(cachedPtrs, a, b, path, failFast)
+		return c.comparePointers(a, b, path)
 	case reflect.Struct:
-		return compareStructs(cachedPtrs, a, b, path, failFast)
+		return c.compareStructs(a, b, path)
 	case reflect.Slice, reflect.Array:
-		return compareSlices(cachedPtrs, a, b, path, failFast)
+		return c.compareSlices(a, b, path)
 	default:
 		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
-			logrus.Printf("Mismatch at %s: values differ (v1: %v, v2: %v, type_v1: %v type_v2: %v)\n", path, a.Interface(), b.Interface(), a.Type(), b.Type())
+			c.report(logrus.InfoLevel, path, "values differ (v1: %v, v2: %v, type_v1: %v type_v2: %v)", a.Interface(), b.Interface(), a.Type(), b.Type())
 			return false
 		}
 		return true
 	}
 }
 
-func compareSymbolicExpressions(a, b reflect.Value, path string) bool {
+func (c *comparison) compareSymbolicExpressions(a, b reflect.Value, path string) bool {
 	ae := a.Interface().(*symbolic.Expression)
 	be := b.Interface().(*symbolic.Expression)
 
@@ -96,7 +147,7 @@ func compareSymbolicExpressions(a, b reflect.Value, path string) bool {
 		return true
 	}
 	if (ae == nil) != (be == nil) {
-		logrus.Errorf("Mismatch at %s: one value is nil, the other is not\n", path)
+		c.report(logrus.ErrorLevel, path, "one value is nil, the other is not")
 		return false
 	}
 
@@ -125,41 +176,41 @@ func compareSymbolicExpressions(a, b reflect.Value, path string) bool {
 
 	// 3. Report Validation Failures without Crashing
 	if errA != nil || errB != nil {
-		logrus.Warnf("Validation failed at %s. This implies the object was not fully restored.\n\tTruth Err: %v\n\tProver Err: %v", path, errA, errB)
+		c.report(logrus.WarnLevel, path, "validation failed, this implies the object was not fully restored (truth err: %v, prover err: %v)", errA, errB)
 		// We return false to fail the test, but we don't crash the runner.
 		return false
 	}
 
 	// 4. Compare Hash
 	if ae.ESHash != be.ESHash {
-		logrus.Errorf("Mismatch at %s: hashes differ (v1: %v, v2: %v)\n", path, ae.ESHash.String(), be.ESHash.String())
+		c.report(logrus.ErrorLevel, path, "hashes differ (v1: %v, v2: %v)", ae.ESHash.String(), be.ESHash.String())
 		return false
 	}
 
 	return true
 }
 
-func comparePointers(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path string, failFast bool) bool {
+func (c *comparison) comparePointers(a, b reflect.Value, path string) bool {
 	if a.IsNil() && b.IsNil() {
 		return true
 	}
 
 	if a.IsNil() != b.IsNil() {
-		logrus.Printf("Mismatch at %s: nil status differs (v1: %v, v2: %v, type: %v)\n", path, a, b, a.Type())
+		c.report(logrus.InfoLevel, path, "nil status differs (v1: %v, v2: %v, type: %v)", a, b, a.Type())
 		return false
 	}
 
-	if _, seen := cachedPtrs[a.Pointer()]; seen {
+	if _, seen := c.cachedPtrs[a.Pointer()]; seen {
 		return true
 	}
 
-	cachedPtrs[a.Pointer()] = struct{}{}
-	return compareExportedFieldsWithPath(cachedPtrs, a.Elem(), b.Elem(), path, failFast)
+	c.cachedPtrs[a.Pointer()] = struct{}{}
+	return c.compare(a.Elem(), b.Elem(), path)
 }
 
-func compareMaps(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path string, failFast bool) bool {
+func (c *comparison) compareMaps(a, b reflect.Value, path string) bool {
 	if a.Len() != b.Len() {
-		logrus.Printf("Mismatch at %s: map lengths differ (v1: %v, v2: %v, type: %v)\n", path, a.Len(), b.Len(), a.Type())
+		c.report(logrus.InfoLevel, path, "map lengths differ (v1: %v, v2: %v, type: %v)", a.Len(), b.Len(), a.Type())
 		return false
 	}
 
@@ -174,12 +225,12 @@ func compareMaps(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path strin
 		valA := a.MapIndex(key)
 		valB := b.MapIndex(key)
 		if !valB.IsValid() {
-			logrus.Printf("Mismatch at %s: key %v is missing in second map\n", path, key)
+			c.report(logrus.InfoLevel, path, "key %v is missing in second map", key)
 			return false
 		}
 		keyPath := fmt.Sprintf("%s[%v]", path, key)
-		if !compareExportedFieldsWithPath(cachedPtrs, valA, valB, keyPath, failFast) {
-			if failFast {
+		if !c.compare(valA, valB, keyPath) {
+			if c.failFast {
 				return false
 			}
 		}
@@ -187,7 +238,7 @@ func compareMaps(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path strin
 	return true
 }
 
-func compareStructs(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path string, failFast bool) bool {
+func (c *comparison) compareStructs(a, b reflect.Value, path string) bool {
 	// Log progress for top-level struct fields (path has no dots = top-level)
 	isTopLevel := !strings.Contains(path, ".")
 	parentHasCustomMarshaller := hasCustomMarshaller(a.Type())
@@ -224,9 +275,9 @@ func compareStructs(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path st
 			logrus.Debugf("DeepCmp: comparing field %d/%d: %s", i+1, a.NumField(), fieldPath)
 		}
 
-		if !compareExportedFieldsWithPath(cachedPtrs, a.Field(i), b.Field(i), fieldPath, failFast) {
+		if !c.compare(a.Field(i), b.Field(i), fieldPath) {
 			equal = false
-			if failFast {
+			if c.failFast {
 				return false
 			}
 		}
@@ -283,9 +334,9 @@ func isDeepEqualSafeVisited(t reflect.Type, visited map[reflect.Type]bool) bool
 	}
 }
 
-func compareSlices(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path string, failFast bool) bool {
+func (c *comparison) compareSlices(a, b reflect.Value, path string) bool {
 	if a.Len() != b.Len() {
-		logrus.Printf("Mismatch at %s: slice lengths differ (v1: %v, v2: %v, type: %v)\n", path, a.Len(), b.Len(), a.Type())
+		c.report(logrus.InfoLevel, path, "slice lengths differ (v1: %v, v2: %v, type: %v)", a.Len(), b.Len(), a.Type())
 		return false
 	}
 
@@ -297,7 +348,7 @@ func compareSlices(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path str
 	// interfaces, or funcs, use reflect.DeepEqual on the whole slice.
 	if isDeepEqualSafe(a.Type().Elem()) {
 		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
-			logrus.Printf("Mismatch at %s: slice values differ (type: %v, len: %d)\n", path, a.Type(), a.Len())
+			c.report(logrus.InfoLevel, path, "slice values differ (type: %v, len: %d)", a.Type(), a.Len())
 			return false
 		}
 		return true
@@ -306,9 +357,9 @@ func compareSlices(cachedPtrs map[uintptr]struct{}, a, b reflect.Value, path str
 	equal := true
 	for i := 0; i < a.Len(); i++ {
 		elemPath := fmt.Sprintf("%s[%d]", path, i)
-		if !compareExportedFieldsWithPath(cachedPtrs, a.Index(i), b.Index(i), elemPath, failFast) {
+		if !c.compare(a.Index(i), b.Index(i), elemPath) {
 			equal = false
-			if failFast {
+			if c.failFast {
 				return false
 			}
 		}
@@ -148,3 +148,41 @@ func TestIsDeepEqualSafe_BasicTypes(t *testing.T) {
 	assert.False(t, isDeepEqualSafe(reflect.TypeOf(structWithPtr{})), "struct with pointer field")
 	assert.False(t, isDeepEqualSafe(reflect.TypeOf([]*int{})), "slice of pointers")
 }
+
+type diffInner struct {
+	Round int
+	Names []string
+}
+
+type diffOuter struct {
+	Label string
+	Inner *diffInner
+	Items []diffInner
+}
+
+func TestDeepDiff_ReportsPaths(t *testing.T) {
+	newValue := func() diffOuter {
+		return diffOuter{
+			Label: "a",
+			Inner: &diffInner{Round: 1, Names: []string{"x"}},
+			Items: []diffInner{{Round: 2}, {Round: 3, Names: []string{"y", "z"}}},
+		}
+	}
+
+	a, b := newValue(), newValue()
+	assert.Empty(t, DeepDiff(a, b, false))
+
+	b.Inner.Round = 4
+	b.Items[1].Names = []string{"y"}
+
+	mismatches := DeepDiff(a, b, false)
+	paths := make([]string, len(mismatches))
+	for i := range mismatches {
+		paths[i] = mismatches[i].Path
+	}
+	assert.Equal(t, []string{"Inner.Round", "Items[1].Names"}, paths)
+	assert.Contains(t, mismatches[1].String(), "slice lengths differ")
+
+	assert.Len(t, DeepDiff(a, b, true), 1, "failFast stops at the first mismatch")
+	assert.False(t, DeepCmp(a, b, false))
+}
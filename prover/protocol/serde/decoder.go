@@ -21,6 +21,10 @@ type decoder struct {
 	// When it encounters the offset for C the second time, it grabs the existing object from ptrMap.
 	// Benefit: This preserves cycles (A -> B -> A) and saves memory.
 	ptrMap map[int64]reflect.Value
+
+	// schema holds the type layouts recorded in the asset. It is nil for the
+	// legacy assets, which are decoded assuming the current layouts.
+	schema *decodeSchema
 }
 
 func (dec *decoder) decode(target reflect.Value, offset int64) error {
@@ -147,6 +151,14 @@ func (dec *decoder) decodeSlice(target reflect.Value, offset int64) error {
 		if !info.isPOD {
 			hasPtrs = true
 		}
+		// The elements whose layout changed since the asset was written are
+		// decoded one by one.
+		if !dec.sameLayout(elemType) {
+			if err := dec.checkRawLayout(elemType); err != nil {
+				return err
+			}
+			hasPtrs = true
+		}
 	}
 	if hasPtrs {
 		target.Set(reflect.MakeSlice(target.Type(), int(fs.Len), int(fs.Cap)))
@@ -263,10 +275,10 @@ func (dec *decoder) decodeInterface(target reflect.Value, offset int64) error {
 		return dec.decodeBoxedComposite(target, ih)
 	}
 
-	if int(ih.TypeID) >= len(IDToType) {
-		return fmt.Errorf("invalid type ID: %d", ih.TypeID)
+	concreteType, err := dec.typeByID(ih.TypeID)
+	if err != nil {
+		return err
 	}
-	concreteType := IDToType[ih.TypeID]
 	for i := 0; i < int(ih.PtrIndirection); i++ {
 		concreteType = reflect.PointerTo(concreteType)
 	}
@@ -313,16 +325,16 @@ func (dec *decoder) decodeInterface(target reflect.Value, offset int64) error {
 //
 //   - compositeElemEmptyStruct (0xFFFF) → struct{}
 //   - otherwise: bits 14–15 = pointer indirection, bits 0–13 = base TypeID
-func resolveCompositeTypeField(field uint16) (reflect.Type, error) {
+func (dec *decoder) resolveCompositeTypeField(field uint16) (reflect.Type, error) {
 	if field == compositeElemEmptyStruct {
 		return emptyStructType, nil
 	}
 	indirection := int(field >> compositeIndirectionShift)
 	baseID := field & compositeTypeMask
-	if int(baseID) >= len(IDToType) {
-		return nil, fmt.Errorf("decodeBoxedComposite: base TypeID %d out of range (registry size %d)", baseID, len(IDToType))
+	t, err := dec.typeByID(baseID)
+	if err != nil {
+		return nil, fmt.Errorf("decodeBoxedComposite: base %w", err)
 	}
-	t := IDToType[baseID]
 	for i := 0; i < indirection; i++ {
 		t = reflect.PointerTo(t)
 	}
@@ -348,7 +360,7 @@ func (dec *decoder) decodeBoxedComposite(target reflect.Value, ih *InterfaceHead
 	primaryField := uint16(ih.Reserved[1]) | uint16(ih.Reserved[2])<<8
 	secondaryRaw := uint16(ih.Reserved[3]) | uint16(ih.Reserved[4])<<8
 
-	primaryType, err := resolveCompositeTypeField(primaryField)
+	primaryType, err := dec.resolveCompositeTypeField(primaryField)
 	if err != nil {
 		return fmt.Errorf("decodeBoxedComposite primary: %w", err)
 	}
@@ -359,7 +371,7 @@ func (dec *decoder) decodeBoxedComposite(target reflect.Value, ih *InterfaceHead
 		compositeType = reflect.SliceOf(primaryType)
 
 	case compositeKindMap:
-		valType, err := resolveCompositeTypeField(secondaryRaw)
+		valType, err := dec.resolveCompositeTypeField(secondaryRaw)
 		if err != nil {
 			return fmt.Errorf("decodeBoxedComposite map value: %w", err)
 		}
@@ -393,15 +405,22 @@ func (dec *decoder) decodeBoxedComposite(target reflect.Value, ih *InterfaceHead
 func (dec *decoder) decodeArray(target reflect.Value, offset int64) error {
 	t := target.Type()
 	info := getTypeInfo(t.Elem())
-	elemSize := info.binSize
+	elemSize := dec.binSize(t.Elem())
 	totalSize := elemSize * int64(target.Len())
 	if offset < 0 || offset+totalSize > int64(len(dec.data)) {
 		return fmt.Errorf("array out of bounds")
 	}
 
+	sameLayout := dec.sameLayout(t.Elem())
+	if !sameLayout {
+		if err := dec.checkRawLayout(t.Elem()); err != nil {
+			return err
+		}
+	}
+
 	// Optimization: Bulk Copy for POD types. If the element type is POD
 	// (eg field.Element [4]uint64), we can copy the whole block at once.
-	if info.isPOD {
+	if info.isPOD && sameLayout {
 		dstPtr := unsafe.Pointer(target.UnsafeAddr())
 		srcPtr := unsafe.Pointer(&dec.data[offset])
 		// unsafe.Slice allows us to treat the pointers as byte slices for the copy
@@ -441,6 +460,12 @@ func (dec *decoder) decodePrimitive(target reflect.Value, offset int64) error {
 // collection of fields, we loop through the struct schema (i.e. type definition) and
 // decode each field accordingly as per `decodeSeqItem`.
 func (dec *decoder) decodeStruct(target reflect.Value, offset int64) error {
+	// The fields of a struct whose layout changed are located from the
+	// recorded layout rather than the current type.
+	if !dec.sameLayout(target.Type()) {
+		return dec.decodeStructRemapped(target, offset)
+	}
+
 	currentOffSet := offset
 	for i := 0; i < target.NumField(); i++ {
 		tf := target.Type().Field(i)
@@ -455,6 +480,9 @@ func (dec *decoder) decodeStruct(target reflect.Value, offset int64) error {
 		// If it's POD and we can get the address, we perform a bulk memory copy.
 		// This covers uint64, [4]uint64, and even nested POD structs.
 		if info.isPOD && f.CanAddr() {
+			if currentOffSet < 0 || currentOffSet+info.binSize > int64(len(dec.data)) {
+				return fmt.Errorf("field '%s' out of bounds: offset %d, size %d (len: %d)", tf.Name, currentOffSet, info.binSize, len(dec.data))
+			}
 			dstPtr := unsafe.Pointer(f.UnsafeAddr())
 			srcPtr := unsafe.Pointer(&dec.data[currentOffSet])
 
@@ -514,8 +542,9 @@ func (dec *decoder) decodeSeqItem(target reflect.Value, offset int64) (int64, er
 		return offset + 8, nil
 	}
 	// If the type is "direct" (size known at compile time), the data is stored "inline" within the map's data block.
-	// The function decodes it at the current position and moves the cursor forward by the size of that type.
-	binSize := info.binSize
+	// The function decodes it at the current position and moves the cursor forward by the size of that type,
+	// as it was when the asset was written.
+	binSize := dec.binSize(t)
 	if int(offset)+int(binSize) > len(dec.data) {
 		return 0, fmt.Errorf("decodeSeqItem: unable to read binary data of size %d at offset %d", binSize, offset)
 	}
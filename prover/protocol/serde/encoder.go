@@ -48,6 +48,11 @@ type encoder struct {
 	// profiler is non-nil only when Profile() is used instead of Serialize().
 	// It builds a size tree without any overhead in normal (non-profiling) runs.
 	profiler *sizeProfiler
+
+	// schema records the layouts of the encoded types and the TypeIDs of the
+	// interface implementations. It is nil when profiling, in which case the
+	// TypeIDs are the indexes of IDToType.
+	schema *schemaBuilder
 }
 
 func newEncoder() *encoder {
@@ -320,6 +325,10 @@ func linearize(w *encoder, v reflect.Value) (Ref, error) {
 		}
 	}
 
+	if w.schema != nil {
+		w.schema.record(v.Type())
+	}
+
 	// Custom handlers override default behaviour
 	if handler, ok := customRegistry[v.Type()]; ok {
 		traceLog("Using Custom Handler for %s", v.Type())
@@ -479,7 +488,7 @@ func linearizeInterface(w *encoder, v reflect.Value) (Ref, error) {
 		baseType = baseType.Elem()
 		indirection++
 	}
-	typeID, ok := TypeToID[baseType]
+	typeID, ok := w.typeID(baseType)
 	if !ok {
 		// Do not stop at the first missing type. Accumulate all unregistered types so
 		// that the caller gets a single comprehensive error listing everything that needs
@@ -504,7 +513,7 @@ func linearizeInterface(w *encoder, v reflect.Value) (Ref, error) {
 // Returns an error when the base type is not in TypeToID or when pointer
 // indirection exceeds 3. On an unregistered-type error the caller should add
 // stripPointers(t) to w.missingTypes so the bulk error report names the base type.
-func encodeCompositeTypeField(t reflect.Type) (uint16, error) {
+func encodeCompositeTypeField(w *encoder, t reflect.Type) (uint16, error) {
 	indirection := 0
 	base := t
 	for base.Kind() == reflect.Ptr {
@@ -520,13 +529,23 @@ func encodeCompositeTypeField(t reflect.Type) (uint16, error) {
 	if indirection > 3 {
 		return 0, fmt.Errorf("boxed composite: pointer indirection %d for type %v exceeds maximum of 3", indirection, t)
 	}
-	id, ok := TypeToID[base]
+	id, ok := w.typeID(base)
 	if !ok {
 		return 0, fmt.Errorf("boxed composite: type %v not registered; add it via RegisterImplementation", base)
 	}
 	return uint16(indirection)<<compositeIndirectionShift | id&compositeTypeMask, nil
 }
 
+// typeID returns the TypeID written in the interface headers for the type t
+// and whether t is registered at all.
+func (w *encoder) typeID(t reflect.Type) (uint16, bool) {
+	id, ok := TypeToID[t]
+	if !ok || w.schema == nil {
+		return id, ok
+	}
+	return w.schema.typeID(t), true
+}
+
 // stripPointers returns the base type after removing all pointer indirections.
 // Used to extract the unregistered base type for error accumulation.
 func stripPointers(t reflect.Type) reflect.Type {
@@ -549,7 +568,7 @@ func stripPointers(t reflect.Type) reflect.Type {
 //	    Offset        → FileSlice header of the slice data
 //	}
 func linearizeBoxedSlice(w *encoder, v reflect.Value) (Ref, error) {
-	elemField, err := encodeCompositeTypeField(v.Type().Elem())
+	elemField, err := encodeCompositeTypeField(w, v.Type().Elem())
 	if err != nil {
 		base := stripPointers(v.Type().Elem())
 		if base != emptyStructType {
@@ -585,8 +604,8 @@ func linearizeBoxedSlice(w *encoder, v reflect.Value) (Ref, error) {
 //	    Offset        → FileSlice header of the map data
 //	}
 func linearizeBoxedMap(w *encoder, v reflect.Value) (Ref, error) {
-	keyField, keyErr := encodeCompositeTypeField(v.Type().Key())
-	valField, valErr := encodeCompositeTypeField(v.Type().Elem())
+	keyField, keyErr := encodeCompositeTypeField(w, v.Type().Key())
+	valField, valErr := encodeCompositeTypeField(w, v.Type().Elem())
 
 	if keyErr != nil || valErr != nil {
 		if keyErr != nil {
@@ -634,7 +653,7 @@ func linearizeBoxedArray(w *encoder, v reflect.Value) (Ref, error) {
 	if length > 65535 {
 		return 0, fmt.Errorf("boxed array: length %d exceeds the maximum of 65535 for interface-boxed arrays", length)
 	}
-	elemField, err := encodeCompositeTypeField(v.Type().Elem())
+	elemField, err := encodeCompositeTypeField(w, v.Type().Elem())
 	if err != nil {
 		if base := stripPointers(v.Type().Elem()); base != emptyStructType {
 			w.missingTypes[base] = struct{}{}
@@ -303,6 +303,38 @@ func (b *MmapBackedBuffer) Release() {
 func LoadFromDiskMmapBacked(filePath string, assetPtr any) (*MmapBackedBuffer, error) {
 	logrus.Infof("Loading compressed asset to mmap buffer: %s...", filePath)
 
+	var (
+		mmapData []byte
+		loadErr  error
+	)
+	tLoad := profiling.TimeIt(func() {
+		mmapData, loadErr = readCompressedToMmap(filePath)
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	// Deserialize (overlay Go headers onto mmap buffer)
+	var deserErr error
+	tDeser := profiling.TimeIt(func() {
+		deserErr = Deserialize(mmapData, assetPtr)
+	})
+	if deserErr != nil {
+		_ = syscall.Munmap(mmapData)
+		return nil, fmt.Errorf("deserialization failed: %w", deserErr)
+	}
+
+	logrus.Infof("Loaded %s to mmap [Size: %s] | Deser: %s | Load+Decomp: %s",
+		filePath, formatSize(len(mmapData)), tDeser, tLoad)
+
+	return &MmapBackedBuffer{data: mmapData}, nil
+}
+
+// readCompressedToMmap decompresses the zstd-compressed asset at filePath into
+// an anonymous mmap buffer sized after the DataSize of its serde header. The
+// caller is responsible for unmapping the returned buffer.
+func readCompressedToMmap(filePath string) ([]byte, error) {
+
 	// 1. Read compressed file from disk (respects IO semaphore)
 	_ = diskReadSemaphore.Acquire(context.Background(), 1)
 	compressedData, err := os.ReadFile(filePath)
@@ -348,35 +380,17 @@ func LoadFromDiskMmapBacked(filePath string, assetPtr any) (*MmapBackedBuffer, e
 	//    region, using only its internal block buffer (~128 KB) on the heap.
 	//    With 4 concurrent loads, this saves ~180 GiB of peak heap compared to
 	//    the previous io.ReadAll + copy approach.
-	var tDecomp time.Duration
-	var decompErr error
-	tDecomp = profiling.TimeIt(func() {
-		decoder, err := zstd.NewReader(bytes.NewReader(compressedData))
-		if err != nil {
-			decompErr = err
-			return
-		}
-		defer decoder.Close()
-		_, decompErr = io.ReadFull(decoder, mmapData)
-	})
-	compressedData = nil // allow GC to collect compressed data
-	if decompErr != nil {
+	decoder, err := zstd.NewReader(bytes.NewReader(compressedData))
+	if err != nil {
 		_ = syscall.Munmap(mmapData)
-		return nil, fmt.Errorf("decompression failed: %w", decompErr)
+		return nil, fmt.Errorf("decompression setup failed: %w", err)
 	}
+	defer decoder.Close()
 
-	// 5. Deserialize (overlay Go headers onto mmap buffer)
-	var deserErr error
-	tDeser := profiling.TimeIt(func() {
-		deserErr = Deserialize(mmapData, assetPtr)
-	})
-	if deserErr != nil {
+	if _, err := io.ReadFull(decoder, mmapData); err != nil {
 		_ = syscall.Munmap(mmapData)
-		return nil, fmt.Errorf("deserialization failed: %w", deserErr)
+		return nil, fmt.Errorf("decompression failed: %w", err)
 	}
 
-	logrus.Infof("Loaded %s to mmap [Size: %s] | Deser: %s | Decomp: %s",
-		filePath, formatSize(len(mmapData)), tDeser, tDecomp)
-
-	return &MmapBackedBuffer{data: mmapData}, nil
+	return mmapData, nil
 }
@@ -0,0 +1,212 @@
+package serde
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// zstdMagic is the magic number opening every zstd frame.
+var zstdMagic = []byte{0x28, 0xB5, 0x2F, 0xFD}
+
+// AssetFormat tells how a serialized asset is laid out on disk.
+type AssetFormat int
+
+const (
+	// AssetFlat is an uncompressed file, memory-mapped as is.
+	AssetFlat AssetFormat = iota
+	// AssetZstd is a zstd-compressed file, as written by StoreToDisk with
+	// compression.
+	AssetZstd
+	// AssetChunked is a directory of lz4 chunks, as written by StoreChunked.
+	AssetChunked
+)
+
+func (f AssetFormat) String() string {
+	switch f {
+	case AssetFlat:
+		return "flat"
+	case AssetZstd:
+		return "zstd"
+	case AssetChunked:
+		return "chunked"
+	default:
+		return fmt.Sprintf("AssetFormat(%d)", int(f))
+	}
+}
+
+// RawAsset is a serialized asset loaded in memory but not deserialized. It is
+// meant for tooling inspecting assets whose Go type is not known in advance.
+type RawAsset struct {
+	Path   string
+	Format AssetFormat
+	// NumChunks is the number of chunks of an AssetChunked asset.
+	NumChunks int
+	// Data is the serialized buffer, starting with its FileHeader.
+	Data []byte
+
+	release func()
+}
+
+// OpenRawAsset loads the serialized asset at path. The format is detected
+// from the file itself: a directory holding a chunk manifest, a zstd frame or
+// an uncompressed buffer which is then memory-mapped. The caller must Close
+// the returned asset once done with it, and with anything deserialized from
+// it.
+func OpenRawAsset(path string) (*RawAsset, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		if !HasChunkedAsset(path) {
+			return nil, fmt.Errorf("%s is a directory but has no %s", path, filepath.Join(path, "manifest"))
+		}
+		data, numChunks, err := readChunkedToMmap(path)
+		if err != nil {
+			return nil, err
+		}
+		return &RawAsset{
+			Path:      path,
+			Format:    AssetChunked,
+			NumChunks: numChunks,
+			Data:      data,
+			release:   func() { _ = syscall.Munmap(data) },
+		}, nil
+	}
+
+	isZstd, err := hasPrefix(path, zstdMagic)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZstd {
+		data, err := readCompressedToMmap(path)
+		if err != nil {
+			return nil, err
+		}
+		return &RawAsset{
+			Path:    path,
+			Format:  AssetZstd,
+			Data:    data,
+			release: func() { _ = syscall.Munmap(data) },
+		}, nil
+	}
+
+	mfile, err := openMappedFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &RawAsset{
+		Path:    path,
+		Format:  AssetFlat,
+		Data:    mfile.Data(),
+		release: func() { _ = mfile.Close() },
+	}, nil
+}
+
+// Close releases the memory backing the asset. Any object deserialized from
+// it becomes invalid.
+func (a *RawAsset) Close() {
+	if a != nil && a.release != nil {
+		a.release()
+		a.release = nil
+		a.Data = nil
+	}
+}
+
+// Header validates and returns the FileHeader of the asset.
+func (a *RawAsset) Header() (*FileHeader, error) {
+	return ValidateHeader(a.Data)
+}
+
+// Decode validates the header of the asset and deserializes it into assetPtr.
+// Unlike Deserialize, it turns the panics raised on malformed buffers into
+// errors, so that it can be used to check that every reference of an
+// untrusted asset resolves within the buffer.
+func (a *RawAsset) Decode(assetPtr any) (err error) {
+
+	if _, err := a.Header(); err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("deserialization panicked: %v", r)
+		}
+	}()
+
+	return Deserialize(a.Data, assetPtr)
+}
+
+// ValidateHeader checks the consistency of the FileHeader at the beginning of
+// b against the buffer itself and returns a copy of it.
+func ValidateHeader(b []byte) (*FileHeader, error) {
+
+	headerSize := SizeOf[FileHeader]()
+	if int64(len(b)) < headerSize {
+		return nil, fmt.Errorf("buffer of %d bytes is too small to contain a header", len(b))
+	}
+
+	header := *(*FileHeader)(unsafe.Pointer(&b[0]))
+
+	if header.Magic != Magic {
+		return nil, fmt.Errorf("invalid magic bytes 0x%08X, expected 0x%08X", header.Magic, Magic)
+	}
+
+	// Serialize only ever writes payload type 0.
+	if header.Version < legacyFormatVersion || header.Version > currentFormatVersion {
+		return nil, fmt.Errorf("unsupported format version %d", header.Version)
+	}
+
+	if header.PayloadType != 0 {
+		return nil, fmt.Errorf("unsupported payload type %d", header.PayloadType)
+	}
+
+	if header.DataSize != int64(len(b)) {
+		return nil, fmt.Errorf("header announces %d bytes but the buffer has %d", header.DataSize, len(b))
+	}
+
+	// The data section of the assets with a schema starts after the Ref to
+	// their schema descriptor.
+	dataStart := headerSize
+	if header.Version >= schemaFormatVersion {
+		dataStart += 8
+		if header.DataSize < dataStart {
+			return nil, fmt.Errorf("buffer of %d bytes is too small to contain the schema reference", len(b))
+		}
+		if ref := *(*Ref)(unsafe.Pointer(&b[headerSize])); int64(ref) < dataStart || int64(ref) >= header.DataSize {
+			return nil, fmt.Errorf("schema offset %d is outside of the data section [%d, %d)", ref, dataStart, header.DataSize)
+		}
+	}
+
+	if off := Ref(header.PayloadOff); !off.IsNull() && (int64(off) < dataStart || int64(off) >= header.DataSize) {
+		return nil, fmt.Errorf("root payload offset %d is outside of the data section [%d, %d)", off, dataStart, header.DataSize)
+	}
+
+	return &header, nil
+}
+
+// hasPrefix reports whether the file at path starts with prefix.
+func hasPrefix(path string, prefix []byte) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, len(prefix))
+	if _, err := io.ReadFull(f, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(buf, prefix), nil
+}
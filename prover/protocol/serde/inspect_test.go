@@ -0,0 +1,125 @@
+package serde
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenRawAsset_Formats checks that the three on-disk formats are detected
+// and decode back to the stored value.
+func TestOpenRawAsset_Formats(t *testing.T) {
+	dir := t.TempDir()
+	original := testPayload{A: 7, B: [4]uint64{1, 2, 3, 4}, C: 2.5}
+
+	flatPath := filepath.Join(dir, "flat.bin")
+	require.NoError(t, StoreToDisk(flatPath, original, false))
+
+	zstdPath := filepath.Join(dir, "compressed.bin")
+	require.NoError(t, StoreToDisk(zstdPath, original, true))
+
+	chunkedPath := filepath.Join(dir, "chunked")
+	require.NoError(t, StoreChunked(chunkedPath, original))
+
+	cases := map[string]AssetFormat{
+		flatPath:    AssetFlat,
+		zstdPath:    AssetZstd,
+		chunkedPath: AssetChunked,
+	}
+
+	for path, format := range cases {
+		asset, err := OpenRawAsset(path)
+		require.NoError(t, err, path)
+
+		assert.Equal(t, format, asset.Format, path)
+
+		header, err := asset.Header()
+		require.NoError(t, err, path)
+		assert.Equal(t, int64(len(asset.Data)), header.DataSize, path)
+
+		var loaded testPayload
+		require.NoError(t, asset.Decode(&loaded), path)
+		assert.Equal(t, original, loaded, path)
+
+		asset.Close()
+	}
+}
+
+func TestValidateHeader(t *testing.T) {
+	valid, err := Serialize(testPayload{A: 1})
+	require.NoError(t, err)
+
+	_, err = ValidateHeader(valid)
+	require.NoError(t, err)
+
+	corrupt := func(off int, v uint64) []byte {
+		b := append([]byte{}, valid...)
+		binary.LittleEndian.PutUint64(b[off:], v)
+		return b
+	}
+
+	_, err = ValidateHeader(valid[:16])
+	assert.ErrorContains(t, err, "too small")
+
+	_, err = ValidateHeader(valid[:len(valid)-1])
+	assert.ErrorContains(t, err, "announces")
+
+	// Version sits in the upper half of the first word, after the magic.
+	_, err = ValidateHeader(corrupt(0, uint64(Magic)|uint64(currentFormatVersion+1)<<32))
+	assert.ErrorContains(t, err, "version")
+
+	// The Ref to the schema descriptor follows the header.
+	_, err = ValidateHeader(corrupt(32, uint64(len(valid))))
+	assert.ErrorContains(t, err, "schema offset")
+
+	_, err = ValidateHeader(corrupt(16, uint64(len(valid))))
+	assert.ErrorContains(t, err, "payload offset")
+
+	_, err = ValidateHeader(corrupt(16, 8))
+	assert.ErrorContains(t, err, "payload offset")
+
+	_, err = ValidateHeader(corrupt(16, 32))
+	assert.ErrorContains(t, err, "payload offset")
+}
+
+// TestRawAssetDecode_OutOfBounds checks that a reference pointing past the
+// buffer is reported as an error rather than a crash.
+func TestRawAssetDecode_OutOfBounds(t *testing.T) {
+	type withSlice struct {
+		Vals []uint64
+	}
+
+	b, err := Serialize(withSlice{Vals: []uint64{1, 2, 3}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "asset.bin")
+	require.NoError(t, os.WriteFile(path, b, 0600))
+
+	asset, err := OpenRawAsset(path)
+	require.NoError(t, err)
+	defer asset.Close()
+
+	var ok withSlice
+	require.NoError(t, asset.Decode(&ok))
+	require.Equal(t, []uint64{1, 2, 3}, ok.Vals)
+
+	// The struct body sits at the root offset and holds a Ref to the
+	// FileSlice of Vals; make the slice length overflow the buffer.
+	root := binary.LittleEndian.Uint64(b[16:])
+	fileSlice := binary.LittleEndian.Uint64(b[root:])
+	corrupted := append([]byte{}, b...)
+	binary.LittleEndian.PutUint64(corrupted[fileSlice+8:], 1<<20)
+	corruptedPath := filepath.Join(filepath.Dir(path), "corrupted.bin")
+	require.NoError(t, os.WriteFile(corruptedPath, corrupted, 0600))
+
+	asset2, err := OpenRawAsset(corruptedPath)
+	require.NoError(t, err)
+	defer asset2.Close()
+
+	var bad withSlice
+	assert.Error(t, asset2.Decode(&bad))
+}
@@ -0,0 +1,508 @@
+package serde
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+const (
+	// legacyFormatVersion is the version of the assets written without a
+	// schema. They decode only into types with the exact layout and registry
+	// indexes of the build that wrote them.
+	legacyFormatVersion = uint32(1)
+
+	// schemaFormatVersion is the version of the assets carrying a schema
+	// descriptor. The Ref to the descriptor sits right after the FileHeader.
+	schemaFormatVersion = uint32(2)
+
+	// currentFormatVersion is the version written by Serialize.
+	currentFormatVersion = schemaFormatVersion
+)
+
+// schemaDescriptor records how the types of an asset were laid out when it
+// was serialized, so that the asset can still be decoded after the types or
+// the registry of interface implementations have changed. It is serialized
+// with the asset itself, as a plain value.
+type schemaDescriptor struct {
+	// TypeNames lists the names, as per getPkgPathAndTypeName, of the
+	// concrete types stored in interfaces. The TypeIDs of the interface
+	// headers of the asset index this list instead of IDToType.
+	TypeNames []string
+	// Types holds the layout of every type encoded in the asset. The types
+	// refer to one another by their position in the list.
+	Types []schemaType
+	// Root is the position in Types of the type of the root object, pointers
+	// stripped.
+	Root int64
+}
+
+// schemaType is the layout of a type of an asset.
+type schemaType struct {
+	// Name is empty for unnamed types such as []int or struct{ A int }.
+	Name string
+	Kind string
+	// Elem is the element type of pointers, slices, arrays and maps and Key
+	// the key type of maps.
+	Elem, Key int64
+	// Len is the length of arrays.
+	Len int64
+	// Size is the number of bytes taken by a value of the type when it is
+	// inlined in a struct, an array or a sequence.
+	Size int64
+	// MemSize and POD tell whether the slices of the type were copied as raw
+	// memory, with elements MemSize bytes apart.
+	MemSize int64
+	POD     bool
+	// Fields lists the serialized fields of structs, in order.
+	Fields []schemaField
+}
+
+type schemaField struct {
+	Name string
+	Type int64
+}
+
+// schemaBuilder records the schema descriptor of an asset while it is
+// encoded.
+type schemaBuilder struct {
+	desc    schemaDescriptor
+	types   map[reflect.Type]int64
+	typeIDs map[reflect.Type]uint16
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		types:   make(map[reflect.Type]int64),
+		typeIDs: make(map[reflect.Type]uint16),
+	}
+}
+
+// record adds the layout of t, and of every type it is made of, to the
+// descriptor and returns its position.
+func (s *schemaBuilder) record(t reflect.Type) int64 {
+	if pos, ok := s.types[t]; ok {
+		return pos
+	}
+
+	// The position is reserved before recursing, which ends the cycles of
+	// recursive types.
+	pos := int64(len(s.desc.Types))
+	s.types[t] = pos
+	s.desc.Types = append(s.desc.Types, schemaType{})
+
+	info := getTypeInfo(t)
+	st := schemaType{
+		Name:    schemaTypeName(t),
+		Kind:    t.Kind().String(),
+		Size:    info.binSize,
+		MemSize: int64(t.Size()),
+		POD:     info.isPOD,
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice:
+		st.Elem = s.record(t.Elem())
+	case reflect.Array:
+		st.Len = int64(t.Len())
+		st.Elem = s.record(t.Elem())
+	case reflect.Map:
+		st.Key = s.record(t.Key())
+		st.Elem = s.record(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); isSerializedField(f) {
+				st.Fields = append(st.Fields, schemaField{Name: f.Name, Type: s.record(f.Type)})
+			}
+		}
+	}
+
+	s.desc.Types[pos] = st
+	return pos
+}
+
+// typeID returns the TypeID under which the registered type t is written in
+// the interface headers of the asset. The IDs are assigned in order of first
+// use.
+func (s *schemaBuilder) typeID(t reflect.Type) uint16 {
+	if id, ok := s.typeIDs[t]; ok {
+		return id
+	}
+	id := uint16(len(s.desc.TypeNames))
+	s.typeIDs[t] = id
+	s.desc.TypeNames = append(s.desc.TypeNames, getPkgPathAndTypeName(t))
+	return id
+}
+
+// schemaTypeName returns the name under which the layout of t is recorded in
+// a schema, or an empty string if t is not a named type.
+func schemaTypeName(t reflect.Type) string {
+	switch {
+	case t.Name() == "":
+		return ""
+	case t.PkgPath() == "":
+		// Predeclared types such as int or string.
+		return t.Name()
+	default:
+		return getPkgPathAndTypeName(t)
+	}
+}
+
+// isSerializedField tells whether the field f is written by the encoder.
+func isSerializedField(f reflect.StructField) bool {
+	return f.IsExported() && !strings.Contains(f.Tag.Get(serdeStructTag), serdeStructTagOmit)
+}
+
+// renamedTypes maps the names of the types as recorded in older assets to the
+// types they have been renamed or moved to.
+var renamedTypes = map[string]reflect.Type{}
+
+// RegisterRenamedType declares that the type of instance was known as oldName,
+// in the "path/to/package#Type" form of the schema descriptors, when older
+// assets were serialized. Those assets can then still be decoded after the
+// type has been renamed or moved to another package. Pointers are stripped
+// from the type of instance. This function is meant to be called from init
+// functions.
+func RegisterRenamedType(oldName string, instance any) {
+	if instance == nil {
+		panic("serde: RegisterRenamedType called with a nil instance")
+	}
+	renamedTypes[oldName] = stripPointers(reflect.TypeOf(instance))
+}
+
+var (
+	registeredByNameOnce sync.Once
+	registeredByName     map[string]reflect.Type
+)
+
+// lookupRegisteredType returns the type registered for interfaces under the
+// given name, either its current name or a name it had before being renamed.
+func lookupRegisteredType(name string) (reflect.Type, bool) {
+	registeredByNameOnce.Do(func() {
+		registeredByName = make(map[string]reflect.Type, len(IDToType))
+		for _, t := range IDToType {
+			if t != nil {
+				registeredByName[getPkgPathAndTypeName(t)] = t
+			}
+		}
+	})
+
+	if t, ok := registeredByName[name]; ok {
+		return t, true
+	}
+	if t, ok := renamedTypes[name]; ok {
+		if _, registered := TypeToID[t]; registered {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// decodeSchema is the schema descriptor of an asset, resolved against the
+// types of the current build.
+type decodeSchema struct {
+	desc schemaDescriptor
+	// typeIDs maps the TypeIDs of the asset to the registered types, nil for
+	// the names no longer registered.
+	typeIDs []reflect.Type
+	// layouts indexes the named struct types of desc by name.
+	layouts map[string]int64
+
+	sameLayoutMemo map[reflect.Type]bool
+	binSizeMemo    map[reflect.Type]int64
+	matchMemo      map[schemaMatchKey]bool
+}
+
+type schemaMatchKey struct {
+	pos int64
+	t   reflect.Type
+}
+
+// readSchema decodes the schema descriptor of the asset b, whose header
+// announces schemaFormatVersion.
+func readSchema(b []byte) (*decodeSchema, error) {
+
+	slot := SizeOf[FileHeader]()
+	if int64(len(b)) < slot+8 {
+		return nil, fmt.Errorf("buffer too small to contain the schema reference")
+	}
+
+	ref := *(*Ref)(unsafe.Pointer(&b[slot]))
+	if ref.IsNull() || int64(ref) < slot+8 || int64(ref) >= int64(len(b)) {
+		return nil, fmt.Errorf("schema reference %d out of bounds", ref)
+	}
+
+	s := &decodeSchema{
+		layouts:        make(map[string]int64),
+		sameLayoutMemo: make(map[reflect.Type]bool),
+		binSizeMemo:    make(map[reflect.Type]int64),
+		matchMemo:      make(map[schemaMatchKey]bool),
+	}
+
+	// The descriptor only uses built-in types, so it is decoded without a
+	// schema.
+	dec := &decoder{data: b, ptrMap: make(map[int64]reflect.Value)}
+	if err := dec.decode(reflect.ValueOf(&s.desc).Elem(), int64(ref)); err != nil {
+		return nil, fmt.Errorf("could not decode the schema: %w", err)
+	}
+
+	numTypes := int64(len(s.desc.Types))
+	for pos, st := range s.desc.Types {
+		for _, p := range []int64{st.Elem, st.Key} {
+			if p < 0 || p >= numTypes {
+				return nil, fmt.Errorf("schema type %d refers to type %d out of range", pos, p)
+			}
+		}
+		for _, f := range st.Fields {
+			if f.Type < 0 || f.Type >= numTypes {
+				return nil, fmt.Errorf("schema type %d refers to type %d out of range", pos, f.Type)
+			}
+		}
+		if st.Kind == reflect.Struct.String() && st.Name != "" {
+			s.layouts[st.Name] = int64(pos)
+		}
+	}
+	if s.desc.Root < 0 || s.desc.Root >= numTypes {
+		return nil, fmt.Errorf("schema root type %d out of range", s.desc.Root)
+	}
+
+	s.typeIDs = make([]reflect.Type, len(s.desc.TypeNames))
+	for id, name := range s.desc.TypeNames {
+		s.typeIDs[id], _ = lookupRegisteredType(name)
+	}
+
+	return s, nil
+}
+
+// typeByID returns the registered type behind the TypeID of an interface
+// header of the asset.
+func (dec *decoder) typeByID(id uint16) (reflect.Type, error) {
+	if dec.schema == nil {
+		if int(id) >= len(IDToType) {
+			return nil, fmt.Errorf("invalid type ID: %d", id)
+		}
+		return IDToType[id], nil
+	}
+
+	if int(id) >= len(dec.schema.typeIDs) {
+		return nil, fmt.Errorf("invalid type ID: %d (the asset has %d)", id, len(dec.schema.typeIDs))
+	}
+	t := dec.schema.typeIDs[id]
+	if t == nil {
+		return nil, fmt.Errorf("type %q stored in the asset is no longer registered; restore it or declare its new name with RegisterRenamedType", dec.schema.desc.TypeNames[id])
+	}
+	return t, nil
+}
+
+// layoutOf returns the position of the recorded layout of the struct type t,
+// looked up by its current name or a former one.
+func (s *decodeSchema) layoutOf(t reflect.Type) (int64, bool) {
+	if pos, ok := s.layouts[schemaTypeName(t)]; ok {
+		return pos, true
+	}
+	for oldName, renamed := range renamedTypes {
+		if renamed == t {
+			if pos, ok := s.layouts[oldName]; ok {
+				return pos, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// sameLayout tells whether values of type t are laid out in the asset as the
+// current build would lay them out, in which case the raw memory copies of
+// the decoder apply. Only structs and arrays, which are inlined in their
+// parent, can differ: the other types are reached through a Ref and are
+// checked on their own. A struct without a recorded layout is assumed
+// unchanged.
+func (dec *decoder) sameLayout(t reflect.Type) bool {
+	if dec.schema == nil {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Array:
+	default:
+		return true
+	}
+
+	s := dec.schema
+	if res, ok := s.sameLayoutMemo[t]; ok {
+		return res
+	}
+	// Recursive types go through a Ref, hence the optimistic value while
+	// recursing.
+	s.sameLayoutMemo[t] = true
+
+	res := true
+	if t.Kind() == reflect.Array {
+		res = dec.sameLayout(t.Elem())
+	} else if pos, ok := s.layoutOf(t); ok {
+		var (
+			old = s.desc.Types[pos]
+			i   = 0
+		)
+		for j := 0; j < t.NumField() && res; j++ {
+			f := t.Field(j)
+			if !isSerializedField(f) {
+				continue
+			}
+			res = i < len(old.Fields) &&
+				old.Fields[i].Name == f.Name &&
+				s.typesMatch(old.Fields[i].Type, f.Type) &&
+				dec.sameLayout(f.Type)
+			i++
+		}
+		res = res && i == len(old.Fields)
+	}
+
+	s.sameLayoutMemo[t] = res
+	return res
+}
+
+// binSize returns the number of bytes taken in the asset by a value of type t
+// inlined in its parent.
+func (dec *decoder) binSize(t reflect.Type) int64 {
+	if dec.sameLayout(t) {
+		return getTypeInfo(t).binSize
+	}
+
+	s := dec.schema
+	if size, ok := s.binSizeMemo[t]; ok {
+		return size
+	}
+
+	var size int64
+	if t.Kind() == reflect.Array {
+		size = int64(t.Len()) * dec.binSize(t.Elem())
+	} else {
+		pos, _ := s.layoutOf(t)
+		size = s.desc.Types[pos].Size
+	}
+
+	s.binSizeMemo[t] = size
+	return size
+}
+
+// checkRawLayout returns an error if the values of type t, whose layout
+// changed, were copied as raw memory with padding between the fields. The
+// serialized fields are then not where the recorded layout places them.
+func (dec *decoder) checkRawLayout(t reflect.Type) error {
+	for t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	pos, ok := dec.schema.layoutOf(t)
+	if !ok {
+		return nil
+	}
+	if old := dec.schema.desc.Types[pos]; old.POD && old.MemSize != old.Size {
+		return fmt.Errorf("the layout of %v changed and the asset stores it as raw memory with padding, it cannot be remapped", t)
+	}
+	return nil
+}
+
+// typesMatch tells whether a value recorded with the type at position pos of
+// the schema can be decoded into a value of type t. The named struct types
+// match by name only, their fields are remapped when decoding them.
+func (s *decodeSchema) typesMatch(pos int64, t reflect.Type) bool {
+
+	key := schemaMatchKey{pos: pos, t: t}
+	if res, ok := s.matchMemo[key]; ok {
+		return res
+	}
+	s.matchMemo[key] = true
+
+	old := s.desc.Types[pos]
+	res := old.Kind == t.Kind().String()
+
+	if res && (old.Name != "" || t.Name() != "") {
+		res = old.Name == schemaTypeName(t) || renamedTypes[old.Name] == t
+	}
+
+	if res && !(t.Kind() == reflect.Struct && t.Name() != "") {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice:
+			res = s.typesMatch(old.Elem, t.Elem())
+		case reflect.Array:
+			res = old.Len == int64(t.Len()) && s.typesMatch(old.Elem, t.Elem())
+		case reflect.Map:
+			res = s.typesMatch(old.Key, t.Key()) && s.typesMatch(old.Elem, t.Elem())
+		case reflect.Struct:
+			res = s.fieldsMatch(old, t)
+		}
+	}
+
+	s.matchMemo[key] = res
+	return res
+}
+
+// fieldsMatch compares the fields of an unnamed struct type, which cannot be
+// remapped as they have no recorded layout of their own.
+func (s *decodeSchema) fieldsMatch(old schemaType, t reflect.Type) bool {
+	i := 0
+	for j := 0; j < t.NumField(); j++ {
+		f := t.Field(j)
+		if !isSerializedField(f) {
+			continue
+		}
+		if i >= len(old.Fields) || old.Fields[i].Name != f.Name || !s.typesMatch(old.Fields[i].Type, f.Type) {
+			return false
+		}
+		i++
+	}
+	return i == len(old.Fields)
+}
+
+// typeString names the type at position pos of the schema in the error
+// messages.
+func (s *decodeSchema) typeString(pos int64) string {
+	old := s.desc.Types[pos]
+	if old.Name != "" {
+		return old.Name
+	}
+	return old.Kind
+}
+
+// decodeStructRemapped decodes a struct whose layout changed since the asset
+// was serialized. The recorded fields are matched with the current ones by
+// name: the removed fields are skipped and the new ones are set to their zero
+// value. A field whose type changed in an incompatible way is an error.
+func (dec *decoder) decodeStructRemapped(target reflect.Value, offset int64) error {
+
+	t := target.Type()
+	s := dec.schema
+	pos, _ := s.layoutOf(t)
+	old := s.desc.Types[pos]
+
+	decoded := make([]bool, t.NumField())
+	currentOffSet := offset
+	for _, of := range old.Fields {
+		oldSize := s.desc.Types[of.Type].Size
+
+		tf, ok := t.FieldByName(of.Name)
+		if !ok || len(tf.Index) != 1 || !isSerializedField(tf) {
+			currentOffSet += oldSize
+			continue
+		}
+
+		if !s.typesMatch(of.Type, tf.Type) {
+			return fmt.Errorf("field %v.%s changed from %s to %v and cannot be decoded", t, of.Name, s.typeString(of.Type), tf.Type)
+		}
+
+		if _, err := dec.decodeSeqItem(target.Field(tf.Index[0]), currentOffSet); err != nil {
+			return fmt.Errorf("failed to decode field '%s': %w", of.Name, err)
+		}
+		decoded[tf.Index[0]] = true
+		currentOffSet += oldSize
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if !decoded[i] && isSerializedField(t.Field(i)) {
+			target.Field(i).Set(reflect.Zero(t.Field(i).Type))
+		}
+	}
+	return nil
+}
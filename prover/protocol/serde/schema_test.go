@@ -0,0 +1,166 @@
+package serde
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The assets of testdata/compat were serialized with the "V1" layouts below
+// and are decoded into their current versions. The version 2 assets are
+// regenerated by running with -update; the legacy one was written by the last
+// build without schema descriptors and cannot be regenerated.
+//
+//	go test ./protocol/serde/ -run TestCompatCorpus -update
+
+type compatInnerV1 struct {
+	A uint64
+}
+
+type compatAssetV1 struct {
+	Name   string
+	Round  int
+	Values []uint64
+	Inner  compatInnerV1
+	Items  []compatInnerV1
+	Ptr    *compatInnerV1
+	Legacy uint32
+}
+
+// compatInner adds B to compatInnerV1.
+type compatInner struct {
+	A uint64
+	B string
+}
+
+// compatAsset moves Round, drops Legacy and adds Extra.
+type compatAsset struct {
+	Name   string
+	Values []uint64
+	Round  int
+	Inner  compatInner
+	Items  []compatInner
+	Ptr    *compatInner
+	Extra  []string
+}
+
+type compatRetypedV1 struct {
+	Name  string
+	Round int
+}
+
+// compatRetyped changes the type of Round, which cannot be remapped.
+type compatRetyped struct {
+	Name  string
+	Round string
+}
+
+func init() {
+	RegisterRenamedType("/protocol/serde#compatInnerV1", compatInner{})
+	RegisterRenamedType("/protocol/serde#compatAssetV1", compatAsset{})
+	RegisterRenamedType("/protocol/serde#compatRetypedV1", compatRetyped{})
+}
+
+var compatAssetV1Value = compatAssetV1{
+	Name:   "asset",
+	Round:  3,
+	Values: []uint64{1, 2, 3},
+	Inner:  compatInnerV1{A: 4},
+	Items:  []compatInnerV1{{A: 5}, {A: 6}},
+	Ptr:    &compatInnerV1{A: 7},
+	Legacy: 8,
+}
+
+// readCompatAsset returns the corpus asset of the given name, after writing it
+// from written when running with -update or when it is missing. A nil written
+// means the asset cannot be regenerated.
+func readCompatAsset(t *testing.T, name string, written any) []byte {
+	path := filepath.Join(testdataDir, "compat", name)
+
+	if written != nil && (*update || !fileExists(path)) {
+		b, err := Serialize(written)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, b, 0o600))
+		t.Logf("wrote %s", path)
+	}
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return b
+}
+
+func TestCompatCorpus(t *testing.T) {
+
+	t.Run("legacy", func(t *testing.T) {
+		b := readCompatAsset(t, "v1-legacy.bin", nil)
+
+		header, err := ValidateHeader(b)
+		require.NoError(t, err)
+		require.Equal(t, legacyFormatVersion, header.Version)
+
+		var decoded compatAssetV1
+		require.NoError(t, Deserialize(b, &decoded))
+		assert.Equal(t, compatAssetV1Value, decoded)
+	})
+
+	t.Run("changed-layout", func(t *testing.T) {
+		b := readCompatAsset(t, "v2-changed-layout.bin", compatAssetV1Value)
+
+		var decoded compatAsset
+		require.NoError(t, Deserialize(b, &decoded))
+		assert.Equal(t, compatAsset{
+			Name:   "asset",
+			Values: []uint64{1, 2, 3},
+			Round:  3,
+			Inner:  compatInner{A: 4},
+			Items:  []compatInner{{A: 5}, {A: 6}},
+			Ptr:    &compatInner{A: 7},
+		}, decoded)
+	})
+
+	t.Run("retyped-field", func(t *testing.T) {
+		b := readCompatAsset(t, "v2-retyped-field.bin", compatRetypedV1{Name: "asset", Round: 3})
+
+		var decoded compatRetyped
+		err := Deserialize(b, &decoded)
+		assert.ErrorContains(t, err, "field serde.compatRetyped.Round changed from int to string")
+	})
+}
+
+func TestDeserialize_OtherRootType(t *testing.T) {
+	b, err := Serialize(compatRetypedV1{Name: "asset", Round: 3})
+	require.NoError(t, err)
+
+	var decoded compatInner
+	assert.ErrorContains(t, Deserialize(b, &decoded), "cannot be decoded into")
+}
+
+func TestDeserialize_InterfaceTypeIDs(t *testing.T) {
+	type boxes struct {
+		A, B any
+	}
+
+	original := boxes{A: "text", B: 42}
+	b, err := Serialize(original)
+	require.NoError(t, err)
+
+	// The TypeIDs of the asset index its own list of type names, in order of
+	// first use, rather than IDToType.
+	schema, err := readSchema(b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"#string", "#int"}, schema.desc.TypeNames)
+
+	var decoded boxes
+	require.NoError(t, Deserialize(b, &decoded))
+	assert.Equal(t, original, decoded)
+
+	// A type that is no longer registered is reported by name.
+	require.Equal(t, 1, bytes.Count(b, []byte("#string")))
+	unknown := bytes.Replace(b, []byte("#string"), []byte("#strinx"), 1)
+	assert.ErrorContains(t, Deserialize(unknown, &decoded), `type "#strinx" stored in the asset is no longer registered`)
+}
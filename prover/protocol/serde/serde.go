@@ -19,14 +19,21 @@ var (
 
 // Serialize transforms a Go object into a binary-packed byte slice.
 // The resulting slice is structured with a metadata header at the beginning,
-// followed by a linearized heap of data.
+// followed by a linearized heap of data. The heap ends with the schema
+// descriptor of the encoded types, referenced right after the header, which
+// lets Deserialize decode the slice after the types have changed.
 func Serialize(v any) ([]byte, error) {
 	enc := newEncoder()
+	enc.schema = newSchemaBuilder()
 
 	// Reserve space for the FileHeader at the start of the buffer (Offset 0).
 	// We will come back and fill this in once we know the final PayloadOffset and DataSize.
 	_ = enc.write(FileHeader{})
 
+	// Reserve the Ref to the schema descriptor, which is only known once the
+	// whole object graph has been encoded.
+	schemaSlot := enc.write(Ref(0))
+
 	// Start the recursive serialization of the object graph.
 	rootOff, err := encode(enc, reflect.ValueOf(v))
 	if err != nil {
@@ -48,10 +55,23 @@ func Serialize(v any) ([]byte, error) {
 		)
 	}
 
+	// Encode the schema descriptor as a plain value, without recording its own
+	// types.
+	schema := enc.schema
+	if t := reflect.TypeOf(v); t != nil {
+		schema.desc.Root = schema.record(stripPointers(t))
+	}
+	enc.schema = nil
+	schemaOff, err := encode(enc, reflect.ValueOf(&schema.desc))
+	if err != nil {
+		return nil, fmt.Errorf("could not encode the schema: %w", err)
+	}
+	enc.patch(schemaSlot, schemaOff)
+
 	// Construct the final metadata. PayloadOff points to the "Root" object.
 	finalHeader := FileHeader{
 		Magic:       Magic,
-		Version:     1,
+		Version:     currentFormatVersion,
 		PayloadType: 0,
 		PayloadOff:  int64(rootOff),
 		DataSize:    enc.offset,
@@ -117,6 +137,25 @@ func Deserialize(b []byte, v any) error {
 		ptrMap: make(map[int64]reflect.Value),
 	}
 
+	// The legacy assets are decoded as is. The newer ones come with the
+	// layouts they were written with, which the decoder maps onto the
+	// current types.
+	switch header.Version {
+	case legacyFormatVersion:
+	case schemaFormatVersion:
+		schema, err := readSchema(b)
+		if err != nil {
+			return err
+		}
+		target := stripPointers(val.Type())
+		if target.Kind() != reflect.Interface && !schema.typesMatch(schema.desc.Root, target) {
+			return fmt.Errorf("the asset holds a %s and cannot be decoded into a %v", schema.typeString(schema.desc.Root), target)
+		}
+		dec.schema = schema
+	default:
+		return fmt.Errorf("unsupported format version %d, expected at most %d", header.Version, currentFormatVersion)
+	}
+
 	// Begin the "Pointer Swizzling" process starting from the PayloadOff.
 	return dec.decode(val.Elem(), int64(header.PayloadOff))
 }
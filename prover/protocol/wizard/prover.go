@@ -1123,6 +1123,7 @@ func (run *ProverRuntime) InsertCoin(name coin.Name, value any) {
 func (runtime *ProverRuntime) exec(name string, action any) {
 
 	t := time.Now()
+	profiling.Heartbeat(name)
 
 	defer func() {
 		totalTime := time.Since(t)
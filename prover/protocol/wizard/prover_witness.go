@@ -0,0 +1,94 @@
+package wizard
+
+import (
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/utils"
+)
+
+// ProverWitness is the assignment produced by a [MainProverStep] on its own,
+// without any of the compiler's prover actions. It can be serialized and
+// replayed by another process, which then does the cryptographic part of the
+// proving. This allows running the witness generation and the proving on
+// different machines.
+//
+// The witness only depends on the columns and queries declared before the
+// compilation. It can thus be generated with a cheaply compiled version of
+// the protocol, for instance with the dummy compiler, and replayed against
+// the fully compiled one.
+type ProverWitness struct {
+	// Columns are the columns assigned by the main prover step. The
+	// precomputed columns are not included as they are part of the
+	// [CompiledIOP].
+	Columns map[ifaces.ColID]ifaces.ColAssignment
+	// QueriesParams are the query parameters assigned by the main prover
+	// step.
+	QueriesParams map[ifaces.QueryID]ifaces.QueryParams
+}
+
+// RunHighLevelProver runs the provided [MainProverStep] over a fresh
+// [ProverRuntime] for c and returns what it assigned. None of the prover
+// actions registered by the compilers are run. The function panics if the
+// step assigns anything past the first round as the returned witness could
+// not be replayed without the Fiat-Shamir randomness.
+func RunHighLevelProver(c *CompiledIOP, highLevelProver MainProverStep, IsBLS bool) *ProverWitness {
+
+	run := c.createProver(IsBLS)
+	run.HighLevelProver = highLevelProver
+	run.exec("high-level-prover", mainProverStepWrapper{step: highLevelProver})
+
+	res := &ProverWitness{
+		Columns:       make(map[ifaces.ColID]ifaces.ColAssignment, run.Columns.Len()),
+		QueriesParams: make(map[ifaces.QueryID]ifaces.QueryParams, run.QueriesParams.Len()),
+	}
+
+	for name, val := range run.Columns.GetInnerMap() {
+		if c.Precomputed.Exists(name) {
+			continue
+		}
+		if round := c.Columns.GetHandle(name).Round(); round != 0 {
+			utils.Panic("the main prover step assigned column %v at round %v", name, round)
+		}
+		res.Columns[name] = val
+	}
+
+	for name, val := range run.QueriesParams.GetInnerMap() {
+		if round := c.QueriesParams.Round(name); round != 0 {
+			utils.Panic("the main prover step assigned query %v at round %v", name, round)
+		}
+		res.QueriesParams[name] = val
+	}
+
+	return res
+}
+
+// MainProverStep returns a [MainProverStep] assigning the content of the
+// witness. The returned step panics if one of the columns or queries of the
+// witness is not registered in the [CompiledIOP] being proven, or does not
+// match its registration.
+func (w *ProverWitness) MainProverStep() MainProverStep {
+	return func(run *ProverRuntime) {
+
+		run.lock.Lock()
+		defer run.lock.Unlock()
+
+		for name, val := range w.Columns {
+			if !run.Spec.Columns.Exists(name) {
+				utils.Panic("witness column %v is not registered in the compiled IOP", name)
+			}
+			handle := run.Spec.Columns.GetHandle(name)
+			if handle.Round() != run.currRound || handle.Size() != val.Len() {
+				utils.Panic("witness column %v does not match its registration: round=%v size=%v, got size=%v at round %v",
+					name, handle.Round(), handle.Size(), val.Len(), run.currRound)
+			}
+			run.Columns.InsertNew(name, val)
+		}
+
+		for name, val := range w.QueriesParams {
+			if !run.Spec.QueriesParams.Exists(name) {
+				utils.Panic("witness query %v is not registered in the compiled IOP", name)
+			}
+			run.Spec.QueriesParams.MustBeInRound(run.currRound, name)
+			run.QueriesParams.InsertNew(name, val)
+		}
+	}
+}
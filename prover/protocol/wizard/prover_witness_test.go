@@ -0,0 +1,66 @@
+package wizard_test
+
+import (
+	"testing"
+
+	"github.com/consensys/linea-monorepo/prover/maths/common/smartvectors"
+	"github.com/consensys/linea-monorepo/prover/maths/field"
+	"github.com/consensys/linea-monorepo/prover/protocol/compiler/dummy"
+	"github.com/consensys/linea-monorepo/prover/protocol/ifaces"
+	"github.com/consensys/linea-monorepo/prover/protocol/serde"
+	"github.com/consensys/linea-monorepo/prover/protocol/wizard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProverWitness(t *testing.T) {
+
+	var (
+		P ifaces.ColID   = "P"
+		Q ifaces.ColID   = "Q"
+		U ifaces.QueryID = "U"
+	)
+
+	define := func(build *wizard.Builder) {
+		p := build.RegisterCommit(P, SIZE)
+		build.RegisterPrecomputed(Q, smartvectors.ForTest(1, 1, 1, 1))
+		build.LocalOpening(U, p)
+	}
+
+	prover := func(run *wizard.ProverRuntime) {
+		run.AssignColumn(P, smartvectors.ForTest(3, 2, 1, 0))
+		run.AssignLocalPoint(U, field.NewElement(3))
+	}
+
+	compiled := wizard.Compile(define, dummy.Compile)
+	witness := wizard.RunHighLevelProver(compiled, prover, false)
+
+	require.Len(t, witness.Columns, 1)
+	require.Contains(t, witness.Columns, P)
+	require.Contains(t, witness.QueriesParams, U)
+
+	// The witness is meant to be replayed by another process
+	b, err := serde.Serialize(witness)
+	require.NoError(t, err)
+
+	var decoded wizard.ProverWitness
+	require.NoError(t, serde.Deserialize(b, &decoded))
+
+	proof := wizard.Prove(compiled, decoded.MainProverStep(), false)
+	require.NoError(t, wizard.Verify(compiled, proof, false))
+
+	// The witness does not depend on the compilation and can be replayed
+	// against another compilation of the same protocol.
+	other := wizard.Compile(define, dummy.CompileAtProverLvl())
+	proof = wizard.Prove(other, decoded.MainProverStep(), false)
+	require.NoError(t, wizard.Verify(other, proof, false))
+
+	// But not against a protocol declaring its columns differently.
+	resized := wizard.Compile(func(build *wizard.Builder) {
+		p := build.RegisterCommit(P, 2*SIZE)
+		build.RegisterPrecomputed(Q, smartvectors.ForTest(1, 1, 1, 1, 1, 1, 1, 1))
+		build.LocalOpening(U, p)
+	}, dummy.Compile)
+	require.Panics(t, func() {
+		wizard.Prove(resized, decoded.MainProverStep(), false)
+	})
+}
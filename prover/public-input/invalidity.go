@@ -124,3 +124,89 @@ func (pi *Invalidity) SumAsField() fr377.Element {
 	sum := new(fr377.Element).SetBytes(sumBytes)
 	return *sum
 }
+
+// InvalidityBatch represents the functional public inputs of a batched
+// invalidity proof, covering the consecutive forced transactions FirstTxNumber
+// to LastTxNumber. The rolling hash is chained internally from
+// PrevFtxRollingHash, so only its end points are exposed. All the transactions
+// of the batch are proven against the same state and simulated block.
+type InvalidityBatch struct {
+	FirstTxNumber      uint64
+	LastTxNumber       uint64
+	PrevFtxRollingHash types.Bls12377Fr    // the rolling hash before the first forced transaction of the batch
+	FtxRollingHash     types.Bls12377Fr    // the rolling hash after the last forced transaction of the batch
+	StateRootHash      types.KoalaOctuplet // state-root-hash on which the invalidity is based
+
+	// From execution PI (shared between execution and invalidity)
+	CoinBase                types.EthAddress
+	BaseFee                 uint64
+	ChainID                 uint64
+	L2MessageServiceAddr    types.EthAddress
+	SimulatedBlockTimestamp uint64
+	SimulatedBlockNumber    uint64
+}
+
+// Sum compute the Poseidon2 hash over the functional public inputs
+func (pi *InvalidityBatch) Sum(hsh hash.Hash) []byte {
+	if hsh == nil {
+		hsh = gchash.POSEIDON2_BLS12_377.New()
+	}
+	stateRootHash := pi.StateRootHash.ToBytes()
+	hsh.Reset()
+	_, err := writeNum(hsh, pi.FirstTxNumber)
+	if err != nil {
+		panic(err)
+	}
+	_, err = writeNum(hsh, pi.LastTxNumber)
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(pi.PrevFtxRollingHash[:])
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(pi.FtxRollingHash[:])
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(stateRootHash[:16])
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(stateRootHash[16:])
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(pi.CoinBase[:])
+	if err != nil {
+		panic(err)
+	}
+	_, err = writeNum(hsh, pi.BaseFee)
+	if err != nil {
+		panic(err)
+	}
+	_, err = writeNum(hsh, pi.ChainID)
+	if err != nil {
+		panic(err)
+	}
+	_, err = hsh.Write(pi.L2MessageServiceAddr[:])
+	if err != nil {
+		panic(err)
+	}
+	_, err = writeNum(hsh, pi.SimulatedBlockTimestamp)
+	if err != nil {
+		panic(err)
+	}
+	_, err = writeNum(hsh, pi.SimulatedBlockNumber)
+	if err != nil {
+		panic(err)
+	}
+
+	return hsh.Sum(nil)
+}
+
+func (pi *InvalidityBatch) SumAsField() fr377.Element {
+	sumBytes := pi.Sum(nil)
+	sum := new(fr377.Element).SetBytes(sumBytes)
+	return *sum
+}
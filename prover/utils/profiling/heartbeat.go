@@ -0,0 +1,45 @@
+package profiling
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/linea-monorepo/prover/config"
+	"github.com/sirupsen/logrus"
+)
+
+// heartbeatMinInterval rate-limits the writes of the heartbeat file as the
+// wizard may run many steps per second.
+const heartbeatMinInterval = time.Second
+
+var (
+	// heartbeatFile is set by the controller. Heartbeats are disabled if empty.
+	heartbeatFile = os.Getenv(config.HeartbeatFileEnv)
+	// lastHeartbeat is the unix time in nanoseconds of the last write
+	lastHeartbeat atomic.Int64
+)
+
+// Heartbeat tells the watchdog of the controller that the prover is still
+// progressing by writing the name of the current stage in the heartbeat file.
+// It is a no-op if the prover is not run by a controller with a watchdog and
+// is safe for concurrent use.
+func Heartbeat(stage string) {
+
+	if heartbeatFile == "" {
+		return
+	}
+
+	var (
+		now  = time.Now().UnixNano()
+		last = lastHeartbeat.Load()
+	)
+
+	if now-last < int64(heartbeatMinInterval) || !lastHeartbeat.CompareAndSwap(last, now) {
+		return
+	}
+
+	if err := os.WriteFile(heartbeatFile, []byte(stage+"\n"), 0o600); err != nil {
+		logrus.Warnf("could not write the heartbeat file %v: %v", heartbeatFile, err)
+	}
+}
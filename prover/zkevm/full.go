@@ -1,6 +1,7 @@
 package zkevm
 
 import (
+	"slices"
 	"sync"
 
 	"github.com/consensys/go-corset/pkg/ir/mir"
@@ -295,6 +296,18 @@ func FullZkEvmLarge(tl *config.TracesLimits, cfg *config.Config) *ZkEvm {
 	return fullZkEvmLarge
 }
 
+// FullCompilationSuites returns copies of the pre-recursion and
+// post-recursion compilation suites used by [FullZkEvm], or by
+// [FullZkEvmLarge] when large is set. The copies can be instrumented and
+// passed to [FullZKEVMWithSuite].
+func FullCompilationSuites(large bool) (pre, post CompilationSuite) {
+	pre = fullInitialCompilationSuite
+	if large {
+		pre = fullInitialCompilationSuiteLarge
+	}
+	return slices.Clone(pre), slices.Clone(fullSecondCompilationSuite)
+}
+
 func FullZkEVMCheckOnly(tl *config.TracesLimits, cfg *config.Config) *ZkEvm {
 
 	onceFullZkEvmCheckOnly.Do(func() {
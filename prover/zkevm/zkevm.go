@@ -145,11 +145,29 @@ func NewZkEVM(
 // Prove assigns and runs the inner-prover of the zkEVM and then, it returns the
 // inner-proof
 func (z *ZkEvm) ProveInner(input *Witness) wizard.Proof {
+	return z.proveInner(z.GetMainProverStep(input))
+}
+
+// ExportWitness runs the arithmetization, the state-manager and the
+// public-input assignment of the zkEVM for the provided input and returns
+// the resulting assignment. The returned witness can be proven by
+// [ZkEvm.ProveInnerFromWitness], possibly by another process.
+func (z *ZkEvm) ExportWitness(input *Witness) *wizard.ProverWitness {
+	return wizard.RunHighLevelProver(z.InitialCompiledIOP, z.GetMainProverStep(input), z.Recursion == nil)
+}
+
+// ProveInnerFromWitness is as [ZkEvm.ProveInner] but starts from a witness
+// returned by [ZkEvm.ExportWitness].
+func (z *ZkEvm) ProveInnerFromWitness(w *wizard.ProverWitness) wizard.Proof {
+	return z.proveInner(w.MainProverStep())
+}
+
+func (z *ZkEvm) proveInner(mainStep wizard.MainProverStep) wizard.Proof {
 
 	if z.Recursion == nil {
 		return wizard.Prove(
 			z.InitialCompiledIOP,
-			z.GetMainProverStep(input),
+			mainStep,
 			true,
 		)
 	}
@@ -158,7 +176,7 @@ func (z *ZkEvm) ProveInner(input *Witness) wizard.Proof {
 		stoppingRound    = recursion.VortexQueryRound(z.InitialCompiledIOP) + 1
 		initialProverRun = wizard.RunProverUntilRound(
 			z.InitialCompiledIOP,
-			z.GetMainProverStep(input),
+			mainStep,
 			stoppingRound,
 			false,
 		)
@@ -41,6 +41,10 @@ pub fn build(b: *std.Build) void {
             .{ .name = "verifier_ray", .module = verifier_mod },
         },
     });
+    // Binary fixture shared with prover-ray/wiop/wire, consumed via @embedFile.
+    const test_proof_minimal_mod = b.createModule(.{
+        .root_source_file = b.path("testdata/proofs/minimal.bin"),
+    });
     const exe = b.addExecutable(.{
         .name = "verifier-ray",
         .root_module = b.createModule(.{
@@ -85,6 +89,7 @@ pub fn build(b: *std.Build) void {
                     .{ .name = "verifier_ray", .module = verifier_mod },
                     .{ .name = "test_vectors", .module = test_vectors_mod },
                     .{ .name = "test_vanishing", .module = test_vanishing_mod },
+                    .{ .name = "test_proof_minimal", .module = test_proof_minimal_mod },
                 },
             }),
         });
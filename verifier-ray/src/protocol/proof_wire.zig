@@ -0,0 +1,205 @@
+//! Decoder for the wiop proof wire format.
+//!
+//! The format is specified in prover-ray/docs/proof-wire-format.md and
+//! produced by prover-ray's `wiop/wire` package. Decoding only checks that the
+//! bytes are well formed; binding the proof to a compiled system is done by
+//! comparing `Header.system_digest` with the digest emitted by the codegen.
+
+const std = @import("std");
+const base = @import("../field/koalabear.zig");
+const ext = @import("../field/koalabear_ext.zig");
+const value = @import("../field/value.zig");
+
+pub const version: u16 = 1;
+pub const header_bytes: usize = 40;
+const magic = "WIOP";
+
+const tag_base: u8 = 0;
+const tag_ext: u8 = 1;
+
+/// Object kinds, matching prover-ray's `wiop.ObjectKind`.
+const object_kind_column: u8 = 0x01;
+const object_kind_cell: u8 = 0x02;
+
+pub const Error = error{
+    BadMagic,
+    UnsupportedVersion,
+    UnexpectedKind,
+    Truncated,
+    Malformed,
+} || std.mem.Allocator.Error;
+
+pub const Kind = enum(u16) {
+    proof = 1,
+    _,
+};
+
+pub const Header = struct {
+    version: u16,
+    kind: Kind,
+    system_digest: [32]u8,
+};
+
+pub const DynamicSize = struct {
+    module: u32,
+    size: u32,
+};
+
+pub const Column = struct {
+    id: u64,
+    padding: base.Element,
+    values: value.Vector,
+};
+
+pub const Cell = struct {
+    id: u64,
+    value: value.Scalar,
+};
+
+/// A decoded `wiop.Proof`. Entries keep their wire order, i.e. sorted by key.
+pub const Proof = struct {
+    header: Header,
+    dynamic_sizes: []const DynamicSize,
+    columns: []const Column,
+    cells: []const Cell,
+
+    pub fn deinit(self: Proof, allocator: std.mem.Allocator) void {
+        for (self.columns) |col| freeVector(allocator, col.values);
+        allocator.free(self.columns);
+        allocator.free(self.dynamic_sizes);
+        allocator.free(self.cells);
+    }
+};
+
+/// Reads the header at the start of `bytes`. Checks the magic and the version
+/// but not the kind.
+pub fn decodeHeader(bytes: []const u8) Error!Header {
+    if (bytes.len >= magic.len and !std.mem.eql(u8, bytes[0..magic.len], magic)) return error.BadMagic;
+    if (bytes.len < header_bytes) return error.Truncated;
+    const header = Header{
+        .version = std.mem.readInt(u16, bytes[4..6], .big),
+        .kind = @enumFromInt(std.mem.readInt(u16, bytes[6..8], .big)),
+        .system_digest = bytes[8..header_bytes].*,
+    };
+    if (header.version != version) return error.UnsupportedVersion;
+    return header;
+}
+
+/// Decodes a proof payload. The caller owns the result and releases it with
+/// `Proof.deinit`.
+pub fn decodeProof(allocator: std.mem.Allocator, bytes: []const u8) Error!Proof {
+    const header = try decodeHeader(bytes);
+    if (header.kind != .proof) return error.UnexpectedKind;
+
+    var r = Reader{ .bytes = bytes, .offset = header_bytes };
+
+    const dynamic_sizes = try allocator.alloc(DynamicSize, try r.count(8));
+    errdefer allocator.free(dynamic_sizes);
+    for (dynamic_sizes, 0..) |*entry, i| {
+        entry.* = .{ .module = try r.int(u32), .size = try r.int(u32) };
+        if (i > 0 and entry.module <= dynamic_sizes[i - 1].module) return error.Malformed;
+    }
+
+    const columns = try allocator.alloc(Column, try r.count(8 + 1 + base.bytes + 4));
+    var decoded_columns: usize = 0;
+    errdefer {
+        for (columns[0..decoded_columns]) |col| freeVector(allocator, col.values);
+        allocator.free(columns);
+    }
+    for (columns, 0..) |*col, i| {
+        const id = try r.id(object_kind_column, if (i > 0) columns[i - 1].id else null);
+        const tag = try r.tag();
+        const padding = try r.element();
+        const values: value.Vector = switch (tag) {
+            tag_base => blk: {
+                const vals = try allocator.alloc(base.Element, try r.count(base.bytes));
+                errdefer allocator.free(vals);
+                for (vals) |*v| v.* = try r.element();
+                break :blk .{ .base = vals };
+            },
+            else => blk: {
+                const vals = try allocator.alloc(ext.Ext, try r.count(ext.bytes));
+                errdefer allocator.free(vals);
+                for (vals) |*v| v.* = try r.extension();
+                break :blk .{ .ext = vals };
+            },
+        };
+        col.* = .{ .id = id, .padding = padding, .values = values };
+        decoded_columns += 1;
+    }
+
+    const cells = try allocator.alloc(Cell, try r.count(8 + 1 + base.bytes));
+    errdefer allocator.free(cells);
+    for (cells, 0..) |*cell, i| {
+        const id = try r.id(object_kind_cell, if (i > 0) cells[i - 1].id else null);
+        cell.* = .{
+            .id = id,
+            .value = switch (try r.tag()) {
+                tag_base => .{ .base = try r.element() },
+                else => .{ .ext = try r.extension() },
+            },
+        };
+    }
+
+    if (r.offset != bytes.len) return error.Malformed;
+
+    return .{
+        .header = header,
+        .dynamic_sizes = dynamic_sizes,
+        .columns = columns,
+        .cells = cells,
+    };
+}
+
+fn freeVector(allocator: std.mem.Allocator, vector: value.Vector) void {
+    switch (vector) {
+        .base => |vals| allocator.free(vals),
+        .ext => |vals| allocator.free(vals),
+    }
+}
+
+const Reader = struct {
+    bytes: []const u8,
+    offset: usize,
+
+    fn take(self: *Reader, comptime n: usize) Error!*const [n]u8 {
+        if (self.bytes.len - self.offset < n) return error.Truncated;
+        const out = self.bytes[self.offset..][0..n];
+        self.offset += n;
+        return out;
+    }
+
+    fn int(self: *Reader, comptime T: type) Error!T {
+        return std.mem.readInt(T, try self.take(@sizeOf(T)), .big);
+    }
+
+    /// Reads a u32 count and checks that the remaining input can hold that
+    /// many entries of at least `min_size` bytes each.
+    fn count(self: *Reader, min_size: usize) Error!usize {
+        const n: usize = try self.int(u32);
+        if (n > (self.bytes.len - self.offset) / min_size) return error.Truncated;
+        return n;
+    }
+
+    /// Reads an object ID of the given kind, strictly greater than `prev`.
+    fn id(self: *Reader, kind: u8, prev: ?u64) Error!u64 {
+        const v = try self.int(u64);
+        if (@as(u8, @truncate(v >> 56)) != kind) return error.Malformed;
+        if (prev) |p| if (v <= p) return error.Malformed;
+        return v;
+    }
+
+    fn tag(self: *Reader) Error!u8 {
+        const t = (try self.take(1))[0];
+        if (t != tag_base and t != tag_ext) return error.Malformed;
+        return t;
+    }
+
+    fn element(self: *Reader) Error!base.Element {
+        return base.Element.fromBytesCanonical((try self.take(base.bytes)).*) catch error.Malformed;
+    }
+
+    fn extension(self: *Reader) Error!ext.Ext {
+        return ext.Ext.fromBytesCanonical((try self.take(ext.bytes)).*) catch error.Malformed;
+    }
+};
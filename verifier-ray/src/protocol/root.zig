@@ -1,6 +1,8 @@
 const types = @import("types.zig");
 const fiat_shamir = @import("../crypto/fiat_shamir.zig");
 
+pub const proof_wire = @import("proof_wire.zig");
+
 pub const Error = error{InvalidRoundCount};
 
 pub const Visibility = types.Visibility;
@@ -1,6 +1,7 @@
 comptime {
     _ = @import("field_test.zig");
     _ = @import("golden_test.zig");
+    _ = @import("proof_wire_test.zig");
     _ = @import("transcript_test.zig");
     _ = @import("vanishing_test.zig");
     _ = @import("verifier_test.zig");
@@ -0,0 +1,85 @@
+//! Decodes the golden proof fixture shared with prover-ray/wiop/wire. The
+//! expected values below mirror TestDecodeProof_Golden on the Go side.
+
+const std = @import("std");
+const verifier_ray = @import("verifier_ray");
+
+const field = verifier_ray.field.koalabear;
+const ext = verifier_ray.field.koalabear_ext;
+const proof_wire = verifier_ray.protocol.proof_wire;
+
+const minimal = @embedFile("test_proof_minimal");
+
+test "proof wire decodes the golden fixture" {
+    const allocator = std.testing.allocator;
+    const proof = try proof_wire.decodeProof(allocator, minimal);
+    defer proof.deinit(allocator);
+
+    try std.testing.expectEqual(proof_wire.version, proof.header.version);
+    try std.testing.expectEqual(proof_wire.Kind.proof, proof.header.kind);
+    for (proof.header.system_digest, 0..) |b, i| {
+        try std.testing.expectEqual(@as(u8, @intCast(i)), b);
+    }
+
+    try std.testing.expectEqual(@as(usize, 1), proof.dynamic_sizes.len);
+    try std.testing.expectEqual(proof_wire.DynamicSize{ .module = 2, .size = 8 }, proof.dynamic_sizes[0]);
+
+    try std.testing.expectEqual(@as(usize, 2), proof.columns.len);
+    const col0 = proof.columns[0];
+    try std.testing.expectEqual(@as(u64, 0x0100000000000000), col0.id);
+    try std.testing.expect(col0.padding.isZero());
+    try std.testing.expectEqualSlices(field.Element, &.{
+        field.Element.init(1),
+        field.Element.init(2),
+        field.Element.init(3),
+    }, col0.values.base);
+
+    const col1 = proof.columns[1];
+    try std.testing.expectEqual(@as(u64, 0x0100010000000002), col1.id);
+    try std.testing.expect(col1.padding.eql(field.Element.init(5)));
+    try std.testing.expectEqual(@as(usize, 1), col1.values.ext.len);
+    try std.testing.expect(col1.values.ext[0].eql(ext.Ext.fromUints(.{ 1, 2, 3, 4, 5, 6 })));
+
+    try std.testing.expectEqual(@as(usize, 2), proof.cells.len);
+    try std.testing.expectEqual(@as(u64, 0x0201000000000000), proof.cells[0].id);
+    try std.testing.expect(proof.cells[0].value.base.eql(field.Element.init(7)));
+    try std.testing.expectEqual(@as(u64, 0x0201000000000001), proof.cells[1].id);
+    try std.testing.expect(proof.cells[1].value.ext.eql(ext.Ext.fromUints(.{ field.modulus - 1, 0, 0, 0, 0, 1 })));
+}
+
+test "proof wire rejects every truncated prefix" {
+    for (0..minimal.len) |n| {
+        try std.testing.expectError(error.Truncated, proof_wire.decodeProof(std.testing.allocator, minimal[0..n]));
+    }
+}
+
+test "proof wire rejects corrupted fixtures" {
+    const Case = struct { offset: usize, byte: u8, err: proof_wire.Error };
+    const cases = [_]Case{
+        .{ .offset = 0, .byte = 'X', .err = error.BadMagic },
+        .{ .offset = 5, .byte = 2, .err = error.UnsupportedVersion },
+        .{ .offset = 7, .byte = 9, .err = error.UnexpectedKind },
+        // First column ID carries the cell kind.
+        .{ .offset = 56, .byte = 0x02, .err = error.Malformed },
+        // Unknown field tag on the first column.
+        .{ .offset = 64, .byte = 7, .err = error.Malformed },
+        // Non-canonical value of the first cell.
+        .{ .offset = 139, .byte = 0xff, .err = error.Malformed },
+        // Second cell ID equal to the first.
+        .{ .offset = 150, .byte = 0, .err = error.Malformed },
+    };
+    for (cases) |case| {
+        var bytes = minimal.*;
+        bytes[case.offset] = case.byte;
+        try std.testing.expectError(case.err, proof_wire.decodeProof(std.testing.allocator, &bytes));
+    }
+}
+
+test "proof wire does not leak on allocation failure" {
+    try std.testing.checkAllAllocationFailures(std.testing.allocator, decodeAndFree, .{});
+}
+
+fn decodeAndFree(allocator: std.mem.Allocator) !void {
+    const proof = try proof_wire.decodeProof(allocator, minimal);
+    proof.deinit(allocator);
+}